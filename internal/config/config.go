@@ -8,12 +8,28 @@ import (
 )
 
 type Config struct {
-	Server   ServerConfig   `yaml:"server"`
-	Database DatabaseConfig `yaml:"database"`
-	Redis    RedisConfig    `yaml:"redis"`
-	JWT      JWTConfig      `yaml:"jwt"`
-	CORS     CORSConfig     `yaml:"cors"`
-	LogLevel string         `yaml:"log_level"`
+	Server        ServerConfig        `yaml:"server"`
+	Database      DatabaseConfig      `yaml:"database"`
+	Redis         RedisConfig         `yaml:"redis"`
+	JWT           JWTConfig           `yaml:"jwt"`
+	Impersonation ImpersonationConfig `yaml:"impersonation"`
+	CORS          CORSConfig          `yaml:"cors"`
+	Services      ServiceAuthConfig   `yaml:"services"`
+	Audit         AuditConfig         `yaml:"audit"`
+	Airtable      AirtableConfig      `yaml:"airtable"`
+	Names         NamesConfig         `yaml:"names"`
+	Platform      PlatformConfig      `yaml:"platform"`
+	API           APIConfig           `yaml:"api"`
+	Sort          SortConfig          `yaml:"sort"`
+	RateLimit     RateLimitConfig     `yaml:"rate_limit"`
+	Concurrency   ConcurrencyConfig   `yaml:"concurrency"`
+	Maintenance   MaintenanceConfig   `yaml:"maintenance"`
+	Quota         QuotaConfig         `yaml:"quota"`
+	CacheRefresh  CacheRefreshConfig  `yaml:"cache_refresh"`
+	CacheWarm     CacheWarmConfig     `yaml:"cache_warm"`
+	Deletion      DeletionConfig      `yaml:"deletion"`
+	Members       MembersConfig       `yaml:"members"`
+	LogLevel      string              `yaml:"log_level"`
 }
 
 type ServerConfig struct {
@@ -36,6 +52,12 @@ type DatabaseConfig struct {
 	MaxIdleConns    int    `yaml:"max_idle_conns"`
 	ConnMaxLifetime int    `yaml:"conn_max_lifetime"`
 	ConnMaxIdleTime int    `yaml:"conn_max_idle_time"`
+	// Retry settings for transient errors (connection resets, serialization failures)
+	RetryMaxAttempts int `yaml:"retry_max_attempts"`
+	RetryBaseDelayMs int `yaml:"retry_base_delay_ms"`
+	RetryMaxDelayMs  int `yaml:"retry_max_delay_ms"`
+	// ReplicaDSN optionally names a read replica; reads outside transactions are served from it
+	ReplicaDSN string `yaml:"replica_dsn"`
 }
 
 type RedisConfig struct {
@@ -49,6 +71,8 @@ type RedisConfig struct {
 	ReadTimeout  int    `yaml:"read_timeout"`
 	WriteTimeout int    `yaml:"write_timeout"`
 	IdleTimeout  int    `yaml:"idle_timeout"`
+	// KeyVersion is appended to the cache key version; changing it abandons every cached entry
+	KeyVersion string `yaml:"key_version"`
 }
 
 type JWTConfig struct {
@@ -56,8 +80,340 @@ type JWTConfig struct {
 	TTL    int    `yaml:"ttl"`
 }
 
+type ImpersonationConfig struct {
+	// Claim is the JWT claim that must be true for a caller to send X-Impersonate-User
+	Claim string `yaml:"claim"`
+	// AllowDestructive permits impersonated requests to routes marked destructive
+	AllowDestructive bool `yaml:"allow_destructive"`
+}
+
+type AirtableConfig struct {
+	// MetadataTTL is how long, in seconds, cached base metadata is served without refetching
+	MetadataTTL int `yaml:"metadata_ttl"`
+	// MetadataRetention is how long, in seconds, metadata is kept as a stale fallback
+	MetadataRetention int `yaml:"metadata_retention"`
+}
+
 type CORSConfig struct {
 	AllowedOrigins string `yaml:"allowed_origins"`
+	// TenantOrigins lists extra origins per tenant, as "tenant=origin|origin;tenant=origin"
+	TenantOrigins string `yaml:"tenant_origins"`
+}
+
+type AuditConfig struct {
+	// ExtraActions and ExtraResourceTypes extend the built-in audit vocabulary (comma-separated)
+	ExtraActions       string `yaml:"extra_actions"`
+	ExtraResourceTypes string `yaml:"extra_resource_types"`
+	// LogDenials emits an authz.denied event when a request is refused for lack of access,
+	// at most once per user every DenialLogInterval seconds
+	LogDenials        bool `yaml:"log_denials"`
+	DenialLogInterval int  `yaml:"denial_log_interval"`
+	// MaxChangesBytes caps the JSON size of an entry's changes; larger diffs are stored as a
+	// truncation summary. Zero disables the cap.
+	MaxChangesBytes int `yaml:"max_changes_bytes"`
+	// IngestQueueSize bounds the batches ingested from other services that wait for the
+	// background writer. Zero writes them on the request path.
+	IngestQueueSize int `yaml:"ingest_queue_size"`
+	// Archive ships audit logs to an object store before retention cleanup deletes them
+	Archive AuditArchiveConfig `yaml:"archive"`
+}
+
+// AuditArchiveConfig controls audit log archival. When enabled, entries are uploaded as NDJSON
+// objects under Prefix in Bucket, and only entries whose upload succeeded are deleted.
+type AuditArchiveConfig struct {
+	Enabled bool   `yaml:"enabled"`
+	Bucket  string `yaml:"bucket"`
+	Prefix  string `yaml:"prefix"`
+}
+
+// Validate requires a bucket when archival is enabled
+func (c *AuditArchiveConfig) Validate() error {
+	if c.Enabled && c.Bucket == "" {
+		return fmt.Errorf("audit archival is enabled but no bucket is configured")
+	}
+	return nil
+}
+
+type ServiceAuthConfig struct {
+	// Principals lists internal callers as "name:token:tenant|tenant;..."; a tenant of * allows all
+	Principals string `yaml:"principals"`
+}
+
+// ServicePrincipal is an internal service authenticated by a shared token
+type ServicePrincipal struct {
+	Name    string
+	Tenants []string
+}
+
+// AllowsTenant reports whether the principal may act on the tenant's workspaces
+func (p *ServicePrincipal) AllowsTenant(tenantID string) bool {
+	for _, tenant := range p.Tenants {
+		if tenant == "*" || tenant == tenantID {
+			return true
+		}
+	}
+	return false
+}
+
+type APIConfig struct {
+	// StrictFields rejects unknown names in the fields query parameter instead of ignoring them
+	StrictFields bool `yaml:"strict_fields"`
+	// MinSearchLength is the shortest search term accepted by list endpoints
+	MinSearchLength int `yaml:"min_search_length"`
+	// MaxQueryParams caps the query parameters a request may carry; zero disables the check
+	MaxQueryParams int `yaml:"max_query_params"`
+	// MaxQueryLength caps the raw query string length in bytes; zero disables the check
+	MaxQueryLength int `yaml:"max_query_length"`
+	// MaxPageOffset caps how many items a page-numbered list may skip; zero disables the check
+	MaxPageOffset int `yaml:"max_page_offset"`
+}
+
+type PlatformConfig struct {
+	// Admins lists the user IDs allowed to run cross-tenant operations (comma-separated)
+	Admins string `yaml:"admins"`
+	// Provisioners lists the user IDs allowed to create workspaces owned by someone else (comma-separated)
+	Provisioners string `yaml:"provisioners"`
+}
+
+// IsAdmin reports whether userID is a platform admin
+func (c *PlatformConfig) IsAdmin(userID string) bool {
+	return listsUser(c.Admins, userID)
+}
+
+// CanProvision reports whether userID may create workspaces on behalf of another owner.
+// Platform admins always may.
+func (c *PlatformConfig) CanProvision(userID string) bool {
+	return c.IsAdmin(userID) || listsUser(c.Provisioners, userID)
+}
+
+// listsUser reports whether the comma-separated list contains userID
+func listsUser(list, userID string) bool {
+	if userID == "" {
+		return false
+	}
+	for _, candidate := range strings.Split(list, ",") {
+		if strings.TrimSpace(candidate) == userID {
+			return true
+		}
+	}
+	return false
+}
+
+type RateLimitConfig struct {
+	// RequestsPerMinute is each tenant's request budget per minute; zero disables rate limiting
+	RequestsPerMinute int `yaml:"requests_per_minute"`
+	// TenantOverrides replaces the budget for specific tenants, as "tenant=limit;tenant=limit"
+	TenantOverrides string `yaml:"tenant_overrides"`
+}
+
+// GetTenantOverrides parses TenantOverrides into a tenant ID to requests per minute map,
+// skipping malformed or non-positive limits
+func (c *RateLimitConfig) GetTenantOverrides() map[string]int {
+	overrides := make(map[string]int)
+	for _, entry := range strings.Split(c.TenantOverrides, ";") {
+		tenantID, limit, found := strings.Cut(entry, "=")
+		tenantID = strings.TrimSpace(tenantID)
+		if !found || tenantID == "" {
+			continue
+		}
+
+		if n, err := strconv.Atoi(strings.TrimSpace(limit)); err == nil && n > 0 {
+			overrides[tenantID] = n
+		}
+	}
+	return overrides
+}
+
+// ConcurrencyConfig caps the writes running at once against a single workspace. Each instance
+// counts only its own requests.
+type ConcurrencyConfig struct {
+	// PerWorkspace caps the writes running at once against one workspace; zero disables the cap
+	PerWorkspace int `yaml:"per_workspace"`
+	// QueueTimeoutMs is how long a write over the cap waits for a slot before receiving 429
+	QueueTimeoutMs int `yaml:"queue_timeout_ms"`
+}
+
+// QuotaConfig caps what a tenant or workspace may hold; a limit of zero means unlimited
+type QuotaConfig struct {
+	WorkspacesPerTenant  int `yaml:"workspaces_per_tenant"`
+	ProjectsPerWorkspace int `yaml:"projects_per_workspace"`
+	MembersPerWorkspace  int `yaml:"members_per_workspace"`
+	BasesPerWorkspace    int `yaml:"bases_per_workspace"`
+}
+
+// CacheRefreshConfig controls the opt-in background refresh of the user workspace cache. Users
+// who listed their workspaces within ActiveWindow seconds have their entry rebuilt every
+// Interval seconds, at most MaxUsers of them per run, so it is renewed before it expires.
+type CacheRefreshConfig struct {
+	Enabled      bool `yaml:"enabled"`
+	Interval     int  `yaml:"interval"`
+	ActiveWindow int  `yaml:"active_window"`
+	MaxUsers     int  `yaml:"max_users"`
+}
+
+// CacheWarmConfig controls the opt-in cache warming after startup. The TopWorkspaces workspaces
+// with the most audit activity in the last ActivityWindow seconds are loaded into the cache.
+type CacheWarmConfig struct {
+	Enabled        bool `yaml:"enabled"`
+	TopWorkspaces  int  `yaml:"top_workspaces"`
+	ActivityWindow int  `yaml:"activity_window"`
+}
+
+// DeletionConfig controls the background job that deletes workspaces whose scheduled deletion
+// is due. It runs every Interval seconds; 0 turns it off.
+type DeletionConfig struct {
+	Interval int `yaml:"interval"`
+}
+
+// MaintenanceReadOnly is the maintenance mode that rejects writes while serving reads
+const MaintenanceReadOnly = "read_only"
+
+type MaintenanceConfig struct {
+	// Mode is MaintenanceReadOnly to reject every write regardless of the admin toggle, or
+	// empty to leave it to the toggle
+	Mode string `yaml:"mode"`
+}
+
+// Validate rejects maintenance modes other than MaintenanceReadOnly
+func (c *MaintenanceConfig) Validate() error {
+	if c.Mode != "" && c.Mode != MaintenanceReadOnly {
+		return fmt.Errorf("invalid maintenance mode %q: use %q or leave it empty", c.Mode, MaintenanceReadOnly)
+	}
+	return nil
+}
+
+// Policies for the projects and Airtable bases a removed member created
+const (
+	// MemberRemovalOrphan removes the member and leaves created_by pointing at them
+	MemberRemovalOrphan = "orphan"
+	// MemberRemovalBlock refuses to remove a member who created projects or bases
+	MemberRemovalBlock = "block"
+	// MemberRemovalReassignToOwner moves created_by to the primary owner as the member is removed
+	MemberRemovalReassignToOwner = "reassign_to_owner"
+)
+
+type MembersConfig struct {
+	// RemovalPolicy is applied by member removal; empty means MemberRemovalOrphan. Removal with
+	// an explicit reassignment target is not affected.
+	RemovalPolicy string `yaml:"removal_policy"`
+	// AddDedupWindow is how many seconds an add stays deduplicated: a repeat of it for the same
+	// workspace and user in that time gets the first add's result instead of a conflict.
+	// 0 disables deduplication.
+	AddDedupWindow int `yaml:"add_dedup_window"`
+}
+
+// Validate rejects unknown removal policies
+func (c *MembersConfig) Validate() error {
+	switch c.RemovalPolicy {
+	case "", MemberRemovalOrphan, MemberRemovalBlock, MemberRemovalReassignToOwner:
+		return nil
+	}
+	return fmt.Errorf("invalid member removal policy %q: use %q, %q or %q", c.RemovalPolicy, MemberRemovalOrphan, MemberRemovalBlock, MemberRemovalReassignToOwner)
+}
+
+type NamesConfig struct {
+	// CaseInsensitive compares workspace/project names trimmed and lowercased for uniqueness
+	CaseInsensitive bool `yaml:"case_insensitive"`
+	// AccentInsensitive also strips diacritics after NFKD decomposition, so "Café" and "cafe"
+	// collide. It implies CaseInsensitive. Names are stored and shown as given either way.
+	AccentInsensitive bool `yaml:"accent_insensitive"`
+}
+
+// List endpoints that accept a configurable default sort
+const (
+	SortListWorkspaces    = "workspaces"
+	SortListProjects      = "projects"
+	SortListAirtableBases = "airtable_bases"
+	SortListMembers       = "members"
+	SortListAuditLogs     = "audit_logs"
+)
+
+// SortableColumns is the allowlist of columns each list endpoint may be ordered by
+var SortableColumns = map[string][]string{
+	SortListWorkspaces:    {"created_at", "updated_at", "name", "id"},
+	SortListProjects:      {"created_at", "updated_at", "name", "status", "id"},
+	SortListAirtableBases: {"created_at", "updated_at", "name", "last_sync_at", "id"},
+	SortListMembers:       {"joined_at", "role", "user_id"},
+	SortListAuditLogs:     {"created_at", "action", "resource_type"},
+}
+
+// builtinSorts is the order a list falls back to when no default is configured
+var builtinSorts = map[string]string{
+	SortListWorkspaces:    "created_at desc",
+	SortListProjects:      "created_at desc",
+	SortListAirtableBases: "created_at desc",
+	SortListMembers:       "joined_at desc",
+	SortListAuditLogs:     "created_at desc",
+}
+
+type SortConfig struct {
+	// Per-list default order used when a request omits sort_by, as "column" or "column asc|desc"
+	Workspaces    string `yaml:"workspaces"`
+	Projects      string `yaml:"projects"`
+	AirtableBases string `yaml:"airtable_bases"`
+	Members       string `yaml:"members"`
+	AuditLogs     string `yaml:"audit_logs"`
+}
+
+func (c *SortConfig) entries() map[string]string {
+	return map[string]string{
+		SortListWorkspaces:    c.Workspaces,
+		SortListProjects:      c.Projects,
+		SortListAirtableBases: c.AirtableBases,
+		SortListMembers:       c.Members,
+		SortListAuditLogs:     c.AuditLogs,
+	}
+}
+
+// Default returns the column and direction (ASC or DESC) a list is ordered by when the
+// request does not choose one. Unset entries use the built-in order for the list.
+func (c *SortConfig) Default(list string) (column, order string) {
+	spec := c.entries()[list]
+	if strings.TrimSpace(spec) == "" {
+		spec = builtinSorts[list]
+	}
+	column, order, _ = parseSort(spec)
+	return column, order
+}
+
+// Validate checks every configured default against SortableColumns
+func (c *SortConfig) Validate() error {
+	for list, spec := range c.entries() {
+		if strings.TrimSpace(spec) == "" {
+			continue
+		}
+		column, _, err := parseSort(spec)
+		if err != nil {
+			return fmt.Errorf("invalid default sort for %s: %w", list, err)
+		}
+		if !IsSortable(list, column) {
+			return fmt.Errorf("invalid default sort for %s: %q is not a sortable column", list, column)
+		}
+	}
+	return nil
+}
+
+// IsSortable reports whether column is in the list's sortable-columns allowlist
+func IsSortable(list, column string) bool {
+	for _, sortable := range SortableColumns[list] {
+		if sortable == column {
+			return true
+		}
+	}
+	return false
+}
+
+// parseSort splits "column [asc|desc]" into a column and an upper-case direction,
+// ascending when none is given as in SQL
+func parseSort(spec string) (column, order string, err error) {
+	fields := strings.Fields(strings.ToLower(spec))
+	switch {
+	case len(fields) == 1:
+		return fields[0], "ASC", nil
+	case len(fields) == 2 && (fields[1] == "asc" || fields[1] == "desc"):
+		return fields[0], strings.ToUpper(fields[1]), nil
+	}
+	return "", "", fmt.Errorf("%q is not of the form \"column [asc|desc]\"", spec)
 }
 
 func Load() (*Config, error) {
@@ -81,6 +437,10 @@ func Load() (*Config, error) {
 			MaxIdleConns:    getEnvAsInt("DB_MAX_IDLE_CONNS", 5),
 			ConnMaxLifetime: getEnvAsInt("DB_CONN_MAX_LIFETIME", 300),
 			ConnMaxIdleTime: getEnvAsInt("DB_CONN_MAX_IDLE_TIME", 60),
+			RetryMaxAttempts: getEnvAsInt("DB_RETRY_MAX_ATTEMPTS", 3),
+			RetryBaseDelayMs: getEnvAsInt("DB_RETRY_BASE_DELAY_MS", 50),
+			RetryMaxDelayMs:  getEnvAsInt("DB_RETRY_MAX_DELAY_MS", 1000),
+			ReplicaDSN:       getEnv("DB_REPLICA_DSN", ""),
 		},
 		Redis: RedisConfig{
 			Host:         getEnv("REDIS_HOST", "localhost"),
@@ -93,17 +453,113 @@ func Load() (*Config, error) {
 			ReadTimeout:  getEnvAsInt("REDIS_READ_TIMEOUT", 3),
 			WriteTimeout: getEnvAsInt("REDIS_WRITE_TIMEOUT", 3),
 			IdleTimeout:  getEnvAsInt("REDIS_IDLE_TIMEOUT", 300),
+			KeyVersion:   getEnv("REDIS_KEY_VERSION", ""),
 		},
 		JWT: JWTConfig{
 			Secret: getEnv("JWT_SECRET", "your-secret-key"),
 			TTL:    getEnvAsInt("JWT_TTL", 3600),
 		},
+		Impersonation: ImpersonationConfig{
+			Claim:            getEnv("IMPERSONATION_CLAIM", "impersonate"),
+			AllowDestructive: getEnvAsBool("IMPERSONATION_ALLOW_DESTRUCTIVE", false),
+		},
 		CORS: CORSConfig{
 			AllowedOrigins: getEnv("CORS_ALLOWED_ORIGINS", "*"),
+			TenantOrigins:  getEnv("CORS_TENANT_ORIGINS", ""),
+		},
+		Audit: AuditConfig{
+			ExtraActions:       getEnv("AUDIT_EXTRA_ACTIONS", ""),
+			ExtraResourceTypes: getEnv("AUDIT_EXTRA_RESOURCE_TYPES", ""),
+			LogDenials:         getEnvAsBool("AUDIT_LOG_DENIALS", true),
+			DenialLogInterval:  getEnvAsInt("AUDIT_DENIAL_LOG_INTERVAL", 60),
+			MaxChangesBytes:    getEnvAsInt("AUDIT_MAX_CHANGES_BYTES", 65536),
+			IngestQueueSize:    getEnvAsInt("AUDIT_INGEST_QUEUE_SIZE", 100),
+			Archive: AuditArchiveConfig{
+				Enabled: getEnvAsBool("AUDIT_ARCHIVE_ENABLED", false),
+				Bucket:  getEnv("AUDIT_ARCHIVE_BUCKET", ""),
+				Prefix:  getEnv("AUDIT_ARCHIVE_PREFIX", "audit-logs/"),
+			},
+		},
+		Airtable: AirtableConfig{
+			MetadataTTL:       getEnvAsInt("AIRTABLE_METADATA_TTL", 300),
+			MetadataRetention: getEnvAsInt("AIRTABLE_METADATA_RETENTION", 86400),
+		},
+		Services: ServiceAuthConfig{
+			Principals: getEnv("SERVICE_PRINCIPALS", ""),
+		},
+		Names: NamesConfig{
+			CaseInsensitive:   getEnvAsBool("NAMES_CASE_INSENSITIVE", true),
+			AccentInsensitive: getEnvAsBool("NAMES_ACCENT_INSENSITIVE", false),
+		},
+		Platform: PlatformConfig{
+			Admins:       getEnv("PLATFORM_ADMINS", ""),
+			Provisioners: getEnv("PLATFORM_PROVISIONERS", ""),
+		},
+		API: APIConfig{
+			StrictFields:    getEnvAsBool("API_STRICT_FIELDS", false),
+			MinSearchLength: getEnvAsInt("API_MIN_SEARCH_LENGTH", 2),
+			MaxQueryParams:  getEnvAsInt("API_MAX_QUERY_PARAMS", 100),
+			MaxQueryLength:  getEnvAsInt("API_MAX_QUERY_LENGTH", 8192),
+			MaxPageOffset:   getEnvAsInt("API_MAX_PAGE_OFFSET", 10000),
+		},
+		Sort: SortConfig{
+			Workspaces:    getEnv("SORT_DEFAULT_WORKSPACES", ""),
+			Projects:      getEnv("SORT_DEFAULT_PROJECTS", ""),
+			AirtableBases: getEnv("SORT_DEFAULT_AIRTABLE_BASES", ""),
+			Members:       getEnv("SORT_DEFAULT_MEMBERS", ""),
+			AuditLogs:     getEnv("SORT_DEFAULT_AUDIT_LOGS", ""),
+		},
+		RateLimit: RateLimitConfig{
+			RequestsPerMinute: getEnvAsInt("RATE_LIMIT_REQUESTS_PER_MINUTE", 0),
+			TenantOverrides:   getEnv("RATE_LIMIT_TENANT_OVERRIDES", ""),
+		},
+		Concurrency: ConcurrencyConfig{
+			PerWorkspace:   getEnvAsInt("CONCURRENCY_PER_WORKSPACE", 0),
+			QueueTimeoutMs: getEnvAsInt("CONCURRENCY_QUEUE_TIMEOUT_MS", 0),
+		},
+		Maintenance: MaintenanceConfig{
+			Mode: getEnv("MAINTENANCE_MODE", ""),
+		},
+		Quota: QuotaConfig{
+			WorkspacesPerTenant:  getEnvAsInt("QUOTA_WORKSPACES_PER_TENANT", 10),
+			ProjectsPerWorkspace: getEnvAsInt("QUOTA_PROJECTS_PER_WORKSPACE", 50),
+			MembersPerWorkspace:  getEnvAsInt("QUOTA_MEMBERS_PER_WORKSPACE", 0),
+			BasesPerWorkspace:    getEnvAsInt("QUOTA_BASES_PER_WORKSPACE", 0),
+		},
+		CacheRefresh: CacheRefreshConfig{
+			Enabled:      getEnvAsBool("CACHE_REFRESH_ENABLED", false),
+			Interval:     getEnvAsInt("CACHE_REFRESH_INTERVAL", 60),
+			ActiveWindow: getEnvAsInt("CACHE_REFRESH_ACTIVE_WINDOW", 900),
+			MaxUsers:     getEnvAsInt("CACHE_REFRESH_MAX_USERS", 500),
+		},
+		CacheWarm: CacheWarmConfig{
+			Enabled:        getEnvAsBool("CACHE_WARM_ENABLED", false),
+			TopWorkspaces:  getEnvAsInt("CACHE_WARM_TOP_WORKSPACES", 100),
+			ActivityWindow: getEnvAsInt("CACHE_WARM_ACTIVITY_WINDOW", 86400),
+		},
+		Deletion: DeletionConfig{
+			Interval: getEnvAsInt("SCHEDULED_DELETION_INTERVAL", 300),
+		},
+		Members: MembersConfig{
+			RemovalPolicy:  getEnv("MEMBER_REMOVAL_POLICY", MemberRemovalOrphan),
+			AddDedupWindow: getEnvAsInt("MEMBER_ADD_DEDUP_WINDOW", 10),
 		},
 		LogLevel: getEnv("LOG_LEVEL", "info"),
 	}
 
+	if err := config.Sort.Validate(); err != nil {
+		return nil, err
+	}
+	if err := config.Maintenance.Validate(); err != nil {
+		return nil, err
+	}
+	if err := config.Members.Validate(); err != nil {
+		return nil, err
+	}
+	if err := config.Audit.Archive.Validate(); err != nil {
+		return nil, err
+	}
+
 	return config, nil
 }
 
@@ -122,6 +578,14 @@ func getEnvAsInt(key string, defaultValue int) int {
 	return defaultValue
 }
 
+func getEnvAsBool(key string, defaultValue bool) bool {
+	valueStr := getEnv(key, "")
+	if value, err := strconv.ParseBool(valueStr); err == nil {
+		return value
+	}
+	return defaultValue
+}
+
 func (c *CORSConfig) GetAllowedOrigins() []string {
 	if c.AllowedOrigins == "*" {
 		return []string{"*"}
@@ -129,6 +593,80 @@ func (c *CORSConfig) GetAllowedOrigins() []string {
 	return strings.Split(c.AllowedOrigins, ",")
 }
 
+// GetTenantOrigins parses TenantOrigins into a tenant ID to allowed origins map
+func (c *CORSConfig) GetTenantOrigins() map[string][]string {
+	tenants := make(map[string][]string)
+	for _, entry := range strings.Split(c.TenantOrigins, ";") {
+		tenantID, origins, found := strings.Cut(entry, "=")
+		tenantID = strings.TrimSpace(tenantID)
+		if !found || tenantID == "" {
+			continue
+		}
+
+		for _, origin := range strings.Split(origins, "|") {
+			if origin = strings.TrimSpace(origin); origin != "" {
+				tenants[tenantID] = append(tenants[tenantID], origin)
+			}
+		}
+	}
+	return tenants
+}
+
+// GetPrincipals parses Principals into a map keyed by token
+func (c *ServiceAuthConfig) GetPrincipals() map[string]*ServicePrincipal {
+	principals := make(map[string]*ServicePrincipal)
+	for _, entry := range strings.Split(c.Principals, ";") {
+		parts := strings.SplitN(strings.TrimSpace(entry), ":", 3)
+		if len(parts) != 3 || parts[0] == "" || parts[1] == "" {
+			continue
+		}
+
+		principal := &ServicePrincipal{Name: parts[0]}
+		for _, tenant := range strings.Split(parts[2], "|") {
+			if tenant = strings.TrimSpace(tenant); tenant != "" {
+				principal.Tenants = append(principal.Tenants, tenant)
+			}
+		}
+		principals[parts[1]] = principal
+	}
+	return principals
+}
+
+// RedactedValue is the placeholder that replaces secrets in Redacted
+const RedactedValue = "[REDACTED]"
+
+// Redacted returns a copy of the config with its secrets replaced by RedactedValue: the JWT
+// secret, database and Redis passwords, the replica DSN and service principal tokens. Unset
+// secrets stay empty so the copy still shows which ones were configured.
+func (c *Config) Redacted() *Config {
+	redacted := *c
+	redacted.JWT.Secret = redact(c.JWT.Secret)
+	redacted.Database.Password = redact(c.Database.Password)
+	redacted.Database.ReplicaDSN = redact(c.Database.ReplicaDSN)
+	redacted.Redis.Password = redact(c.Redis.Password)
+
+	// Principal names and tenants say which callers are configured; only the tokens are secret
+	entries := strings.Split(c.Services.Principals, ";")
+	for i, entry := range entries {
+		if parts := strings.SplitN(strings.TrimSpace(entry), ":", 3); len(parts) == 3 {
+			entries[i] = parts[0] + ":" + redact(parts[1]) + ":" + parts[2]
+		} else if strings.TrimSpace(entry) != "" {
+			entries[i] = RedactedValue
+		}
+	}
+	redacted.Services.Principals = strings.Join(entries, ";")
+
+	return &redacted
+}
+
+// redact masks a secret, leaving an unset one empty
+func redact(secret string) string {
+	if secret == "" {
+		return ""
+	}
+	return RedactedValue
+}
+
 // GetDSN returns the database connection string
 func (c *DatabaseConfig) GetDSN() string {
 	return fmt.Sprintf("host=%s port=%d user=%s password=%s dbname=%s sslmode=%s",
@@ -1,36 +1,85 @@
 package handlers
 
 import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/gofiber/fiber/v2"
 	"go.uber.org/zap"
 
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/config"
 	"github.com/Reg-Kris/pyairtable-workspace-service/internal/models"
 	"github.com/Reg-Kris/pyairtable-workspace-service/internal/services"
+	"github.com/Reg-Kris/pyairtable-workspace-service/pkg/database"
+	"github.com/Reg-Kris/pyairtable-workspace-service/pkg/health"
 )
 
 // Handlers aggregates all handler functions
 type Handlers struct {
-	services *services.Services
-	logger   *zap.Logger
+	services  *services.Services
+	config    *config.Config
+	logger    *zap.Logger
+	readiness *health.Registry
+	denials   *denialLog
 }
 
-// New creates a new Handlers instance
-func New(services *services.Services, logger *zap.Logger) *Handlers {
+// New creates a new Handlers instance. Readiness starts with the database and Redis checks of
+// the services' stores.
+func New(services *services.Services, config *config.Config, logger *zap.Logger) *Handlers {
+	readiness := health.NewRegistry()
+	for _, check := range services.HealthChecks() {
+		readiness.Register(check)
+	}
+
 	return &Handlers{
-		services: services,
-		logger:   logger,
+		services:  services,
+		config:    config,
+		logger:    logger,
+		readiness: readiness,
+		denials:   newDenialLog(config, logger),
 	}
 }
 
+// Readiness returns the registry background subsystems register their health checks with
+func (h *Handlers) Readiness() *health.Registry {
+	return h.readiness
+}
+
+// StartBackgroundJobs starts the services' background jobs against this readiness registry.
+// Call it once the server is listening; the jobs stop when ctx is done.
+func (h *Handlers) StartBackgroundJobs(ctx context.Context) {
+	h.services.StartBackgroundJobs(ctx, h.readiness)
+}
+
 // Health handles health check requests
 func (h *Handlers) Health(c *fiber.Ctx) error {
 	return c.JSON(fiber.Map{
 		"status":    "healthy",
 		"service":   "workspace-service",
-		"timestamp": time.Now(),
+		"timestamp": time.Now().UTC(),
+	})
+}
+
+// Ready reports readiness, returning 503 when a critical subsystem is unhealthy
+func (h *Handlers) Ready(c *fiber.Ctx) error {
+	report := h.readiness.Run(h.requestContext(c))
+
+	status := fiber.StatusOK
+	if !report.Ready() {
+		status = fiber.StatusServiceUnavailable
+	}
+
+	return c.Status(status).JSON(fiber.Map{
+		"status":     report.Status,
+		"service":    "workspace-service",
+		"subsystems": report.Subsystems,
+		"timestamp":  time.Now().UTC(),
 	})
 }
 
@@ -52,38 +101,127 @@ func (h *Handlers) getTenantID(c *fiber.Ctx) string {
 	return tenantID.(string)
 }
 
+// requestContext returns the request context, carrying the request's operation context and
+// the real caller when the request is impersonating another user. Mutating requests read from
+// the primary so the rows they check or modify are never stale replica copies.
+func (h *Handlers) requestContext(c *fiber.Ctx) context.Context {
+	ctx := context.Context(c.Context())
+	if op, ok := c.Locals("operation").(*services.OperationContext); ok {
+		ctx = services.WithOperation(ctx, op)
+	}
+	if actorID, ok := c.Locals("impersonated_by").(string); ok && actorID != "" {
+		ctx = services.WithImpersonator(ctx, actorID)
+	}
+	if c.Method() != fiber.MethodGet && c.Method() != fiber.MethodHead {
+		ctx = database.WithPrimary(ctx)
+	}
+	return ctx
+}
+
+// readContext returns the request context, marked to bypass caches and any read replica when
+// the client sends Cache-Control: no-cache or ?no_cache=true, e.g. to read its own write
+func (h *Handlers) readContext(c *fiber.Ctx) context.Context {
+	ctx := h.requestContext(c)
+	if c.QueryBool("no_cache") || strings.Contains(strings.ToLower(c.Get(fiber.HeaderCacheControl)), "no-cache") {
+		ctx = database.WithPrimary(services.WithCacheBypass(ctx))
+	}
+	return ctx
+}
+
+// errUnsupportedContentType is returned by parseBody for bodies it cannot decode as JSON
+// when the content type gives it nothing else to go on
+var errUnsupportedContentType = errors.New("unsupported content type")
+
+// parseBody decodes the request body like BodyParser, falling back to JSON when the content
+// type is missing or unsupported but the body looks like JSON
+func (h *Handlers) parseBody(c *fiber.Ctx, out interface{}) error {
+	err := c.BodyParser(out)
+	if err != fiber.ErrUnprocessableEntity {
+		return err
+	}
+
+	body := bytes.TrimSpace(c.Body())
+	if len(body) == 0 || (body[0] != '{' && body[0] != '[') {
+		return errUnsupportedContentType
+	}
+	return c.App().Config().JSONDecoder(body, out)
+}
+
+// invalidBody responds 400 to a body parseBody rejected, naming the content type when that
+// was the problem
+func (h *Handlers) invalidBody(c *fiber.Ctx, err error) error {
+	message := "Invalid request body"
+	if err == errUnsupportedContentType {
+		message = "Unsupported content type, set Content-Type: application/json"
+	}
+	return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+		"error": message,
+	})
+}
+
 // handleError returns appropriate error response
 func (h *Handlers) handleError(c *fiber.Ctx, err error) error {
+	status, message := h.errorResponse(c, err)
+	return c.Status(status).JSON(fiber.Map{
+		"error": message,
+	})
+}
+
+// errorResponse maps a service error to the status and message handleError sends. Denials are
+// recorded and unexpected errors logged here, so batch handlers reporting per-item failures
+// behave like their single-item counterparts.
+func (h *Handlers) errorResponse(c *fiber.Ctx, err error) (int, string) {
 	switch err {
 	case services.ErrWorkspaceNotFound:
-		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
-			"error": "Workspace not found",
-		})
+		return fiber.StatusNotFound, "Workspace not found"
 	case services.ErrProjectNotFound:
-		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
-			"error": "Project not found",
-		})
+		return fiber.StatusNotFound, "Project not found"
 	case services.ErrAirtableBaseNotFound:
-		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
-			"error": "Airtable base not found",
-		})
+		return fiber.StatusNotFound, "Airtable base not found"
+	case services.ErrMemberNotFound:
+		return fiber.StatusNotFound, "Member not found"
+	case services.ErrServiceAccountNotFound:
+		return fiber.StatusNotFound, "Service account not found"
 	case services.ErrUnauthorized:
-		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
-			"error": "Unauthorized",
-		})
+		h.denials.record(c, h.getUserID(c))
+		return fiber.StatusForbidden, "Unauthorized"
 	case services.ErrQuotaExceeded:
-		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
-			"error": "Quota exceeded",
-		})
+		return fiber.StatusForbidden, "Quota exceeded"
 	case services.ErrInvalidInput:
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": "Invalid input",
-		})
+		return fiber.StatusBadRequest, "Invalid input"
+	case services.ErrInvalidReassignment:
+		return fiber.StatusBadRequest, "Reassignment target must be another workspace member"
+	case services.ErrInvalidOwner:
+		return fiber.StatusBadRequest, "Project owner must be a workspace member"
+	case services.ErrConfirmationRequired:
+		return fiber.StatusBadRequest, "Deleting active projects requires confirm_active"
+	case services.ErrProjectHasBases:
+		return fiber.StatusConflict, "Projects have connected Airtable bases; set force to delete them"
+	case services.ErrWorkspaceNotEmpty:
+		return fiber.StatusConflict, "Workspace still has projects"
+	case services.ErrWorkspaceNameTaken:
+		return fiber.StatusConflict, "A workspace with this name already exists in the tenant"
+	case services.ErrProjectNameTaken:
+		return fiber.StatusConflict, "A project with this name already exists in the workspace"
+	case services.ErrMemberExists:
+		return fiber.StatusConflict, "User is already a member of the workspace"
+	case services.ErrAirtableBaseExists:
+		return fiber.StatusConflict, "Airtable base is already connected to the project; set upsert to update it"
+	case services.ErrSyncInProgress:
+		return fiber.StatusConflict, "Airtable base sync is in progress; set force to disconnect"
+	case services.ErrAirtableBaseUnverified:
+		return fiber.StatusBadRequest, "Airtable base could not be verified with Airtable"
+	case services.ErrPrimaryOwner:
+		return fiber.StatusConflict, "Member is the primary owner; make another owner primary first"
+	case services.ErrMemberOwnsResources:
+		return fiber.StatusConflict, "Member created projects or Airtable bases; reassign them before removing the member"
+	case services.ErrTooManyTags:
+		return fiber.StatusBadRequest, "Project would exceed the maximum number of tags"
+	case services.ErrIngestQueueFull:
+		return fiber.StatusServiceUnavailable, "Audit ingestion is backed up; retry shortly"
 	default:
 		h.logger.Error("Unhandled error", zap.Error(err))
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"error": "Internal server error",
-		})
+		return fiber.StatusInternalServerError, "Internal server error"
 	}
 }
 
@@ -101,18 +239,22 @@ func (h *Handlers) CreateWorkspace(c *fiber.Ctx) error {
 	}
 
 	var req models.CreateWorkspaceRequest
-	if err := c.BodyParser(&req); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": "Invalid request body",
-		})
+	if err := h.parseBody(c, &req); err != nil {
+		return h.invalidBody(c, err)
+	}
+
+	strict := c.QueryBool("strict")
+	validation := services.ValidateCreateWorkspace(&req)
+	if validation.Rejected(strict) {
+		return h.validationFailed(c, validation, strict)
 	}
 
-	workspace, err := h.services.Workspace.CreateWorkspace(c.Context(), tenantID, userID, &req)
+	workspace, err := h.services.Workspace.CreateWorkspace(h.requestContext(c), tenantID, userID, &req)
 	if err != nil {
 		return h.handleError(c, err)
 	}
 
-	return c.Status(fiber.StatusCreated).JSON(workspace)
+	return h.sendWithWarnings(c, fiber.StatusCreated, workspace, validation.Warnings)
 }
 
 // GetWorkspace retrieves a workspace by ID
@@ -126,12 +268,30 @@ func (h *Handlers) GetWorkspace(c *fiber.Ctx) error {
 		})
 	}
 
-	workspace, err := h.services.Workspace.GetWorkspace(c.Context(), workspaceID, userID)
+	workspace, err := h.services.Workspace.GetWorkspace(h.readContext(c), workspaceID, userID)
 	if err != nil {
 		return h.handleError(c, err)
 	}
 
-	return c.JSON(workspace)
+	if models.HasInclude(c.Query("include"), "projects") {
+		// One page of live projects, read with the same query as the project listing
+		// rather than preloading every project of the workspace
+		filter := &models.ProjectFilter{
+			WorkspaceID: workspaceID,
+			Page:        c.QueryInt("page", 1),
+			PageSize:    c.QueryInt("page_size", 20),
+		}
+		projects, err := h.services.Project.ListProjects(h.readContext(c), filter, userID)
+		if err != nil {
+			return h.handleError(c, err)
+		}
+		projects.Links = h.pageLinks(c, projects.Page, projects.TotalPages)
+
+		return h.sendFields(c, &models.WorkspaceWithProjects{Workspace: workspace, Projects: projects},
+			restricted(workspaceVisibility), restrictedList("projects.projects", projectVisibility))
+	}
+
+	return h.sendFields(c, workspace, restricted(workspaceVisibility))
 }
 
 // UpdateWorkspace updates a workspace
@@ -146,18 +306,75 @@ func (h *Handlers) UpdateWorkspace(c *fiber.Ctx) error {
 	}
 
 	var req models.UpdateWorkspaceRequest
-	if err := c.BodyParser(&req); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": "Invalid request body",
+	if err := h.parseBody(c, &req); err != nil {
+		return h.invalidBody(c, err)
+	}
+
+	strict := c.QueryBool("strict")
+	validation := services.ValidateUpdateWorkspace(&req)
+	if validation.Rejected(strict) {
+		return h.validationFailed(c, validation, strict)
+	}
+
+	workspace, err := h.services.Workspace.UpdateWorkspace(h.requestContext(c), workspaceID, userID, &req)
+	if err != nil {
+		return h.handleError(c, err)
+	}
+
+	if req.IsEmpty() {
+		return h.sendUnchanged(c, workspace)
+	}
+
+	return h.sendWithWarnings(c, fiber.StatusOK, workspace, validation.Warnings)
+}
+
+// GetNotificationSettings returns a workspace's notification preferences
+func (h *Handlers) GetNotificationSettings(c *fiber.Ctx) error {
+	workspaceID := c.Params("id")
+	userID := h.getUserID(c)
+
+	if userID == "" {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Missing authentication",
+		})
+	}
+
+	settings, err := h.services.Workspace.GetNotificationSettings(h.readContext(c), workspaceID, userID)
+	if err != nil {
+		return h.handleError(c, err)
+	}
+
+	return c.JSON(settings)
+}
+
+// UpdateNotificationSettings replaces a workspace's notification preferences
+func (h *Handlers) UpdateNotificationSettings(c *fiber.Ctx) error {
+	workspaceID := c.Params("id")
+	userID := h.getUserID(c)
+
+	if userID == "" {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Missing authentication",
 		})
 	}
 
-	workspace, err := h.services.Workspace.UpdateWorkspace(c.Context(), workspaceID, userID, &req)
+	var req models.NotificationSettings
+	if err := h.parseBody(c, &req); err != nil {
+		return h.invalidBody(c, err)
+	}
+
+	strict := c.QueryBool("strict")
+	validation := services.ValidateNotificationSettings(&req)
+	if validation.Rejected(strict) {
+		return h.validationFailed(c, validation, strict)
+	}
+
+	settings, err := h.services.Workspace.UpdateNotificationSettings(h.requestContext(c), workspaceID, userID, &req)
 	if err != nil {
 		return h.handleError(c, err)
 	}
 
-	return c.JSON(workspace)
+	return c.JSON(settings)
 }
 
 // DeleteWorkspace deletes a workspace
@@ -171,7 +388,13 @@ func (h *Handlers) DeleteWorkspace(c *fiber.Ctx) error {
 		})
 	}
 
-	if err := h.services.Workspace.DeleteWorkspace(c.Context(), workspaceID, userID); err != nil {
+	// if_empty makes the emptiness check and the delete a single atomic operation
+	deleteWorkspace := h.services.Workspace.DeleteWorkspace
+	if c.QueryBool("if_empty") {
+		deleteWorkspace = h.services.Workspace.DeleteWorkspaceIfEmpty
+	}
+
+	if err := deleteWorkspace(h.requestContext(c), workspaceID, userID); err != nil {
 		return h.handleError(c, err)
 	}
 
@@ -200,12 +423,32 @@ func (h *Handlers) ListWorkspaces(c *fiber.Ctx) error {
 		})
 	}
 
-	response, err := h.services.Workspace.ListWorkspaces(c.Context(), filter, userID)
+	response, err := h.services.Workspace.ListWorkspaces(h.readContext(c), filter, userID)
 	if err != nil {
 		return h.handleError(c, err)
 	}
 
-	return c.JSON(response)
+	if filter.CountOnly {
+		return h.sendCount(c, response.Total)
+	}
+
+	response.Links = h.pageLinks(c, response.Page, response.TotalPages)
+
+	deleted := make(map[string]string)
+	for _, workspace := range response.Workspaces {
+		if workspace.DeletedAt.Valid {
+			deleted[workspace.ID] = workspace.ID
+		}
+	}
+
+	visible := h.visibleDeletions(c, userID, deleted)
+	if filter.ModifiedAfter != nil {
+		// A delta sync must still tell deletions apart from updates, so callers who may not see
+		// deletion metadata get a bare tombstone instead
+		return h.sendTombstoneList(c, response, "workspaces", visible, restrictedList("workspaces", workspaceVisibility))
+	}
+
+	return h.sendListFields(c, response, "workspaces", visible, restrictedList("workspaces", workspaceVisibility))
 }
 
 // GetWorkspaceStats retrieves workspace statistics
@@ -219,7 +462,14 @@ func (h *Handlers) GetWorkspaceStats(c *fiber.Ctx) error {
 		})
 	}
 
-	stats, err := h.services.Workspace.GetWorkspaceStats(c.Context(), tenantID, userID)
+	filter := &models.WorkspaceStatsFilter{}
+	if err := c.QueryParser(filter); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid query parameters",
+		})
+	}
+
+	stats, err := h.services.Workspace.GetWorkspaceStats(h.requestContext(c), tenantID, userID, filter)
 	if err != nil {
 		return h.handleError(c, err)
 	}
@@ -227,11 +477,8 @@ func (h *Handlers) GetWorkspaceStats(c *fiber.Ctx) error {
 	return c.JSON(stats)
 }
 
-// Project Handlers
-
-// CreateProject creates a new project
-func (h *Handlers) CreateProject(c *fiber.Ctx) error {
-	workspaceID := c.Params("workspace_id")
+// GetTenantQuota reports a tenant's workspace quota headroom
+func (h *Handlers) GetTenantQuota(c *fiber.Ctx) error {
 	userID := h.getUserID(c)
 
 	if userID == "" {
@@ -240,24 +487,17 @@ func (h *Handlers) CreateProject(c *fiber.Ctx) error {
 		})
 	}
 
-	var req models.CreateProjectRequest
-	if err := c.BodyParser(&req); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": "Invalid request body",
-		})
-	}
-
-	project, err := h.services.Project.CreateProject(c.Context(), workspaceID, userID, &req)
+	quota, err := h.services.Workspace.GetTenantQuota(h.readContext(c), c.Params("id"), h.getTenantID(c), userID)
 	if err != nil {
 		return h.handleError(c, err)
 	}
 
-	return c.Status(fiber.StatusCreated).JSON(project)
+	return c.JSON(quota)
 }
 
-// GetProject retrieves a project by ID
-func (h *Handlers) GetProject(c *fiber.Ctx) error {
-	projectID := c.Params("id")
+// GetWorkspaceQuota reports a workspace's project, member and Airtable base quota headroom
+func (h *Handlers) GetWorkspaceQuota(c *fiber.Ctx) error {
+	workspaceID := c.Params("id")
 	userID := h.getUserID(c)
 
 	if userID == "" {
@@ -266,17 +506,17 @@ func (h *Handlers) GetProject(c *fiber.Ctx) error {
 		})
 	}
 
-	project, err := h.services.Project.GetProject(c.Context(), projectID, userID)
+	quota, err := h.services.Workspace.GetWorkspaceQuota(h.readContext(c), workspaceID, userID)
 	if err != nil {
 		return h.handleError(c, err)
 	}
 
-	return c.JSON(project)
+	return c.JSON(quota)
 }
 
-// UpdateProject updates a project
-func (h *Handlers) UpdateProject(c *fiber.Ctx) error {
-	projectID := c.Params("id")
+// RefreshTenantStats recomputes and re-caches a tenant's statistics; platform admins only
+func (h *Handlers) RefreshTenantStats(c *fiber.Ctx) error {
+	tenantID := c.Params("id")
 	userID := h.getUserID(c)
 
 	if userID == "" {
@@ -285,24 +525,34 @@ func (h *Handlers) UpdateProject(c *fiber.Ctx) error {
 		})
 	}
 
-	var req models.UpdateProjectRequest
-	if err := c.BodyParser(&req); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": "Invalid request body",
+	stats, err := h.services.Workspace.RefreshWorkspaceStats(h.requestContext(c), tenantID, userID)
+	if err != nil {
+		return h.handleError(c, err)
+	}
+
+	return c.JSON(stats)
+}
+
+// GetMaintenanceMode reports the maintenance mode in effect
+func (h *Handlers) GetMaintenanceMode(c *fiber.Ctx) error {
+	userID := h.getUserID(c)
+
+	if userID == "" {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Missing authentication",
 		})
 	}
 
-	project, err := h.services.Project.UpdateProject(c.Context(), projectID, userID, &req)
+	status, err := h.services.Maintenance.Status(h.requestContext(c))
 	if err != nil {
 		return h.handleError(c, err)
 	}
 
-	return c.JSON(project)
+	return c.JSON(status)
 }
 
-// DeleteProject deletes a project
-func (h *Handlers) DeleteProject(c *fiber.Ctx) error {
-	projectID := c.Params("id")
+// SetMaintenanceMode sets or clears the admin maintenance toggle; platform admins only
+func (h *Handlers) SetMaintenanceMode(c *fiber.Ctx) error {
 	userID := h.getUserID(c)
 
 	if userID == "" {
@@ -311,15 +561,22 @@ func (h *Handlers) DeleteProject(c *fiber.Ctx) error {
 		})
 	}
 
-	if err := h.services.Project.DeleteProject(c.Context(), projectID, userID); err != nil {
+	var req models.SetMaintenanceModeRequest
+	if err := h.parseBody(c, &req); err != nil {
+		return h.invalidBody(c, err)
+	}
+
+	status, err := h.services.Maintenance.SetMode(h.requestContext(c), userID, req.Mode)
+	if err != nil {
 		return h.handleError(c, err)
 	}
 
-	return c.SendStatus(fiber.StatusNoContent)
+	return c.JSON(status)
 }
 
-// ListProjects lists projects
-func (h *Handlers) ListProjects(c *fiber.Ctx) error {
+// GetEffectiveConfig returns the configuration the service loaded, with secrets redacted;
+// platform admins only
+func (h *Handlers) GetEffectiveConfig(c *fiber.Ctx) error {
 	userID := h.getUserID(c)
 
 	if userID == "" {
@@ -328,28 +585,42 @@ func (h *Handlers) ListProjects(c *fiber.Ctx) error {
 		})
 	}
 
-	filter := &models.ProjectFilter{}
+	if !h.config.Platform.IsAdmin(userID) {
+		return h.handleError(c, services.ErrUnauthorized)
+	}
 
-	// Parse query parameters
+	return c.JSON(h.config.Redacted())
+}
+
+// GetWorkspaceTrends returns the tenant's workspace size averages and daily creation series
+func (h *Handlers) GetWorkspaceTrends(c *fiber.Ctx) error {
+	tenantID := h.getTenantID(c)
+	userID := h.getUserID(c)
+
+	if tenantID == "" || userID == "" {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Missing authentication context",
+		})
+	}
+
+	filter := &models.WorkspaceTrendsFilter{}
 	if err := c.QueryParser(filter); err != nil {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
 			"error": "Invalid query parameters",
 		})
 	}
 
-	response, err := h.services.Project.ListProjects(c.Context(), filter, userID)
+	trends, err := h.services.Workspace.GetWorkspaceTrends(h.readContext(c), tenantID, userID, filter)
 	if err != nil {
 		return h.handleError(c, err)
 	}
 
-	return c.JSON(response)
+	return c.JSON(trends)
 }
 
-// Airtable Base Handlers
-
-// ConnectAirtableBase connects an Airtable base to a project
-func (h *Handlers) ConnectAirtableBase(c *fiber.Ctx) error {
-	projectID := c.Params("project_id")
+// ChangeWorkspaceTenant moves a workspace to another tenant; platform admins only
+func (h *Handlers) ChangeWorkspaceTenant(c *fiber.Ctx) error {
+	workspaceID := c.Params("id")
 	userID := h.getUserID(c)
 
 	if userID == "" {
@@ -358,24 +629,26 @@ func (h *Handlers) ConnectAirtableBase(c *fiber.Ctx) error {
 		})
 	}
 
-	var req models.CreateAirtableBaseRequest
-	if err := c.BodyParser(&req); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": "Invalid request body",
-		})
+	var req models.ChangeWorkspaceTenantRequest
+	if err := h.parseBody(c, &req); err != nil {
+		return h.invalidBody(c, err)
+	}
+
+	if validation := services.ValidateRequest(&req); validation.Rejected(false) {
+		return h.validationFailed(c, validation, false)
 	}
 
-	base, err := h.services.AirtableBase.ConnectBase(c.Context(), projectID, userID, &req)
+	workspace, err := h.services.Workspace.ChangeTenant(h.requestContext(c), workspaceID, req.TenantID, userID)
 	if err != nil {
 		return h.handleError(c, err)
 	}
 
-	return c.Status(fiber.StatusCreated).JSON(base)
+	return h.sendFields(c, workspace)
 }
 
-// GetAirtableBase retrieves an Airtable base by ID
-func (h *Handlers) GetAirtableBase(c *fiber.Ctx) error {
-	baseID := c.Params("id")
+// ScheduleWorkspaceDeletion marks a workspace for deletion after a number of days; owners only
+func (h *Handlers) ScheduleWorkspaceDeletion(c *fiber.Ctx) error {
+	workspaceID := c.Params("id")
 	userID := h.getUserID(c)
 
 	if userID == "" {
@@ -384,17 +657,26 @@ func (h *Handlers) GetAirtableBase(c *fiber.Ctx) error {
 		})
 	}
 
-	base, err := h.services.AirtableBase.GetBase(c.Context(), baseID, userID)
+	var req models.ScheduleWorkspaceDeletionRequest
+	if err := h.parseBody(c, &req); err != nil {
+		return h.invalidBody(c, err)
+	}
+
+	if validation := services.ValidateRequest(&req); validation.Rejected(false) {
+		return h.validationFailed(c, validation, false)
+	}
+
+	workspace, err := h.services.Workspace.ScheduleWorkspaceDeletion(h.requestContext(c), workspaceID, userID, req.Days)
 	if err != nil {
 		return h.handleError(c, err)
 	}
 
-	return c.JSON(base)
+	return h.sendFields(c, workspace)
 }
 
-// UpdateAirtableBase updates an Airtable base
-func (h *Handlers) UpdateAirtableBase(c *fiber.Ctx) error {
-	baseID := c.Params("id")
+// CancelScheduledWorkspaceDeletion clears a workspace's scheduled deletion; owners only
+func (h *Handlers) CancelScheduledWorkspaceDeletion(c *fiber.Ctx) error {
+	workspaceID := c.Params("id")
 	userID := h.getUserID(c)
 
 	if userID == "" {
@@ -403,41 +685,35 @@ func (h *Handlers) UpdateAirtableBase(c *fiber.Ctx) error {
 		})
 	}
 
-	var req models.UpdateAirtableBaseRequest
-	if err := c.BodyParser(&req); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": "Invalid request body",
-		})
-	}
-
-	base, err := h.services.AirtableBase.UpdateBase(c.Context(), baseID, userID, &req)
+	workspace, err := h.services.Workspace.CancelScheduledDeletion(h.requestContext(c), workspaceID, userID)
 	if err != nil {
 		return h.handleError(c, err)
 	}
 
-	return c.JSON(base)
+	return h.sendFields(c, workspace)
 }
 
-// DisconnectAirtableBase disconnects an Airtable base
-func (h *Handlers) DisconnectAirtableBase(c *fiber.Ctx) error {
-	baseID := c.Params("id")
+// CheckWorkspaceNameAvailable reports whether a workspace name is free in the caller's tenant
+func (h *Handlers) CheckWorkspaceNameAvailable(c *fiber.Ctx) error {
+	tenantID := h.getTenantID(c)
 	userID := h.getUserID(c)
 
-	if userID == "" {
+	if tenantID == "" || userID == "" {
 		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
-			"error": "Missing authentication",
+			"error": "Missing authentication context",
 		})
 	}
 
-	if err := h.services.AirtableBase.DisconnectBase(c.Context(), baseID, userID); err != nil {
+	available, err := h.services.Workspace.IsNameAvailable(h.requestContext(c), tenantID, c.Query("name"))
+	if err != nil {
 		return h.handleError(c, err)
 	}
 
-	return c.SendStatus(fiber.StatusNoContent)
+	return c.JSON(&models.NameAvailabilityResponse{Available: available})
 }
 
-// ListAirtableBases lists Airtable bases
-func (h *Handlers) ListAirtableBases(c *fiber.Ctx) error {
+// ResolveWorkspaceNames maps workspace IDs to names for the ones the caller can access
+func (h *Handlers) ResolveWorkspaceNames(c *fiber.Ctx) error {
 	userID := h.getUserID(c)
 
 	if userID == "" {
@@ -446,27 +722,28 @@ func (h *Handlers) ListAirtableBases(c *fiber.Ctx) error {
 		})
 	}
 
-	filter := &models.AirtableBaseFilter{}
+	var req models.ResolveWorkspaceNamesRequest
+	if err := h.parseBody(c, &req); err != nil {
+		return h.invalidBody(c, err)
+	}
 
-	// Parse query parameters
-	if err := c.QueryParser(filter); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": "Invalid query parameters",
-		})
+	validation := services.ValidateResolveWorkspaceNames(&req)
+	if validation.Rejected(false) {
+		return h.validationFailed(c, validation, false)
 	}
 
-	response, err := h.services.AirtableBase.ListBases(c.Context(), filter, userID)
+	names, err := h.services.Workspace.ResolveNames(h.readContext(c), req.WorkspaceIDs, userID)
 	if err != nil {
 		return h.handleError(c, err)
 	}
 
-	return c.JSON(response)
+	return c.JSON(names)
 }
 
-// Member Handlers
+// Project Handlers
 
-// AddWorkspaceMember adds a member to a workspace
-func (h *Handlers) AddWorkspaceMember(c *fiber.Ctx) error {
+// CreateProject creates a new project
+func (h *Handlers) CreateProject(c *fiber.Ctx) error {
 	workspaceID := c.Params("workspace_id")
 	userID := h.getUserID(c)
 
@@ -476,25 +753,29 @@ func (h *Handlers) AddWorkspaceMember(c *fiber.Ctx) error {
 		})
 	}
 
-	var req models.AddWorkspaceMemberRequest
-	if err := c.BodyParser(&req); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": "Invalid request body",
-		})
+	var req models.CreateProjectRequest
+	if err := h.parseBody(c, &req); err != nil {
+		return h.invalidBody(c, err)
 	}
 
-	member, err := h.services.Member.AddMember(c.Context(), workspaceID, userID, &req)
+	strict := c.QueryBool("strict")
+	validation := services.ValidateCreateProject(&req)
+	if validation.Rejected(strict) {
+		return h.validationFailed(c, validation, strict)
+	}
+
+	project, err := h.services.Project.CreateProject(h.requestContext(c), workspaceID, userID, &req)
 	if err != nil {
 		return h.handleError(c, err)
 	}
 
-	return c.Status(fiber.StatusCreated).JSON(member)
+	return h.sendWithWarnings(c, fiber.StatusCreated, project, validation.Warnings)
 }
 
-// UpdateWorkspaceMemberRole updates a member's role
-func (h *Handlers) UpdateWorkspaceMemberRole(c *fiber.Ctx) error {
+// BatchCreateProjects creates several projects, reporting each item's outcome with 207 when
+// any of them fails
+func (h *Handlers) BatchCreateProjects(c *fiber.Ctx) error {
 	workspaceID := c.Params("workspace_id")
-	memberUserID := c.Params("user_id")
 	userID := h.getUserID(c)
 
 	if userID == "" {
@@ -503,26 +784,44 @@ func (h *Handlers) UpdateWorkspaceMemberRole(c *fiber.Ctx) error {
 		})
 	}
 
-	var req models.UpdateWorkspaceMemberRequest
-	if err := c.BodyParser(&req); err != nil {
+	var req models.BatchCreateProjectsRequest
+	if err := h.parseBody(c, &req); err != nil {
+		return h.invalidBody(c, err)
+	}
+
+	if err := validateBatchSize(len(req.Projects)); err != nil {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": "Invalid request body",
+			"error": "projects " + err.Error(),
 		})
 	}
 
-	if err := h.services.Member.UpdateMemberRole(c.Context(), workspaceID, memberUserID, userID, &req); err != nil {
-		return h.handleError(c, err)
+	ctx := h.requestContext(c)
+	strict := c.QueryBool("strict")
+	result := models.NewBatchResult[models.Project](len(req.Projects))
+	for i := range req.Projects {
+		item := &req.Projects[i]
+
+		validation := services.ValidateCreateProject(item)
+		if validation.Rejected(strict) {
+			result.Invalid(i, validation.Problems(strict), validation.FieldErrors)
+			continue
+		}
+
+		project, err := h.services.Project.CreateProject(ctx, workspaceID, userID, item)
+		if err != nil {
+			status, message := h.errorResponse(c, err)
+			result.Fail(i, status, message, nil)
+			continue
+		}
+		result.Succeed(i, fiber.StatusCreated, project, validation.Warnings)
 	}
 
-	return c.JSON(fiber.Map{
-		"message": "Member role updated successfully",
-	})
+	return c.Status(result.Status(fiber.StatusCreated)).JSON(result)
 }
 
-// RemoveWorkspaceMember removes a member from a workspace
-func (h *Handlers) RemoveWorkspaceMember(c *fiber.Ctx) error {
-	workspaceID := c.Params("workspace_id")
-	memberUserID := c.Params("user_id")
+// GetProject retrieves a project by ID
+func (h *Handlers) GetProject(c *fiber.Ctx) error {
+	projectID := c.Params("id")
 	userID := h.getUserID(c)
 
 	if userID == "" {
@@ -531,16 +830,17 @@ func (h *Handlers) RemoveWorkspaceMember(c *fiber.Ctx) error {
 		})
 	}
 
-	if err := h.services.Member.RemoveMember(c.Context(), workspaceID, memberUserID, userID); err != nil {
+	project, err := h.services.Project.GetProject(h.readContext(c), projectID, userID)
+	if err != nil {
 		return h.handleError(c, err)
 	}
 
-	return c.SendStatus(fiber.StatusNoContent)
+	return h.sendFields(c, project, restricted(projectVisibility))
 }
 
-// ListWorkspaceMembers lists workspace members
-func (h *Handlers) ListWorkspaceMembers(c *fiber.Ctx) error {
-	workspaceID := c.Params("workspace_id")
+// UpdateProject updates a project
+func (h *Handlers) UpdateProject(c *fiber.Ctx) error {
+	projectID := c.Params("id")
 	userID := h.getUserID(c)
 
 	if userID == "" {
@@ -549,19 +849,32 @@ func (h *Handlers) ListWorkspaceMembers(c *fiber.Ctx) error {
 		})
 	}
 
-	page, _ := strconv.Atoi(c.Query("page", "1"))
-	pageSize, _ := strconv.Atoi(c.Query("page_size", "20"))
+	var req models.UpdateProjectRequest
+	if err := h.parseBody(c, &req); err != nil {
+		return h.invalidBody(c, err)
+	}
+
+	strict := c.QueryBool("strict")
+	validation := services.ValidateUpdateProject(&req)
+	if validation.Rejected(strict) {
+		return h.validationFailed(c, validation, strict)
+	}
 
-	response, err := h.services.Member.ListMembers(c.Context(), workspaceID, userID, page, pageSize)
+	project, err := h.services.Project.UpdateProject(h.requestContext(c), projectID, userID, &req)
 	if err != nil {
 		return h.handleError(c, err)
 	}
 
-	return c.JSON(response)
+	if req.IsEmpty() {
+		return h.sendUnchanged(c, project)
+	}
+
+	return h.sendWithWarnings(c, fiber.StatusOK, project, validation.Warnings)
 }
 
-// GetUserWorkspaces retrieves all workspaces for a user
-func (h *Handlers) GetUserWorkspaces(c *fiber.Ctx) error {
+// SetProjectOwner changes a project's owner attribution
+func (h *Handlers) SetProjectOwner(c *fiber.Ctx) error {
+	projectID := c.Params("id")
 	userID := h.getUserID(c)
 
 	if userID == "" {
@@ -570,17 +883,944 @@ func (h *Handlers) GetUserWorkspaces(c *fiber.Ctx) error {
 		})
 	}
 
-	workspaces, err := h.services.Member.GetUserWorkspaces(c.Context(), userID)
-	if err != nil {
+	var req models.SetProjectOwnerRequest
+	if err := h.parseBody(c, &req); err != nil {
+		return h.invalidBody(c, err)
+	}
+
+	if validation := services.ValidateRequest(&req); validation.Rejected(false) {
+		return h.validationFailed(c, validation, false)
+	}
+
+	project, err := h.services.Project.SetProjectOwner(h.requestContext(c), projectID, req.UserID, userID)
+	if err != nil {
+		return h.handleError(c, err)
+	}
+
+	return h.sendFields(c, project)
+}
+
+// GetProjectAccess lists the users who can see a project and the roles they resolve to
+func (h *Handlers) GetProjectAccess(c *fiber.Ctx) error {
+	projectID := c.Params("id")
+	userID := h.getUserID(c)
+
+	if userID == "" {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Missing authentication",
+		})
+	}
+
+	access, err := h.services.Project.GetProjectAccess(h.readContext(c), projectID, userID)
+	if err != nil {
+		return h.handleError(c, err)
+	}
+
+	return c.JSON(access)
+}
+
+// DeleteProject deletes a project
+func (h *Handlers) DeleteProject(c *fiber.Ctx) error {
+	projectID := c.Params("id")
+	userID := h.getUserID(c)
+
+	if userID == "" {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Missing authentication",
+		})
+	}
+
+	if err := h.services.Project.DeleteProject(h.requestContext(c), projectID, userID); err != nil {
+		return h.handleError(c, err)
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// CheckProjectNameAvailable reports whether a project name is free in the workspace
+func (h *Handlers) CheckProjectNameAvailable(c *fiber.Ctx) error {
+	workspaceID := c.Params("workspace_id")
+	userID := h.getUserID(c)
+
+	if userID == "" {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Missing authentication",
+		})
+	}
+
+	available, err := h.services.Project.IsNameAvailable(h.requestContext(c), workspaceID, userID, c.Query("name"))
+	if err != nil {
+		return h.handleError(c, err)
+	}
+
+	return c.JSON(&models.NameAvailabilityResponse{Available: available})
+}
+
+// BulkDeleteProjects deletes the projects in a workspace selected by ID list or status
+func (h *Handlers) BulkDeleteProjects(c *fiber.Ctx) error {
+	workspaceID := c.Params("workspace_id")
+	userID := h.getUserID(c)
+
+	if userID == "" {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Missing authentication",
+		})
+	}
+
+	var req models.BulkDeleteProjectsRequest
+	if err := h.parseBody(c, &req); err != nil {
+		return h.invalidBody(c, err)
+	}
+
+	result, err := h.services.Project.BulkDeleteProjects(h.requestContext(c), workspaceID, userID, &req)
+	if err != nil {
+		return h.handleError(c, err)
+	}
+
+	return c.JSON(result)
+}
+
+// TagProjects adds and removes tags on several projects of a workspace
+func (h *Handlers) TagProjects(c *fiber.Ctx) error {
+	workspaceID := c.Params("workspace_id")
+	userID := h.getUserID(c)
+
+	if userID == "" {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Missing authentication",
+		})
+	}
+
+	var req models.TagProjectsRequest
+	if err := h.parseBody(c, &req); err != nil {
+		return h.invalidBody(c, err)
+	}
+
+	validation := services.ValidateTagProjects(&req)
+	if validation.Rejected(false) {
+		return h.validationFailed(c, validation, false)
+	}
+
+	result, err := h.services.Project.TagProjects(h.requestContext(c), workspaceID, userID, &req)
+	if err != nil {
+		return h.handleError(c, err)
+	}
+
+	return c.JSON(result)
+}
+
+// ListProjects lists projects
+func (h *Handlers) ListProjects(c *fiber.Ctx) error {
+	userID := h.getUserID(c)
+
+	if userID == "" {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Missing authentication",
+		})
+	}
+
+	filter := &models.ProjectFilter{}
+
+	// Parse query parameters
+	if err := c.QueryParser(filter); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid query parameters",
+		})
+	}
+
+	switch filter.GroupBy {
+	case "":
+	case models.ProjectGroupByWorkspace:
+		grouped, err := h.services.Project.ListProjectsByWorkspace(h.requestContext(c), filter, userID)
+		if err != nil {
+			return h.handleError(c, err)
+		}
+		if filter.CountOnly {
+			return h.sendCount(c, grouped.Total)
+		}
+
+		grouped.Links = h.pageLinks(c, grouped.Page, grouped.TotalPages)
+		shaped, err := h.restrictFields(c, grouped, []fieldRestriction{restrictedList("workspaces.projects", projectVisibility)})
+		if err != nil {
+			return h.handleError(c, err)
+		}
+		return c.JSON(shaped)
+	default:
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Unsupported group_by; use workspace",
+		})
+	}
+
+	response, err := h.services.Project.ListProjects(h.requestContext(c), filter, userID)
+	if err != nil {
+		return h.handleError(c, err)
+	}
+
+	if filter.CountOnly {
+		return h.sendCount(c, response.Total)
+	}
+
+	response.Links = h.pageLinks(c, response.Page, response.TotalPages)
+
+	deleted := make(map[string]string)
+	for _, project := range response.Projects {
+		if project.DeletedAt.Valid {
+			deleted[project.ID] = project.WorkspaceID
+		}
+	}
+
+	return h.sendListFields(c, response, "projects", h.visibleDeletions(c, userID, deleted), restrictedList("projects", projectVisibility))
+}
+
+// Airtable Base Handlers
+
+// ConnectAirtableBase connects an Airtable base to a project
+func (h *Handlers) ConnectAirtableBase(c *fiber.Ctx) error {
+	projectID := c.Params("project_id")
+	userID := h.getUserID(c)
+
+	if userID == "" {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Missing authentication",
+		})
+	}
+
+	// Parsed for upsert so an update can tell omitted fields from zero values
+	var req models.UpsertAirtableBaseRequest
+	if err := h.parseBody(c, &req); err != nil {
+		return h.invalidBody(c, err)
+	}
+	create := req.CreateRequest()
+
+	strict := c.QueryBool("strict")
+	validation := services.ValidateCreateAirtableBase(create)
+	if validation.Rejected(strict) {
+		return h.validationFailed(c, validation, strict)
+	}
+
+	if c.QueryBool("upsert") {
+		base, created, err := h.services.AirtableBase.UpsertBase(h.requestContext(c), projectID, userID, &req)
+		if err != nil {
+			return h.handleError(c, err)
+		}
+		status := fiber.StatusOK
+		if created {
+			status = fiber.StatusCreated
+		}
+		return h.sendWithWarnings(c, status, base, validation.Warnings)
+	}
+
+	base, err := h.services.AirtableBase.ConnectBase(h.requestContext(c), projectID, userID, create)
+	if err != nil {
+		return h.handleError(c, err)
+	}
+
+	return h.sendWithWarnings(c, fiber.StatusCreated, base, validation.Warnings)
+}
+
+// BatchConnectAirtableBases connects several Airtable bases to a project, reporting each base.
+// Bases already connected are skipped and reported with 409.
+func (h *Handlers) BatchConnectAirtableBases(c *fiber.Ctx) error {
+	projectID := c.Params("project_id")
+	userID := h.getUserID(c)
+
+	if userID == "" {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Missing authentication",
+		})
+	}
+
+	var req models.BatchConnectAirtableBasesRequest
+	if err := h.parseBody(c, &req); err != nil {
+		return h.invalidBody(c, err)
+	}
+
+	if err := validateBatchSize(len(req.Bases)); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "bases " + err.Error(),
+		})
+	}
+
+	// Invalid items never reach the service; the rest are connected together
+	strict := c.QueryBool("strict")
+	validations := make([]*services.ValidationResult, len(req.Bases))
+	var valid []models.CreateAirtableBaseRequest
+	for i := range req.Bases {
+		validations[i] = services.ValidateCreateAirtableBase(&req.Bases[i])
+		if !validations[i].Rejected(strict) {
+			valid = append(valid, req.Bases[i])
+		}
+	}
+
+	var connected []*services.ConnectBaseResult
+	if len(valid) > 0 {
+		var err error
+		connected, err = h.services.AirtableBase.ConnectBases(h.requestContext(c), projectID, userID, valid)
+		if err != nil {
+			return h.handleError(c, err)
+		}
+	}
+
+	result := models.NewBatchResult[models.AirtableBase](len(req.Bases))
+	for i, validation := range validations {
+		if validation.Rejected(strict) {
+			result.Invalid(i, validation.Problems(strict), validation.FieldErrors)
+			continue
+		}
+
+		item := connected[0]
+		connected = connected[1:]
+		switch item.Err {
+		case nil:
+			result.Succeed(i, fiber.StatusCreated, item.Base, validation.Warnings)
+		case services.ErrAirtableBaseExists:
+			result.Fail(i, fiber.StatusConflict, "Airtable base is already connected to the project; skipped", nil)
+		default:
+			status, message := h.errorResponse(c, item.Err)
+			result.Fail(i, status, message, nil)
+		}
+	}
+
+	return c.Status(result.Status(fiber.StatusCreated)).JSON(result)
+}
+
+// GetAirtableBase retrieves an Airtable base by ID
+func (h *Handlers) GetAirtableBase(c *fiber.Ctx) error {
+	baseID := c.Params("id")
+	userID := h.getUserID(c)
+
+	if userID == "" {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Missing authentication",
+		})
+	}
+
+	base, err := h.services.AirtableBase.GetBase(h.requestContext(c), baseID, userID)
+	if err != nil {
+		return h.handleError(c, err)
+	}
+
+	if models.HasInclude(c.Query("include"), "metadata") {
+		metadata, err := h.services.AirtableBase.GetBaseMetadata(h.requestContext(c), base)
+		if err != nil {
+			return h.handleError(c, err)
+		}
+		base.Metadata = metadata
+	}
+
+	return h.sendFields(c, base, baseRestrictions("")...)
+}
+
+// LookupAirtableBases finds the caller's connections of an Airtable base by its base_id
+func (h *Handlers) LookupAirtableBases(c *fiber.Ctx) error {
+	userID := h.getUserID(c)
+
+	if userID == "" {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Missing authentication",
+		})
+	}
+
+	airtableBaseID := c.Query("base_id")
+	if airtableBaseID == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "base_id is required",
+		})
+	}
+
+	bases, err := h.services.AirtableBase.LookupBases(h.readContext(c), airtableBaseID, userID)
+	if err != nil {
+		return h.handleError(c, err)
+	}
+
+	return h.sendListFields(c, &models.AirtableBaseLookupResponse{Bases: bases, Total: len(bases)}, "bases", nil)
+}
+
+// UpdateAirtableBase updates an Airtable base
+func (h *Handlers) UpdateAirtableBase(c *fiber.Ctx) error {
+	baseID := c.Params("id")
+	userID := h.getUserID(c)
+
+	if userID == "" {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Missing authentication",
+		})
+	}
+
+	var req models.UpdateAirtableBaseRequest
+	if err := h.parseBody(c, &req); err != nil {
+		return h.invalidBody(c, err)
+	}
+
+	strict := c.QueryBool("strict")
+	validation := services.ValidateUpdateAirtableBase(&req)
+	if validation.Rejected(strict) {
+		return h.validationFailed(c, validation, strict)
+	}
+
+	base, err := h.services.AirtableBase.UpdateBase(h.requestContext(c), baseID, userID, &req)
+	if err != nil {
+		return h.handleError(c, err)
+	}
+
+	if req.IsEmpty() {
+		return h.sendUnchanged(c, base)
+	}
+
+	return h.sendWithWarnings(c, fiber.StatusOK, base, validation.Warnings)
+}
+
+// SetWorkspaceSync pauses or resumes syncing for every base in a workspace
+func (h *Handlers) SetWorkspaceSync(c *fiber.Ctx) error {
+	workspaceID := c.Params("workspace_id")
+	userID := h.getUserID(c)
+
+	if userID == "" {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Missing authentication",
+		})
+	}
+
+	var req models.SetWorkspaceSyncRequest
+	if err := h.parseBody(c, &req); err != nil {
+		return h.invalidBody(c, err)
+	}
+	if req.Enabled == nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Request body must set enabled",
+		})
+	}
+
+	updated, err := h.services.AirtableBase.SetWorkspaceSync(h.requestContext(c), workspaceID, userID, *req.Enabled)
+	if err != nil {
+		return h.handleError(c, err)
+	}
+
+	return c.JSON(&models.SetWorkspaceSyncResponse{
+		SyncEnabled:  *req.Enabled,
+		BasesUpdated: updated,
+	})
+}
+
+// DisconnectAirtableBase disconnects an Airtable base
+func (h *Handlers) DisconnectAirtableBase(c *fiber.Ctx) error {
+	baseID := c.Params("id")
+	userID := h.getUserID(c)
+
+	if userID == "" {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Missing authentication",
+		})
+	}
+
+	if err := h.services.AirtableBase.DisconnectBase(h.requestContext(c), baseID, userID, c.QueryBool("force")); err != nil {
+		return h.handleError(c, err)
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// ListAirtableBases lists Airtable bases
+func (h *Handlers) ListAirtableBases(c *fiber.Ctx) error {
+	userID := h.getUserID(c)
+
+	if userID == "" {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Missing authentication",
+		})
+	}
+
+	filter := &models.AirtableBaseFilter{}
+
+	// Parse query parameters
+	if err := c.QueryParser(filter); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid query parameters",
+		})
+	}
+
+	response, err := h.services.AirtableBase.ListBases(h.requestContext(c), filter, userID)
+	if err != nil {
+		return h.handleError(c, err)
+	}
+
+	if filter.CountOnly {
+		return h.sendCount(c, response.Total)
+	}
+
+	response.Links = h.pageLinks(c, response.Page, response.TotalPages)
+
+	return h.sendListFields(c, response, "bases", nil, baseRestrictions("bases")...)
+}
+
+// Member Handlers
+
+// AddWorkspaceMember adds a member to a workspace
+func (h *Handlers) AddWorkspaceMember(c *fiber.Ctx) error {
+	workspaceID := c.Params("workspace_id")
+	userID := h.getUserID(c)
+
+	if userID == "" {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Missing authentication",
+		})
+	}
+
+	var req models.AddWorkspaceMemberRequest
+	if err := h.parseBody(c, &req); err != nil {
+		return h.invalidBody(c, err)
+	}
+
+	if validation := services.ValidateRequest(&req); validation.Rejected(false) {
+		return h.validationFailed(c, validation, false)
+	}
+
+	member, err := h.services.Member.AddMember(h.requestContext(c), workspaceID, userID, &req)
+	if err != nil {
+		return h.handleError(c, err)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(member)
+}
+
+// BatchAddWorkspaceMembers adds several members, reporting each item's outcome with 207 when
+// any of them fails
+func (h *Handlers) BatchAddWorkspaceMembers(c *fiber.Ctx) error {
+	workspaceID := c.Params("workspace_id")
+	userID := h.getUserID(c)
+
+	if userID == "" {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Missing authentication",
+		})
+	}
+
+	var req models.BatchAddWorkspaceMembersRequest
+	if err := h.parseBody(c, &req); err != nil {
+		return h.invalidBody(c, err)
+	}
+
+	if err := validateBatchSize(len(req.Members)); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "members " + err.Error(),
+		})
+	}
+
+	ctx := h.requestContext(c)
+	result := models.NewBatchResult[models.WorkspaceMember](len(req.Members))
+	for i := range req.Members {
+		if validation := services.ValidateRequest(&req.Members[i]); validation.Rejected(false) {
+			result.Invalid(i, validation.Problems(false), validation.FieldErrors)
+			continue
+		}
+
+		member, err := h.services.Member.AddMember(ctx, workspaceID, userID, &req.Members[i])
+		if err != nil {
+			status, message := h.errorResponse(c, err)
+			result.Fail(i, status, message, nil)
+			continue
+		}
+		result.Succeed(i, fiber.StatusCreated, member, nil)
+	}
+
+	return c.Status(result.Status(fiber.StatusCreated)).JSON(result)
+}
+
+// UpdateWorkspaceMemberRole updates a member's role
+func (h *Handlers) UpdateWorkspaceMemberRole(c *fiber.Ctx) error {
+	workspaceID := c.Params("workspace_id")
+	memberUserID := c.Params("user_id")
+	userID := h.getUserID(c)
+
+	if userID == "" {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Missing authentication",
+		})
+	}
+
+	var req models.UpdateWorkspaceMemberRequest
+	if err := h.parseBody(c, &req); err != nil {
+		return h.invalidBody(c, err)
+	}
+
+	if validation := services.ValidateRequest(&req); validation.Rejected(false) {
+		return h.validationFailed(c, validation, false)
+	}
+
+	member, err := h.services.Member.UpdateMemberRole(h.requestContext(c), workspaceID, memberUserID, userID, &req)
+	if err != nil {
+		return h.handleError(c, err)
+	}
+
+	return c.JSON(member)
+}
+
+// SetWorkspacePrimaryOwner makes another owner the workspace's primary owner
+func (h *Handlers) SetWorkspacePrimaryOwner(c *fiber.Ctx) error {
+	workspaceID := c.Params("workspace_id")
+	userID := h.getUserID(c)
+
+	if userID == "" {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Missing authentication",
+		})
+	}
+
+	var req models.SetPrimaryOwnerRequest
+	if err := h.parseBody(c, &req); err != nil {
+		return h.invalidBody(c, err)
+	}
+
+	if validation := services.ValidateRequest(&req); validation.Rejected(false) {
+		return h.validationFailed(c, validation, false)
+	}
+
+	member, err := h.services.Member.SetPrimaryOwner(h.requestContext(c), workspaceID, strings.TrimSpace(req.UserID), userID)
+	if err != nil {
+		return h.handleError(c, err)
+	}
+
+	return c.JSON(member)
+}
+
+// RemoveWorkspaceMember removes a member from a workspace
+func (h *Handlers) RemoveWorkspaceMember(c *fiber.Ctx) error {
+	workspaceID := c.Params("workspace_id")
+	memberUserID := c.Params("user_id")
+	userID := h.getUserID(c)
+
+	if userID == "" {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Missing authentication",
+		})
+	}
+
+	// Optionally transfer the member's projects and bases before removing them
+	if reassignTo := c.Query("reassign_to"); reassignTo != "" {
+		if err := h.services.Member.RemoveMemberAndReassign(h.requestContext(c), workspaceID, memberUserID, reassignTo, userID); err != nil {
+			return h.handleError(c, err)
+		}
+		return c.SendStatus(fiber.StatusNoContent)
+	}
+
+	if err := h.services.Member.RemoveMember(h.requestContext(c), workspaceID, memberUserID, userID); err != nil {
+		return h.handleError(c, err)
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// ListWorkspaceMembers lists workspace members
+func (h *Handlers) ListWorkspaceMembers(c *fiber.Ctx) error {
+	workspaceID := c.Params("workspace_id")
+	userID := h.getUserID(c)
+
+	if userID == "" {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Missing authentication",
+		})
+	}
+
+	page, _ := strconv.Atoi(c.Query("page", "1"))
+	pageSize, _ := strconv.Atoi(c.Query("page_size", "20"))
+
+	response, err := h.services.Member.ListMembers(h.requestContext(c), workspaceID, userID, page, pageSize, c.Query("search"))
+	if err != nil {
+		return h.handleError(c, err)
+	}
+
+	response.Links = h.pageLinks(c, response.Page, response.TotalPages)
+
+	return h.sendListFields(c, response, "members", nil, restrictedList("members", memberVisibility))
+}
+
+// memberExportColumns is the header row of a member roster export
+var memberExportColumns = []string{"user_id", "role", "joined_at"}
+
+// ExportWorkspaceMembers writes the workspace's full member roster as a CSV attachment
+func (h *Handlers) ExportWorkspaceMembers(c *fiber.Ctx) error {
+	workspaceID := c.Params("workspace_id")
+	userID := h.getUserID(c)
+
+	if userID == "" {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Missing authentication",
+		})
+	}
+
+	if format := c.Query("format", "csv"); format != "csv" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Unsupported export format; use csv",
+		})
+	}
+
+	// Rows are written batch by batch as the roster is scanned
+	writer := csv.NewWriter(c.Response().BodyWriter())
+	started := false
+	start := func() error {
+		started = true
+		c.Set(fiber.HeaderContentType, "text/csv; charset=utf-8")
+		c.Set(fiber.HeaderContentDisposition, fmt.Sprintf(`attachment; filename="workspace-%s-members.csv"`, workspaceID))
+		return writer.Write(memberExportColumns)
+	}
+
+	err := h.services.Member.ExportMembers(h.readContext(c), workspaceID, userID, func(batch []*models.WorkspaceMember) error {
+		if !started {
+			if err := start(); err != nil {
+				return err
+			}
+		}
+		for _, member := range batch {
+			if err := writer.Write([]string{member.UserID, string(member.Role), member.JoinedAt.UTC().Format(time.RFC3339)}); err != nil {
+				return err
+			}
+		}
+		writer.Flush()
+		return writer.Error()
+	})
+	if err == nil && !started {
+		err = start()
+	}
+	if err == nil {
+		writer.Flush()
+		err = writer.Error()
+	}
+	if err != nil {
+		// Nothing has been sent yet, so a partial roster is replaced by the error
+		c.Response().ResetBody()
+		c.Response().Header.Del(fiber.HeaderContentDisposition)
+		return h.handleError(c, err)
+	}
+
+	return nil
+}
+
+// ListWorkspaceMemberHistory lists a workspace's former members with join and leave times
+func (h *Handlers) ListWorkspaceMemberHistory(c *fiber.Ctx) error {
+	workspaceID := c.Params("workspace_id")
+	userID := h.getUserID(c)
+
+	if userID == "" {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Missing authentication",
+		})
+	}
+
+	page, _ := strconv.Atoi(c.Query("page", "1"))
+	pageSize, _ := strconv.Atoi(c.Query("page_size", "20"))
+
+	response, err := h.services.Member.ListMemberHistory(h.readContext(c), workspaceID, userID, page, pageSize)
+	if err != nil {
+		return h.handleError(c, err)
+	}
+
+	response.Links = h.pageLinks(c, response.Page, response.TotalPages)
+
+	return c.JSON(response)
+}
+
+// GetWorkspaceMemberImpact previews what a member created before they are removed
+func (h *Handlers) GetWorkspaceMemberImpact(c *fiber.Ctx) error {
+	workspaceID := c.Params("workspace_id")
+	memberUserID := c.Params("user_id")
+	userID := h.getUserID(c)
+
+	if userID == "" {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Missing authentication",
+		})
+	}
+
+	impact, err := h.services.Member.GetMemberImpact(h.requestContext(c), workspaceID, memberUserID, userID)
+	if err != nil {
+		return h.handleError(c, err)
+	}
+
+	return c.JSON(impact)
+}
+
+// Service Account Handlers
+
+// CreateServiceAccount creates a workspace service account; its token is only returned here
+func (h *Handlers) CreateServiceAccount(c *fiber.Ctx) error {
+	workspaceID := c.Params("workspace_id")
+	userID := h.getUserID(c)
+
+	if userID == "" {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Missing authentication",
+		})
+	}
+
+	var req models.CreateServiceAccountRequest
+	if err := h.parseBody(c, &req); err != nil {
+		return h.invalidBody(c, err)
+	}
+
+	if validation := services.ValidateRequest(&req); validation.Rejected(false) {
+		return h.validationFailed(c, validation, false)
+	}
+
+	account, err := h.services.ServiceAccount.CreateServiceAccount(h.requestContext(c), workspaceID, userID, &req)
+	if err != nil {
+		return h.handleError(c, err)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(account)
+}
+
+// ListServiceAccounts lists a workspace's service accounts
+func (h *Handlers) ListServiceAccounts(c *fiber.Ctx) error {
+	workspaceID := c.Params("workspace_id")
+	userID := h.getUserID(c)
+
+	if userID == "" {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Missing authentication",
+		})
+	}
+
+	accounts, err := h.services.ServiceAccount.ListServiceAccounts(h.readContext(c), workspaceID, userID)
+	if err != nil {
 		return h.handleError(c, err)
 	}
 
 	return c.JSON(fiber.Map{
+		"service_accounts": accounts,
+		"total":            len(accounts),
+	})
+}
+
+// RevokeServiceAccount revokes a workspace service account
+func (h *Handlers) RevokeServiceAccount(c *fiber.Ctx) error {
+	workspaceID := c.Params("workspace_id")
+	accountID := c.Params("id")
+	userID := h.getUserID(c)
+
+	if userID == "" {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Missing authentication",
+		})
+	}
+
+	if err := h.services.ServiceAccount.RevokeServiceAccount(h.requestContext(c), workspaceID, accountID, userID); err != nil {
+		return h.handleError(c, err)
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// GetUserWorkspaces retrieves all workspaces for a user
+func (h *Handlers) GetUserWorkspaces(c *fiber.Ctx) error {
+	userID := h.getUserID(c)
+
+	if userID == "" {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Missing authentication",
+		})
+	}
+
+	workspaces, err := h.services.Member.GetUserWorkspaces(h.requestContext(c), userID, c.Query("sort_by"))
+	if err != nil {
+		return h.handleError(c, err)
+	}
+
+	shaped, err := h.restrictFields(c, fiber.Map{
 		"workspaces": workspaces,
 		"total":      len(workspaces),
+	}, []fieldRestriction{restrictedList("workspaces", workspaceVisibility)})
+	if err != nil {
+		return h.handleError(c, err)
+	}
+
+	return c.JSON(shaped)
+}
+
+// GetUserActivity lists recent activity across the caller's workspaces
+func (h *Handlers) GetUserActivity(c *fiber.Ctx) error {
+	userID := h.getUserID(c)
+
+	if userID == "" {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Missing authentication",
+		})
+	}
+
+	page, _ := strconv.Atoi(c.Query("page", "1"))
+	pageSize, _ := strconv.Atoi(c.Query("page_size", "20"))
+
+	response, err := h.services.Audit.ListUserActivity(h.readContext(c), userID, page, pageSize)
+	if err != nil {
+		return h.handleError(c, err)
+	}
+
+	response.Links = h.pageLinks(c, response.Page, response.TotalPages)
+
+	return c.JSON(response)
+}
+
+// RebuildUserWorkspaceCache recomputes a user's cached workspace list. Users span tenants,
+// so only service principals allowed on every tenant may call it.
+func (h *Handlers) RebuildUserWorkspaceCache(c *fiber.Ctx) error {
+	userID := c.Params("user_id")
+	principal, _ := c.Locals("service_principal").(*config.ServicePrincipal)
+
+	if principal == nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Missing service authentication",
+		})
+	}
+
+	if !principal.AllowsTenant("*") {
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"error": "Unauthorized",
+		})
+	}
+
+	workspaceIDs, err := h.services.Member.RebuildUserWorkspaceCache(h.requestContext(c), userID)
+	if err != nil {
+		return h.handleError(c, err)
+	}
+
+	return c.JSON(fiber.Map{
+		"workspace_ids": workspaceIDs,
+		"total":         len(workspaceIDs),
 	})
 }
 
+// GetUserAirtableBases lists the Airtable bases the user can access across workspaces
+func (h *Handlers) GetUserAirtableBases(c *fiber.Ctx) error {
+	userID := h.getUserID(c)
+
+	if userID == "" {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Missing authentication",
+		})
+	}
+
+	filter := &models.AirtableBaseFilter{}
+
+	// Parse query parameters
+	if err := c.QueryParser(filter); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid query parameters",
+		})
+	}
+
+	response, err := h.services.AirtableBase.ListUserBases(h.requestContext(c), userID, filter)
+	if err != nil {
+		return h.handleError(c, err)
+	}
+
+	if filter.CountOnly {
+		return h.sendCount(c, response.Total)
+	}
+
+	response.Links = h.pageLinks(c, response.Page, response.TotalPages)
+
+	return h.sendListFields(c, response, "bases", nil, baseRestrictions("bases")...)
+}
+
 // Audit Log Handlers
 
 // GetAuditLogs retrieves audit logs
@@ -602,10 +1842,124 @@ func (h *Handlers) GetAuditLogs(c *fiber.Ctx) error {
 		})
 	}
 
-	response, err := h.services.Audit.GetAuditLogs(c.Context(), filter, userID)
+	response, err := h.services.Audit.GetAuditLogs(h.requestContext(c), filter, userID)
 	if err != nil {
 		return h.handleError(c, err)
 	}
 
-	return c.JSON(response)
-}
\ No newline at end of file
+	if filter.CountOnly {
+		return h.sendCount(c, response.Total)
+	}
+
+	// Cursor pagination already returns next_cursor; page links would mix the two schemes
+	if filter.Cursor == "" {
+		response.Links = h.pageLinks(c, response.Page, response.TotalPages)
+	}
+
+	return h.sendListFields(c, response, "logs", nil, restrictedList("logs", auditVisibility))
+}
+
+// GetAuditLogFacets lists the distinct actions and users found in a workspace's audit log
+func (h *Handlers) GetAuditLogFacets(c *fiber.Ctx) error {
+	workspaceID := c.Params("id")
+	userID := h.getUserID(c)
+
+	if userID == "" {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Missing authentication",
+		})
+	}
+
+	facets, err := h.services.Audit.GetAuditLogFacets(h.readContext(c), workspaceID, userID)
+	if err != nil {
+		return h.handleError(c, err)
+	}
+
+	return c.JSON(facets)
+}
+
+// GetWorkspaceHistory lists the audit entries recorded for a workspace
+func (h *Handlers) GetWorkspaceHistory(c *fiber.Ctx) error {
+	return h.resourceHistory(c, models.AuditResourceWorkspace)
+}
+
+// GetProjectHistory lists the audit entries recorded for a project
+func (h *Handlers) GetProjectHistory(c *fiber.Ctx) error {
+	return h.resourceHistory(c, models.AuditResourceProject)
+}
+
+// GetAirtableBaseHistory lists the audit entries recorded for an Airtable base
+func (h *Handlers) GetAirtableBaseHistory(c *fiber.Ctx) error {
+	return h.resourceHistory(c, models.AuditResourceAirtableBase)
+}
+
+// resourceHistory serves the chronological audit timeline of the resource named by :id
+func (h *Handlers) resourceHistory(c *fiber.Ctx, resourceType string) error {
+	resourceID := c.Params("id")
+	userID := h.getUserID(c)
+
+	if userID == "" {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Missing authentication",
+		})
+	}
+
+	filter := &models.AuditLogFilter{}
+	if err := c.QueryParser(filter); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid query parameters",
+		})
+	}
+
+	response, err := h.services.Audit.GetResourceHistory(h.requestContext(c), resourceType, resourceID, userID, filter)
+	if err != nil {
+		return h.handleError(c, err)
+	}
+
+	if filter.Cursor == "" {
+		response.Links = h.pageLinks(c, response.Page, response.TotalPages)
+	}
+
+	return h.sendListFields(c, response, "logs", nil, restrictedList("logs", auditVisibility))
+}
+
+// IngestAuditLogs accepts a batch of audit entries sent by another service for writing
+func (h *Handlers) IngestAuditLogs(c *fiber.Ctx) error {
+	workspaceID := c.Params("id")
+	principal, _ := c.Locals("service_principal").(*config.ServicePrincipal)
+
+	if principal == nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Missing service authentication",
+		})
+	}
+
+	var req models.IngestAuditLogsRequest
+	if err := h.parseBody(c, &req); err != nil {
+		return h.invalidBody(c, err)
+	}
+
+	validation := services.ValidateIngestAuditLogs(&req, h.services.Audit.Vocabulary())
+	if validation.Rejected(false) {
+		return h.validationFailed(c, validation, false)
+	}
+
+	accepted, err := h.services.Audit.IngestLogs(h.requestContext(c), workspaceID, principal, req.Entries)
+	if err != nil {
+		return h.handleError(c, err)
+	}
+
+	return c.Status(fiber.StatusAccepted).JSON(fiber.Map{
+		"accepted": accepted,
+	})
+}
+
+// GetAuditVocabulary lists the known audit actions and resource types
+func (h *Handlers) GetAuditVocabulary(c *fiber.Ctx) error {
+	vocabulary := h.services.Audit.Vocabulary()
+
+	return c.JSON(fiber.Map{
+		"actions":        vocabulary.Actions(),
+		"resource_types": vocabulary.ResourceTypes(),
+	})
+}
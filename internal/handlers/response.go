@@ -0,0 +1,184 @@
+package handlers
+
+import (
+	"fmt"
+	"net/url"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/models"
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/services"
+	"github.com/Reg-Kris/pyairtable-workspace-service/pkg/response"
+)
+
+// sendFields writes an entity as JSON, trimmed to the fields requested via ?fields= and to the
+// fields the restrictions let the caller's role see
+func (h *Handlers) sendFields(c *fiber.Ctx, v interface{}, restrictions ...fieldRestriction) error {
+	fields := response.ParseFields(c.Query("fields"))
+	if h.config.API.StrictFields {
+		if err := response.CheckFields(v, fields); err != nil {
+			return h.fieldsError(c, err)
+		}
+	}
+
+	// Restrict first: selection may drop the field that names the object's workspace
+	shaped, err := h.restrictFields(c, v, restrictions)
+	if err != nil {
+		return h.handleError(c, err)
+	}
+
+	shaped, err = response.SelectFields(shaped, fields, false)
+	if err != nil {
+		return h.handleError(c, err)
+	}
+
+	// Single-entity reads never return deleted rows
+	shaped, err = response.HideDeletedAt(shaped, "", nil)
+	if err != nil {
+		return h.handleError(c, err)
+	}
+
+	return c.JSON(shaped)
+}
+
+// sendListFields writes a list response as JSON, trimming each item under listKey to ?fields=.
+// deleted_at is only kept for items whose id is marked in visibleDeletions.
+func (h *Handlers) sendListFields(c *fiber.Ctx, v interface{}, listKey string, visibleDeletions map[string]bool, restrictions ...fieldRestriction) error {
+	shaped, err := h.shapeListFields(c, v, listKey, visibleDeletions, restrictions)
+	if err != nil {
+		return h.fieldsError(c, err)
+	}
+
+	return c.JSON(shaped)
+}
+
+// sendTombstoneList writes a list response like sendListFields, but replaces the deleted items
+// whose id is not marked in visibleDeletions with bare tombstones rather than listing them
+func (h *Handlers) sendTombstoneList(c *fiber.Ctx, v interface{}, listKey string, visibleDeletions map[string]bool, restrictions ...fieldRestriction) error {
+	shaped, err := h.shapeListFields(c, v, listKey, visibleDeletions, restrictions)
+	if err != nil {
+		return h.fieldsError(c, err)
+	}
+
+	hidden := make(map[string]bool, len(visibleDeletions))
+	for id, visible := range visibleDeletions {
+		hidden[id] = !visible
+	}
+
+	shaped, err = response.Tombstones(shaped, listKey, hidden)
+	if err != nil {
+		return h.handleError(c, err)
+	}
+
+	return c.JSON(shaped)
+}
+
+// shapeListFields applies ?fields=, the role restrictions and deletion visibility to a list
+// response
+func (h *Handlers) shapeListFields(c *fiber.Ctx, v interface{}, listKey string, visibleDeletions map[string]bool, restrictions []fieldRestriction) (interface{}, error) {
+	fields := response.ParseFields(c.Query("fields"))
+	if h.config.API.StrictFields {
+		if err := response.CheckListFields(v, listKey, fields); err != nil {
+			return nil, err
+		}
+	}
+
+	// Restrict first: selection may drop the field that names each item's workspace
+	shaped, err := h.restrictFields(c, v, restrictions)
+	if err != nil {
+		return nil, err
+	}
+
+	shaped, err = response.SelectListFields(shaped, listKey, fields, false)
+	if err != nil {
+		return nil, err
+	}
+
+	return response.HideDeletedAt(shaped, listKey, visibleDeletions)
+}
+
+// sendCount answers a count_only list request with the total alone
+func (h *Handlers) sendCount(c *fiber.Ctx, total int64) error {
+	return c.JSON(fiber.Map{
+		"total": total,
+	})
+}
+
+// visibleDeletions reports, for each soft-deleted item id mapped to its workspace, whether the
+// caller is an admin of that workspace and may therefore see its deletion metadata
+func (h *Handlers) visibleDeletions(c *fiber.Ctx, userID string, deleted map[string]string) map[string]bool {
+	visible := make(map[string]bool, len(deleted))
+	isAdmin := make(map[string]bool)
+
+	for id, workspaceID := range deleted {
+		admin, checked := isAdmin[workspaceID]
+		if !checked {
+			admin = h.services.Workspace.CheckUserAccess(h.requestContext(c), workspaceID, userID, models.WorkspaceRoleAdmin) == nil
+			isAdmin[workspaceID] = admin
+		}
+		visible[id] = admin
+	}
+	return visible
+}
+
+// pageLinks builds pagination links for the current request URL
+func (h *Handlers) pageLinks(c *fiber.Ctx, page, totalPages int) *models.PaginationLinks {
+	query, _ := url.ParseQuery(string(c.Request().URI().QueryString()))
+	links := response.PageLinks(c.BaseURL()+c.Path(), query, page, totalPages)
+	return &models.PaginationLinks{First: links.First, Prev: links.Prev, Next: links.Next, Last: links.Last}
+}
+
+// fieldsError maps field selection failures to a response
+func (h *Handlers) fieldsError(c *fiber.Ctx, err error) error {
+	if unknown, ok := err.(*response.UnknownFieldError); ok {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Unknown field: " + unknown.Field,
+		})
+	}
+	return h.handleError(c, err)
+}
+
+// maxBatchItems bounds how many items a single batch request may carry
+const maxBatchItems = 100
+
+// validateBatchSize rejects empty and oversized batches
+func validateBatchSize(n int) error {
+	if n == 0 || n > maxBatchItems {
+		return fmt.Errorf("must contain between 1 and %d items", maxBatchItems)
+	}
+	return nil
+}
+
+// validationFailed writes a 400 listing the problems that caused the request to be rejected,
+// both as messages in details and per field in errors
+func (h *Handlers) validationFailed(c *fiber.Ctx, result *services.ValidationResult, strict bool) error {
+	fieldErrors := result.FieldErrors
+	if fieldErrors == nil {
+		fieldErrors = []services.FieldError{}
+	}
+	return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+		"error":   "Validation failed",
+		"details": result.Problems(strict),
+		"errors":  fieldErrors,
+	})
+}
+
+// sendUnchanged writes v as the 200 response of an update that set no field
+func (h *Handlers) sendUnchanged(c *fiber.Ctx, v interface{}) error {
+	body, err := response.Unchanged(v)
+	if err != nil {
+		return h.handleError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(body)
+}
+
+// sendWithWarnings writes v as JSON with any non-blocking validation warnings attached
+func (h *Handlers) sendWithWarnings(c *fiber.Ctx, status int, v interface{}, warnings []string) error {
+	body, err := response.WithWarnings(v, warnings)
+	if err != nil {
+		return h.handleError(c, err)
+	}
+
+	return c.Status(status).JSON(body)
+}
@@ -0,0 +1,140 @@
+package handlers
+
+import (
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/utils"
+
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/middleware"
+)
+
+// maintenancePath is the admin maintenance toggle, which stays writable in read-only mode so
+// the mode can be lifted
+const maintenancePath = "/api/v1/admin/maintenance"
+
+// RegisterRoutes mounts all API routes on the given router. Routes match with or without a
+// trailing slash, and case-insensitively as long as the app keeps Fiber's default CaseSensitive
+// off; path parameters keep the case they were sent in. Callers authenticate users by mounting
+// middleware.JWT ahead of these routes; service account tokens are authenticated here.
+func (h *Handlers) RegisterRoutes(router fiber.Router) {
+	router.Use(middleware.TrailingSlash(), middleware.QueryLimits(h.config.API), middleware.PageDepth(h.config.API))
+
+	router.Get("/health", h.Health)
+	router.Get("/ready", h.Ready)
+
+	api := router.Group("/api/v1", middleware.ServiceAccount(h.services.ServiceAccount), middleware.RateLimit(h.config.RateLimit), middleware.Impersonation(h.config.Impersonation), middleware.Operation(),
+		middleware.Maintenance(h.config.Maintenance, h.services.Maintenance, maintenancePath))
+
+	// ids guards the workspace, project, base and service account IDs routes take from the path
+	ids := middleware.UUIDParams("id", "workspace_id", "project_id")
+	// writes caps the concurrent changes to one workspace across all its write routes
+	writes := middleware.WorkspaceConcurrency(h.config.Concurrency, h.writeWorkspace)
+	// destructive keeps impersonated callers off routes that delete or hand off data
+	destructive := middleware.Destructive(h.config.Impersonation)
+	// users keeps service accounts, which act in one workspace only, off user and tenant routes
+	users := middleware.UserOnly()
+
+	// Workspaces
+	api.Post("/workspaces", users, h.CreateWorkspace)
+	api.Get("/workspaces", h.ListWorkspaces)
+	api.Get("/workspaces/stats", users, h.GetWorkspaceStats)
+	api.Get("/workspaces/stats/trends", users, h.GetWorkspaceTrends)
+	api.Get("/workspaces/name-available", users, h.CheckWorkspaceNameAvailable)
+	api.Post("/workspaces/resolve", h.ResolveWorkspaceNames)
+	api.Get("/workspaces/:id", ids, h.GetWorkspace)
+	api.Get("/workspaces/:id/history", ids, h.GetWorkspaceHistory)
+	api.Get("/workspaces/:id/quota", ids, h.GetWorkspaceQuota)
+	api.Put("/workspaces/:id", ids, writes, h.UpdateWorkspace)
+	api.Get("/workspaces/:id/notification-settings", ids, h.GetNotificationSettings)
+	api.Put("/workspaces/:id/notification-settings", ids, writes, h.UpdateNotificationSettings)
+	api.Put("/workspaces/:id/tenant", ids, destructive, writes, h.ChangeWorkspaceTenant)
+	api.Post("/workspaces/:id/scheduled-deletion", ids, destructive, writes, h.ScheduleWorkspaceDeletion)
+	api.Delete("/workspaces/:id/scheduled-deletion", ids, writes, h.CancelScheduledWorkspaceDeletion)
+	api.Delete("/workspaces/:id", ids, destructive, writes, h.DeleteWorkspace)
+
+	// Members
+	api.Post("/workspaces/:workspace_id/members", ids, writes, h.AddWorkspaceMember)
+	api.Post("/workspaces/:workspace_id/members/batch", ids, writes, h.BatchAddWorkspaceMembers)
+	api.Get("/workspaces/:workspace_id/members", ids, h.ListWorkspaceMembers)
+	api.Get("/workspaces/:workspace_id/members/export", ids, h.ExportWorkspaceMembers)
+	api.Get("/workspaces/:workspace_id/members/history", ids, h.ListWorkspaceMemberHistory)
+	api.Put("/workspaces/:workspace_id/members/:user_id", ids, writes, h.UpdateWorkspaceMemberRole)
+	api.Delete("/workspaces/:workspace_id/members/:user_id", ids, destructive, writes, h.RemoveWorkspaceMember)
+	api.Get("/workspaces/:workspace_id/members/:user_id/impact", ids, h.GetWorkspaceMemberImpact)
+	api.Put("/workspaces/:workspace_id/primary-owner", ids, writes, h.SetWorkspacePrimaryOwner)
+
+	// Service accounts
+	api.Post("/workspaces/:workspace_id/service-accounts", ids, writes, h.CreateServiceAccount)
+	api.Get("/workspaces/:workspace_id/service-accounts", ids, h.ListServiceAccounts)
+	api.Delete("/workspaces/:workspace_id/service-accounts/:id", ids, destructive, writes, h.RevokeServiceAccount)
+
+	// Projects
+	api.Post("/workspaces/:workspace_id/projects", ids, writes, h.CreateProject)
+	api.Post("/workspaces/:workspace_id/projects/batch", ids, writes, h.BatchCreateProjects)
+	api.Get("/workspaces/:workspace_id/projects/name-available", ids, h.CheckProjectNameAvailable)
+	api.Post("/workspaces/:workspace_id/projects/bulk-delete", ids, destructive, writes, h.BulkDeleteProjects)
+	api.Post("/workspaces/:workspace_id/projects/tags", ids, writes, h.TagProjects)
+	api.Get("/projects", h.ListProjects)
+	api.Get("/projects/:id", ids, h.GetProject)
+	api.Get("/projects/:id/history", ids, h.GetProjectHistory)
+	api.Get("/projects/:id/access", ids, h.GetProjectAccess)
+	api.Put("/projects/:id", ids, writes, h.UpdateProject)
+	api.Put("/projects/:id/owner", ids, writes, h.SetProjectOwner)
+	api.Delete("/projects/:id", ids, destructive, writes, h.DeleteProject)
+
+	// Airtable bases
+	api.Post("/projects/:project_id/airtable-bases", ids, writes, h.ConnectAirtableBase)
+	api.Post("/projects/:project_id/airtable-bases/batch", ids, writes, h.BatchConnectAirtableBases)
+	api.Get("/airtable-bases", h.ListAirtableBases)
+	api.Get("/airtable-bases/lookup", h.LookupAirtableBases)
+	api.Get("/airtable-bases/:id", ids, h.GetAirtableBase)
+	api.Get("/airtable-bases/:id/history", ids, h.GetAirtableBaseHistory)
+	api.Put("/airtable-bases/:id", ids, writes, h.UpdateAirtableBase)
+	api.Delete("/airtable-bases/:id", ids, destructive, writes, h.DisconnectAirtableBase)
+	api.Post("/workspaces/:workspace_id/sync", ids, writes, h.SetWorkspaceSync)
+
+	// Tenants
+	api.Get("/tenants/:id/quota", users, h.GetTenantQuota)
+
+	// Users
+	api.Get("/users/me/workspaces", users, h.GetUserWorkspaces)
+	api.Get("/users/me/airtable-bases", users, h.GetUserAirtableBases)
+	api.Get("/users/me/activity", users, h.GetUserActivity)
+	api.Post("/users/:user_id/workspaces/cache/rebuild", middleware.ServiceAuth(h.config.Services), h.RebuildUserWorkspaceCache)
+
+	// Platform maintenance
+	api.Post("/admin/tenants/:id/stats/refresh", users, h.RefreshTenantStats)
+	api.Get("/admin/maintenance", users, h.GetMaintenanceMode)
+	api.Put("/admin/maintenance", users, h.SetMaintenanceMode)
+	api.Get("/admin/config", users, h.GetEffectiveConfig)
+
+	// Audit logs
+	api.Get("/audit-logs", h.GetAuditLogs)
+	api.Get("/audit-logs/actions", h.GetAuditVocabulary)
+	api.Get("/workspaces/:id/audit-logs/facets", ids, h.GetAuditLogFacets)
+	api.Post("/workspaces/:id/audit-logs", middleware.ServiceAuth(h.config.Services), ids, h.IngestAuditLogs)
+}
+
+// writeWorkspace resolves the workspace a write route changes, for the concurrency cap: from
+// the path on workspace routes, or through the project or Airtable base the path names.
+// Resources that fail to resolve are not capped and are left for the handler to reject.
+func (h *Handlers) writeWorkspace(c *fiber.Ctx) string {
+	route := c.Route().Path
+	switch {
+	case c.Params("workspace_id") != "":
+		return utils.CopyString(c.Params("workspace_id"))
+	case strings.Contains(route, "/workspaces/:id"):
+		return utils.CopyString(c.Params("id"))
+	case c.Params("project_id") != "":
+		workspaceID, _ := h.services.Project.ResolveWorkspace(h.requestContext(c), c.Params("project_id"))
+		return workspaceID
+	case strings.Contains(route, "/projects/:id"):
+		workspaceID, _ := h.services.Project.ResolveWorkspace(h.requestContext(c), c.Params("id"))
+		return workspaceID
+	case strings.Contains(route, "/airtable-bases/:id"):
+		workspaceID, _ := h.services.AirtableBase.ResolveWorkspace(h.requestContext(c), c.Params("id"))
+		return workspaceID
+	}
+	return ""
+}
@@ -0,0 +1,106 @@
+package handlers
+
+import (
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/models"
+	"github.com/Reg-Kris/pyairtable-workspace-service/pkg/response"
+)
+
+// Role-gated response fields, by resource. Viewers read workspaces, projects and bases without
+// their settings, which hold integration configuration they have no use for, and bases without
+// their sync errors. Only admins see members' emails and last access, and only owners see who
+// impersonated the user an audit entry names.
+var (
+	workspaceVisibility = response.FieldVisibility{
+		WorkspaceKey: "id",
+		Fields: map[string]string{
+			"settings": string(models.WorkspaceRoleMember),
+		},
+	}
+	projectVisibility = response.FieldVisibility{
+		WorkspaceKey: "workspace_id",
+		Fields: map[string]string{
+			"settings": string(models.WorkspaceRoleMember),
+		},
+	}
+	baseVisibility = response.FieldVisibility{
+		WorkspaceKey: "project.workspace_id",
+		Fields: map[string]string{
+			"settings":        string(models.WorkspaceRoleMember),
+			"last_sync_error": string(models.WorkspaceRoleMember),
+		},
+	}
+	memberVisibility = response.FieldVisibility{
+		WorkspaceKey: "workspace_id",
+		Fields: map[string]string{
+			"email":            string(models.WorkspaceRoleAdmin),
+			"last_accessed_at": string(models.WorkspaceRoleAdmin),
+		},
+	}
+	auditVisibility = response.FieldVisibility{
+		WorkspaceKey: "workspace_id",
+		Fields: map[string]string{
+			"impersonated_by": string(models.WorkspaceRoleOwner),
+		},
+	}
+)
+
+// baseRestrictions restrict a base, or the bases listed under listKey, along with the project
+// and workspace embedded in each
+func baseRestrictions(listKey string) []fieldRestriction {
+	prefix := ""
+	if listKey != "" {
+		prefix = listKey + "."
+	}
+	return []fieldRestriction{
+		restrictedList(listKey, baseVisibility),
+		restrictedList(prefix+"project", projectVisibility),
+		restrictedList(prefix+"project.workspace", workspaceVisibility),
+	}
+}
+
+// fieldRestriction applies a resource's visibility to the response object, or to the list at
+// listKey when it is set
+type fieldRestriction struct {
+	listKey    string
+	visibility response.FieldVisibility
+}
+
+// restricted is the restriction for a single entity of a resource
+func restricted(visibility response.FieldVisibility) fieldRestriction {
+	return fieldRestriction{visibility: visibility}
+}
+
+// restrictedList is the restriction for the items of a resource listed under listKey
+func restrictedList(listKey string, visibility response.FieldVisibility) fieldRestriction {
+	return fieldRestriction{listKey: listKey, visibility: visibility}
+}
+
+// restrictFields removes the fields the caller's role doesn't reach. Each workspace role is
+// checked once per response however many objects share it.
+func (h *Handlers) restrictFields(c *fiber.Ctx, v interface{}, restrictions []fieldRestriction) (interface{}, error) {
+	if len(restrictions) == 0 {
+		return v, nil
+	}
+
+	userID := h.getUserID(c)
+	checked := make(map[string]bool)
+	allowed := func(workspaceID, role string) bool {
+		key := workspaceID + "\x00" + role
+		ok, seen := checked[key]
+		if !seen {
+			ok = h.services.Workspace.CheckUserAccess(h.requestContext(c), workspaceID, userID, models.WorkspaceMemberRole(role)) == nil
+			checked[key] = ok
+		}
+		return ok
+	}
+
+	var err error
+	for _, restriction := range restrictions {
+		if v, err = response.RestrictFields(v, restriction.listKey, restriction.visibility, allowed); err != nil {
+			return nil, err
+		}
+	}
+	return v, nil
+}
@@ -1,21 +1,39 @@
 package middleware
 
 import (
+	"bytes"
+	"crypto/sha256"
+	"crypto/subtle"
 	"errors"
 	"fmt"
 	"log/slog"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/limiter"
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
 
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/config"
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/models"
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/services"
 	"github.com/Reg-Kris/pyairtable-workspace-service/pkg/metrics"
 )
 
+const (
+	corsAllowMethods = "GET,POST,PUT,DELETE,OPTIONS"
+	corsAllowHeaders = "Origin,Content-Type,Accept,Authorization,X-Tenant-ID,X-Impersonate-User"
+
+	impersonateHeader = "X-Impersonate-User"
+	requestIDHeader   = "X-Request-ID"
+)
+
 // ErrorHandler provides centralized error handling
 func ErrorHandler(logger *slog.Logger) fiber.ErrorHandler {
-	return func(c fiber.Ctx, err error) error {
+	return func(c *fiber.Ctx, err error) error {
 		code := fiber.StatusInternalServerError
 
 		var e *fiber.Error
@@ -37,9 +55,13 @@ func ErrorHandler(logger *slog.Logger) fiber.ErrorHandler {
 	}
 }
 
-// JWT middleware for authentication
+// JWT middleware for authentication. The token's user_id and tenant_id claims become the
+// "user_id" and "tenant_id" locals, which rate limits and CORS key tenants on.
+// Service account tokens are passed through untouched for ServiceAccount, which RegisterRoutes
+// mounts on the API group, to authenticate; JWT itself is mounted by the caller ahead of the
+// routes.
 func JWT(secret string) fiber.Handler {
-	return func(c fiber.Ctx) error {
+	return func(c *fiber.Ctx) error {
 		authHeader := c.Get("Authorization")
 		if authHeader == "" {
 			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
@@ -49,7 +71,13 @@ func JWT(secret string) fiber.Handler {
 		}
 
 		tokenString := strings.Replace(authHeader, "Bearer ", "", 1)
-		
+
+		// Service account tokens are resolved by the ServiceAccount middleware instead
+		if strings.HasPrefix(strings.TrimSpace(tokenString), models.ServiceAccountTokenPrefix) {
+			return c.Next()
+		}
+
+
 		token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
 			if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
 				return nil, errors.New("unexpected signing method")
@@ -73,15 +101,325 @@ func JWT(secret string) fiber.Handler {
 		}
 
 		c.Locals("userID", claims["user_id"])
+		if userID, ok := claims["user_id"].(string); ok {
+			c.Locals("user_id", userID)
+		}
+		if tenantID, ok := claims["tenant_id"].(string); ok {
+			c.Locals("tenant_id", tenantID)
+		}
 		c.Locals("claims", claims)
 
 		return c.Next()
 	}
 }
 
+// ServiceAccount authenticates requests whose bearer token is a service account token, acting
+// as the account's principal with its tenant. The account is stored in locals under
+// "service_account"; other tokens pass through untouched. JWT passes service account tokens on
+// to it, so it runs after JWT.
+func ServiceAccount(accounts services.ServiceAccountService) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		token := strings.TrimSpace(strings.TrimPrefix(c.Get(fiber.HeaderAuthorization), "Bearer "))
+		if !strings.HasPrefix(token, models.ServiceAccountTokenPrefix) {
+			return c.Next()
+		}
+
+		if accounts == nil {
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+				"error":   true,
+				"message": "Invalid service account token",
+			})
+		}
+
+		account, err := accounts.Authenticate(c.UserContext(), token)
+		if err != nil {
+			if err != services.ErrInvalidToken {
+				return err
+			}
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+				"error":   true,
+				"message": "Invalid service account token",
+			})
+		}
+
+		c.Locals("service_account", account)
+		c.Locals("user_id", account.PrincipalID())
+		c.Locals("tenant_id", account.TenantID)
+
+		return c.Next()
+	}
+}
+
+// UserOnly refuses requests authenticated with a service account token. It guards routes that
+// act for the calling user or across a tenant, which a principal scoped to one workspace has no
+// business reaching.
+func UserOnly() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if _, ok := c.Locals("service_account").(*models.ServiceAccount); ok {
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+				"error":   true,
+				"message": "Not permitted for service accounts",
+			})
+		}
+
+		return c.Next()
+	}
+}
+
+// Impersonation lets callers whose token carries the configured claim act as the user named
+// in X-Impersonate-User. The real caller is kept in locals under "impersonated_by" and the
+// impersonated user replaces "user_id". Routes that destroy data refuse impersonated callers
+// through Destructive.
+func Impersonation(cfg config.ImpersonationConfig) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		target := strings.TrimSpace(c.Get(impersonateHeader))
+		if target == "" {
+			return c.Next()
+		}
+
+		claims, _ := c.Locals("claims").(jwt.MapClaims)
+		privileged, _ := claims[cfg.Claim].(bool)
+		if cfg.Claim == "" || !privileged {
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+				"error":   true,
+				"message": "Impersonation not permitted",
+			})
+		}
+
+		actor, _ := c.Locals("user_id").(string)
+		if actor == "" {
+			actor, _ = claims["user_id"].(string)
+		}
+		if actor == "" {
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+				"error":   true,
+				"message": "Missing authentication",
+			})
+		}
+
+		c.Locals("impersonated_by", actor)
+		c.Locals("user_id", target)
+
+		return c.Next()
+	}
+}
+
+// Destructive marks a route that deletes or hands off data. Impersonated requests to it are
+// refused unless the config allows destructive impersonation. It must run after Impersonation.
+func Destructive(cfg config.ImpersonationConfig) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		actor, _ := c.Locals("impersonated_by").(string)
+		if actor != "" && !cfg.AllowDestructive {
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+				"error":   true,
+				"message": "Impersonation not permitted for destructive operations",
+			})
+		}
+
+		return c.Next()
+	}
+}
+
+// Operation builds the request's services.OperationContext from the authenticated user and
+// tenant in locals, or from the service account that authenticated the request, and stores it
+// under "operation". It must run after authentication and impersonation so the acting user is
+// final.
+func Operation() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		userID, _ := c.Locals("user_id").(string)
+		tenantID, _ := c.Locals("tenant_id").(string)
+
+		// Prefer the ID assigned by the requestid middleware, then the caller's header
+		requestID, _ := c.Locals("requestid").(string)
+		if requestID == "" {
+			requestID = c.Get(requestIDHeader)
+		}
+
+		if account, ok := c.Locals("service_account").(*models.ServiceAccount); ok {
+			c.Locals("operation", services.NewServiceAccountOperationContext(account, requestID))
+			return c.Next()
+		}
+
+		c.Locals("operation", services.NewOperationContext(userID, tenantID, requestID))
+
+		return c.Next()
+	}
+}
+
+// hashedPrincipal pairs a service principal with the SHA-256 of its token
+type hashedPrincipal struct {
+	tokenHash [sha256.Size]byte
+	principal *config.ServicePrincipal
+}
+
+// ServiceAuth middleware for service-to-service endpoints. The caller's principal is
+// stored in locals under "service_principal". Tokens are matched by comparing hashes in
+// constant time against every principal, so timing reveals nothing about the tokens.
+func ServiceAuth(cfg config.ServiceAuthConfig) fiber.Handler {
+	var principals []hashedPrincipal
+	for token, principal := range cfg.GetPrincipals() {
+		principals = append(principals, hashedPrincipal{tokenHash: sha256.Sum256([]byte(token)), principal: principal})
+	}
+
+	return func(c *fiber.Ctx) error {
+		presented := sha256.Sum256([]byte(c.Get("X-Service-Token")))
+		var principal *config.ServicePrincipal
+		for _, candidate := range principals {
+			if subtle.ConstantTimeCompare(presented[:], candidate.tokenHash[:]) == 1 {
+				principal = candidate.principal
+			}
+		}
+		if principal == nil {
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+				"error":   true,
+				"message": "Invalid service token",
+			})
+		}
+
+		c.Locals("service_principal", principal)
+
+		return c.Next()
+	}
+}
+
+// TrailingSlash strips trailing slashes from the path before routing, so /workspaces/ and
+// /workspaces reach the same route whether or not the app enables strict routing
+func TrailingSlash() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		path := c.Path()
+		if trimmed := strings.TrimRight(path, "/"); trimmed != path && trimmed != "" {
+			c.Path(trimmed)
+		}
+		return c.Next()
+	}
+}
+
+// QueryLimits rejects requests whose query string is longer than cfg.MaxQueryLength bytes or
+// carries more than cfg.MaxQueryParams parameters, before any handler parses it. The raw string
+// is measured and its separators counted, so an oversized query is never decoded.
+func QueryLimits(cfg config.APIConfig) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		query := c.Request().URI().QueryString()
+		if len(query) == 0 {
+			return c.Next()
+		}
+
+		if cfg.MaxQueryLength > 0 && len(query) > cfg.MaxQueryLength {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": fmt.Sprintf("Query string exceeds %d bytes", cfg.MaxQueryLength),
+			})
+		}
+
+		if cfg.MaxQueryParams > 0 && bytes.Count(query, []byte("&"))+1 > cfg.MaxQueryParams {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": fmt.Sprintf("Too many query parameters; at most %d are allowed", cfg.MaxQueryParams),
+			})
+		}
+
+		return c.Next()
+	}
+}
+
+// PageDepth rejects list requests whose page starts more than cfg.MaxPageOffset items in, since
+// Postgres still reads every skipped row of a deep OFFSET. The page size is normalized the way
+// the repositories do it. Cursor requests and unparseable pages are left to the handlers.
+func PageDepth(cfg config.APIConfig) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if cfg.MaxPageOffset <= 0 || c.Query("cursor") != "" {
+			return c.Next()
+		}
+
+		page, err := strconv.Atoi(c.Query("page"))
+		if err != nil || page <= 1 {
+			return c.Next()
+		}
+		pageSize, err := strconv.Atoi(c.Query("page_size"))
+		if err != nil || pageSize < 1 {
+			pageSize = 20
+		}
+		if pageSize > 100 {
+			pageSize = 100
+		}
+
+		// Compared by division so a huge page cannot overflow the offset
+		if page-1 > cfg.MaxPageOffset/pageSize {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": fmt.Sprintf("Page is beyond the first %d results; narrow the filters, or use cursor pagination where the list supports it", cfg.MaxPageOffset),
+			})
+		}
+
+		return c.Next()
+	}
+}
+
+// Maintenance rejects writes with 503 while the service is in read-only maintenance mode, set
+// either by cfg or by the admin toggle that maintenance reads; reads are always served. Paths
+// in exempt, such as the toggle itself, accept writes regardless; they match ignoring case, as
+// routes do. If the toggle cannot be read
+// writes are let through, leaving the database to refuse them during an outage.
+func Maintenance(cfg config.MaintenanceConfig, maintenance services.MaintenanceService, exempt ...string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if !writeRequest(c) {
+			return c.Next()
+		}
+		for _, path := range exempt {
+			if strings.EqualFold(c.Path(), path) {
+				return c.Next()
+			}
+		}
+
+		mode := cfg.Mode
+		if mode == "" && maintenance != nil {
+			if status, err := maintenance.Status(c.UserContext()); err == nil {
+				mode = status.Mode
+			}
+		}
+
+		if mode == config.MaintenanceReadOnly {
+			return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{
+				"error": "Service is in read-only maintenance mode; writes are temporarily rejected",
+			})
+		}
+
+		return c.Next()
+	}
+}
+
+// writeRequest reports whether the request's method may modify resources
+func writeRequest(c *fiber.Ctx) bool {
+	switch c.Method() {
+	case fiber.MethodPost, fiber.MethodPut, fiber.MethodPatch, fiber.MethodDelete:
+		return true
+	}
+	return false
+}
+
+// UUIDParams rejects requests whose named path parameters are not UUIDs with the 400 handlers
+// send for invalid input, so malformed IDs never reach a uuid column. Parameters the route lacks
+// are ignored, letting one handler guard routes with different parameter names.
+func UUIDParams(names ...string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		for _, name := range names {
+			value := c.Params(name)
+			if value == "" {
+				continue
+			}
+			// uuid.Parse also accepts braced and URN forms; only the canonical form is an ID here
+			if _, err := uuid.Parse(value); err != nil || len(value) != 36 {
+				return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+					"error":   "Invalid input",
+					"details": []string{name + " must be a UUID"},
+				})
+			}
+		}
+
+		return c.Next()
+	}
+}
+
 // Metrics middleware for Prometheus metrics
 func Metrics(registry *metrics.Registry) fiber.Handler {
-	return func(c fiber.Ctx) error {
+	return func(c *fiber.Ctx) error {
 		start := time.Now()
 
 		err := c.Next()
@@ -102,4 +440,229 @@ func Metrics(registry *metrics.Registry) fiber.Handler {
 
 		return err
 	}
-}
\ No newline at end of file
+}
+
+// WorkspaceConcurrency caps the requests running at once against each workspace at
+// cfg.PerWorkspace, so a burst of writes to one hot workspace cannot pile up on its rows. The
+// workspace is the one workspaceOf resolves for the request; requests it resolves none for are
+// not capped. The ID it returns is kept past the request, so it must not alias Fiber's buffers.
+// Requests over the cap wait up to cfg.QueueTimeoutMs for a slot and are then refused with 429.
+// Mount one instance on every route that should share the cap.
+func WorkspaceConcurrency(cfg config.ConcurrencyConfig, workspaceOf func(c *fiber.Ctx) string) fiber.Handler {
+	if cfg.PerWorkspace <= 0 {
+		return func(c *fiber.Ctx) error {
+			return c.Next()
+		}
+	}
+
+	slots := newKeyedSemaphore(cfg.PerWorkspace)
+	wait := time.Duration(cfg.QueueTimeoutMs) * time.Millisecond
+
+	return func(c *fiber.Ctx) error {
+		workspaceID := workspaceOf(c)
+		if workspaceID == "" {
+			return c.Next()
+		}
+
+		slot, ok := slots.acquire(workspaceID, wait)
+		if !ok {
+			return c.Status(fiber.StatusTooManyRequests).JSON(fiber.Map{
+				"error": "Too many concurrent changes to this workspace; retry shortly",
+			})
+		}
+		defer slots.release(workspaceID, slot)
+
+		return c.Next()
+	}
+}
+
+// keyedSemaphore holds an independent counting semaphore per key, dropping a key's semaphore
+// once nobody holds or waits on it
+type keyedSemaphore struct {
+	mu    sync.Mutex
+	size  int
+	slots map[string]*semaphoreSlot
+}
+
+type semaphoreSlot struct {
+	tokens chan struct{}
+	users  int
+}
+
+func newKeyedSemaphore(size int) *keyedSemaphore {
+	return &keyedSemaphore{size: size, slots: make(map[string]*semaphoreSlot)}
+}
+
+// acquire takes one of key's slots, waiting up to wait for one to free up, and reports whether
+// it got one. The slot is handed back to release.
+func (s *keyedSemaphore) acquire(key string, wait time.Duration) (*semaphoreSlot, bool) {
+	s.mu.Lock()
+	slot, ok := s.slots[key]
+	if !ok {
+		slot = &semaphoreSlot{tokens: make(chan struct{}, s.size)}
+		s.slots[key] = slot
+	}
+	slot.users++
+	s.mu.Unlock()
+
+	select {
+	case slot.tokens <- struct{}{}:
+		return slot, true
+	default:
+	}
+
+	if wait > 0 {
+		timer := time.NewTimer(wait)
+		defer timer.Stop()
+		select {
+		case slot.tokens <- struct{}{}:
+			return slot, true
+		case <-timer.C:
+		}
+	}
+
+	s.leave(key, slot)
+	return nil, false
+}
+
+// release gives back a slot taken by acquire
+func (s *keyedSemaphore) release(key string, slot *semaphoreSlot) {
+	<-slot.tokens
+	s.leave(key, slot)
+}
+
+func (s *keyedSemaphore) leave(key string, slot *semaphoreSlot) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	slot.users--
+	if slot.users == 0 {
+		delete(s.slots, key)
+	}
+}
+
+// RateLimit middleware that caps each tenant's requests per minute. Tenants with an override
+// get their own budget; everyone else shares the default limit, counted per tenant (or per
+// client IP when no tenant is known).
+func RateLimit(cfg config.RateLimitConfig) fiber.Handler {
+	if cfg.RequestsPerMinute <= 0 {
+		return func(c *fiber.Ctx) error {
+			return c.Next()
+		}
+	}
+
+	newLimiter := func(max int) fiber.Handler {
+		return limiter.New(limiter.Config{
+			Max:          max,
+			Expiration:   time.Minute,
+			KeyGenerator: rateLimitKey,
+			LimitReached: func(c *fiber.Ctx) error {
+				return c.Status(fiber.StatusTooManyRequests).JSON(fiber.Map{
+					"error":   true,
+					"message": "Rate limit exceeded",
+				})
+			},
+		})
+	}
+
+	defaultLimiter := newLimiter(cfg.RequestsPerMinute)
+	tenantLimiters := make(map[string]fiber.Handler)
+	for tenantID, max := range cfg.GetTenantOverrides() {
+		tenantLimiters[tenantID] = newLimiter(max)
+	}
+
+	return func(c *fiber.Ctx) error {
+		tenantID, _ := c.Locals("tenant_id").(string)
+		if tenantLimiter, ok := tenantLimiters[tenantID]; ok && tenantID != "" {
+			return tenantLimiter(c)
+		}
+		return defaultLimiter(c)
+	}
+}
+
+// rateLimitKey buckets requests by tenant, falling back to the client IP
+func rateLimitKey(c *fiber.Ctx) string {
+	if tenantID, ok := c.Locals("tenant_id").(string); ok && tenantID != "" {
+		return "tenant:" + tenantID
+	}
+	return "ip:" + c.IP()
+}
+
+// CORS middleware that checks the request origin against the tenant's allowlist.
+// Tenants without an entry fall back to the global allowed origins. The tenant is taken from
+// authentication only, so CORS must be mounted after JWT for tenant origins to apply.
+func CORS(cfg config.CORSConfig) fiber.Handler {
+	globalOrigins := cfg.GetAllowedOrigins()
+	tenantOrigins := cfg.GetTenantOrigins()
+
+	return func(c *fiber.Ctx) error {
+		origin := c.Get(fiber.HeaderOrigin)
+		if origin == "" {
+			return c.Next()
+		}
+
+		c.Vary(fiber.HeaderOrigin)
+
+		preflight := c.Method() == fiber.MethodOptions
+		tenantID, _ := c.Locals("tenant_id").(string)
+		if allowOrigin, credentials := corsOrigin(origin, tenantID, preflight, globalOrigins, tenantOrigins); allowOrigin != "" {
+			c.Set(fiber.HeaderAccessControlAllowOrigin, allowOrigin)
+			if credentials {
+				c.Set(fiber.HeaderAccessControlAllowCredentials, "true")
+			}
+		}
+
+		if preflight {
+			if c.GetRespHeader(fiber.HeaderAccessControlAllowOrigin) != "" {
+				c.Set(fiber.HeaderAccessControlAllowMethods, corsAllowMethods)
+				c.Set(fiber.HeaderAccessControlAllowHeaders, corsAllowHeaders)
+			}
+			return c.SendStatus(fiber.StatusNoContent)
+		}
+
+		return c.Next()
+	}
+}
+
+// corsOrigin returns the Access-Control-Allow-Origin value for origin, empty to refuse it, and
+// whether credentials are allowed. Only explicitly listed origins are echoed, with credentials;
+// a global wildcard answers "*" without them, and only for tenants without their own list.
+// Preflights carry no authentication, so they accept an origin listed for any tenant and leave
+// the tenant check to the request that follows.
+func corsOrigin(origin, tenantID string, preflight bool, globalOrigins []string, tenantOrigins map[string][]string) (string, bool) {
+	listed := false
+	if preflight {
+		for _, allowed := range tenantOrigins {
+			if containsOrigin(allowed, origin) {
+				return origin, true
+			}
+		}
+	} else {
+		var allowed []string
+		allowed, listed = tenantOrigins[tenantID]
+		if containsOrigin(allowed, origin) {
+			return origin, true
+		}
+	}
+
+	wildcard := false
+	for _, candidate := range globalOrigins {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == origin {
+			return origin, true
+		}
+		wildcard = wildcard || candidate == "*"
+	}
+	if wildcard && !listed {
+		return "*", false
+	}
+	return "", false
+}
+
+func containsOrigin(origins []string, origin string) bool {
+	for _, candidate := range origins {
+		if candidate == origin {
+			return true
+		}
+	}
+	return false
+}
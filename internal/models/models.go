@@ -5,6 +5,8 @@ import (
 	"encoding/json"
 	"database/sql/driver"
 	"fmt"
+	"net/http"
+	"strings"
 
 	"gorm.io/gorm"
 )
@@ -17,6 +19,35 @@ type BaseModel struct {
 	DeletedAt gorm.DeletedAt `gorm:"index" json:"deleted_at,omitempty"`
 }
 
+// AfterFind normalizes timestamps read from the database to UTC
+func (m *BaseModel) AfterFind(tx *gorm.DB) error {
+	m.normalizeTimes()
+	return nil
+}
+
+// BeforeSave normalizes timestamps written to the database to UTC
+func (m *BaseModel) BeforeSave(tx *gorm.DB) error {
+	m.normalizeTimes()
+	return nil
+}
+
+func (m *BaseModel) normalizeTimes() {
+	m.CreatedAt = m.CreatedAt.UTC()
+	m.UpdatedAt = m.UpdatedAt.UTC()
+	if m.DeletedAt.Valid {
+		m.DeletedAt.Time = m.DeletedAt.Time.UTC()
+	}
+}
+
+// UTC returns t in UTC so it serializes as RFC3339 with a Z suffix
+func UTC(t *time.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+	utc := t.UTC()
+	return &utc
+}
+
 // JSONMap represents a JSON map field
 type JSONMap map[string]interface{}
 
@@ -45,6 +76,34 @@ func (j JSONMap) Value() (driver.Value, error) {
 	return json.Marshal(j)
 }
 
+// StringList is a list of strings stored as a JSON array
+type StringList []string
+
+// Scan implements the sql.Scanner interface for StringList
+func (l *StringList) Scan(value interface{}) error {
+	if value == nil {
+		*l = StringList{}
+		return nil
+	}
+
+	switch v := value.(type) {
+	case []byte:
+		return json.Unmarshal(v, l)
+	case string:
+		return json.Unmarshal([]byte(v), l)
+	default:
+		return fmt.Errorf("cannot scan %T into StringList", value)
+	}
+}
+
+// Value implements the driver.Valuer interface for StringList
+func (l StringList) Value() (driver.Value, error) {
+	if l == nil {
+		return "[]", nil
+	}
+	return json.Marshal(l)
+}
+
 // Workspace represents a workspace in the system
 type Workspace struct {
 	BaseModel
@@ -53,6 +112,8 @@ type Workspace struct {
 	Description string  `gorm:"type:text" json:"description"`
 	Settings    JSONMap `gorm:"type:jsonb;default:'{}';not null" json:"settings"`
 	CreatedBy   string  `gorm:"size:255;not null" json:"created_by"`
+	// ScheduledDeletionAt is when the scheduled deletion job will delete the workspace, if set
+	ScheduledDeletionAt *time.Time `gorm:"index" json:"scheduled_deletion_at,omitempty"`
 	
 	// Relationships
 	Projects []*Project `gorm:"foreignKey:WorkspaceID;constraint:OnDelete:CASCADE" json:"projects,omitempty"`
@@ -64,6 +125,89 @@ func (Workspace) TableName() string {
 	return "workspaces"
 }
 
+// WorkspaceSettingAuditRetentionDays is the settings key overriding how many days a workspace's
+// audit logs are kept
+const WorkspaceSettingAuditRetentionDays = "audit_retention_days"
+
+// AuditRetentionDays returns the workspace's audit retention override, if its settings hold a
+// positive whole number of days under WorkspaceSettingAuditRetentionDays
+func (w *Workspace) AuditRetentionDays() (int, bool) {
+	switch days := w.Settings[WorkspaceSettingAuditRetentionDays].(type) {
+	case float64:
+		if days >= 1 && days == float64(int(days)) {
+			return int(days), true
+		}
+	case int:
+		if days >= 1 {
+			return days, true
+		}
+	}
+	return 0, false
+}
+
+// WorkspaceSettingNotifications is the settings key holding a workspace's NotificationSettings
+const WorkspaceSettingNotifications = "notifications"
+
+// Notification digest frequencies
+const (
+	NotificationDigestOff    = "off"
+	NotificationDigestDaily  = "daily"
+	NotificationDigestWeekly = "weekly"
+)
+
+// NotificationSettings are a workspace's notification preferences, stored in its settings under
+// WorkspaceSettingNotifications. Preferences left out of the stored settings are off.
+type NotificationSettings struct {
+	EmailOnMemberAdded    bool `json:"email_on_member_added"`
+	EmailOnMemberRemoved  bool `json:"email_on_member_removed"`
+	EmailOnProjectDeleted bool `json:"email_on_project_deleted"`
+	EmailOnSyncFailed     bool `json:"email_on_sync_failed"`
+	// Digest is how often a summary of workspace activity is sent: off, daily or weekly
+	Digest string `json:"digest"`
+}
+
+// ParseNotificationSettings decodes a stored notifications setting. An empty digest reads as
+// NotificationDigestOff.
+func ParseNotificationSettings(value interface{}) (NotificationSettings, error) {
+	var settings NotificationSettings
+	if value != nil {
+		data, err := json.Marshal(value)
+		if err != nil {
+			return settings, err
+		}
+		if err := json.Unmarshal(data, &settings); err != nil {
+			return settings, err
+		}
+	}
+	if settings.Digest == "" {
+		settings.Digest = NotificationDigestOff
+	}
+	return settings, nil
+}
+
+// NotificationSettings returns the workspace's notification preferences. Settings that cannot
+// be decoded read as all notifications off.
+func (w *Workspace) NotificationSettings() NotificationSettings {
+	settings, err := ParseNotificationSettings(w.Settings[WorkspaceSettingNotifications])
+	if err != nil {
+		settings, _ = ParseNotificationSettings(nil)
+	}
+	return settings
+}
+
+// SetNotificationSettings stores notification preferences in the workspace's settings, leaving
+// its other settings untouched
+func (w *Workspace) SetNotificationSettings(settings NotificationSettings) {
+	if w.Settings == nil {
+		w.Settings = make(JSONMap)
+	}
+	// Stored as a plain map, the shape the settings column decodes to
+	data, _ := json.Marshal(settings)
+	var stored map[string]interface{}
+	_ = json.Unmarshal(data, &stored)
+	w.Settings[WorkspaceSettingNotifications] = stored
+}
+
 // Project represents a project within a workspace
 type Project struct {
 	BaseModel
@@ -72,8 +216,14 @@ type Project struct {
 	Description string  `gorm:"type:text" json:"description"`
 	Status      string  `gorm:"size:50;not null;default:'active'" json:"status"` // active, archived, deleted
 	Settings    JSONMap `gorm:"type:jsonb;default:'{}';not null" json:"settings"`
+	Tags        StringList `gorm:"type:jsonb;default:'[]';not null" json:"tags"`
 	CreatedBy   string  `gorm:"size:255;not null" json:"created_by"`
 	
+	// Computed fields, populated only when requested
+	BaseCount *int64 `gorm:"-" json:"base_count,omitempty"`
+	// WorkspaceName is filled by project listings in place of the full Workspace
+	WorkspaceName string `gorm:"-" json:"workspace_name,omitempty"`
+	
 	// Relationships
 	Workspace     *Workspace     `gorm:"foreignKey:WorkspaceID" json:"workspace,omitempty"`
 	AirtableBases []*AirtableBase `gorm:"foreignKey:ProjectID;constraint:OnDelete:CASCADE" json:"airtable_bases,omitempty"`
@@ -87,22 +237,70 @@ func (Project) TableName() string {
 // AirtableBase represents an Airtable base connection
 type AirtableBase struct {
 	BaseModel
-	ProjectID   string     `gorm:"size:255;not null;index" json:"project_id"`
-	BaseID      string     `gorm:"size:255;not null" json:"base_id"`
-	Name        string     `gorm:"size:255;not null" json:"name"`
-	Description string     `gorm:"type:text" json:"description"`
-	SyncEnabled bool       `gorm:"default:true" json:"sync_enabled"`
-	LastSyncAt  *time.Time `json:"last_sync_at,omitempty"`
+	ProjectID          string     `gorm:"size:255;not null;index" json:"project_id"`
+	BaseID             string     `gorm:"size:255;not null" json:"base_id"`
+	Name               string     `gorm:"size:255;not null" json:"name"`
+	Description        string     `gorm:"type:text" json:"description"`
+	SyncEnabled        bool       `gorm:"default:true" json:"sync_enabled"`
+	SyncStatus         string     `gorm:"size:20;not null;default:'idle'" json:"sync_status"`
+	LastSyncAt         *time.Time `json:"last_sync_at,omitempty"`
+	LastSyncAttemptAt  *time.Time `json:"last_sync_attempt_at,omitempty"`
+	LastSyncError      string     `gorm:"type:text;not null;default:''" json:"last_sync_error,omitempty"`
+	LastSyncDurationMs int        `gorm:"not null;default:0" json:"last_sync_duration_ms,omitempty"`
+	Settings           JSONMap    `gorm:"type:jsonb;default:'{}';not null" json:"settings"`
+	CreatedBy          string     `gorm:"size:255;not null;default:''" json:"created_by"`
+	
+	// Computed fields, populated only when requested
+	Metadata *AirtableBaseMetadata `gorm:"-" json:"metadata,omitempty"`
 	
 	// Relationships
 	Project *Project `gorm:"foreignKey:ProjectID" json:"project,omitempty"`
 }
 
+// Airtable base sync statuses. A base is pending while the sync worker is copying its data, and
+// failed when its last sync did not complete.
+const (
+	AirtableSyncStatusIdle    = "idle"
+	AirtableSyncStatusPending = "pending"
+	AirtableSyncStatusFailed  = "failed"
+)
+
 // TableName sets the table name for AirtableBase
 func (AirtableBase) TableName() string {
 	return "airtable_bases"
 }
 
+// AfterFind normalizes timestamps read from the database to UTC
+func (b *AirtableBase) AfterFind(tx *gorm.DB) error {
+	b.BaseModel.normalizeTimes()
+	b.LastSyncAt = UTC(b.LastSyncAt)
+	b.LastSyncAttemptAt = UTC(b.LastSyncAttemptAt)
+	return nil
+}
+
+// BeforeSave normalizes timestamps written to the database to UTC
+func (b *AirtableBase) BeforeSave(tx *gorm.DB) error {
+	b.BaseModel.normalizeTimes()
+	b.LastSyncAt = UTC(b.LastSyncAt)
+	b.LastSyncAttemptAt = UTC(b.LastSyncAttemptAt)
+	return nil
+}
+
+// AirtableBaseMetadata is the gateway's description of a base, cached by base ID
+type AirtableBaseMetadata struct {
+	BaseID    string                  `json:"base_id"`
+	Tables    []AirtableTableMetadata `json:"tables"`
+	FetchedAt time.Time               `json:"fetched_at"`
+	IsStale   bool                    `json:"is_stale"`
+}
+
+// AirtableTableMetadata summarises one table of an Airtable base
+type AirtableTableMetadata struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	RecordCount int64  `json:"record_count"`
+}
+
 // WorkspaceMemberRole represents workspace member roles
 type WorkspaceMemberRole string
 
@@ -119,6 +317,14 @@ type WorkspaceMember struct {
 	UserID      string                `gorm:"size:255;not null;primaryKey" json:"user_id"`
 	Role        WorkspaceMemberRole   `gorm:"size:50;not null" json:"role"`
 	JoinedAt    time.Time             `gorm:"default:now()" json:"joined_at"`
+	// IsPrimary marks the one owner who is the workspace's billing and notification contact
+	IsPrimary bool `gorm:"not null;default:false" json:"is_primary"`
+	// LastAccessedAt is when the member last opened the workspace, if ever
+	LastAccessedAt *time.Time `json:"last_accessed_at,omitempty"`
+	// DisplayName and Email are resolved from the user directory when listing members;
+	// they are empty when the directory does not know the user or is unavailable
+	DisplayName string `gorm:"-" json:"display_name,omitempty"`
+	Email       string `gorm:"-" json:"email,omitempty"`
 	
 	// Relationships
 	Workspace *Workspace `gorm:"foreignKey:WorkspaceID" json:"workspace,omitempty"`
@@ -129,16 +335,141 @@ func (WorkspaceMember) TableName() string {
 	return "workspace_members"
 }
 
+// PastWorkspaceMember records a membership that ended. Removing a member deletes its
+// workspace_members row, so active-member queries never see it, and writes one of these.
+type PastWorkspaceMember struct {
+	ID          string              `gorm:"primarykey;type:uuid;default:gen_random_uuid()" json:"id"`
+	WorkspaceID string              `gorm:"size:255;not null;index:idx_membership_history_workspace_left,priority:1" json:"workspace_id"`
+	UserID      string              `gorm:"size:255;not null" json:"user_id"`
+	Role        WorkspaceMemberRole `gorm:"size:50;not null" json:"role"` // role held when the membership ended
+	JoinedAt    time.Time           `gorm:"not null" json:"joined_at"`
+	LeftAt      time.Time           `gorm:"not null;default:now();index:idx_membership_history_workspace_left,priority:2" json:"left_at"`
+}
+
+// TableName sets the table name for PastWorkspaceMember
+func (PastWorkspaceMember) TableName() string {
+	return "workspace_membership_history"
+}
+
+// AfterFind normalizes timestamps read from the database to UTC
+func (m *WorkspaceMember) AfterFind(tx *gorm.DB) error {
+	m.JoinedAt = m.JoinedAt.UTC()
+	m.LastAccessedAt = UTC(m.LastAccessedAt)
+	return nil
+}
+
+// AfterCreate normalizes the database-assigned join time to UTC
+func (m *WorkspaceMember) AfterCreate(tx *gorm.DB) error {
+	m.JoinedAt = m.JoinedAt.UTC()
+	return nil
+}
+
+// UserInfo is the user directory's description of a user
+type UserInfo struct {
+	UserID      string `json:"user_id"`
+	DisplayName string `json:"display_name"`
+	Email       string `json:"email"`
+}
+
+// ServiceAccountTokenPrefix marks bearer tokens that authenticate a service account
+const ServiceAccountTokenPrefix = "wsa_"
+
+// ServiceAccount is a non-human principal holding a fixed role in a single workspace. Only a
+// hash of its token is stored; the token itself is returned once, when the account is created.
+type ServiceAccount struct {
+	BaseModel
+	WorkspaceID string              `gorm:"size:255;not null;index" json:"workspace_id"`
+	TenantID    string              `gorm:"size:255;not null" json:"tenant_id"`
+	Name        string              `gorm:"size:255;not null" json:"name"`
+	Role        WorkspaceMemberRole `gorm:"size:50;not null" json:"role"`
+	TokenHash   string              `gorm:"size:64;not null;uniqueIndex" json:"-"`
+	CreatedBy   string              `gorm:"size:255;not null" json:"created_by"`
+	RevokedAt   *time.Time          `json:"revoked_at,omitempty"`
+}
+
+// TableName sets the table name for ServiceAccount
+func (ServiceAccount) TableName() string {
+	return "service_accounts"
+}
+
+// PrincipalID is the user ID the service account acts as in membership checks and audit logs
+func (a *ServiceAccount) PrincipalID() string {
+	return "service-account:" + a.ID
+}
+
+// AfterFind normalizes timestamps read from the database to UTC
+func (a *ServiceAccount) AfterFind(tx *gorm.DB) error {
+	a.BaseModel.normalizeTimes()
+	a.RevokedAt = UTC(a.RevokedAt)
+	return nil
+}
+
+// BeforeSave normalizes timestamps written to the database to UTC
+func (a *ServiceAccount) BeforeSave(tx *gorm.DB) error {
+	a.BaseModel.normalizeTimes()
+	a.RevokedAt = UTC(a.RevokedAt)
+	return nil
+}
+
+// Audit actions written by this service or ingested from other services
+const (
+	AuditActionWorkspaceCreated            = "workspace.created"
+	AuditActionWorkspaceUpdated            = "workspace.updated" // superseded by the granular actions below; kept for existing entries
+	AuditActionWorkspaceRenamed            = "workspace.renamed"
+	AuditActionWorkspaceDescriptionChanged = "workspace.description_changed"
+	AuditActionWorkspaceSettingsChanged    = "workspace.settings_changed"
+	AuditActionWorkspaceDeleted            = "workspace.deleted"
+	AuditActionWorkspaceSyncUpdated        = "workspace.sync_updated"
+	AuditActionWorkspaceTenantChanged      = "workspace.tenant_changed"
+	AuditActionWorkspaceDeletionScheduled  = "workspace.deletion_scheduled"
+	AuditActionWorkspaceDeletionCancelled  = "workspace.deletion_cancelled"
+	AuditActionProjectCreated              = "project.created"
+	AuditActionProjectUpdated              = "project.updated"
+	AuditActionProjectDeleted              = "project.deleted"
+	AuditActionProjectOwnerChanged         = "project.owner_changed"
+	AuditActionBaseConnected               = "airtable_base.connected"
+	AuditActionBaseUpdated                 = "airtable_base.updated"
+	AuditActionBaseDisconnected            = "airtable_base.disconnected"
+	AuditActionBaseSynced                  = "airtable_base.synced"
+	AuditActionMemberAdded                 = "member.added"
+	AuditActionMemberRoleUpdated           = "member.role_updated"
+	AuditActionMemberRemoved               = "member.removed"
+	AuditActionMemberResourcesReassigned   = "member.resources_reassigned"
+	AuditActionMemberPrimaryOwnerChanged   = "member.primary_owner_changed"
+	AuditActionDataExported                = "data.exported"
+	AuditActionDataImported                = "data.imported"
+	AuditActionReportGenerated             = "report.generated"
+	AuditActionAutomationTriggered         = "automation.triggered"
+	AuditActionServiceAccountCreated       = "service_account.created"
+	AuditActionServiceAccountRevoked       = "service_account.revoked"
+)
+
+// Audit resource types
+const (
+	AuditResourceWorkspace       = "workspace"
+	AuditResourceProject         = "project"
+	AuditResourceAirtableBase    = "airtable_base"
+	AuditResourceWorkspaceMember = "workspace_member"
+	AuditResourceReport          = "report"
+	AuditResourceAutomation      = "automation"
+	AuditResourceServiceAccount  = "service_account"
+)
+
+// AuditUnknown is stored in place of an action or resource type outside the vocabulary
+const AuditUnknown = "unknown"
+
 // WorkspaceAuditLog represents audit log entries for workspace activities
 type WorkspaceAuditLog struct {
-	ID           string    `gorm:"primarykey;type:uuid;default:gen_random_uuid()" json:"id"`
-	WorkspaceID  string    `gorm:"size:255;not null;index" json:"workspace_id"`
-	UserID       string    `gorm:"size:255;not null" json:"user_id"`
-	Action       string    `gorm:"size:100;not null" json:"action"`
-	ResourceType string    `gorm:"size:50;not null" json:"resource_type"`
-	ResourceID   string    `gorm:"size:255" json:"resource_id"`
-	Changes      JSONMap   `gorm:"type:jsonb" json:"changes"`
-	CreatedAt    time.Time `gorm:"default:now()" json:"created_at"`
+	ID             string    `gorm:"primarykey;type:uuid;default:gen_random_uuid()" json:"id"`
+	WorkspaceID    string    `gorm:"size:255;not null;index" json:"workspace_id"`
+	UserID         string    `gorm:"size:255;not null" json:"user_id"`
+	ImpersonatedBy string    `gorm:"size:255" json:"impersonated_by,omitempty"` // real caller when UserID was impersonated
+	Action         string    `gorm:"size:100;not null" json:"action"`
+	ResourceType   string    `gorm:"size:50;not null" json:"resource_type"`
+	ResourceID     string    `gorm:"size:255" json:"resource_id"`
+	Changes        JSONMap   `gorm:"type:jsonb" json:"changes"`
+	OperationID    string    `gorm:"size:36;index" json:"operation_id,omitempty"` // shared by the entries one request wrote
+	CreatedAt      time.Time `gorm:"default:now()" json:"created_at"`
 	
 	// Relationships
 	Workspace *Workspace `gorm:"foreignKey:WorkspaceID" json:"workspace,omitempty"`
@@ -149,22 +480,62 @@ func (WorkspaceAuditLog) TableName() string {
 	return "workspace_audit_logs"
 }
 
+// AfterFind normalizes timestamps read from the database to UTC
+func (l *WorkspaceAuditLog) AfterFind(tx *gorm.DB) error {
+	l.CreatedAt = l.CreatedAt.UTC()
+	return nil
+}
+
+// AfterCreate normalizes the database-assigned creation time to UTC
+func (l *WorkspaceAuditLog) AfterCreate(tx *gorm.DB) error {
+	l.CreatedAt = l.CreatedAt.UTC()
+	return nil
+}
+
+// Event types published for downstream consumers such as notification services
+const (
+	EventMemberRoleUpdated    = "member.role_updated"
+	EventMemberRoleDowngraded = "member.role_downgraded"
+
+	EventWorkspaceDeletionScheduled = "workspace.deletion_scheduled"
+	EventWorkspaceDeletionCancelled = "workspace.deletion_cancelled"
+	EventWorkspaceDeletionExecuted  = "workspace.deletion_executed"
+)
+
+// Event is a domain event published when workspace state changes
+type Event struct {
+	Type        string                 `json:"type"`
+	WorkspaceID string                 `json:"workspace_id"`
+	ActorID     string                 `json:"actor_id"`
+	Data        map[string]interface{} `json:"data"`
+	OccurredAt  time.Time              `json:"occurred_at"`
+}
+
 // Request and Response Models
 
-// CreateWorkspaceRequest represents a workspace creation request
+// CreateWorkspaceRequest represents a workspace creation request. OwnerUserID names the
+// user who becomes the owner member, defaulting to the caller; only provisioners may name
+// someone else.
 type CreateWorkspaceRequest struct {
 	Name        string  `json:"name" validate:"required,min=1,max=255"`
 	Description string  `json:"description"`
 	Settings    JSONMap `json:"settings,omitempty"`
+	OwnerUserID string  `json:"owner_user_id,omitempty"`
 }
 
 // UpdateWorkspaceRequest represents a workspace update request
+// Settings replaces the stored map wholesale: {} clears it, while omitting it or sending null leaves it unchanged
 type UpdateWorkspaceRequest struct {
 	Name        *string  `json:"name,omitempty" validate:"omitempty,min=1,max=255"`
 	Description *string  `json:"description,omitempty"`
 	Settings    *JSONMap `json:"settings,omitempty"`
 }
 
+// IsEmpty reports whether the request sets no field, making the update a no-op
+func (r *UpdateWorkspaceRequest) IsEmpty() bool {
+	return r.Name == nil && r.Description == nil && r.Settings == nil
+}
+
 // CreateProjectRequest represents a project creation request
 type CreateProjectRequest struct {
 	Name        string  `json:"name" validate:"required,min=1,max=255"`
@@ -173,6 +544,7 @@ type CreateProjectRequest struct {
 }
 
 // UpdateProjectRequest represents a project update request
+// Settings replaces the stored map wholesale: {} clears it, while omitting it or sending null leaves it unchanged
 type UpdateProjectRequest struct {
 	Name        *string  `json:"name,omitempty" validate:"omitempty,min=1,max=255"`
 	Description *string  `json:"description,omitempty"`
@@ -180,19 +552,69 @@ type UpdateProjectRequest struct {
 	Settings    *JSONMap `json:"settings,omitempty"`
 }
 
+// IsEmpty reports whether the request sets no field, making the update a no-op
+func (r *UpdateProjectRequest) IsEmpty() bool {
+	return r.Name == nil && r.Description == nil && r.Status == nil && r.Settings == nil
+}
+
 // CreateAirtableBaseRequest represents an Airtable base creation request
 type CreateAirtableBaseRequest struct {
-	BaseID      string `json:"base_id" validate:"required"`
-	Name        string `json:"name" validate:"required,min=1,max=255"`
-	Description string `json:"description"`
-	SyncEnabled bool   `json:"sync_enabled"`
+	BaseID      string  `json:"base_id" validate:"required"`
+	Name        string  `json:"name" validate:"required,min=1,max=255"`
+	Description string  `json:"description"`
+	SyncEnabled bool    `json:"sync_enabled"`
+	Settings    JSONMap `json:"settings,omitempty"`
 }
 
-// UpdateAirtableBaseRequest represents an Airtable base update request
-type UpdateAirtableBaseRequest struct {
-	Name        *string `json:"name,omitempty" validate:"omitempty,min=1,max=255"`
+// UpsertAirtableBaseRequest connects an Airtable base or updates its existing connection.
+// Description and SyncEnabled are pointers so an update keeps the stored values of fields the
+// request leaves out; settings are only replaced when sent.
+type UpsertAirtableBaseRequest struct {
+	BaseID      string  `json:"base_id" validate:"required"`
+	Name        string  `json:"name" validate:"required,min=1,max=255"`
 	Description *string `json:"description,omitempty"`
 	SyncEnabled *bool   `json:"sync_enabled,omitempty"`
+	Settings    JSONMap `json:"settings,omitempty"`
+}
+
+// CreateRequest returns the connection the request creates when the base is not yet connected
+func (r *UpsertAirtableBaseRequest) CreateRequest() *CreateAirtableBaseRequest {
+	req := &CreateAirtableBaseRequest{BaseID: r.BaseID, Name: r.Name, Settings: r.Settings}
+	if r.Description != nil {
+		req.Description = *r.Description
+	}
+	if r.SyncEnabled != nil {
+		req.SyncEnabled = *r.SyncEnabled
+	}
+	return req
+}
+
+// UpdateAirtableBaseRequest represents an Airtable base update request
+// Settings replaces the stored map wholesale: {} clears it, while omitting it or sending null leaves it unchanged
+type UpdateAirtableBaseRequest struct {
+	Name        *string  `json:"name,omitempty" validate:"omitempty,min=1,max=255"`
+	Description *string  `json:"description,omitempty"`
+	SyncEnabled *bool    `json:"sync_enabled,omitempty"`
+	Settings    *JSONMap `json:"settings,omitempty"`
+}
+
+// IsEmpty reports whether the request sets no field, making the update a no-op
+func (r *UpdateAirtableBaseRequest) IsEmpty() bool {
+	return r.Name == nil && r.Description == nil && r.SyncEnabled == nil && r.Settings == nil
+}
+
+// CreateServiceAccountRequest represents a service account creation request. Owner is not an
+// assignable role, so a service account can never manage other accounts or workspace ownership.
+type CreateServiceAccountRequest struct {
+	Name string              `json:"name" validate:"required,min=1,max=255"`
+	Role WorkspaceMemberRole `json:"role" validate:"required,oneof=admin member viewer"`
+}
+
+// CreatedServiceAccount is a newly created service account together with its token, which is
+// never shown again
+type CreatedServiceAccount struct {
+	*ServiceAccount
+	Token string `json:"token"`
 }
 
 // AddWorkspaceMemberRequest represents a request to add a member to workspace
@@ -206,33 +628,282 @@ type UpdateWorkspaceMemberRequest struct {
 	Role WorkspaceMemberRole `json:"role" validate:"required,oneof=owner admin member viewer"`
 }
 
+// SetPrimaryOwnerRequest names the owner to make the workspace's primary owner
+type SetPrimaryOwnerRequest struct {
+	UserID string `json:"user_id" validate:"required"`
+}
+
+// IngestAuditLogEntry is one audit entry written by another service
+type IngestAuditLogEntry struct {
+	UserID       string  `json:"user_id" validate:"required"`
+	Action       string  `json:"action" validate:"required"`
+	ResourceType string  `json:"resource_type" validate:"required"`
+	ResourceID   string  `json:"resource_id"`
+	Changes      JSONMap `json:"changes"`
+}
+
+// IngestAuditLogsRequest is a batch of audit entries for one workspace
+type IngestAuditLogsRequest struct {
+	Entries []IngestAuditLogEntry `json:"entries" validate:"required"`
+}
+
 // List Response Models
 
+// PaginationLinks holds navigation URLs for a page-based list response
+type PaginationLinks struct {
+	First string `json:"first"`
+	Prev  string `json:"prev,omitempty"`
+	Next  string `json:"next,omitempty"`
+	Last  string `json:"last"`
+}
+
+// SetWorkspaceSyncRequest pauses or resumes syncing for every base in a workspace
+type SetWorkspaceSyncRequest struct {
+	Enabled *bool `json:"enabled" validate:"required"`
+}
+
+// SetWorkspaceSyncResponse reports how many bases a workspace sync change affected
+type SetWorkspaceSyncResponse struct {
+	SyncEnabled  bool  `json:"sync_enabled"`
+	BasesUpdated int64 `json:"bases_updated"`
+}
+
+// SetProjectOwnerRequest represents a request to change a project's owner attribution
+type SetProjectOwnerRequest struct {
+	UserID string `json:"user_id" validate:"required"`
+}
+
+// ScheduleWorkspaceDeletionRequest schedules a workspace for deletion after a grace period
+type ScheduleWorkspaceDeletionRequest struct {
+	Days int `json:"days" validate:"required,min=1,max=365"`
+}
+
+// ChangeWorkspaceTenantRequest represents a request to move a workspace to another tenant
+type ChangeWorkspaceTenantRequest struct {
+	TenantID string `json:"tenant_id" validate:"required"`
+}
+
+// BulkDeleteProjectsRequest selects projects in a workspace by explicit IDs or by status
+type BulkDeleteProjectsRequest struct {
+	ProjectIDs    []string `json:"project_ids"`
+	Status        string   `json:"status"`
+	Force         bool     `json:"force"`          // also disconnect the projects' Airtable bases
+	ConfirmActive bool     `json:"confirm_active"` // required when any selected project is active
+}
+
+// BulkDeleteProjectsResponse reports what a bulk delete removed
+type BulkDeleteProjectsResponse struct {
+	ProjectsDeleted int64 `json:"projects_deleted"`
+	BasesDeleted    int64 `json:"bases_deleted"`
+}
+
+// TagProjectsRequest adds and removes tags on several projects of a workspace at once
+type TagProjectsRequest struct {
+	ProjectIDs []string `json:"project_ids"`
+	Add        []string `json:"add"`
+	Remove     []string `json:"remove"`
+}
+
+// ProjectTagsResult reports one project's tags after a bulk tag change
+type ProjectTagsResult struct {
+	ProjectID string     `json:"project_id"`
+	Tags      StringList `json:"tags"`
+	Added     []string   `json:"added"`
+	Removed   []string   `json:"removed"`
+}
+
+// TagProjectsResponse reports every project of a bulk tag change, in request order
+type TagProjectsResponse struct {
+	Projects []*ProjectTagsResult `json:"projects"`
+}
+
+// NormalizeTag returns the stored form of a tag: trimmed and lowercased, so "Q3" and "q3 " are one tag
+func NormalizeTag(tag string) string {
+	return strings.ToLower(strings.TrimSpace(tag))
+}
+
+// BatchAddWorkspaceMembersRequest adds several members to a workspace in one call
+type BatchAddWorkspaceMembersRequest struct {
+	Members []AddWorkspaceMemberRequest `json:"members"`
+}
+
+// BatchCreateProjectsRequest creates several projects in a workspace in one call
+type BatchCreateProjectsRequest struct {
+	Projects []CreateProjectRequest `json:"projects"`
+}
+
+// BatchConnectAirtableBasesRequest connects several Airtable bases to a project in one call
+type BatchConnectAirtableBasesRequest struct {
+	Bases []CreateAirtableBaseRequest `json:"bases"`
+}
+
+// FieldError is a blocking problem with one request field. Field is the JSON path of the
+// field, such as "name" or "entries[2].user_id".
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// BatchItemResult is the outcome of one item of a batch request, at the item's request index.
+// Status is the HTTP status the item would have had as a single request.
+type BatchItemResult[T any] struct {
+	Index    int          `json:"index"`
+	Status   int          `json:"status"`
+	Item     *T           `json:"item,omitempty"`
+	Error    string       `json:"error,omitempty"`
+	Details  []string     `json:"details,omitempty"`
+	Errors   []FieldError `json:"errors,omitempty"`
+	Warnings []string     `json:"warnings,omitempty"`
+}
+
+// BatchResult reports each item of a batch request so clients can retry only the failures
+type BatchResult[T any] struct {
+	Results   []*BatchItemResult[T] `json:"results"`
+	Succeeded int                   `json:"succeeded"`
+	Failed    int                   `json:"failed"`
+}
+
+// NewBatchResult creates an empty result sized for n items
+func NewBatchResult[T any](n int) *BatchResult[T] {
+	return &BatchResult[T]{Results: make([]*BatchItemResult[T], 0, n)}
+}
+
+// Succeed records a successful item
+func (r *BatchResult[T]) Succeed(index, status int, item *T, warnings []string) {
+	r.Results = append(r.Results, &BatchItemResult[T]{Index: index, Status: status, Item: item, Warnings: warnings})
+	r.Succeeded++
+}
+
+// Fail records a failed item
+func (r *BatchResult[T]) Fail(index, status int, message string, details []string) {
+	r.Results = append(r.Results, &BatchItemResult[T]{Index: index, Status: status, Error: message, Details: details})
+	r.Failed++
+}
+
+// Invalid records an item rejected by validation with 400, listing its problems both as
+// messages in details and per field in errors, as a single request's rejection does
+func (r *BatchResult[T]) Invalid(index int, details []string, fieldErrors []FieldError) {
+	r.Results = append(r.Results, &BatchItemResult[T]{
+		Index:   index,
+		Status:  http.StatusBadRequest,
+		Error:   "Validation failed",
+		Details: details,
+		Errors:  fieldErrors,
+	})
+	r.Failed++
+}
+
+// Status is the HTTP status for the whole batch: 207 Multi-Status once any item failed,
+// otherwise the status every item shares
+func (r *BatchResult[T]) Status(success int) int {
+	if r.Failed > 0 {
+		return http.StatusMultiStatus
+	}
+	return success
+}
+
+// NameAvailabilityResponse reports whether a workspace or project name is free to use
+type NameAvailabilityResponse struct {
+	Available bool `json:"available"`
+}
+
+// ResolveWorkspaceNamesRequest lists the workspace IDs whose names a client wants to show
+type ResolveWorkspaceNamesRequest struct {
+	WorkspaceIDs []string `json:"workspace_ids"`
+}
+
 // WorkspaceListResponse represents a paginated list of workspaces
 type WorkspaceListResponse struct {
-	Workspaces []*Workspace `json:"workspaces"`
-	Total      int64        `json:"total"`
-	Page       int          `json:"page"`
-	PageSize   int          `json:"page_size"`
-	TotalPages int          `json:"total_pages"`
+	Workspaces []*Workspace     `json:"workspaces"`
+	Total      int64            `json:"total"`
+	Page       int              `json:"page"`
+	PageSize   int              `json:"page_size"`
+	TotalPages int              `json:"total_pages"`
+	Links      *PaginationLinks `json:"links,omitempty"`
 }
 
 // ProjectListResponse represents a paginated list of projects
 type ProjectListResponse struct {
-	Projects   []*Project `json:"projects"`
-	Total      int64      `json:"total"`
-	Page       int        `json:"page"`
-	PageSize   int        `json:"page_size"`
-	TotalPages int        `json:"total_pages"`
+	Projects   []*Project       `json:"projects"`
+	Total      int64            `json:"total"`
+	Page       int              `json:"page"`
+	PageSize   int              `json:"page_size"`
+	TotalPages int              `json:"total_pages"`
+	Links      *PaginationLinks `json:"links,omitempty"`
+}
+
+// WorkspaceWithProjects is a workspace with one page of its projects embedded, returned by
+// GET /workspaces/:id?include=projects
+type WorkspaceWithProjects struct {
+	*Workspace
+	Projects *ProjectListResponse `json:"projects"`
+}
+
+// ProjectWorkspaceGroup is one workspace's projects in a grouped project listing
+type ProjectWorkspaceGroup struct {
+	WorkspaceID   string     `json:"workspace_id"`
+	WorkspaceName string     `json:"workspace_name"`
+	Projects      []*Project `json:"projects"`
+}
+
+// GroupedProjectListResponse represents a paginated project listing grouped by workspace. Pages
+// are cut by project, so a workspace's projects may continue on the next page.
+type GroupedProjectListResponse struct {
+	Workspaces []*ProjectWorkspaceGroup `json:"workspaces"`
+	Total      int64                    `json:"total"`
+	Page       int                      `json:"page"`
+	PageSize   int                      `json:"page_size"`
+	TotalPages int                      `json:"total_pages"`
+	Links      *PaginationLinks         `json:"links,omitempty"`
 }
 
 // AirtableBaseListResponse represents a paginated list of Airtable bases
 type AirtableBaseListResponse struct {
-	Bases      []*AirtableBase `json:"bases"`
-	Total      int64           `json:"total"`
-	Page       int             `json:"page"`
-	PageSize   int             `json:"page_size"`
-	TotalPages int             `json:"total_pages"`
+	Bases      []*AirtableBase  `json:"bases"`
+	Total      int64            `json:"total"`
+	Page       int              `json:"page"`
+	PageSize   int              `json:"page_size"`
+	TotalPages int              `json:"total_pages"`
+	Links      *PaginationLinks `json:"links,omitempty"`
+}
+
+// AirtableBaseLookupResponse lists the caller's connections of one Airtable base
+type AirtableBaseLookupResponse struct {
+	Bases []*AirtableBase `json:"bases"`
+	Total int             `json:"total"`
+}
+
+// ActivityEntry is an audit entry as the activity feed shows it: what happened, to what and by
+// whom, without the audit-only detail of the change itself
+type ActivityEntry struct {
+	ID            string    `json:"id"`
+	WorkspaceID   string    `json:"workspace_id"`
+	WorkspaceName string    `json:"workspace_name"`
+	UserID        string    `json:"user_id"`
+	Action        string    `json:"action"`
+	ResourceType  string    `json:"resource_type"`
+	ResourceID    string    `json:"resource_id"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+// ActivityFeedResponse is a page of a user's activity feed, latest first
+type ActivityFeedResponse struct {
+	Entries    []*ActivityEntry `json:"entries"`
+	Total      int64            `json:"total"`
+	Page       int              `json:"page"`
+	PageSize   int              `json:"page_size"`
+	TotalPages int              `json:"total_pages"`
+	Links      *PaginationLinks `json:"links,omitempty"`
+}
+
+// PastWorkspaceMemberListResponse is a page of a workspace's former members, latest to leave first
+type PastWorkspaceMemberListResponse struct {
+	Members    []*PastWorkspaceMember `json:"members"`
+	Total      int64                  `json:"total"`
+	Page       int                    `json:"page"`
+	PageSize   int                    `json:"page_size"`
+	TotalPages int                    `json:"total_pages"`
+	Links      *PaginationLinks       `json:"links,omitempty"`
 }
 
 // WorkspaceMemberListResponse represents a list of workspace members
@@ -242,6 +913,7 @@ type WorkspaceMemberListResponse struct {
 	Page       int                `json:"page"`
 	PageSize   int                `json:"page_size"`
 	TotalPages int                `json:"total_pages"`
+	Links      *PaginationLinks   `json:"links,omitempty"`
 }
 
 // AuditLogListResponse represents a paginated list of audit logs
@@ -251,6 +923,21 @@ type AuditLogListResponse struct {
 	Page       int                  `json:"page"`
 	PageSize   int                  `json:"page_size"`
 	TotalPages int                  `json:"total_pages"`
+	Links      *PaginationLinks     `json:"links,omitempty"`
+	NextCursor string               `json:"next_cursor,omitempty"`
+}
+
+// AuditFacetCount is one distinct value found in a workspace's audit log and how many entries carry it
+type AuditFacetCount struct {
+	Value string `json:"value"`
+	Count int64  `json:"count"`
+}
+
+// AuditLogFacets lists the actions and acting users that appear in a workspace's audit log,
+// most frequent first
+type AuditLogFacets struct {
+	Actions []AuditFacetCount `json:"actions"`
+	Users   []AuditFacetCount `json:"users"`
 }
 
 // Filter Models
@@ -265,43 +952,171 @@ type WorkspaceFilter struct {
 	SortBy         string `query:"sort_by"`
 	SortOrder      string `query:"sort_order"`
 	IncludeDeleted bool   `query:"include_deleted"`
+	CountOnly      bool   `query:"count_only"`
+	// ModifiedSince is an RFC3339 time; only workspaces updated, or with IncludeDeleted
+	// deleted, after it are listed
+	ModifiedSince string `query:"modified_since"`
+	// ModifiedAfter is ModifiedSince parsed by the service
+	ModifiedAfter *time.Time `query:"-"`
+	// AccessedBy is the user whose last access orders the list when SortBy is last_accessed
+	AccessedBy string `query:"-"`
+	// DeletedVisibleTo limits the deleted workspaces IncludeDeleted lists to those this user
+	// administers; empty lists all of them
+	DeletedVisibleTo string `query:"-"`
 }
 
+// WorkspaceSortLastAccessed orders workspaces by the caller's last access, most recent first,
+// with workspaces the caller never opened last
+const WorkspaceSortLastAccessed = "last_accessed"
+
 // ProjectFilter represents filters for listing projects
 type ProjectFilter struct {
 	WorkspaceID    string `query:"workspace_id"`
 	Status         string `query:"status"`
 	Search         string `query:"search"`
 	CreatedBy      string `query:"created_by"`
+	Include        string `query:"include"` // comma-separated: base_count
 	Page           int    `query:"page"`
 	PageSize       int    `query:"page_size"`
 	SortBy         string `query:"sort_by"`
 	SortOrder      string `query:"sort_order"`
 	IncludeDeleted bool   `query:"include_deleted"`
+	GroupBy        string `query:"group_by"` // workspace
+	CountOnly      bool   `query:"count_only"`
+	// DeletedVisibleTo limits the deleted projects IncludeDeleted lists to workspaces this user
+	// administers; empty lists all of them
+	DeletedVisibleTo string `query:"-"`
 }
 
-// AirtableBaseFilter represents filters for listing Airtable bases
-type AirtableBaseFilter struct {
-	ProjectID   string `query:"project_id"`
-	SyncEnabled *bool  `query:"sync_enabled"`
-	Search      string `query:"search"`
-	Page        int    `query:"page"`
-	PageSize    int    `query:"page_size"`
-	SortBy      string `query:"sort_by"`
-	SortOrder   string `query:"sort_order"`
+// ProjectGroupByWorkspace groups a project listing under the caller's workspaces
+const ProjectGroupByWorkspace = "workspace"
+
+// Includes reports whether the named expansion was requested via include
+func (f *ProjectFilter) Includes(name string) bool {
+	return HasInclude(f.Include, name)
 }
 
-// AuditLogFilter represents filters for listing audit logs
-type AuditLogFilter struct {
-	WorkspaceID  string `query:"workspace_id"`
-	UserID       string `query:"user_id"`
-	Action       string `query:"action"`
-	ResourceType string `query:"resource_type"`
-	ResourceID   string `query:"resource_id"`
+// AirtableBaseFilter represents filters for listing Airtable bases
+type AirtableBaseFilter struct {
+	ProjectID    string `query:"project_id"`
+	SyncEnabled  *bool  `query:"sync_enabled"`
+	Search       string `query:"search"`
 	Page         int    `query:"page"`
 	PageSize     int    `query:"page_size"`
 	SortBy       string `query:"sort_by"`
 	SortOrder    string `query:"sort_order"`
+	CountOnly    bool   `query:"count_only"`
+	AccessibleBy string `query:"-"` // restrict to bases in workspaces this user is a member of
+}
+
+// AuditLogFilter represents filters for listing audit logs
+type AuditLogFilter struct {
+	WorkspaceID  string   `query:"workspace_id"` // one ID, or several separated by commas
+	WorkspaceIDs []string `query:"-"`            // authorized workspaces of a multi-workspace query
+	UserID       string   `query:"user_id"`
+	Action       string   `query:"action"`
+	ResourceType string   `query:"resource_type"`
+	ResourceID   string   `query:"resource_id"`
+	ChangedField string   `query:"changed_field"` // top-level key present in changes
+	OperationID  string   `query:"operation_id"`
+	Cursor       string   `query:"cursor"`        // keyset cursor on (created_at, id); takes precedence over page
+	Page         int      `query:"page"`
+	PageSize     int      `query:"page_size"`
+	SortBy       string   `query:"sort_by"`
+	SortOrder    string   `query:"sort_order"`
+	CountOnly    bool     `query:"count_only"`
+}
+
+// HasInclude reports whether a comma-separated include list contains name
+func HasInclude(include, name string) bool {
+	for _, part := range strings.Split(include, ",") {
+		if strings.TrimSpace(part) == name {
+			return true
+		}
+	}
+	return false
+}
+
+// MemberImpact summarizes the resources attributed to a member via created_by
+type MemberImpact struct {
+	WorkspaceID            string              `json:"workspace_id"`
+	UserID                 string              `json:"user_id"`
+	Role                   WorkspaceMemberRole `json:"role"`
+	ProjectsCreated        int64               `json:"projects_created"`
+	AirtableBasesConnected int64               `json:"airtable_bases_connected"`
+}
+
+// MaintenanceStatus reports whether the service is rejecting writes for maintenance
+type MaintenanceStatus struct {
+	// Mode is the maintenance mode in effect, empty when writes are accepted
+	Mode string `json:"mode"`
+	// Configured is true when MAINTENANCE_MODE sets the mode, which the admin toggle cannot lift
+	Configured bool `json:"configured"`
+}
+
+// SetMaintenanceModeRequest sets the admin maintenance toggle; an empty mode clears it
+type SetMaintenanceModeRequest struct {
+	Mode string `json:"mode"`
+}
+
+// Quota Models
+
+// QuotaUsage reports how much of one quota is used. Limit and Remaining are null when the quota
+// is unlimited.
+type QuotaUsage struct {
+	Used      int64  `json:"used"`
+	Limit     *int64 `json:"limit"`
+	Remaining *int64 `json:"remaining"`
+}
+
+// NewQuotaUsage reports used against limit, where a limit of zero or less is unlimited
+func NewQuotaUsage(used int64, limit int) QuotaUsage {
+	usage := QuotaUsage{Used: used}
+	if limit > 0 {
+		max := int64(limit)
+		remaining := max - used
+		if remaining < 0 {
+			remaining = 0
+		}
+		usage.Limit = &max
+		usage.Remaining = &remaining
+	}
+	return usage
+}
+
+// TenantQuota reports a tenant's workspace quota
+type TenantQuota struct {
+	TenantID   string     `json:"tenant_id"`
+	Workspaces QuotaUsage `json:"workspaces"`
+}
+
+// WorkspaceQuota reports the quotas of what a workspace holds
+type WorkspaceQuota struct {
+	WorkspaceID   string     `json:"workspace_id"`
+	Projects      QuotaUsage `json:"projects"`
+	Members       QuotaUsage `json:"members"`
+	AirtableBases QuotaUsage `json:"airtable_bases"`
+}
+
+// Project access sources
+const (
+	ProjectAccessSourceWorkspaceMember = "workspace_member"
+	ProjectAccessSourceServiceAccount  = "service_account"
+)
+
+// ProjectAccessEntry is one principal able to see a project, with the role it resolves to
+type ProjectAccessEntry struct {
+	UserID string              `json:"user_id"`
+	Role   WorkspaceMemberRole `json:"role"`
+	Source string              `json:"source"`
+}
+
+// ProjectAccess lists everyone with effective access to a project
+type ProjectAccess struct {
+	ProjectID   string                `json:"project_id"`
+	WorkspaceID string                `json:"workspace_id"`
+	Users       []*ProjectAccessEntry `json:"users"`
+	Total       int                   `json:"total"`
 }
 
 // Statistics Models
@@ -314,6 +1129,36 @@ type WorkspaceStats struct {
 	ActiveProjects       int64              `json:"active_projects"`
 	TotalAirtableBases   int64              `json:"total_airtable_bases"`
 	WorkspacesByTenant   map[string]int64   `json:"workspaces_by_tenant"`
+	TotalTenants         int64              `json:"total_tenants,omitempty"`
+	TenantPage           int                `json:"tenant_page,omitempty"`
+	TenantPageSize       int                `json:"tenant_page_size,omitempty"`
 	ProjectsByStatus     map[string]int64   `json:"projects_by_status"`
 	LastUpdated          time.Time          `json:"last_updated"`
+}
+
+// WorkspaceTrends summarizes workspace sizes and daily creation counts over a window
+type WorkspaceTrends struct {
+	AvgProjectsPerWorkspace   float64          `json:"avg_projects_per_workspace"`
+	MedianMembersPerWorkspace float64          `json:"median_members_per_workspace"`
+	Days                      int              `json:"days"`
+	Series                    []DailyCreations `json:"series"`
+	LastUpdated               time.Time        `json:"last_updated"`
+}
+
+// DailyCreations counts workspaces and projects created on one UTC day
+type DailyCreations struct {
+	Date       string `json:"date"`
+	Workspaces int64  `json:"workspaces"`
+	Projects   int64  `json:"projects"`
+}
+
+// WorkspaceTrendsFilter sets the number of days covered by the trend series
+type WorkspaceTrendsFilter struct {
+	Days int `query:"days"`
+}
+
+// WorkspaceStatsFilter selects the page of the per-tenant breakdown in global statistics
+type WorkspaceStatsFilter struct {
+	TenantPage     int `query:"tenant_page"`
+	TenantPageSize int `query:"tenant_page_size"`
 }
\ No newline at end of file
@@ -2,45 +2,48 @@ package repositories
 
 import (
 	"context"
-	"fmt"
 	"strings"
 	"time"
 
 	"go.uber.org/zap"
 	"gorm.io/gorm"
 
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/config"
 	"github.com/Reg-Kris/pyairtable-workspace-service/internal/models"
+	"github.com/Reg-Kris/pyairtable-workspace-service/pkg/database"
 )
 
 type airtableBaseRepository struct {
 	db     *gorm.DB
 	logger *zap.Logger
+	retry  database.RetryPolicy
+	sorts  config.SortConfig
 }
 
 // NewAirtableBaseRepository creates a new Airtable base repository
-func NewAirtableBaseRepository(db *gorm.DB, logger *zap.Logger) AirtableBaseRepository {
+func NewAirtableBaseRepository(db *gorm.DB, config *config.Config, logger *zap.Logger) AirtableBaseRepository {
 	return &airtableBaseRepository{
 		db:     db,
 		logger: logger,
+		retry:  retryPolicy(config),
+		sorts:  sortConfig(config),
 	}
 }
 
 // Create creates a new Airtable base connection
 func (r *airtableBaseRepository) Create(ctx context.Context, base *models.AirtableBase) error {
 	// Check if base is already connected to the project
-	var count int64
-	if err := r.db.WithContext(ctx).Model(&models.AirtableBase{}).
-		Where("project_id = ? AND base_id = ? AND deleted_at IS NULL", base.ProjectID, base.BaseID).
-		Count(&count).Error; err != nil {
-		r.logger.Error("Failed to check duplicate airtable base", zap.Error(err))
+	if err := uniqueAirtableBase.check(ctx, r.db, nameMatching{}, "", base.BaseID, base.ProjectID); err != nil {
+		if err != ErrDuplicateAirtableBase {
+			r.logger.Error("Failed to check duplicate airtable base", zap.Error(err))
+		}
 		return err
 	}
-	
-	if count > 0 {
-		return ErrDuplicateAirtableBase
-	}
 
 	if err := r.db.WithContext(ctx).Create(base).Error; err != nil {
+		if err = uniqueAirtableBase.violation(err); err == ErrDuplicateAirtableBase {
+			return err
+		}
 		r.logger.Error("Failed to create airtable base", zap.Error(err))
 		return err
 	}
@@ -51,11 +54,13 @@ func (r *airtableBaseRepository) Create(ctx context.Context, base *models.Airtab
 // GetByID retrieves an Airtable base by ID
 func (r *airtableBaseRepository) GetByID(ctx context.Context, id string) (*models.AirtableBase, error) {
 	var base models.AirtableBase
-	if err := r.db.WithContext(ctx).
-		Preload("Project").
-		Preload("Project.Workspace").
-		Where("id = ? AND deleted_at IS NULL", id).
-		First(&base).Error; err != nil {
+	if err := retryRead(ctx, r.retry, func() error {
+		return r.db.WithContext(ctx).
+			Preload("Project").
+			Preload("Project.Workspace").
+			Where("id = ? AND deleted_at IS NULL", id).
+			First(&base).Error
+	}); err != nil {
 		if err == gorm.ErrRecordNotFound {
 			return nil, ErrAirtableBaseNotFound
 		}
@@ -69,9 +74,11 @@ func (r *airtableBaseRepository) GetByID(ctx context.Context, id string) (*model
 // GetByProjectAndBaseID retrieves an Airtable base by project ID and base ID
 func (r *airtableBaseRepository) GetByProjectAndBaseID(ctx context.Context, projectID, baseID string) (*models.AirtableBase, error) {
 	var base models.AirtableBase
-	if err := r.db.WithContext(ctx).
-		Where("project_id = ? AND base_id = ? AND deleted_at IS NULL", projectID, baseID).
-		First(&base).Error; err != nil {
+	if err := retryRead(ctx, r.retry, func() error {
+		return r.db.WithContext(ctx).
+			Where("project_id = ? AND base_id = ? AND deleted_at IS NULL", projectID, baseID).
+			First(&base).Error
+	}); err != nil {
 		if err == gorm.ErrRecordNotFound {
 			return nil, ErrAirtableBaseNotFound
 		}
@@ -82,12 +89,44 @@ func (r *airtableBaseRepository) GetByProjectAndBaseID(ctx context.Context, proj
 	return &base, nil
 }
 
-// Update updates an Airtable base
+// FindByBaseID returns every live connection of an Airtable base across projects and
+// workspaces, oldest first
+func (r *airtableBaseRepository) FindByBaseID(ctx context.Context, baseID string) ([]*models.AirtableBase, error) {
+	var bases []*models.AirtableBase
+	if err := retryRead(ctx, r.retry, func() error {
+		return r.db.WithContext(ctx).
+			Preload("Project").
+			Preload("Project.Workspace").
+			Where("base_id = ? AND deleted_at IS NULL", baseID).
+			Order("created_at ASC, id ASC").
+			Find(&bases).Error
+	}); err != nil {
+		r.logger.Error("Failed to find airtable bases by base ID", zap.Error(err), zap.String("base_id", baseID))
+		return nil, err
+	}
+
+	return bases, nil
+}
+
+// Update writes an Airtable base's editable fields. They are passed as a map because Updates
+// with a struct skips zero values, which would drop sync_enabled=false and cleared descriptions.
 func (r *airtableBaseRepository) Update(ctx context.Context, base *models.AirtableBase) error {
-	result := r.db.WithContext(ctx).Model(base).Updates(base)
-	if result.Error != nil {
-		r.logger.Error("Failed to update airtable base", zap.Error(result.Error))
+	updates := map[string]interface{}{
+		"name":         base.Name,
+		"description":  base.Description,
+		"sync_enabled": base.SyncEnabled,
+	}
+	if base.Settings != nil {
+		updates["settings"] = base.Settings
+	}
+
+	var result *gorm.DB
+	if err := retryWrite(ctx, r.retry, func() error {
+		result = r.db.WithContext(ctx).Model(base).Updates(updates)
 		return result.Error
+	}); err != nil {
+		r.logger.Error("Failed to update airtable base", zap.Error(err))
+		return err
 	}
 
 	if result.RowsAffected == 0 {
@@ -99,10 +138,13 @@ func (r *airtableBaseRepository) Update(ctx context.Context, base *models.Airtab
 
 // Delete soft deletes an Airtable base
 func (r *airtableBaseRepository) Delete(ctx context.Context, id string) error {
-	result := r.db.WithContext(ctx).Where("id = ?", id).Delete(&models.AirtableBase{})
-	if result.Error != nil {
-		r.logger.Error("Failed to delete airtable base", zap.Error(result.Error))
+	var result *gorm.DB
+	if err := retryWrite(ctx, r.retry, func() error {
+		result = r.db.WithContext(ctx).Where("id = ?", id).Delete(&models.AirtableBase{})
 		return result.Error
+	}); err != nil {
+		r.logger.Error("Failed to delete airtable base", zap.Error(err))
+		return err
 	}
 
 	if result.RowsAffected == 0 {
@@ -125,6 +167,15 @@ func (r *airtableBaseRepository) List(ctx context.Context, filter *models.Airtab
 		query = query.Where("sync_enabled = ?", *filter.SyncEnabled)
 	}
 
+	if filter.AccessibleBy != "" {
+		accessible := r.db.WithContext(ctx).Table("projects").
+			Select("projects.id").
+			Joins("JOIN workspaces ON workspaces.id = projects.workspace_id").
+			Joins("JOIN workspace_members ON workspace_members.workspace_id = workspaces.id").
+			Where("workspace_members.user_id = ? AND projects.deleted_at IS NULL AND workspaces.deleted_at IS NULL", filter.AccessibleBy)
+		query = query.Where("project_id IN (?)", accessible)
+	}
+
 	if filter.Search != "" {
 		search := "%" + strings.ToLower(filter.Search) + "%"
 		query = query.Where("LOWER(name) LIKE ? OR LOWER(description) LIKE ? OR LOWER(base_id) LIKE ?", search, search, search)
@@ -135,23 +186,20 @@ func (r *airtableBaseRepository) List(ctx context.Context, filter *models.Airtab
 
 	// Count total records
 	var total int64
-	if err := query.Count(&total).Error; err != nil {
+	if err := retryRead(ctx, r.retry, func() error {
+		return query.Count(&total).Error
+	}); err != nil {
 		r.logger.Error("Failed to count airtable bases", zap.Error(err))
 		return nil, 0, err
 	}
 
-	// Apply sorting
-	sortBy := "created_at"
-	if filter.SortBy != "" {
-		sortBy = filter.SortBy
-	}
-	
-	sortOrder := "DESC"
-	if filter.SortOrder != "" && strings.ToUpper(filter.SortOrder) == "ASC" {
-		sortOrder = "ASC"
+	if filter.CountOnly {
+		return nil, total, nil
 	}
-	
-	query = query.Order(fmt.Sprintf("%s %s", sortBy, sortOrder))
+
+	// Apply sorting
+	sortBy, sortOrder := listSort(r.sorts, config.SortListAirtableBases, filter.SortBy, filter.SortOrder)
+	query = query.Order(orderWithTiebreaker(sortBy, sortOrder))
 
 	// Apply pagination
 	page := filter.Page
@@ -175,7 +223,9 @@ func (r *airtableBaseRepository) List(ctx context.Context, filter *models.Airtab
 
 	// Fetch bases
 	var bases []*models.AirtableBase
-	if err := query.Find(&bases).Error; err != nil {
+	if err := retryRead(ctx, r.retry, func() error {
+		return query.Find(&bases).Error
+	}); err != nil {
 		r.logger.Error("Failed to list airtable bases", zap.Error(err))
 		return nil, 0, err
 	}
@@ -185,13 +235,18 @@ func (r *airtableBaseRepository) List(ctx context.Context, filter *models.Airtab
 
 // UpdateSyncTime updates the last sync time for an Airtable base
 func (r *airtableBaseRepository) UpdateSyncTime(ctx context.Context, id string, syncTime time.Time) error {
-	result := r.db.WithContext(ctx).Model(&models.AirtableBase{}).
-		Where("id = ? AND deleted_at IS NULL", id).
-		Update("last_sync_at", syncTime)
-		
-	if result.Error != nil {
-		r.logger.Error("Failed to update sync time", zap.Error(result.Error))
+	var result *gorm.DB
+	if err := retryWrite(ctx, r.retry, func() error {
+		result = r.db.WithContext(ctx).Model(&models.AirtableBase{}).
+			Where("id = ? AND deleted_at IS NULL", id).
+			Updates(map[string]interface{}{
+				"last_sync_at": syncTime,
+				"sync_status":  models.AirtableSyncStatusIdle,
+			})
 		return result.Error
+	}); err != nil {
+		r.logger.Error("Failed to update sync time", zap.Error(err))
+		return err
 	}
 
 	if result.RowsAffected == 0 {
@@ -199,4 +254,126 @@ func (r *airtableBaseRepository) UpdateSyncTime(ctx context.Context, id string,
 	}
 
 	return nil
-}
\ No newline at end of file
+}
+
+// RecordSync records the outcome of a sync attempt in a single UPDATE: its status, error and
+// duration, and the attempt time. last_sync_at only advances when the sync left the base idle,
+// so it keeps pointing at the last successful sync.
+func (r *airtableBaseRepository) RecordSync(ctx context.Context, id, status, syncErr string, durationMs int, syncTime time.Time) error {
+	updates := map[string]interface{}{
+		"sync_status":           status,
+		"last_sync_error":       syncErr,
+		"last_sync_duration_ms": durationMs,
+		"last_sync_attempt_at":  syncTime,
+	}
+	if status == models.AirtableSyncStatusIdle {
+		updates["last_sync_at"] = syncTime
+	}
+
+	var result *gorm.DB
+	if err := retryWrite(ctx, r.retry, func() error {
+		result = r.db.WithContext(ctx).Model(&models.AirtableBase{}).
+			Where("id = ? AND deleted_at IS NULL", id).
+			Updates(updates)
+		return result.Error
+	}); err != nil {
+		r.logger.Error("Failed to record sync", zap.Error(err), zap.String("id", id))
+		return err
+	}
+
+	if result.RowsAffected == 0 {
+		return ErrAirtableBaseNotFound
+	}
+
+	return nil
+}
+
+// CountByWorkspace counts the live Airtable bases across a workspace's live projects
+func (r *airtableBaseRepository) CountByWorkspace(ctx context.Context, workspaceID string) (int64, error) {
+	var count int64
+	if err := retryRead(ctx, r.retry, func() error {
+		return r.db.WithContext(ctx).
+			Table("airtable_bases").
+			Joins("JOIN projects ON airtable_bases.project_id = projects.id").
+			Where("projects.workspace_id = ?", workspaceID).
+			Where("airtable_bases.deleted_at IS NULL AND projects.deleted_at IS NULL").
+			Count(&count).Error
+	}); err != nil {
+		r.logger.Error("Failed to count airtable bases by workspace", zap.Error(err))
+		return 0, err
+	}
+
+	return count, nil
+}
+
+// CountByCreator counts Airtable bases across a workspace's projects connected by a user
+func (r *airtableBaseRepository) CountByCreator(ctx context.Context, workspaceID, userID string) (int64, error) {
+	var count int64
+	if err := retryRead(ctx, r.retry, func() error {
+		return r.db.WithContext(ctx).
+			Table("airtable_bases").
+			Joins("JOIN projects ON airtable_bases.project_id = projects.id").
+			Where("projects.workspace_id = ? AND airtable_bases.created_by = ?", workspaceID, userID).
+			Where("airtable_bases.deleted_at IS NULL AND projects.deleted_at IS NULL").
+			Count(&count).Error
+	}); err != nil {
+		r.logger.Error("Failed to count airtable bases by creator", zap.Error(err))
+		return 0, err
+	}
+
+	return count, nil
+}
+
+// ReassignCreator moves attribution of a user's connected bases in a workspace to another user
+func (r *airtableBaseRepository) ReassignCreator(ctx context.Context, workspaceID, fromUserID, toUserID string) (int64, error) {
+	projectIDs := r.db.WithContext(ctx).Model(&models.Project{}).
+		Select("id").
+		Where("workspace_id = ?", workspaceID)
+
+	var result *gorm.DB
+	if err := retryWrite(ctx, r.retry, func() error {
+		result = r.db.WithContext(ctx).Model(&models.AirtableBase{}).
+			Where("project_id IN (?) AND created_by = ?", projectIDs, fromUserID).
+			Update("created_by", toUserID)
+		return result.Error
+	}); err != nil {
+		r.logger.Error("Failed to reassign airtable base creator", zap.Error(err))
+		return 0, err
+	}
+
+	return result.RowsAffected, nil
+}
+
+// DeleteByProject deletes every Airtable base connected to a project
+func (r *airtableBaseRepository) DeleteByProject(ctx context.Context, projectID string) (int64, error) {
+	var result *gorm.DB
+	if err := retryWrite(ctx, r.retry, func() error {
+		result = r.db.WithContext(ctx).Where("project_id = ?", projectID).Delete(&models.AirtableBase{})
+		return result.Error
+	}); err != nil {
+		r.logger.Error("Failed to delete airtable bases for project", zap.Error(err))
+		return 0, err
+	}
+
+	return result.RowsAffected, nil
+}
+
+// SetSyncEnabledForWorkspace sets sync_enabled on every live base across a workspace's projects
+func (r *airtableBaseRepository) SetSyncEnabledForWorkspace(ctx context.Context, workspaceID string, enabled bool) (int64, error) {
+	projectIDs := r.db.WithContext(ctx).Model(&models.Project{}).
+		Select("id").
+		Where("workspace_id = ? AND deleted_at IS NULL", workspaceID)
+
+	var result *gorm.DB
+	if err := retryWrite(ctx, r.retry, func() error {
+		result = r.db.WithContext(ctx).Model(&models.AirtableBase{}).
+			Where("project_id IN (?) AND deleted_at IS NULL", projectIDs).
+			Update("sync_enabled", enabled)
+		return result.Error
+	}); err != nil {
+		r.logger.Error("Failed to set workspace sync", zap.Error(err))
+		return 0, err
+	}
+
+	return result.RowsAffected, nil
+}
@@ -2,6 +2,7 @@ package repositories
 
 import (
 	"context"
+	"encoding/base64"
 	"fmt"
 	"strings"
 	"time"
@@ -9,19 +10,25 @@ import (
 	"go.uber.org/zap"
 	"gorm.io/gorm"
 
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/config"
 	"github.com/Reg-Kris/pyairtable-workspace-service/internal/models"
+	"github.com/Reg-Kris/pyairtable-workspace-service/pkg/database"
 )
 
 type auditLogRepository struct {
 	db     *gorm.DB
 	logger *zap.Logger
+	retry  database.RetryPolicy
+	sorts  config.SortConfig
 }
 
 // NewAuditLogRepository creates a new audit log repository
-func NewAuditLogRepository(db *gorm.DB, logger *zap.Logger) AuditLogRepository {
+func NewAuditLogRepository(db *gorm.DB, config *config.Config, logger *zap.Logger) AuditLogRepository {
 	return &auditLogRepository{
 		db:     db,
 		logger: logger,
+		retry:  retryPolicy(config),
+		sorts:  sortConfig(config),
 	}
 }
 
@@ -35,6 +42,20 @@ func (r *auditLogRepository) Create(ctx context.Context, log *models.WorkspaceAu
 	return nil
 }
 
+// CreateBatch inserts audit log entries in a single statement
+func (r *auditLogRepository) CreateBatch(ctx context.Context, logs []*models.WorkspaceAuditLog) error {
+	if len(logs) == 0 {
+		return nil
+	}
+
+	if err := r.db.WithContext(ctx).Create(&logs).Error; err != nil {
+		r.logger.Error("Failed to create audit log batch", zap.Error(err), zap.Int("count", len(logs)))
+		return err
+	}
+
+	return nil
+}
+
 // List retrieves audit logs based on filter
 func (r *auditLogRepository) List(ctx context.Context, filter *models.AuditLogFilter) ([]*models.WorkspaceAuditLog, int64, error) {
 	query := r.db.WithContext(ctx).Model(&models.WorkspaceAuditLog{})
@@ -44,6 +65,10 @@ func (r *auditLogRepository) List(ctx context.Context, filter *models.AuditLogFi
 		query = query.Where("workspace_id = ?", filter.WorkspaceID)
 	}
 
+	if len(filter.WorkspaceIDs) > 0 {
+		query = query.Where("workspace_id IN ?", filter.WorkspaceIDs)
+	}
+
 	if filter.UserID != "" {
 		query = query.Where("user_id = ?", filter.UserID)
 	}
@@ -60,25 +85,36 @@ func (r *auditLogRepository) List(ctx context.Context, filter *models.AuditLogFi
 		query = query.Where("resource_id = ?", filter.ResourceID)
 	}
 
+	if filter.OperationID != "" {
+		query = query.Where("operation_id = ?", filter.OperationID)
+	}
+
+	if filter.ChangedField != "" {
+		// jsonb_exists backs the ? operator, which GORM would read as a placeholder
+		query = query.Where("jsonb_exists(changes, ?)", filter.ChangedField)
+	}
+
 	// Count total records
 	var total int64
-	if err := query.Count(&total).Error; err != nil {
+	if err := retryRead(ctx, r.retry, func() error {
+		return query.Count(&total).Error
+	}); err != nil {
 		r.logger.Error("Failed to count audit logs", zap.Error(err))
 		return nil, 0, err
 	}
 
-	// Apply sorting
-	sortBy := "created_at"
-	if filter.SortBy != "" {
-		sortBy = filter.SortBy
+	if filter.CountOnly {
+		return nil, total, nil
 	}
-	
-	sortOrder := "DESC"
-	if filter.SortOrder != "" && strings.ToUpper(filter.SortOrder) == "ASC" {
-		sortOrder = "ASC"
+
+	// Apply sorting; cursors are keyset on created_at, so they ignore other sort columns
+	sortBy, sortOrder := listSort(r.sorts, config.SortListAuditLogs, filter.SortBy, filter.SortOrder)
+	if filter.Cursor != "" {
+		sortBy = "created_at"
 	}
 	
-	query = query.Order(fmt.Sprintf("%s %s", sortBy, sortOrder))
+	// Many entries share the now() default, so id breaks ties to keep pages stable
+	query = query.Order(fmt.Sprintf("%s %s, id %s", sortBy, sortOrder, sortOrder))
 
 	// Apply pagination
 	page := filter.Page
@@ -94,12 +130,28 @@ func (r *auditLogRepository) List(ctx context.Context, filter *models.AuditLogFi
 		pageSize = 100
 	}
 	
-	offset := (page - 1) * pageSize
-	query = query.Offset(offset).Limit(pageSize)
+	if filter.Cursor != "" {
+		// Keyset pagination continues strictly after the last (created_at, id) seen
+		createdAt, id, err := DecodeAuditLogCursor(filter.Cursor)
+		if err != nil {
+			return nil, 0, err
+		}
+
+		op := "<"
+		if sortOrder == "ASC" {
+			op = ">"
+		}
+		query = query.Where(fmt.Sprintf("(created_at, id) %s (?, ?)", op), createdAt, id).Limit(pageSize)
+	} else {
+		offset := (page - 1) * pageSize
+		query = query.Offset(offset).Limit(pageSize)
+	}
 
 	// Fetch logs
 	var logs []*models.WorkspaceAuditLog
-	if err := query.Find(&logs).Error; err != nil {
+	if err := retryRead(ctx, r.retry, func() error {
+		return query.Find(&logs).Error
+	}); err != nil {
 		r.logger.Error("Failed to list audit logs", zap.Error(err))
 		return nil, 0, err
 	}
@@ -107,17 +159,102 @@ func (r *auditLogRepository) List(ctx context.Context, filter *models.AuditLogFi
 	return logs, total, nil
 }
 
-// DeleteOlderThan deletes audit logs older than specified days
-func (r *auditLogRepository) DeleteOlderThan(ctx context.Context, days int) error {
+// EncodeAuditLogCursor builds an opaque keyset cursor pointing at the given log entry
+func EncodeAuditLogCursor(log *models.WorkspaceAuditLog) string {
+	raw := log.CreatedAt.UTC().Format(time.RFC3339Nano) + "|" + log.ID
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// DecodeAuditLogCursor parses a cursor produced by EncodeAuditLogCursor
+func DecodeAuditLogCursor(cursor string) (time.Time, string, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return time.Time{}, "", ErrInvalidCursor
+	}
+
+	parts := strings.SplitN(string(raw), "|", 2)
+	if len(parts) != 2 || parts[1] == "" {
+		return time.Time{}, "", ErrInvalidCursor
+	}
+
+	createdAt, err := time.Parse(time.RFC3339Nano, parts[0])
+	if err != nil {
+		return time.Time{}, "", ErrInvalidCursor
+	}
+
+	return createdAt, parts[1], nil
+}
+
+// Facets counts the distinct actions and acting users in a workspace's audit log
+func (r *auditLogRepository) Facets(ctx context.Context, workspaceID string) (*models.AuditLogFacets, error) {
+	actions, err := r.facetCounts(ctx, workspaceID, "action")
+	if err != nil {
+		return nil, err
+	}
+
+	users, err := r.facetCounts(ctx, workspaceID, "user_id")
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.AuditLogFacets{Actions: actions, Users: users}, nil
+}
+
+// facetCounts groups a workspace's audit entries by column, most frequent value first
+func (r *auditLogRepository) facetCounts(ctx context.Context, workspaceID, column string) ([]models.AuditFacetCount, error) {
+	var counts []models.AuditFacetCount
+	if err := retryRead(ctx, r.retry, func() error {
+		counts = []models.AuditFacetCount{}
+		return r.db.WithContext(ctx).Model(&models.WorkspaceAuditLog{}).
+			Select(column+" AS value, COUNT(*) AS count").
+			Where("workspace_id = ?", workspaceID).
+			Group(column).
+			Order("count DESC, value ASC").
+			Scan(&counts).Error
+	}); err != nil {
+		r.logger.Error("Failed to count audit log facets", zap.String("column", column), zap.Error(err))
+		return nil, err
+	}
+
+	return counts, nil
+}
+
+// MostActiveWorkspaces returns the IDs of up to limit workspaces with the most audit entries
+// since the given time, busiest first
+func (r *auditLogRepository) MostActiveWorkspaces(ctx context.Context, since time.Time, limit int) ([]string, error) {
+	var workspaceIDs []string
+	if err := retryRead(ctx, r.retry, func() error {
+		workspaceIDs = nil
+		return r.db.WithContext(ctx).Model(&models.WorkspaceAuditLog{}).
+			Where("created_at >= ?", since).
+			Group("workspace_id").
+			Order("COUNT(*) DESC, workspace_id ASC").
+			Limit(limit).
+			Pluck("workspace_id", &workspaceIDs).Error
+	}); err != nil {
+		r.logger.Error("Failed to find most active workspaces", zap.Error(err))
+		return nil, err
+	}
+
+	return workspaceIDs, nil
+}
+
+// DeleteOlderThan deletes audit logs older than specified days, except those of the given
+// workspaces, which are pruned on their own retention
+func (r *auditLogRepository) DeleteOlderThan(ctx context.Context, days int, exceptWorkspaceIDs []string) error {
 	cutoffDate := time.Now().AddDate(0, 0, -days)
 	
-	result := r.db.WithContext(ctx).
-		Where("created_at < ?", cutoffDate).
-		Delete(&models.WorkspaceAuditLog{})
-		
-	if result.Error != nil {
-		r.logger.Error("Failed to delete old audit logs", zap.Error(result.Error))
+	var result *gorm.DB
+	if err := retryWrite(ctx, r.retry, func() error {
+		query := r.db.WithContext(ctx).Where("created_at < ?", cutoffDate)
+		if len(exceptWorkspaceIDs) > 0 {
+			query = query.Where("workspace_id NOT IN ?", exceptWorkspaceIDs)
+		}
+		result = query.Delete(&models.WorkspaceAuditLog{})
 		return result.Error
+	}); err != nil {
+		r.logger.Error("Failed to delete old audit logs", zap.Error(err))
+		return err
 	}
 
 	r.logger.Info("Deleted old audit logs", 
@@ -125,4 +262,108 @@ func (r *auditLogRepository) DeleteOlderThan(ctx context.Context, days int) erro
 		zap.Time("before", cutoffDate))
 
 	return nil
-}
\ No newline at end of file
+}
+
+// DeleteWorkspaceOlderThan deletes one workspace's audit logs older than specified days
+func (r *auditLogRepository) DeleteWorkspaceOlderThan(ctx context.Context, workspaceID string, days int) error {
+	cutoffDate := time.Now().AddDate(0, 0, -days)
+
+	var result *gorm.DB
+	if err := retryWrite(ctx, r.retry, func() error {
+		result = r.db.WithContext(ctx).
+			Where("workspace_id = ? AND created_at < ?", workspaceID, cutoffDate).
+			Delete(&models.WorkspaceAuditLog{})
+		return result.Error
+	}); err != nil {
+		r.logger.Error("Failed to delete old workspace audit logs", zap.Error(err), zap.String("workspace_id", workspaceID))
+		return err
+	}
+
+	r.logger.Info("Deleted old workspace audit logs",
+		zap.String("workspace_id", workspaceID),
+		zap.Int64("count", result.RowsAffected),
+		zap.Time("before", cutoffDate))
+
+	return nil
+}
+
+// ListOlderThan retrieves up to limit of the oldest audit logs created before before, from one
+// workspace when workspaceID is set and otherwise from all but exceptWorkspaceIDs. Archival
+// reads the entries due for deletion this way, a batch at a time.
+func (r *auditLogRepository) ListOlderThan(ctx context.Context, before time.Time, workspaceID string, exceptWorkspaceIDs []string, limit int) ([]*models.WorkspaceAuditLog, error) {
+	var logs []*models.WorkspaceAuditLog
+	if err := retryRead(ctx, r.retry, func() error {
+		query := r.db.WithContext(ctx).Where("created_at < ?", before)
+		if workspaceID != "" {
+			query = query.Where("workspace_id = ?", workspaceID)
+		} else if len(exceptWorkspaceIDs) > 0 {
+			query = query.Where("workspace_id NOT IN ?", exceptWorkspaceIDs)
+		}
+		return query.Order("created_at ASC, id ASC").Limit(limit).Find(&logs).Error
+	}); err != nil {
+		r.logger.Error("Failed to list old audit logs", zap.Error(err))
+		return nil, err
+	}
+
+	return logs, nil
+}
+
+// DeleteByIDs deletes the given audit logs, returning how many were deleted
+func (r *auditLogRepository) DeleteByIDs(ctx context.Context, ids []string) (int64, error) {
+	if len(ids) == 0 {
+		return 0, nil
+	}
+
+	var result *gorm.DB
+	if err := retryWrite(ctx, r.retry, func() error {
+		result = r.db.WithContext(ctx).Where("id IN ?", ids).Delete(&models.WorkspaceAuditLog{})
+		return result.Error
+	}); err != nil {
+		r.logger.Error("Failed to delete audit logs", zap.Error(err), zap.Int("count", len(ids)))
+		return 0, err
+	}
+
+	return result.RowsAffected, nil
+}
+
+// ListActivity retrieves a page of the entries with one of actions from the live workspaces
+// userID is currently a member of, latest first. Workspaces the user has left are excluded by
+// the membership join, including what happened in them while the user was a member.
+func (r *auditLogRepository) ListActivity(ctx context.Context, userID string, actions []string, page, pageSize int) ([]*models.WorkspaceAuditLog, int64, error) {
+	query := r.db.WithContext(ctx).Model(&models.WorkspaceAuditLog{}).
+		Joins("JOIN workspace_members ON workspace_members.workspace_id = workspace_audit_logs.workspace_id AND workspace_members.user_id = ?", userID).
+		Joins("JOIN workspaces ON workspaces.id = workspace_audit_logs.workspace_id AND workspaces.deleted_at IS NULL").
+		Where("workspace_audit_logs.action IN ?", actions)
+
+	var total int64
+	if err := retryRead(ctx, r.retry, func() error {
+		return query.Count(&total).Error
+	}); err != nil {
+		r.logger.Error("Failed to count activity", zap.Error(err))
+		return nil, 0, err
+	}
+
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 {
+		pageSize = 20
+	}
+	if pageSize > 100 {
+		pageSize = 100
+	}
+
+	var logs []*models.WorkspaceAuditLog
+	if err := retryRead(ctx, r.retry, func() error {
+		return query.Select("workspace_audit_logs.*").
+			Order("workspace_audit_logs.created_at DESC, workspace_audit_logs.id DESC").
+			Offset((page - 1) * pageSize).
+			Limit(pageSize).
+			Find(&logs).Error
+	}); err != nil {
+		r.logger.Error("Failed to list activity", zap.Error(err))
+		return nil, 0, err
+	}
+
+	return logs, total, nil
+}
@@ -3,37 +3,71 @@ package repositories
 import (
 	"context"
 	"encoding/json"
+	"strconv"
 	"time"
 
 	"github.com/redis/go-redis/v9"
 	"go.uber.org/zap"
+	"gorm.io/gorm"
 
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/config"
 	"github.com/Reg-Kris/pyairtable-workspace-service/internal/models"
 )
 
+// CacheSchemaVersion is part of every cache key. Bump it whenever a cached model (Workspace,
+// Project, AirtableBaseMetadata, WorkspaceTrends, ...) gains, loses or changes a JSON field:
+// entries written by the previous release then no longer match any key, so they are treated
+// as misses and left to expire instead of being served with the old shape.
+const CacheSchemaVersion = 1
+
 const (
 	workspaceCachePrefix = "workspace:"
 	projectCachePrefix   = "project:"
 	userWorkspacePrefix  = "user:workspaces:"
+	baseMetadataPrefix   = "airtable_base:metadata:"
+	trendsCachePrefix    = "stats:trends:"
+	statsCachePrefix     = "stats:summary:"
+	activeUsersKey       = "users:active"
+	requestDedupPrefix   = "request:"
 	cacheTTL             = 5 * time.Minute
+
+	// maintenanceModeKey holds the admin maintenance toggle. It is not versioned so the
+	// toggle survives deploys that bump the cache schema.
+	maintenanceModeKey = "maintenance:mode"
 )
 
 type cacheRepository struct {
-	redis  *redis.Client
-	logger *zap.Logger
+	redis   *redis.Client
+	db      *gorm.DB
+	logger  *zap.Logger
+	version string
 }
 
-// NewCacheRepository creates a new cache repository
-func NewCacheRepository(redis *redis.Client, logger *zap.Logger) CacheRepository {
+// NewCacheRepository creates a new cache repository. The database is used to rebuild
+// derived entries such as a user's workspace list. Keys are namespaced by CacheSchemaVersion
+// and the configured Redis key version.
+func NewCacheRepository(redis *redis.Client, db *gorm.DB, config *config.Config, logger *zap.Logger) CacheRepository {
+	version := "v" + strconv.Itoa(CacheSchemaVersion)
+	if config != nil && config.Redis.KeyVersion != "" {
+		version += "-" + config.Redis.KeyVersion
+	}
+
 	return &cacheRepository{
-		redis:  redis,
-		logger: logger,
+		redis:   redis,
+		db:      db,
+		logger:  logger,
+		version: version + ":",
 	}
 }
 
+// key builds a versioned cache key
+func (r *cacheRepository) key(prefix, id string) string {
+	return r.version + prefix + id
+}
+
 // SetWorkspace caches a workspace
 func (r *cacheRepository) SetWorkspace(ctx context.Context, workspace *models.Workspace) error {
-	key := workspaceCachePrefix + workspace.ID
+	key := r.key(workspaceCachePrefix, workspace.ID)
 	
 	data, err := json.Marshal(workspace)
 	if err != nil {
@@ -51,7 +85,7 @@ func (r *cacheRepository) SetWorkspace(ctx context.Context, workspace *models.Wo
 
 // GetWorkspace retrieves a workspace from cache
 func (r *cacheRepository) GetWorkspace(ctx context.Context, id string) (*models.Workspace, error) {
-	key := workspaceCachePrefix + id
+	key := r.key(workspaceCachePrefix, id)
 	
 	data, err := r.redis.Get(ctx, key).Result()
 	if err != nil {
@@ -73,7 +107,7 @@ func (r *cacheRepository) GetWorkspace(ctx context.Context, id string) (*models.
 
 // DeleteWorkspace removes a workspace from cache
 func (r *cacheRepository) DeleteWorkspace(ctx context.Context, id string) error {
-	key := workspaceCachePrefix + id
+	key := r.key(workspaceCachePrefix, id)
 	
 	if err := r.redis.Del(ctx, key).Err(); err != nil {
 		r.logger.Error("Failed to delete workspace from cache", zap.Error(err))
@@ -85,7 +119,7 @@ func (r *cacheRepository) DeleteWorkspace(ctx context.Context, id string) error
 
 // SetProject caches a project
 func (r *cacheRepository) SetProject(ctx context.Context, project *models.Project) error {
-	key := projectCachePrefix + project.ID
+	key := r.key(projectCachePrefix, project.ID)
 	
 	data, err := json.Marshal(project)
 	if err != nil {
@@ -103,7 +137,7 @@ func (r *cacheRepository) SetProject(ctx context.Context, project *models.Projec
 
 // GetProject retrieves a project from cache
 func (r *cacheRepository) GetProject(ctx context.Context, id string) (*models.Project, error) {
-	key := projectCachePrefix + id
+	key := r.key(projectCachePrefix, id)
 	
 	data, err := r.redis.Get(ctx, key).Result()
 	if err != nil {
@@ -125,7 +159,7 @@ func (r *cacheRepository) GetProject(ctx context.Context, id string) (*models.Pr
 
 // DeleteProject removes a project from cache
 func (r *cacheRepository) DeleteProject(ctx context.Context, id string) error {
-	key := projectCachePrefix + id
+	key := r.key(projectCachePrefix, id)
 	
 	if err := r.redis.Del(ctx, key).Err(); err != nil {
 		r.logger.Error("Failed to delete project from cache", zap.Error(err))
@@ -145,7 +179,7 @@ func (r *cacheRepository) InvalidateWorkspaceCache(ctx context.Context, workspac
 	// Find and delete all projects in this workspace
 	// This is a simplified approach - in production, you might want to maintain
 	// a list of project IDs per workspace
-	pattern := projectCachePrefix + "*"
+	pattern := r.key(projectCachePrefix, "*")
 	iter := r.redis.Scan(ctx, 0, pattern, 100).Iterator()
 	
 	for iter.Next(ctx) {
@@ -176,7 +210,7 @@ func (r *cacheRepository) InvalidateWorkspaceCache(ctx context.Context, workspac
 
 // SetUserWorkspaces caches the list of workspace IDs for a user
 func (r *cacheRepository) SetUserWorkspaces(ctx context.Context, userID string, workspaceIDs []string) error {
-	key := userWorkspacePrefix + userID
+	key := r.key(userWorkspacePrefix, userID)
 	
 	data, err := json.Marshal(workspaceIDs)
 	if err != nil {
@@ -194,7 +228,7 @@ func (r *cacheRepository) SetUserWorkspaces(ctx context.Context, userID string,
 
 // GetUserWorkspaces retrieves the list of workspace IDs for a user from cache
 func (r *cacheRepository) GetUserWorkspaces(ctx context.Context, userID string) ([]string, error) {
-	key := userWorkspacePrefix + userID
+	key := r.key(userWorkspacePrefix, userID)
 	
 	data, err := r.redis.Get(ctx, key).Result()
 	if err != nil {
@@ -214,6 +248,149 @@ func (r *cacheRepository) GetUserWorkspaces(ctx context.Context, userID string)
 	return workspaceIDs, nil
 }
 
+// SetBaseMetadata caches Airtable base metadata for the given retention period
+func (r *cacheRepository) SetBaseMetadata(ctx context.Context, metadata *models.AirtableBaseMetadata, retention time.Duration) error {
+	key := r.key(baseMetadataPrefix, metadata.BaseID)
+	
+	data, err := json.Marshal(metadata)
+	if err != nil {
+		r.logger.Error("Failed to marshal base metadata", zap.Error(err))
+		return err
+	}
+
+	if err := r.redis.Set(ctx, key, data, retention).Err(); err != nil {
+		r.logger.Error("Failed to cache base metadata", zap.Error(err))
+		return err
+	}
+
+	return nil
+}
+
+// GetBaseMetadata retrieves Airtable base metadata from cache
+func (r *cacheRepository) GetBaseMetadata(ctx context.Context, baseID string) (*models.AirtableBaseMetadata, error) {
+	key := r.key(baseMetadataPrefix, baseID)
+	
+	data, err := r.redis.Get(ctx, key).Result()
+	if err != nil {
+		if err == redis.Nil {
+			return nil, nil // Cache miss
+		}
+		r.logger.Error("Failed to get base metadata from cache", zap.Error(err))
+		return nil, err
+	}
+
+	var metadata models.AirtableBaseMetadata
+	if err := json.Unmarshal([]byte(data), &metadata); err != nil {
+		r.logger.Error("Failed to unmarshal base metadata", zap.Error(err))
+		return nil, err
+	}
+
+	return &metadata, nil
+}
+
+// SetWorkspaceStats caches a tenant's workspace statistics
+func (r *cacheRepository) SetWorkspaceStats(ctx context.Context, tenantID string, stats *models.WorkspaceStats) error {
+	key := r.key(statsCachePrefix, tenantID)
+
+	data, err := json.Marshal(stats)
+	if err != nil {
+		r.logger.Error("Failed to marshal workspace stats", zap.Error(err))
+		return err
+	}
+
+	if err := r.redis.Set(ctx, key, data, cacheTTL).Err(); err != nil {
+		r.logger.Error("Failed to cache workspace stats", zap.Error(err))
+		return err
+	}
+
+	return nil
+}
+
+// GetWorkspaceStats retrieves a tenant's workspace statistics from cache
+func (r *cacheRepository) GetWorkspaceStats(ctx context.Context, tenantID string) (*models.WorkspaceStats, error) {
+	data, err := r.redis.Get(ctx, r.key(statsCachePrefix, tenantID)).Result()
+	if err != nil {
+		if err == redis.Nil {
+			return nil, nil // Cache miss
+		}
+		r.logger.Error("Failed to get workspace stats from cache", zap.Error(err))
+		return nil, err
+	}
+
+	var stats models.WorkspaceStats
+	if err := json.Unmarshal([]byte(data), &stats); err != nil {
+		r.logger.Error("Failed to unmarshal workspace stats", zap.Error(err))
+		return nil, err
+	}
+
+	return &stats, nil
+}
+
+// SetWorkspaceTrends caches a tenant's trend statistics for the window they cover
+func (r *cacheRepository) SetWorkspaceTrends(ctx context.Context, tenantID string, trends *models.WorkspaceTrends) error {
+	key := r.trendsKey(tenantID, trends.Days)
+
+	data, err := json.Marshal(trends)
+	if err != nil {
+		r.logger.Error("Failed to marshal workspace trends", zap.Error(err))
+		return err
+	}
+
+	if err := r.redis.Set(ctx, key, data, cacheTTL).Err(); err != nil {
+		r.logger.Error("Failed to cache workspace trends", zap.Error(err))
+		return err
+	}
+
+	return nil
+}
+
+// GetWorkspaceTrends retrieves a tenant's trend statistics from cache
+func (r *cacheRepository) GetWorkspaceTrends(ctx context.Context, tenantID string, days int) (*models.WorkspaceTrends, error) {
+	data, err := r.redis.Get(ctx, r.trendsKey(tenantID, days)).Result()
+	if err != nil {
+		if err == redis.Nil {
+			return nil, nil // Cache miss
+		}
+		r.logger.Error("Failed to get workspace trends from cache", zap.Error(err))
+		return nil, err
+	}
+
+	var trends models.WorkspaceTrends
+	if err := json.Unmarshal([]byte(data), &trends); err != nil {
+		r.logger.Error("Failed to unmarshal workspace trends", zap.Error(err))
+		return nil, err
+	}
+
+	return &trends, nil
+}
+
+func (r *cacheRepository) trendsKey(tenantID string, days int) string {
+	return r.key(trendsCachePrefix, tenantID+":"+strconv.Itoa(days))
+}
+
+// InvalidateTenantStats removes a tenant's cached statistics and every cached trends window
+func (r *cacheRepository) InvalidateTenantStats(ctx context.Context, tenantID string) error {
+	if err := r.redis.Del(ctx, r.key(statsCachePrefix, tenantID)).Err(); err != nil {
+		r.logger.Error("Failed to delete workspace stats from cache", zap.String("tenant_id", tenantID), zap.Error(err))
+		return err
+	}
+
+	iter := r.redis.Scan(ctx, 0, r.key(trendsCachePrefix, tenantID+":*"), 100).Iterator()
+	for iter.Next(ctx) {
+		if err := r.redis.Del(ctx, iter.Val()).Err(); err != nil {
+			r.logger.Error("Failed to delete cache key",
+				zap.String("key", iter.Val()),
+				zap.Error(err))
+		}
+	}
+	if err := iter.Err(); err != nil {
+		r.logger.Error("Failed to scan Redis keys", zap.String("tenant_id", tenantID), zap.Error(err))
+		return err
+	}
+
+	return nil
+}
+
 // Additional helper methods for cache warming and invalidation
 
 // WarmWorkspaceCache warms the cache with workspace data
@@ -221,7 +398,7 @@ func (r *cacheRepository) WarmWorkspaceCache(ctx context.Context, workspaces []*
 	pipe := r.redis.Pipeline()
 	
 	for _, workspace := range workspaces {
-		key := workspaceCachePrefix + workspace.ID
+		key := r.key(workspaceCachePrefix, workspace.ID)
 		data, err := json.Marshal(workspace)
 		if err != nil {
 			r.logger.Error("Failed to marshal workspace for warming", zap.Error(err))
@@ -238,9 +415,75 @@ func (r *cacheRepository) WarmWorkspaceCache(ctx context.Context, workspaces []*
 	return nil
 }
 
+// RebuildUserWorkspaces recomputes the user's workspace IDs from their live memberships
+// and overwrites the cached list. A user with no memberships has the entry removed so the
+// next read falls through to the database.
+func (r *cacheRepository) RebuildUserWorkspaces(ctx context.Context, userID string) ([]string, error) {
+	workspaceIDs := make([]string, 0)
+	if err := r.db.WithContext(ctx).
+		Table("workspace_members").
+		Joins("JOIN workspaces ON workspaces.id = workspace_members.workspace_id AND workspaces.deleted_at IS NULL").
+		Where("workspace_members.user_id = ?", userID).
+		Order("workspace_members.workspace_id ASC").
+		Pluck("workspace_members.workspace_id", &workspaceIDs).Error; err != nil {
+		r.logger.Error("Failed to load user workspaces for cache rebuild", zap.Error(err), zap.String("user_id", userID))
+		return nil, err
+	}
+
+	if len(workspaceIDs) == 0 {
+		if err := r.InvalidateUserCache(ctx, userID); err != nil {
+			return nil, err
+		}
+		return workspaceIDs, nil
+	}
+
+	if err := r.SetUserWorkspaces(ctx, userID, workspaceIDs); err != nil {
+		return nil, err
+	}
+
+	return workspaceIDs, nil
+}
+
+// MarkUserActive records that a user was seen at the given time, for the background refresh
+// of their workspace list
+func (r *cacheRepository) MarkUserActive(ctx context.Context, userID string, at time.Time) error {
+	key := r.key(activeUsersKey, "")
+
+	if err := r.redis.ZAdd(ctx, key, redis.Z{Score: float64(at.Unix()), Member: userID}).Err(); err != nil {
+		r.logger.Error("Failed to mark user active", zap.Error(err))
+		return err
+	}
+
+	return nil
+}
+
+// ActiveUsers returns up to limit users seen since the given time, most recent first. Users
+// last seen before it are dropped from the set.
+func (r *cacheRepository) ActiveUsers(ctx context.Context, since time.Time, limit int) ([]string, error) {
+	key := r.key(activeUsersKey, "")
+	cutoff := strconv.FormatInt(since.Unix(), 10)
+
+	if err := r.redis.ZRemRangeByScore(ctx, key, "-inf", "("+cutoff).Err(); err != nil {
+		r.logger.Error("Failed to prune active users", zap.Error(err))
+		return nil, err
+	}
+
+	userIDs, err := r.redis.ZRevRangeByScore(ctx, key, &redis.ZRangeBy{
+		Min:   cutoff,
+		Max:   "+inf",
+		Count: int64(limit),
+	}).Result()
+	if err != nil {
+		r.logger.Error("Failed to list active users", zap.Error(err))
+		return nil, err
+	}
+
+	return userIDs, nil
+}
+
 // InvalidateUserCache invalidates all cache entries for a user
 func (r *cacheRepository) InvalidateUserCache(ctx context.Context, userID string) error {
-	key := userWorkspacePrefix + userID
+	key := r.key(userWorkspacePrefix, userID)
 	
 	if err := r.redis.Del(ctx, key).Err(); err != nil {
 		r.logger.Error("Failed to invalidate user cache", zap.Error(err))
@@ -253,9 +496,9 @@ func (r *cacheRepository) InvalidateUserCache(ctx context.Context, userID string
 // ClearAllCache clears all workspace-related cache entries
 func (r *cacheRepository) ClearAllCache(ctx context.Context) error {
 	patterns := []string{
-		workspaceCachePrefix + "*",
-		projectCachePrefix + "*",
-		userWorkspacePrefix + "*",
+		r.key(workspaceCachePrefix, "*"),
+		r.key(projectCachePrefix, "*"),
+		r.key(userWorkspacePrefix, "*"),
 	}
 	
 	for _, pattern := range patterns {
@@ -276,4 +519,78 @@ func (r *cacheRepository) ClearAllCache(ctx context.Context) error {
 	}
 	
 	return nil
-}
\ No newline at end of file
+}
+
+// GetMaintenanceMode returns the maintenance mode set by the admin toggle, or "" when unset
+func (r *cacheRepository) GetMaintenanceMode(ctx context.Context) (string, error) {
+	mode, err := r.redis.Get(ctx, maintenanceModeKey).Result()
+	if err != nil {
+		if err == redis.Nil {
+			return "", nil
+		}
+		r.logger.Error("Failed to get maintenance mode from cache", zap.Error(err))
+		return "", err
+	}
+	return mode, nil
+}
+
+// SetMaintenanceMode stores the admin maintenance toggle without expiry; "" clears it
+func (r *cacheRepository) SetMaintenanceMode(ctx context.Context, mode string) error {
+	var err error
+	if mode == "" {
+		err = r.redis.Del(ctx, maintenanceModeKey).Err()
+	} else {
+		err = r.redis.Set(ctx, maintenanceModeKey, mode, 0).Err()
+	}
+	if err != nil {
+		r.logger.Error("Failed to store maintenance mode", zap.String("mode", mode), zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+// ClaimRequest marks the request identified by key as in flight for ttl. It reports false when
+// an identical request already holds the claim or has completed within its ttl.
+func (r *cacheRepository) ClaimRequest(ctx context.Context, key string, ttl time.Duration) (bool, error) {
+	claimed, err := r.redis.SetNX(ctx, r.key(requestDedupPrefix, key), "", ttl).Result()
+	if err != nil {
+		r.logger.Error("Failed to claim request", zap.String("key", key), zap.Error(err))
+		return false, err
+	}
+	return claimed, nil
+}
+
+// CompleteRequest replaces a claim with the request's result, kept for ttl
+func (r *cacheRepository) CompleteRequest(ctx context.Context, key string, result []byte, ttl time.Duration) error {
+	if err := r.redis.Set(ctx, r.key(requestDedupPrefix, key), result, ttl).Err(); err != nil {
+		r.logger.Error("Failed to store request result", zap.String("key", key), zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+// RequestResult returns the result of a completed request, or reports true while the request
+// is still in flight. Both are empty when nothing holds the key.
+func (r *cacheRepository) RequestResult(ctx context.Context, key string) ([]byte, bool, error) {
+	result, err := r.redis.Get(ctx, r.key(requestDedupPrefix, key)).Bytes()
+	if err != nil {
+		if err == redis.Nil {
+			return nil, false, nil
+		}
+		r.logger.Error("Failed to get request result", zap.String("key", key), zap.Error(err))
+		return nil, false, err
+	}
+	if len(result) == 0 {
+		return nil, true, nil
+	}
+	return result, false, nil
+}
+
+// ReleaseRequest drops a claim so an identical request can run, e.g. after the first failed
+func (r *cacheRepository) ReleaseRequest(ctx context.Context, key string) error {
+	if err := r.redis.Del(ctx, r.key(requestDedupPrefix, key)).Err(); err != nil {
+		r.logger.Error("Failed to release request", zap.String("key", key), zap.Error(err))
+		return err
+	}
+	return nil
+}
@@ -2,44 +2,49 @@ package repositories
 
 import (
 	"context"
-	"fmt"
 	"strings"
 
 	"go.uber.org/zap"
 	"gorm.io/gorm"
 
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/config"
 	"github.com/Reg-Kris/pyairtable-workspace-service/internal/models"
+	"github.com/Reg-Kris/pyairtable-workspace-service/pkg/database"
 )
 
 type projectRepository struct {
 	db     *gorm.DB
 	logger *zap.Logger
+	names  nameMatching
+	retry  database.RetryPolicy
+	sorts  config.SortConfig
 }
 
 // NewProjectRepository creates a new project repository
-func NewProjectRepository(db *gorm.DB, logger *zap.Logger) ProjectRepository {
+func NewProjectRepository(db *gorm.DB, config *config.Config, logger *zap.Logger) ProjectRepository {
 	return &projectRepository{
 		db:     db,
 		logger: logger,
+		names:  newNameMatching(config),
+		retry:  retryPolicy(config),
+		sorts:  sortConfig(config),
 	}
 }
 
 // Create creates a new project
 func (r *projectRepository) Create(ctx context.Context, project *models.Project) error {
 	// Check if project with same name exists in workspace
-	var count int64
-	if err := r.db.WithContext(ctx).Model(&models.Project{}).
-		Where("workspace_id = ? AND name = ? AND deleted_at IS NULL", project.WorkspaceID, project.Name).
-		Count(&count).Error; err != nil {
-		r.logger.Error("Failed to check duplicate project", zap.Error(err))
+	if err := uniqueProjectName.check(ctx, r.db, r.names, "", project.Name, project.WorkspaceID); err != nil {
+		if err != ErrDuplicateProject {
+			r.logger.Error("Failed to check duplicate project", zap.Error(err))
+		}
 		return err
 	}
-	
-	if count > 0 {
-		return ErrDuplicateProject
-	}
 
 	if err := r.db.WithContext(ctx).Create(project).Error; err != nil {
+		if err = uniqueProjectName.violation(err); err == ErrDuplicateProject {
+			return err
+		}
 		r.logger.Error("Failed to create project", zap.Error(err))
 		return err
 	}
@@ -50,10 +55,12 @@ func (r *projectRepository) Create(ctx context.Context, project *models.Project)
 // GetByID retrieves a project by ID
 func (r *projectRepository) GetByID(ctx context.Context, id string) (*models.Project, error) {
 	var project models.Project
-	if err := r.db.WithContext(ctx).
-		Preload("Workspace").
-		Where("id = ? AND deleted_at IS NULL", id).
-		First(&project).Error; err != nil {
+	if err := retryRead(ctx, r.retry, func() error {
+		return r.db.WithContext(ctx).
+			Preload("Workspace").
+			Where("id = ? AND deleted_at IS NULL", id).
+			First(&project).Error
+	}); err != nil {
 		if err == gorm.ErrRecordNotFound {
 			return nil, ErrProjectNotFound
 		}
@@ -67,9 +74,14 @@ func (r *projectRepository) GetByID(ctx context.Context, id string) (*models.Pro
 // GetByWorkspaceAndName retrieves a project by workspace ID and name
 func (r *projectRepository) GetByWorkspaceAndName(ctx context.Context, workspaceID, name string) (*models.Project, error) {
 	var project models.Project
-	if err := r.db.WithContext(ctx).
-		Where("workspace_id = ? AND name = ? AND deleted_at IS NULL", workspaceID, name).
-		First(&project).Error; err != nil {
+	if err := retryRead(ctx, r.retry, func() error {
+		query, err := r.names.where(r.db.WithContext(ctx).Model(&models.Project{}).
+			Where("workspace_id = ? AND deleted_at IS NULL", workspaceID), name)
+		if err != nil {
+			return err
+		}
+		return query.First(&project).Error
+	}); err != nil {
 		if err == gorm.ErrRecordNotFound {
 			return nil, ErrProjectNotFound
 		}
@@ -80,28 +92,39 @@ func (r *projectRepository) GetByWorkspaceAndName(ctx context.Context, workspace
 	return &project, nil
 }
 
-// Update updates a project
+// Update writes a project's editable fields, listed explicitly for the same reasons as
+// workspaceRepository.Update. Tags are written by SetTags.
 func (r *projectRepository) Update(ctx context.Context, project *models.Project) error {
 	// Check if another project with same name exists
 	if project.Name != "" {
-		var count int64
-		if err := r.db.WithContext(ctx).Model(&models.Project{}).
-			Where("workspace_id = ? AND name = ? AND id != ? AND deleted_at IS NULL", 
-				project.WorkspaceID, project.Name, project.ID).
-			Count(&count).Error; err != nil {
-			r.logger.Error("Failed to check duplicate project", zap.Error(err))
+		if err := uniqueProjectName.check(ctx, r.db, r.names, project.ID, project.Name, project.WorkspaceID); err != nil {
+			if err != ErrDuplicateProject {
+				r.logger.Error("Failed to check duplicate project", zap.Error(err))
+			}
 			return err
 		}
-		
-		if count > 0 {
-			return ErrDuplicateProject
-		}
 	}
 
-	result := r.db.WithContext(ctx).Model(project).Updates(project)
-	if result.Error != nil {
-		r.logger.Error("Failed to update project", zap.Error(result.Error))
+	updates := map[string]interface{}{
+		"name":        project.Name,
+		"description": project.Description,
+		"status":      project.Status,
+		"created_by":  project.CreatedBy,
+	}
+	if project.Settings != nil {
+		updates["settings"] = project.Settings
+	}
+
+	var result *gorm.DB
+	if err := retryWrite(ctx, r.retry, func() error {
+		result = r.db.WithContext(ctx).Model(project).Updates(updates)
 		return result.Error
+	}); err != nil {
+		if err = uniqueProjectName.violation(err); err == ErrDuplicateProject {
+			return err
+		}
+		r.logger.Error("Failed to update project", zap.Error(err))
+		return err
 	}
 
 	if result.RowsAffected == 0 {
@@ -113,10 +136,13 @@ func (r *projectRepository) Update(ctx context.Context, project *models.Project)
 
 // Delete soft deletes a project
 func (r *projectRepository) Delete(ctx context.Context, id string) error {
-	result := r.db.WithContext(ctx).Where("id = ?", id).Delete(&models.Project{})
-	if result.Error != nil {
-		r.logger.Error("Failed to delete project", zap.Error(result.Error))
+	var result *gorm.DB
+	if err := retryWrite(ctx, r.retry, func() error {
+		result = r.db.WithContext(ctx).Where("id = ?", id).Delete(&models.Project{})
 		return result.Error
+	}); err != nil {
+		r.logger.Error("Failed to delete project", zap.Error(err))
+		return err
 	}
 
 	if result.RowsAffected == 0 {
@@ -148,29 +174,32 @@ func (r *projectRepository) List(ctx context.Context, filter *models.ProjectFilt
 		query = query.Where("LOWER(name) LIKE ? OR LOWER(description) LIKE ?", search, search)
 	}
 
-	if !filter.IncludeDeleted {
+	if filter.IncludeDeleted {
+		// Bypass the soft-delete scope so deleted rows are listed too
+		query = query.Unscoped()
+		if filter.DeletedVisibleTo != "" {
+			query = query.Where("projects.deleted_at IS NULL OR projects.workspace_id IN (?)", adminWorkspaceIDs(r.db, filter.DeletedVisibleTo))
+		}
+	} else {
 		query = query.Where("deleted_at IS NULL")
 	}
 
 	// Count total records
 	var total int64
-	if err := query.Count(&total).Error; err != nil {
+	if err := retryRead(ctx, r.retry, func() error {
+		return query.Count(&total).Error
+	}); err != nil {
 		r.logger.Error("Failed to count projects", zap.Error(err))
 		return nil, 0, err
 	}
 
-	// Apply sorting
-	sortBy := "created_at"
-	if filter.SortBy != "" {
-		sortBy = filter.SortBy
-	}
-	
-	sortOrder := "DESC"
-	if filter.SortOrder != "" && strings.ToUpper(filter.SortOrder) == "ASC" {
-		sortOrder = "ASC"
+	if filter.CountOnly {
+		return nil, total, nil
 	}
-	
-	query = query.Order(fmt.Sprintf("%s %s", sortBy, sortOrder))
+
+	// Apply sorting
+	sortBy, sortOrder := listSort(r.sorts, config.SortListProjects, filter.SortBy, filter.SortOrder)
+	query = query.Order(orderWithTiebreaker(sortBy, sortOrder))
 
 	// Apply pagination
 	page := filter.Page
@@ -189,19 +218,179 @@ func (r *projectRepository) List(ctx context.Context, filter *models.ProjectFilt
 	offset := (page - 1) * pageSize
 	query = query.Offset(offset).Limit(pageSize)
 
-	// Preload associations
-	query = query.Preload("Workspace")
-
 	// Fetch projects
 	var projects []*models.Project
-	if err := query.Find(&projects).Error; err != nil {
+	if err := retryRead(ctx, r.retry, func() error {
+		return query.Find(&projects).Error
+	}); err != nil {
 		r.logger.Error("Failed to list projects", zap.Error(err))
 		return nil, 0, err
 	}
 
+	if err := r.loadWorkspaceNames(ctx, projects); err != nil {
+		return nil, 0, err
+	}
+
+	if filter.Includes("base_count") {
+		if err := r.loadBaseCounts(ctx, projects); err != nil {
+			return nil, 0, err
+		}
+	}
+
+	return projects, total, nil
+}
+
+// ListForMember lists the live projects of every workspace the user is a member of, ordered by
+// workspace name and then project name, with WorkspaceName filled. Membership, the workspace
+// names and the total all come from one query; a page past the end has no row to carry the
+// total, so it reports zero.
+func (r *projectRepository) ListForMember(ctx context.Context, userID string, filter *models.ProjectFilter) ([]*models.Project, int64, error) {
+	query := r.db.WithContext(ctx).
+		Table("projects").
+		Joins("JOIN workspaces ON workspaces.id = projects.workspace_id AND workspaces.deleted_at IS NULL").
+		Joins("JOIN workspace_members ON workspace_members.workspace_id = projects.workspace_id AND workspace_members.user_id = ?", userID).
+		Where("projects.deleted_at IS NULL")
+
+	if filter.WorkspaceID != "" {
+		query = query.Where("projects.workspace_id = ?", filter.WorkspaceID)
+	}
+
+	if filter.Status != "" {
+		query = query.Where("projects.status = ?", filter.Status)
+	}
+
+	if filter.CreatedBy != "" {
+		query = query.Where("projects.created_by = ?", filter.CreatedBy)
+	}
+
+	if filter.Search != "" {
+		search := "%" + strings.ToLower(filter.Search) + "%"
+		query = query.Where("(LOWER(projects.name) LIKE ? OR LOWER(projects.description) LIKE ?)", search, search)
+	}
+
+	if filter.CountOnly {
+		var total int64
+		if err := retryRead(ctx, r.retry, func() error {
+			return query.Count(&total).Error
+		}); err != nil {
+			r.logger.Error("Failed to count projects for member", zap.Error(err), zap.String("user_id", userID))
+			return nil, 0, err
+		}
+		return nil, total, nil
+	}
+
+	page := filter.Page
+	if page < 1 {
+		page = 1
+	}
+
+	pageSize := filter.PageSize
+	if pageSize < 1 {
+		pageSize = 20
+	}
+	if pageSize > 100 {
+		pageSize = 100
+	}
+
+	type memberProject struct {
+		models.Project
+		MemberWorkspaceName string
+		TotalCount          int64
+	}
+	var rows []*memberProject
+	if err := retryRead(ctx, r.retry, func() error {
+		return query.
+			Select("projects.*, workspaces.name AS member_workspace_name, COUNT(*) OVER () AS total_count").
+			Order("workspaces.name ASC, workspaces.id ASC, projects.name ASC, projects.id ASC").
+			Offset((page - 1) * pageSize).
+			Limit(pageSize).
+			Find(&rows).Error
+	}); err != nil {
+		r.logger.Error("Failed to list projects for member", zap.Error(err), zap.String("user_id", userID))
+		return nil, 0, err
+	}
+
+	var total int64
+	projects := make([]*models.Project, len(rows))
+	for i, row := range rows {
+		row.Project.WorkspaceName = row.MemberWorkspaceName
+		projects[i] = &row.Project
+		total = row.TotalCount
+	}
+
+	if filter.Includes("base_count") {
+		if err := r.loadBaseCounts(ctx, projects); err != nil {
+			return nil, 0, err
+		}
+	}
+
 	return projects, total, nil
 }
 
+// loadWorkspaceNames populates WorkspaceName for a page of projects with one joined query,
+// so listings don't repeat the whole workspace body on every row
+func (r *projectRepository) loadWorkspaceNames(ctx context.Context, projects []*models.Project) error {
+	if len(projects) == 0 {
+		return nil
+	}
+
+	projectIDs := make([]string, len(projects))
+	for i, project := range projects {
+		projectIDs[i] = project.ID
+	}
+
+	type workspaceName struct {
+		ProjectID     string
+		WorkspaceName string
+	}
+	var workspaceNames []workspaceName
+	if err := retryRead(ctx, r.retry, func() error {
+		return r.db.WithContext(ctx).
+			Table("projects").
+			Select("projects.id AS project_id, workspaces.name AS workspace_name").
+			Joins("JOIN workspaces ON workspaces.id = projects.workspace_id").
+			Where("projects.id IN ?", projectIDs).
+			Scan(&workspaceNames).Error
+	}); err != nil {
+		r.logger.Error("Failed to load workspace names for projects", zap.Error(err))
+		return err
+	}
+
+	names := make(map[string]string, len(workspaceNames))
+	for _, wn := range workspaceNames {
+		names[wn.ProjectID] = wn.WorkspaceName
+	}
+	for _, project := range projects {
+		project.WorkspaceName = names[project.ID]
+	}
+
+	return nil
+}
+
+// loadBaseCounts populates BaseCount for a page of projects with one grouped query
+func (r *projectRepository) loadBaseCounts(ctx context.Context, projects []*models.Project) error {
+	if len(projects) == 0 {
+		return nil
+	}
+
+	projectIDs := make([]string, len(projects))
+	for i, project := range projects {
+		projectIDs[i] = project.ID
+	}
+
+	counts, err := r.CountBases(ctx, projectIDs)
+	if err != nil {
+		return err
+	}
+
+	for _, project := range projects {
+		count := counts[project.ID]
+		project.BaseCount = &count
+	}
+
+	return nil
+}
+
 // CountByWorkspace counts projects in a workspace
 func (r *projectRepository) CountByWorkspace(ctx context.Context, workspaceID string) (int64, error) {
 	var count int64
@@ -213,4 +402,108 @@ func (r *projectRepository) CountByWorkspace(ctx context.Context, workspaceID st
 	}
 
 	return count, nil
-}
\ No newline at end of file
+}
+
+// CountByCreator counts projects in a workspace created by a user
+func (r *projectRepository) CountByCreator(ctx context.Context, workspaceID, userID string) (int64, error) {
+	var count int64
+	if err := retryRead(ctx, r.retry, func() error {
+		return r.db.WithContext(ctx).Model(&models.Project{}).
+			Where("workspace_id = ? AND created_by = ? AND deleted_at IS NULL", workspaceID, userID).
+			Count(&count).Error
+	}); err != nil {
+		r.logger.Error("Failed to count projects by creator", zap.Error(err))
+		return 0, err
+	}
+
+	return count, nil
+}
+
+// ReassignCreator moves attribution of a user's projects in a workspace to another user
+func (r *projectRepository) ReassignCreator(ctx context.Context, workspaceID, fromUserID, toUserID string) (int64, error) {
+	var result *gorm.DB
+	if err := retryWrite(ctx, r.retry, func() error {
+		result = r.db.WithContext(ctx).Model(&models.Project{}).
+			Where("workspace_id = ? AND created_by = ?", workspaceID, fromUserID).
+			Update("created_by", toUserID)
+		return result.Error
+	}); err != nil {
+		r.logger.Error("Failed to reassign project creator", zap.Error(err))
+		return 0, err
+	}
+
+	return result.RowsAffected, nil
+}
+
+// SetTags replaces a project's tags
+func (r *projectRepository) SetTags(ctx context.Context, id string, tags models.StringList) error {
+	var result *gorm.DB
+	if err := retryWrite(ctx, r.retry, func() error {
+		result = r.db.WithContext(ctx).Model(&models.Project{}).
+			Where("id = ? AND deleted_at IS NULL", id).
+			Update("tags", tags)
+		return result.Error
+	}); err != nil {
+		r.logger.Error("Failed to set project tags", zap.Error(err))
+		return err
+	}
+
+	if result.RowsAffected == 0 {
+		return ErrProjectNotFound
+	}
+
+	return nil
+}
+
+// CountBases counts live Airtable bases per project with one grouped query. Projects without
+// bases are absent from the result.
+func (r *projectRepository) CountBases(ctx context.Context, projectIDs []string) (map[string]int64, error) {
+	if len(projectIDs) == 0 {
+		return map[string]int64{}, nil
+	}
+
+	type baseCount struct {
+		ProjectID string
+		Count     int64
+	}
+	var baseCounts []baseCount
+	if err := retryRead(ctx, r.retry, func() error {
+		return r.db.WithContext(ctx).
+			Table("airtable_bases").
+			Select("project_id, COUNT(*) as count").
+			Where("project_id IN ? AND deleted_at IS NULL", projectIDs).
+			Group("project_id").
+			Scan(&baseCounts).Error
+	}); err != nil {
+		r.logger.Error("Failed to count airtable bases by project", zap.Error(err))
+		return nil, err
+	}
+
+	counts := make(map[string]int64, len(baseCounts))
+	for _, bc := range baseCounts {
+		counts[bc.ProjectID] = bc.Count
+	}
+
+	return counts, nil
+}
+
+// FindByWorkspace retrieves live projects in a workspace, narrowed to ids and status when given
+func (r *projectRepository) FindByWorkspace(ctx context.Context, workspaceID string, ids []string, status string) ([]*models.Project, error) {
+	var projects []*models.Project
+	if err := retryRead(ctx, r.retry, func() error {
+		query := r.db.WithContext(ctx).
+			Where("workspace_id = ? AND deleted_at IS NULL", workspaceID)
+		if len(ids) > 0 {
+			query = query.Where("id IN ?", ids)
+		}
+		if status != "" {
+			query = query.Where("status = ?", status)
+		}
+		return query.Order(orderWithTiebreaker("created_at", "ASC")).Find(&projects).Error
+	}); err != nil {
+		r.logger.Error("Failed to find projects in workspace", zap.Error(err))
+		return nil, err
+	}
+
+	return projects, nil
+}
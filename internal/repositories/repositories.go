@@ -3,38 +3,55 @@ package repositories
 import (
 	"context"
 	"errors"
+	"strings"
 	"time"
+	"unicode"
 
 	"github.com/redis/go-redis/v9"
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
 	"gorm.io/gorm"
 	"go.uber.org/zap"
 
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/config"
 	"github.com/Reg-Kris/pyairtable-workspace-service/internal/models"
+	"github.com/Reg-Kris/pyairtable-workspace-service/pkg/database"
+	"github.com/Reg-Kris/pyairtable-workspace-service/pkg/health"
 )
 
 // Common errors
 var (
-	ErrWorkspaceNotFound     = errors.New("workspace not found")
-	ErrProjectNotFound       = errors.New("project not found")
-	ErrAirtableBaseNotFound  = errors.New("airtable base not found")
-	ErrMemberNotFound        = errors.New("member not found")
-	ErrDuplicateWorkspace    = errors.New("workspace with this name already exists")
-	ErrDuplicateProject      = errors.New("project with this name already exists")
-	ErrDuplicateAirtableBase = errors.New("airtable base already connected")
-	ErrDuplicateMember       = errors.New("member already exists in workspace")
-	ErrCannotDeleteOwner     = errors.New("cannot remove workspace owner")
-	ErrLastOwner             = errors.New("cannot remove the last owner")
+	ErrWorkspaceNotFound      = errors.New("workspace not found")
+	ErrProjectNotFound        = errors.New("project not found")
+	ErrAirtableBaseNotFound   = errors.New("airtable base not found")
+	ErrMemberNotFound         = errors.New("member not found")
+	ErrDuplicateWorkspace     = errors.New("workspace with this name already exists")
+	ErrDuplicateProject       = errors.New("project with this name already exists")
+	ErrDuplicateAirtableBase  = errors.New("airtable base already connected")
+	ErrDuplicateMember        = errors.New("member already exists in workspace")
+	ErrCannotDeleteOwner      = errors.New("cannot remove workspace owner")
+	ErrLastOwner              = errors.New("cannot remove the last owner")
+	ErrInvalidCursor          = errors.New("invalid pagination cursor")
+	ErrServiceAccountNotFound = errors.New("service account not found")
 )
 
 // WorkspaceRepository interface
 type WorkspaceRepository interface {
 	Create(ctx context.Context, workspace *models.Workspace) error
 	GetByID(ctx context.Context, id string) (*models.Workspace, error)
+	Lock(ctx context.Context, id string, exclusive bool) (*models.Workspace, error)
 	GetByTenantAndName(ctx context.Context, tenantID, name string) (*models.Workspace, error)
 	Update(ctx context.Context, workspace *models.Workspace) error
 	Delete(ctx context.Context, id string) error
+	SetScheduledDeletion(ctx context.Context, id string, at *time.Time) error
+	ListDueForDeletion(ctx context.Context, now time.Time, limit int) ([]*models.Workspace, error)
+	ListWithSetting(ctx context.Context, key string) ([]*models.Workspace, error)
+	ResolveNames(ctx context.Context, ids []string, memberID string) (map[string]string, error)
+	FindByIDs(ctx context.Context, ids []string) ([]*models.Workspace, error)
 	List(ctx context.Context, filter *models.WorkspaceFilter) ([]*models.Workspace, int64, error)
-	GetStats(ctx context.Context, tenantID string) (*models.WorkspaceStats, error)
+	GetStats(ctx context.Context, tenantID string, filter *models.WorkspaceStatsFilter) (*models.WorkspaceStats, error)
+	GetTrends(ctx context.Context, tenantID string, days int) (*models.WorkspaceTrends, error)
 }
 
 // ProjectRepository interface
@@ -45,7 +62,13 @@ type ProjectRepository interface {
 	Update(ctx context.Context, project *models.Project) error
 	Delete(ctx context.Context, id string) error
 	List(ctx context.Context, filter *models.ProjectFilter) ([]*models.Project, int64, error)
+	ListForMember(ctx context.Context, userID string, filter *models.ProjectFilter) ([]*models.Project, int64, error)
 	CountByWorkspace(ctx context.Context, workspaceID string) (int64, error)
+	CountByCreator(ctx context.Context, workspaceID, userID string) (int64, error)
+	ReassignCreator(ctx context.Context, workspaceID, fromUserID, toUserID string) (int64, error)
+	FindByWorkspace(ctx context.Context, workspaceID string, ids []string, status string) ([]*models.Project, error)
+	CountBases(ctx context.Context, projectIDs []string) (map[string]int64, error)
+	SetTags(ctx context.Context, id string, tags models.StringList) error
 }
 
 // AirtableBaseRepository interface
@@ -53,10 +76,17 @@ type AirtableBaseRepository interface {
 	Create(ctx context.Context, base *models.AirtableBase) error
 	GetByID(ctx context.Context, id string) (*models.AirtableBase, error)
 	GetByProjectAndBaseID(ctx context.Context, projectID, baseID string) (*models.AirtableBase, error)
+	FindByBaseID(ctx context.Context, baseID string) ([]*models.AirtableBase, error)
 	Update(ctx context.Context, base *models.AirtableBase) error
 	Delete(ctx context.Context, id string) error
 	List(ctx context.Context, filter *models.AirtableBaseFilter) ([]*models.AirtableBase, int64, error)
 	UpdateSyncTime(ctx context.Context, id string, syncTime time.Time) error
+	RecordSync(ctx context.Context, id, status, syncErr string, durationMs int, syncTime time.Time) error
+	CountByWorkspace(ctx context.Context, workspaceID string) (int64, error)
+	CountByCreator(ctx context.Context, workspaceID, userID string) (int64, error)
+	ReassignCreator(ctx context.Context, workspaceID, fromUserID, toUserID string) (int64, error)
+	DeleteByProject(ctx context.Context, projectID string) (int64, error)
+	SetSyncEnabledForWorkspace(ctx context.Context, workspaceID string, enabled bool) (int64, error)
 }
 
 // WorkspaceMemberRepository interface
@@ -66,15 +96,37 @@ type WorkspaceMemberRepository interface {
 	UpdateRole(ctx context.Context, workspaceID, userID string, role models.WorkspaceMemberRole) error
 	Remove(ctx context.Context, workspaceID, userID string) error
 	List(ctx context.Context, workspaceID string, page, pageSize int) ([]*models.WorkspaceMember, int64, error)
+	Scan(ctx context.Context, workspaceID string, batchSize int, fn func(batch []*models.WorkspaceMember) error) error
 	CountOwners(ctx context.Context, workspaceID string) (int64, error)
 	IsLastOwner(ctx context.Context, workspaceID, userID string) (bool, error)
+	SetPrimary(ctx context.Context, workspaceID, userID string) (string, error)
+	GetPrimary(ctx context.Context, workspaceID string) (*models.WorkspaceMember, error)
+	TouchLastAccessed(ctx context.Context, workspaceID, userID string, at time.Time) error
+	LastAccessed(ctx context.Context, userID string, workspaceIDs []string) (map[string]time.Time, error)
+	ListHistory(ctx context.Context, workspaceID string, page, pageSize int) ([]*models.PastWorkspaceMember, int64, error)
+}
+
+// ServiceAccountRepository interface
+type ServiceAccountRepository interface {
+	Create(ctx context.Context, account *models.ServiceAccount) error
+	GetByID(ctx context.Context, id string) (*models.ServiceAccount, error)
+	GetActiveByTokenHash(ctx context.Context, tokenHash string) (*models.ServiceAccount, error)
+	ListByWorkspace(ctx context.Context, workspaceID string) ([]*models.ServiceAccount, error)
+	Revoke(ctx context.Context, id string, at time.Time) error
 }
 
 // AuditLogRepository interface
 type AuditLogRepository interface {
 	Create(ctx context.Context, log *models.WorkspaceAuditLog) error
+	CreateBatch(ctx context.Context, logs []*models.WorkspaceAuditLog) error
 	List(ctx context.Context, filter *models.AuditLogFilter) ([]*models.WorkspaceAuditLog, int64, error)
-	DeleteOlderThan(ctx context.Context, days int) error
+	Facets(ctx context.Context, workspaceID string) (*models.AuditLogFacets, error)
+	DeleteOlderThan(ctx context.Context, days int, exceptWorkspaceIDs []string) error
+	DeleteWorkspaceOlderThan(ctx context.Context, workspaceID string, days int) error
+	ListOlderThan(ctx context.Context, before time.Time, workspaceID string, exceptWorkspaceIDs []string, limit int) ([]*models.WorkspaceAuditLog, error)
+	DeleteByIDs(ctx context.Context, ids []string) (int64, error)
+	MostActiveWorkspaces(ctx context.Context, since time.Time, limit int) ([]string, error)
+	ListActivity(ctx context.Context, userID string, actions []string, page, pageSize int) ([]*models.WorkspaceAuditLog, int64, error)
 }
 
 // CacheRepository interface
@@ -82,41 +134,97 @@ type CacheRepository interface {
 	SetWorkspace(ctx context.Context, workspace *models.Workspace) error
 	GetWorkspace(ctx context.Context, id string) (*models.Workspace, error)
 	DeleteWorkspace(ctx context.Context, id string) error
+	WarmWorkspaceCache(ctx context.Context, workspaces []*models.Workspace) error
 	SetProject(ctx context.Context, project *models.Project) error
 	GetProject(ctx context.Context, id string) (*models.Project, error)
 	DeleteProject(ctx context.Context, id string) error
 	InvalidateWorkspaceCache(ctx context.Context, workspaceID string) error
 	SetUserWorkspaces(ctx context.Context, userID string, workspaceIDs []string) error
 	GetUserWorkspaces(ctx context.Context, userID string) ([]string, error)
+	InvalidateUserCache(ctx context.Context, userID string) error
+	RebuildUserWorkspaces(ctx context.Context, userID string) ([]string, error)
+	MarkUserActive(ctx context.Context, userID string, at time.Time) error
+	ActiveUsers(ctx context.Context, since time.Time, limit int) ([]string, error)
+	SetBaseMetadata(ctx context.Context, metadata *models.AirtableBaseMetadata, retention time.Duration) error
+	GetBaseMetadata(ctx context.Context, baseID string) (*models.AirtableBaseMetadata, error)
+	SetWorkspaceStats(ctx context.Context, tenantID string, stats *models.WorkspaceStats) error
+	GetWorkspaceStats(ctx context.Context, tenantID string) (*models.WorkspaceStats, error)
+	SetWorkspaceTrends(ctx context.Context, tenantID string, trends *models.WorkspaceTrends) error
+	GetWorkspaceTrends(ctx context.Context, tenantID string, days int) (*models.WorkspaceTrends, error)
+	InvalidateTenantStats(ctx context.Context, tenantID string) error
+	GetMaintenanceMode(ctx context.Context) (string, error)
+	SetMaintenanceMode(ctx context.Context, mode string) error
+	ClaimRequest(ctx context.Context, key string, ttl time.Duration) (bool, error)
+	CompleteRequest(ctx context.Context, key string, result []byte, ttl time.Duration) error
+	RequestResult(ctx context.Context, key string) ([]byte, bool, error)
+	ReleaseRequest(ctx context.Context, key string) error
 }
 
 // Repositories aggregates all repository interfaces
 type Repositories struct {
-	Workspace     WorkspaceRepository
-	Project       ProjectRepository
-	AirtableBase  AirtableBaseRepository
-	Member        WorkspaceMemberRepository
-	AuditLog      AuditLogRepository
-	Cache         CacheRepository
+	Workspace      WorkspaceRepository
+	Project        ProjectRepository
+	AirtableBase   AirtableBaseRepository
+	Member         WorkspaceMemberRepository
+	ServiceAccount ServiceAccountRepository
+	AuditLog       AuditLogRepository
+	Cache          CacheRepository
 	
 	db     *gorm.DB
 	redis  *redis.Client
+	config *config.Config
 	logger *zap.Logger
 }
 
 // New creates a new Repositories instance
-func New(db *gorm.DB, redis *redis.Client, logger *zap.Logger) *Repositories {
+func New(db *gorm.DB, redis *redis.Client, config *config.Config, logger *zap.Logger) *Repositories {
 	return &Repositories{
-		Workspace:    NewWorkspaceRepository(db, logger),
-		Project:      NewProjectRepository(db, logger),
-		AirtableBase: NewAirtableBaseRepository(db, logger),
-		Member:       NewWorkspaceMemberRepository(db, logger),
-		AuditLog:     NewAuditLogRepository(db, logger),
-		Cache:        NewCacheRepository(redis, logger),
-		db:           db,
-		redis:        redis,
-		logger:       logger,
+		Workspace:      NewWorkspaceRepository(db, config, logger),
+		Project:        NewProjectRepository(db, config, logger),
+		AirtableBase:   NewAirtableBaseRepository(db, config, logger),
+		Member:         NewWorkspaceMemberRepository(db, config, logger),
+		ServiceAccount: NewServiceAccountRepository(db, config, logger),
+		AuditLog:       NewAuditLogRepository(db, config, logger),
+		Cache:          NewCacheRepository(redis, db, config, logger),
+		db:             db,
+		redis:          redis,
+		config:         config,
+		logger:         logger,
+	}
+}
+
+// HealthChecks returns the readiness checks of the stores the repositories use. The database is
+// critical; Redis only degrades the service, since cache failures fall back to the database.
+func (r *Repositories) HealthChecks() []health.Check {
+	var checks []health.Check
+	if r.db != nil {
+		checks = append(checks, health.Check{
+			Name:     "database",
+			Critical: true,
+			Probe: func(ctx context.Context) error {
+				sqlDB, err := r.db.DB()
+				if err != nil {
+					return err
+				}
+				return sqlDB.PingContext(ctx)
+			},
+		})
 	}
+	if r.redis != nil {
+		checks = append(checks, health.Check{
+			Name: "redis",
+			Probe: func(ctx context.Context) error {
+				return r.redis.Ping(ctx).Err()
+			},
+		})
+	}
+	return checks
+}
+
+// adminWorkspaceIDs is a subquery of the workspaces userID is an admin or owner of
+func adminWorkspaceIDs(db *gorm.DB, userID string) *gorm.DB {
+	return db.Model(&models.WorkspaceMember{}).Select("workspace_id").
+		Where("user_id = ? AND role IN ?", userID, []models.WorkspaceMemberRole{models.WorkspaceRoleOwner, models.WorkspaceRoleAdmin})
 }
 
 // BeginTx starts a new transaction
@@ -124,13 +232,206 @@ func (r *Repositories) BeginTx(ctx context.Context) *gorm.DB {
 	return r.db.WithContext(ctx).Begin()
 }
 
+// Transaction runs fn with SQL repositories bound to a single database transaction,
+// committing when fn returns nil and rolling back otherwise
+func (r *Repositories) Transaction(ctx context.Context, fn func(tx *Repositories) error) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// Statements cannot be retried individually once the transaction has aborted
+		txConfig := &config.Config{}
+		if r.config != nil {
+			*txConfig = *r.config
+		}
+		txConfig.Database.RetryMaxAttempts = 1
+
+		txRepos := New(tx, r.redis, txConfig, r.logger)
+		txRepos.Cache = r.Cache
+		return fn(txRepos)
+	})
+}
+
 // AutoMigrate runs database migrations
 func (r *Repositories) AutoMigrate() error {
-	return r.db.AutoMigrate(
+	if err := r.db.AutoMigrate(
 		&models.Workspace{},
 		&models.Project{},
 		&models.AirtableBase{},
 		&models.WorkspaceMember{},
+		&models.PastWorkspaceMember{},
 		&models.WorkspaceAuditLog{},
-	)
+		&models.ServiceAccount{},
+	); err != nil {
+		return err
+	}
+
+	// Partial unique indexes make the duplicate checks race-free among live rows
+	caseInsensitive := r.config != nil && r.config.Names.CaseInsensitive
+	for _, rule := range []uniqueRule{uniqueWorkspaceName, uniqueProjectName, uniqueAirtableBase} {
+		for _, stmt := range rule.indexes(caseInsensitive) {
+			// Pre-existing duplicates block the index; the application check still applies
+			if err := r.db.Exec(stmt).Error; err != nil {
+				r.logger.Warn("Failed to create unique index", zap.String("index", rule.index), zap.Error(err))
+			}
+		}
+	}
+
+	// At most one primary owner per workspace. Workspaces created before primary owners existed
+	// get their longest-standing owner as primary.
+	primaryOwner := []string{
+		"CREATE UNIQUE INDEX IF NOT EXISTS idx_workspace_members_primary ON workspace_members (workspace_id) WHERE is_primary",
+		`UPDATE workspace_members SET is_primary = true WHERE (workspace_id, user_id) IN (
+			SELECT DISTINCT ON (workspace_id) workspace_id, user_id FROM workspace_members
+			WHERE role = 'owner' AND workspace_id NOT IN (SELECT workspace_id FROM workspace_members WHERE is_primary)
+			ORDER BY workspace_id, joined_at, user_id)`,
+	}
+	for _, stmt := range primaryOwner {
+		if err := r.db.Exec(stmt).Error; err != nil {
+			r.logger.Warn("Failed to set up primary owners", zap.Error(err))
+		}
+	}
+
+	// Trigram indexes back the LIKE scans used by the search filter
+	searchIndexes := []string{
+		"CREATE EXTENSION IF NOT EXISTS pg_trgm",
+		"CREATE INDEX IF NOT EXISTS idx_workspaces_name_trgm ON workspaces USING GIN (LOWER(name) gin_trgm_ops)",
+		"CREATE INDEX IF NOT EXISTS idx_workspaces_description_trgm ON workspaces USING GIN (LOWER(description) gin_trgm_ops)",
+		"CREATE INDEX IF NOT EXISTS idx_projects_name_trgm ON projects USING GIN (LOWER(name) gin_trgm_ops)",
+		"CREATE INDEX IF NOT EXISTS idx_projects_description_trgm ON projects USING GIN (LOWER(description) gin_trgm_ops)",
+		"CREATE INDEX IF NOT EXISTS idx_airtable_bases_name_trgm ON airtable_bases USING GIN (LOWER(name) gin_trgm_ops)",
+		"CREATE INDEX IF NOT EXISTS idx_airtable_bases_description_trgm ON airtable_bases USING GIN (LOWER(description) gin_trgm_ops)",
+		"CREATE INDEX IF NOT EXISTS idx_airtable_bases_base_id_trgm ON airtable_bases USING GIN (LOWER(base_id) gin_trgm_ops)",
+	}
+	for _, stmt := range searchIndexes {
+		// The extension may require elevated privileges; search still works, just without the index
+		if err := r.db.Exec(stmt).Error; err != nil {
+			r.logger.Warn("Failed to create search index", zap.Error(err))
+		}
+	}
+
+	return nil
+}
+
+// normalizeName folds a name to the form used for uniqueness comparisons
+func normalizeName(name string) string {
+	return strings.ToLower(strings.TrimSpace(name))
+}
+
+// nameMatch returns the predicate and argument used to compare a name column
+func nameMatch(caseInsensitive bool, name string) (string, string) {
+	if caseInsensitive {
+		return "LOWER(TRIM(name)) = ?", normalizeName(name)
+	}
+	return "name = ?", name
+}
+
+// accentFolder decomposes text and drops the combining marks left over, so "é" becomes "e"
+var accentFolder = transform.Chain(norm.NFKD, runes.Remove(runes.In(unicode.Mn)))
+
+// foldName is normalizeName with accents stripped as well
+func foldName(name string) string {
+	folded, _, err := transform.String(accentFolder, name)
+	if err != nil {
+		folded = name
+	}
+	return normalizeName(folded)
+}
+
+// nameMatching is how a repository decides two names are the same for uniqueness
+type nameMatching struct {
+	caseInsensitive   bool
+	accentInsensitive bool
+}
+
+func newNameMatching(config *config.Config) nameMatching {
+	if config == nil {
+		return nameMatching{}
+	}
+	return nameMatching{
+		caseInsensitive:   config.Names.CaseInsensitive,
+		accentInsensitive: config.Names.AccentInsensitive,
+	}
+}
+
+// where narrows scope to the rows whose name matches name. Folding accents has no SQL form the
+// indexes support, so in that mode the distinct names in scope are compared in Go and the scope
+// is narrowed to those that fold the same. A tenant's workspaces or a workspace's projects are
+// few enough for that.
+func (m nameMatching) where(scope *gorm.DB, name string) (*gorm.DB, error) {
+	if !m.accentInsensitive {
+		nameClause, nameArg := nameMatch(m.caseInsensitive, name)
+		return scope.Where(nameClause, nameArg), nil
+	}
+
+	var names []string
+	if err := scope.Session(&gorm.Session{}).Distinct("name").Pluck("name", &names).Error; err != nil {
+		return nil, err
+	}
+
+	key := foldName(name)
+	matches := make([]string, 0, 1)
+	for _, candidate := range names {
+		if foldName(candidate) == key {
+			matches = append(matches, candidate)
+		}
+	}
+	if len(matches) == 0 {
+		return scope.Where("1 = 0"), nil
+	}
+	return scope.Where("name IN ?", matches), nil
+}
+
+// orderWithTiebreaker returns an ORDER BY clause on column that breaks ties by id,
+// so rows sharing a sort key keep the same order from page to page
+func orderWithTiebreaker(column, direction string) string {
+	if column == "id" {
+		return "id " + direction
+	}
+	return column + " " + direction + ", id ASC"
+}
+
+// listSort resolves the ORDER BY column and direction for a list. An allowlisted sort_by wins
+// and a column outside the allowlist is ignored; otherwise the configured default applies.
+// An explicit sort_order overrides the direction either way.
+func listSort(sorts config.SortConfig, list, sortBy, sortOrder string) (string, string) {
+	column, order := sorts.Default(list)
+	if sortBy != "" && config.IsSortable(list, sortBy) {
+		column, order = sortBy, "DESC"
+	}
+	if sortOrder != "" {
+		order = "DESC"
+		if strings.ToUpper(sortOrder) == "ASC" {
+			order = "ASC"
+		}
+	}
+	return column, order
+}
+
+// sortConfig returns the configured default sorts, or the built-in ones without a configuration
+func sortConfig(cfg *config.Config) config.SortConfig {
+	if cfg == nil {
+		return config.SortConfig{}
+	}
+	return cfg.Sort
+}
+
+// retryPolicy returns the transient-error retry policy for the given configuration
+func retryPolicy(config *config.Config) database.RetryPolicy {
+	if config == nil {
+		return database.RetryPolicy{MaxAttempts: 1}
+	}
+	return database.RetryPolicy{
+		MaxAttempts: config.Database.RetryMaxAttempts,
+		BaseDelay:   time.Duration(config.Database.RetryBaseDelayMs) * time.Millisecond,
+		MaxDelay:    time.Duration(config.Database.RetryMaxDelayMs) * time.Millisecond,
+	}
+}
+
+// retryRead retries an idempotent read when it fails with a transient error
+func retryRead(ctx context.Context, policy database.RetryPolicy, op func() error) error {
+	return database.Retry(ctx, policy, database.IsTransient, op)
+}
+
+// retryWrite retries a single-statement write only on serialization failures and deadlocks,
+// since a dropped connection may have committed the write already
+func retryWrite(ctx context.Context, policy database.RetryPolicy, op func() error) error {
+	return database.Retry(ctx, policy, database.IsSerializationFailure, op)
 }
\ No newline at end of file
@@ -0,0 +1,101 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/config"
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/models"
+	"github.com/Reg-Kris/pyairtable-workspace-service/pkg/database"
+)
+
+type serviceAccountRepository struct {
+	db     *gorm.DB
+	logger *zap.Logger
+	retry  database.RetryPolicy
+}
+
+// NewServiceAccountRepository creates a new service account repository
+func NewServiceAccountRepository(db *gorm.DB, config *config.Config, logger *zap.Logger) ServiceAccountRepository {
+	return &serviceAccountRepository{
+		db:     db,
+		logger: logger,
+		retry:  retryPolicy(config),
+	}
+}
+
+// Create creates a new service account
+func (r *serviceAccountRepository) Create(ctx context.Context, account *models.ServiceAccount) error {
+	if err := r.db.WithContext(ctx).Create(account).Error; err != nil {
+		r.logger.Error("Failed to create service account", zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+// GetByID retrieves a service account by ID, including revoked accounts
+func (r *serviceAccountRepository) GetByID(ctx context.Context, id string) (*models.ServiceAccount, error) {
+	var account models.ServiceAccount
+	if err := retryRead(ctx, r.retry, func() error {
+		return r.db.WithContext(ctx).Where("id = ?", id).First(&account).Error
+	}); err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, ErrServiceAccountNotFound
+		}
+		r.logger.Error("Failed to get service account", zap.Error(err))
+		return nil, err
+	}
+	return &account, nil
+}
+
+// GetActiveByTokenHash retrieves the unrevoked service account whose token hashes to tokenHash
+func (r *serviceAccountRepository) GetActiveByTokenHash(ctx context.Context, tokenHash string) (*models.ServiceAccount, error) {
+	var account models.ServiceAccount
+	if err := retryRead(ctx, r.retry, func() error {
+		return r.db.WithContext(ctx).
+			Where("token_hash = ? AND revoked_at IS NULL", tokenHash).
+			First(&account).Error
+	}); err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, ErrServiceAccountNotFound
+		}
+		r.logger.Error("Failed to get service account by token", zap.Error(err))
+		return nil, err
+	}
+	return &account, nil
+}
+
+// ListByWorkspace retrieves a workspace's service accounts, newest first, including revoked ones
+func (r *serviceAccountRepository) ListByWorkspace(ctx context.Context, workspaceID string) ([]*models.ServiceAccount, error) {
+	var accounts []*models.ServiceAccount
+	if err := retryRead(ctx, r.retry, func() error {
+		accounts = nil
+		return r.db.WithContext(ctx).
+			Where("workspace_id = ?", workspaceID).
+			Order("created_at DESC, id DESC").
+			Find(&accounts).Error
+	}); err != nil {
+		r.logger.Error("Failed to list service accounts", zap.Error(err))
+		return nil, err
+	}
+	return accounts, nil
+}
+
+// Revoke marks a service account revoked so its token no longer authenticates. Revoking an
+// already revoked account keeps the original revocation time.
+func (r *serviceAccountRepository) Revoke(ctx context.Context, id string, at time.Time) error {
+	var result *gorm.DB
+	if err := retryWrite(ctx, r.retry, func() error {
+		result = r.db.WithContext(ctx).Model(&models.ServiceAccount{}).
+			Where("id = ? AND revoked_at IS NULL", id).
+			Update("revoked_at", at.UTC())
+		return result.Error
+	}); err != nil {
+		r.logger.Error("Failed to revoke service account", zap.Error(err))
+		return err
+	}
+	return nil
+}
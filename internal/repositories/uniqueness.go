@@ -0,0 +1,112 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"gorm.io/gorm"
+
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/models"
+	"github.com/Reg-Kris/pyairtable-workspace-service/pkg/database"
+)
+
+// liveRows is the soft-delete predicate for tables whose rows are deleted by setting deleted_at
+const liveRows = "deleted_at IS NULL"
+
+// uniqueRule is a uniqueness constraint among a table's live rows: no two rows matching live
+// share the scope columns and column. check catches duplicates up front with a friendly error,
+// and the unique index created by migrate catches the ones racing past it, which violation
+// maps to the same error.
+type uniqueRule struct {
+	model  interface{}
+	table  string
+	scope  []string
+	column string
+	// names compares column as a name, following the repository's name matching
+	names bool
+	// live selects the rows the rule applies to; empty applies it to every row
+	live string
+	// index is the unique index enforcing the rule. For name rules, index+"_ci" enforces the
+	// case-insensitive form when that is configured.
+	index string
+	err   error
+}
+
+var (
+	uniqueWorkspaceName = uniqueRule{
+		model: &models.Workspace{}, table: "workspaces", scope: []string{"tenant_id"}, column: "name",
+		names: true, live: liveRows, index: "idx_workspaces_tenant_name", err: ErrDuplicateWorkspace,
+	}
+	uniqueProjectName = uniqueRule{
+		model: &models.Project{}, table: "projects", scope: []string{"workspace_id"}, column: "name",
+		names: true, live: liveRows, index: "idx_projects_workspace_name", err: ErrDuplicateProject,
+	}
+	uniqueAirtableBase = uniqueRule{
+		model: &models.AirtableBase{}, table: "airtable_bases", scope: []string{"project_id"}, column: "base_id",
+		live: liveRows, index: "idx_airtable_bases_project_base", err: ErrDuplicateAirtableBase,
+	}
+	// Members are hard-deleted and already unique by primary key
+	uniqueMember = uniqueRule{
+		model: &models.WorkspaceMember{}, table: "workspace_members", scope: []string{"workspace_id"}, column: "user_id",
+		index: "workspace_members_pkey", err: ErrDuplicateMember,
+	}
+)
+
+// check returns the rule's error if a row other than excludeID (when set) already holds value
+// in the scope given by scopeValues, one per scope column
+func (u uniqueRule) check(ctx context.Context, db *gorm.DB, names nameMatching, excludeID string, value string, scopeValues ...interface{}) error {
+	query := db.WithContext(ctx).Model(u.model)
+	for i, column := range u.scope {
+		query = query.Where(column+" = ?", scopeValues[i])
+	}
+	if u.live != "" {
+		query = query.Where(u.live)
+	}
+	if excludeID != "" {
+		query = query.Where("id != ?", excludeID)
+	}
+
+	if u.names {
+		var err error
+		if query, err = names.where(query, value); err != nil {
+			return err
+		}
+	} else {
+		query = query.Where(u.column+" = ?", value)
+	}
+
+	var count int64
+	if err := query.Count(&count).Error; err != nil {
+		return err
+	}
+	if count > 0 {
+		return u.err
+	}
+	return nil
+}
+
+// violation maps a unique violation of the rule's indexes to the rule's error. Other errors,
+// including violations of unrelated indexes, are returned unchanged.
+func (u uniqueRule) violation(err error) error {
+	if constraint, ok := database.UniqueViolation(err); ok && (constraint == u.index || constraint == u.index+"_ci") {
+		return u.err
+	}
+	return err
+}
+
+// indexes returns the statements creating the rule's partial unique indexes: the exact form
+// and, for name rules under case-insensitive matching, the folded form
+func (u uniqueRule) indexes(caseInsensitive bool) []string {
+	where := ""
+	if u.live != "" {
+		where = " WHERE " + u.live
+	}
+	scope := strings.Join(u.scope, ", ")
+
+	stmts := []string{fmt.Sprintf("CREATE UNIQUE INDEX IF NOT EXISTS %s ON %s (%s, %s)%s", u.index, u.table, scope, u.column, where)}
+	if u.names && caseInsensitive {
+		stmts = append(stmts, fmt.Sprintf("CREATE UNIQUE INDEX IF NOT EXISTS %s_ci ON %s (%s, LOWER(TRIM(%s)))%s", u.index, u.table, scope, u.column, where))
+	}
+	return stmts
+}
@@ -2,42 +2,47 @@ package repositories
 
 import (
 	"context"
+	"time"
 
 	"go.uber.org/zap"
 	"gorm.io/gorm"
 
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/config"
 	"github.com/Reg-Kris/pyairtable-workspace-service/internal/models"
+	"github.com/Reg-Kris/pyairtable-workspace-service/pkg/database"
 )
 
 type workspaceMemberRepository struct {
 	db     *gorm.DB
 	logger *zap.Logger
+	retry  database.RetryPolicy
+	sorts  config.SortConfig
 }
 
 // NewWorkspaceMemberRepository creates a new workspace member repository
-func NewWorkspaceMemberRepository(db *gorm.DB, logger *zap.Logger) WorkspaceMemberRepository {
+func NewWorkspaceMemberRepository(db *gorm.DB, config *config.Config, logger *zap.Logger) WorkspaceMemberRepository {
 	return &workspaceMemberRepository{
 		db:     db,
 		logger: logger,
+		retry:  retryPolicy(config),
+		sorts:  sortConfig(config),
 	}
 }
 
 // Add adds a member to a workspace
 func (r *workspaceMemberRepository) Add(ctx context.Context, member *models.WorkspaceMember) error {
 	// Check if member already exists
-	var count int64
-	if err := r.db.WithContext(ctx).Model(&models.WorkspaceMember{}).
-		Where("workspace_id = ? AND user_id = ?", member.WorkspaceID, member.UserID).
-		Count(&count).Error; err != nil {
-		r.logger.Error("Failed to check duplicate member", zap.Error(err))
+	if err := uniqueMember.check(ctx, r.db, nameMatching{}, "", member.UserID, member.WorkspaceID); err != nil {
+		if err != ErrDuplicateMember {
+			r.logger.Error("Failed to check duplicate member", zap.Error(err))
+		}
 		return err
 	}
-	
-	if count > 0 {
-		return ErrDuplicateMember
-	}
 
 	if err := r.db.WithContext(ctx).Create(member).Error; err != nil {
+		if err = uniqueMember.violation(err); err == ErrDuplicateMember {
+			return err
+		}
 		r.logger.Error("Failed to add workspace member", zap.Error(err))
 		return err
 	}
@@ -48,9 +53,11 @@ func (r *workspaceMemberRepository) Add(ctx context.Context, member *models.Work
 // GetByWorkspaceAndUser retrieves a member by workspace and user ID
 func (r *workspaceMemberRepository) GetByWorkspaceAndUser(ctx context.Context, workspaceID, userID string) (*models.WorkspaceMember, error) {
 	var member models.WorkspaceMember
-	if err := r.db.WithContext(ctx).
-		Where("workspace_id = ? AND user_id = ?", workspaceID, userID).
-		First(&member).Error; err != nil {
+	if err := retryRead(ctx, r.retry, func() error {
+		return r.db.WithContext(ctx).
+			Where("workspace_id = ? AND user_id = ?", workspaceID, userID).
+			First(&member).Error
+	}); err != nil {
 		if err == gorm.ErrRecordNotFound {
 			return nil, ErrMemberNotFound
 		}
@@ -74,13 +81,15 @@ func (r *workspaceMemberRepository) UpdateRole(ctx context.Context, workspaceID,
 		}
 	}
 
-	result := r.db.WithContext(ctx).Model(&models.WorkspaceMember{}).
-		Where("workspace_id = ? AND user_id = ?", workspaceID, userID).
-		Update("role", role)
-		
-	if result.Error != nil {
-		r.logger.Error("Failed to update member role", zap.Error(result.Error))
+	var result *gorm.DB
+	if err := retryWrite(ctx, r.retry, func() error {
+		result = r.db.WithContext(ctx).Model(&models.WorkspaceMember{}).
+			Where("workspace_id = ? AND user_id = ?", workspaceID, userID).
+			Update("role", role)
 		return result.Error
+	}); err != nil {
+		r.logger.Error("Failed to update member role", zap.Error(err))
+		return err
 	}
 
 	if result.RowsAffected == 0 {
@@ -90,7 +99,15 @@ func (r *workspaceMemberRepository) UpdateRole(ctx context.Context, workspaceID,
 	return nil
 }
 
-// Remove removes a member from a workspace
+// removeMemberSQL deletes a membership and records it in the history table in one statement
+const removeMemberSQL = `WITH removed AS (
+	DELETE FROM workspace_members WHERE workspace_id = ? AND user_id = ?
+	RETURNING workspace_id, user_id, role, joined_at
+)
+INSERT INTO workspace_membership_history (workspace_id, user_id, role, joined_at, left_at)
+SELECT workspace_id, user_id, role, joined_at, now() FROM removed`
+
+// Remove removes a member from a workspace, keeping a record of the membership in its history
 func (r *workspaceMemberRepository) Remove(ctx context.Context, workspaceID, userID string) error {
 	// Check if member is owner
 	member, err := r.GetByWorkspaceAndUser(ctx, workspaceID, userID)
@@ -109,13 +126,13 @@ func (r *workspaceMemberRepository) Remove(ctx context.Context, workspaceID, use
 		}
 	}
 
-	result := r.db.WithContext(ctx).
-		Where("workspace_id = ? AND user_id = ?", workspaceID, userID).
-		Delete(&models.WorkspaceMember{})
-		
-	if result.Error != nil {
-		r.logger.Error("Failed to remove workspace member", zap.Error(result.Error))
+	var result *gorm.DB
+	if err := retryWrite(ctx, r.retry, func() error {
+		result = r.db.WithContext(ctx).Exec(removeMemberSQL, workspaceID, userID)
 		return result.Error
+	}); err != nil {
+		r.logger.Error("Failed to remove workspace member", zap.Error(err))
+		return err
 	}
 
 	if result.RowsAffected == 0 {
@@ -132,7 +149,9 @@ func (r *workspaceMemberRepository) List(ctx context.Context, workspaceID string
 
 	// Count total records
 	var total int64
-	if err := query.Count(&total).Error; err != nil {
+	if err := retryRead(ctx, r.retry, func() error {
+		return query.Count(&total).Error
+	}); err != nil {
 		r.logger.Error("Failed to count workspace members", zap.Error(err))
 		return nil, 0, err
 	}
@@ -151,12 +170,19 @@ func (r *workspaceMemberRepository) List(ctx context.Context, workspaceID string
 	offset := (page - 1) * pageSize
 	query = query.Offset(offset).Limit(pageSize)
 
-	// Order by joined date
-	query = query.Order("joined_at DESC")
+	// Order by the configured default; members often share joined_at or role, so user_id breaks ties
+	sortBy, sortOrder := r.sorts.Default(config.SortListMembers)
+	if sortBy == "user_id" {
+		query = query.Order("user_id " + sortOrder)
+	} else {
+		query = query.Order(sortBy + " " + sortOrder + ", user_id ASC")
+	}
 
 	// Fetch members
 	var members []*models.WorkspaceMember
-	if err := query.Find(&members).Error; err != nil {
+	if err := retryRead(ctx, r.retry, func() error {
+		return query.Find(&members).Error
+	}); err != nil {
 		r.logger.Error("Failed to list workspace members", zap.Error(err))
 		return nil, 0, err
 	}
@@ -164,6 +190,38 @@ func (r *workspaceMemberRepository) List(ctx context.Context, workspaceID string
 	return members, total, nil
 }
 
+// Scan passes every member of a workspace to fn in batches ordered by user ID. Each batch is
+// read with a keyset query continuing after the last user ID seen, so no page cap applies and
+// deep batches cost the same as the first. Scanning stops at the first error fn returns.
+func (r *workspaceMemberRepository) Scan(ctx context.Context, workspaceID string, batchSize int, fn func(batch []*models.WorkspaceMember) error) error {
+	after := ""
+	for {
+		var batch []*models.WorkspaceMember
+		if err := retryRead(ctx, r.retry, func() error {
+			batch = nil
+			return r.db.WithContext(ctx).
+				Where("workspace_id = ? AND user_id > ?", workspaceID, after).
+				Order("user_id ASC").
+				Limit(batchSize).
+				Find(&batch).Error
+		}); err != nil {
+			r.logger.Error("Failed to scan workspace members", zap.Error(err))
+			return err
+		}
+
+		if len(batch) == 0 {
+			return nil
+		}
+		if err := fn(batch); err != nil {
+			return err
+		}
+		if len(batch) < batchSize {
+			return nil
+		}
+		after = batch[len(batch)-1].UserID
+	}
+}
+
 // CountOwners counts the number of owners in a workspace
 func (r *workspaceMemberRepository) CountOwners(ctx context.Context, workspaceID string) (int64, error) {
 	var count int64
@@ -177,6 +235,69 @@ func (r *workspaceMemberRepository) CountOwners(ctx context.Context, workspaceID
 	return count, nil
 }
 
+// GetPrimary retrieves the workspace's primary owner, or ErrMemberNotFound when it has none
+func (r *workspaceMemberRepository) GetPrimary(ctx context.Context, workspaceID string) (*models.WorkspaceMember, error) {
+	var member models.WorkspaceMember
+	if err := retryRead(ctx, r.retry, func() error {
+		return r.db.WithContext(ctx).
+			Where("workspace_id = ? AND is_primary", workspaceID).
+			First(&member).Error
+	}); err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, ErrMemberNotFound
+		}
+		r.logger.Error("Failed to get primary owner", zap.Error(err))
+		return nil, err
+	}
+
+	return &member, nil
+}
+
+// SetPrimary makes an owner the workspace's only primary owner and returns the user who was
+// primary before, if anyone, or ErrMemberNotFound when the user is not an owner. It is only
+// meaningful inside Transaction with the workspace locked, so concurrent reassignments cannot
+// both win.
+func (r *workspaceMemberRepository) SetPrimary(ctx context.Context, workspaceID, userID string) (string, error) {
+	var previous []string
+	if err := retryRead(ctx, r.retry, func() error {
+		return r.db.WithContext(ctx).Model(&models.WorkspaceMember{}).
+			Where("workspace_id = ? AND is_primary", workspaceID).
+			Pluck("user_id", &previous).Error
+	}); err != nil {
+		r.logger.Error("Failed to get primary owner", zap.Error(err))
+		return "", err
+	}
+
+	if err := retryWrite(ctx, r.retry, func() error {
+		return r.db.WithContext(ctx).Model(&models.WorkspaceMember{}).
+			Where("workspace_id = ? AND user_id <> ? AND is_primary", workspaceID, userID).
+			Update("is_primary", false).Error
+	}); err != nil {
+		r.logger.Error("Failed to clear primary owner", zap.Error(err))
+		return "", err
+	}
+
+	var result *gorm.DB
+	if err := retryWrite(ctx, r.retry, func() error {
+		result = r.db.WithContext(ctx).Model(&models.WorkspaceMember{}).
+			Where("workspace_id = ? AND user_id = ? AND role = ?", workspaceID, userID, models.WorkspaceRoleOwner).
+			Update("is_primary", true)
+		return result.Error
+	}); err != nil {
+		r.logger.Error("Failed to set primary owner", zap.Error(err))
+		return "", err
+	}
+
+	if result.RowsAffected == 0 {
+		return "", ErrMemberNotFound
+	}
+
+	if len(previous) == 0 {
+		return "", nil
+	}
+	return previous[0], nil
+}
+
 // IsLastOwner checks if the user is the last owner of the workspace
 func (r *workspaceMemberRepository) IsLastOwner(ctx context.Context, workspaceID, userID string) (bool, error) {
 	// First check if user is an owner
@@ -196,4 +317,89 @@ func (r *workspaceMemberRepository) IsLastOwner(ctx context.Context, workspaceID
 	}
 
 	return ownerCount <= 1, nil
-}
\ No newline at end of file
+}
+
+// lastAccessResolution is how stale a recorded access may get before another one is written,
+// so frequent reads of a workspace do not each cost a write
+const lastAccessResolution = time.Minute
+
+// TouchLastAccessed records that the user opened the workspace at the given time. Accesses
+// within lastAccessResolution of the recorded one are not written.
+func (r *workspaceMemberRepository) TouchLastAccessed(ctx context.Context, workspaceID, userID string, at time.Time) error {
+	at = at.UTC()
+	if err := retryWrite(ctx, r.retry, func() error {
+		return r.db.WithContext(ctx).Model(&models.WorkspaceMember{}).
+			Where("workspace_id = ? AND user_id = ?", workspaceID, userID).
+			Where("last_accessed_at IS NULL OR last_accessed_at < ?", at.Add(-lastAccessResolution)).
+			UpdateColumn("last_accessed_at", at).Error
+	}); err != nil {
+		r.logger.Error("Failed to record workspace access", zap.Error(err))
+		return err
+	}
+
+	return nil
+}
+
+// LastAccessed returns when the user last opened each of the workspaces, omitting those they
+// never opened
+func (r *workspaceMemberRepository) LastAccessed(ctx context.Context, userID string, workspaceIDs []string) (map[string]time.Time, error) {
+	accessed := make(map[string]time.Time)
+	if len(workspaceIDs) == 0 {
+		return accessed, nil
+	}
+
+	var members []models.WorkspaceMember
+	if err := retryRead(ctx, r.retry, func() error {
+		members = nil
+		return r.db.WithContext(ctx).
+			Select("workspace_id", "last_accessed_at").
+			Where("user_id = ? AND workspace_id IN ? AND last_accessed_at IS NOT NULL", userID, workspaceIDs).
+			Find(&members).Error
+	}); err != nil {
+		r.logger.Error("Failed to get workspace access times", zap.Error(err))
+		return nil, err
+	}
+
+	for _, member := range members {
+		accessed[member.WorkspaceID] = *member.LastAccessedAt
+	}
+	return accessed, nil
+}
+
+// ListHistory retrieves a page of a workspace's former members, latest to leave first. A user
+// who left more than once appears once per membership.
+func (r *workspaceMemberRepository) ListHistory(ctx context.Context, workspaceID string, page, pageSize int) ([]*models.PastWorkspaceMember, int64, error) {
+	query := r.db.WithContext(ctx).Model(&models.PastWorkspaceMember{}).
+		Where("workspace_id = ?", workspaceID)
+
+	var total int64
+	if err := retryRead(ctx, r.retry, func() error {
+		return query.Count(&total).Error
+	}); err != nil {
+		r.logger.Error("Failed to count membership history", zap.Error(err))
+		return nil, 0, err
+	}
+
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 {
+		pageSize = 20
+	}
+	if pageSize > 100 {
+		pageSize = 100
+	}
+
+	var members []*models.PastWorkspaceMember
+	if err := retryRead(ctx, r.retry, func() error {
+		return query.Order(orderWithTiebreaker("left_at", "DESC")).
+			Offset((page - 1) * pageSize).
+			Limit(pageSize).
+			Find(&members).Error
+	}); err != nil {
+		r.logger.Error("Failed to list membership history", zap.Error(err))
+		return nil, 0, err
+	}
+
+	return members, total, nil
+}
@@ -2,45 +2,51 @@ package repositories
 
 import (
 	"context"
-	"fmt"
 	"strings"
 	"time"
 
 	"go.uber.org/zap"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/config"
 	"github.com/Reg-Kris/pyairtable-workspace-service/internal/models"
+	"github.com/Reg-Kris/pyairtable-workspace-service/pkg/database"
 )
 
 type workspaceRepository struct {
-	db     *gorm.DB
-	logger *zap.Logger
+	db              *gorm.DB
+	logger          *zap.Logger
+	names           nameMatching
+	retry           database.RetryPolicy
+	sorts           config.SortConfig
 }
 
 // NewWorkspaceRepository creates a new workspace repository
-func NewWorkspaceRepository(db *gorm.DB, logger *zap.Logger) WorkspaceRepository {
+func NewWorkspaceRepository(db *gorm.DB, config *config.Config, logger *zap.Logger) WorkspaceRepository {
 	return &workspaceRepository{
-		db:     db,
-		logger: logger,
+		db:              db,
+		logger:          logger,
+		names:           newNameMatching(config),
+		retry:           retryPolicy(config),
+		sorts:           sortConfig(config),
 	}
 }
 
 // Create creates a new workspace
 func (r *workspaceRepository) Create(ctx context.Context, workspace *models.Workspace) error {
 	// Check if workspace with same name exists for tenant
-	var count int64
-	if err := r.db.WithContext(ctx).Model(&models.Workspace{}).
-		Where("tenant_id = ? AND name = ? AND deleted_at IS NULL", workspace.TenantID, workspace.Name).
-		Count(&count).Error; err != nil {
-		r.logger.Error("Failed to check duplicate workspace", zap.Error(err))
+	if err := uniqueWorkspaceName.check(ctx, r.db, r.names, "", workspace.Name, workspace.TenantID); err != nil {
+		if err != ErrDuplicateWorkspace {
+			r.logger.Error("Failed to check duplicate workspace", zap.Error(err))
+		}
 		return err
 	}
-	
-	if count > 0 {
-		return ErrDuplicateWorkspace
-	}
 
 	if err := r.db.WithContext(ctx).Create(workspace).Error; err != nil {
+		if err = uniqueWorkspaceName.violation(err); err == ErrDuplicateWorkspace {
+			return err
+		}
 		r.logger.Error("Failed to create workspace", zap.Error(err))
 		return err
 	}
@@ -48,16 +54,42 @@ func (r *workspaceRepository) Create(ctx context.Context, workspace *models.Work
 	return nil
 }
 
-// GetByID retrieves a workspace by ID
+// GetByID retrieves a workspace by ID. Members are deliberately not preloaded so the
+// detail payload stays small; ListMembers pages through them instead.
 func (r *workspaceRepository) GetByID(ctx context.Context, id string) (*models.Workspace, error) {
+	var workspace models.Workspace
+	if err := retryRead(ctx, r.retry, func() error {
+		return r.db.WithContext(ctx).
+			Where("id = ? AND deleted_at IS NULL", id).
+			First(&workspace).Error
+	}); err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, ErrWorkspaceNotFound
+		}
+		r.logger.Error("Failed to get workspace by ID", zap.Error(err), zap.String("id", id))
+		return nil, err
+	}
+
+	return &workspace, nil
+}
+
+// Lock reads a live workspace and row-locks it until the surrounding transaction ends,
+// FOR UPDATE when exclusive and FOR SHARE otherwise. It is only meaningful inside Transaction.
+func (r *workspaceRepository) Lock(ctx context.Context, id string, exclusive bool) (*models.Workspace, error) {
+	strength := "SHARE"
+	if exclusive {
+		strength = "UPDATE"
+	}
+
 	var workspace models.Workspace
 	if err := r.db.WithContext(ctx).
+		Clauses(clause.Locking{Strength: strength}).
 		Where("id = ? AND deleted_at IS NULL", id).
 		First(&workspace).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
 			return nil, ErrWorkspaceNotFound
 		}
-		r.logger.Error("Failed to get workspace by ID", zap.Error(err), zap.String("id", id))
+		r.logger.Error("Failed to lock workspace", zap.Error(err), zap.String("id", id))
 		return nil, err
 	}
 
@@ -67,9 +99,14 @@ func (r *workspaceRepository) GetByID(ctx context.Context, id string) (*models.W
 // GetByTenantAndName retrieves a workspace by tenant ID and name
 func (r *workspaceRepository) GetByTenantAndName(ctx context.Context, tenantID, name string) (*models.Workspace, error) {
 	var workspace models.Workspace
-	if err := r.db.WithContext(ctx).
-		Where("tenant_id = ? AND name = ? AND deleted_at IS NULL", tenantID, name).
-		First(&workspace).Error; err != nil {
+	if err := retryRead(ctx, r.retry, func() error {
+		query, err := r.names.where(r.db.WithContext(ctx).Model(&models.Workspace{}).
+			Where("tenant_id = ? AND deleted_at IS NULL", tenantID), name)
+		if err != nil {
+			return err
+		}
+		return query.First(&workspace).Error
+	}); err != nil {
 		if err == gorm.ErrRecordNotFound {
 			return nil, ErrWorkspaceNotFound
 		}
@@ -80,28 +117,39 @@ func (r *workspaceRepository) GetByTenantAndName(ctx context.Context, tenantID,
 	return &workspace, nil
 }
 
-// Update updates a workspace
+// Update writes a workspace's editable fields. They are listed explicitly rather than passing the
+// struct to Updates, which would persist any other field set on it and skip zero values such as a
+// cleared description.
 func (r *workspaceRepository) Update(ctx context.Context, workspace *models.Workspace) error {
 	// Check if another workspace with same name exists
 	if workspace.Name != "" {
-		var count int64
-		if err := r.db.WithContext(ctx).Model(&models.Workspace{}).
-			Where("tenant_id = ? AND name = ? AND id != ? AND deleted_at IS NULL", 
-				workspace.TenantID, workspace.Name, workspace.ID).
-			Count(&count).Error; err != nil {
-			r.logger.Error("Failed to check duplicate workspace", zap.Error(err))
+		if err := uniqueWorkspaceName.check(ctx, r.db, r.names, workspace.ID, workspace.Name, workspace.TenantID); err != nil {
+			if err != ErrDuplicateWorkspace {
+				r.logger.Error("Failed to check duplicate workspace", zap.Error(err))
+			}
 			return err
 		}
-		
-		if count > 0 {
-			return ErrDuplicateWorkspace
-		}
 	}
 
-	result := r.db.WithContext(ctx).Model(workspace).Updates(workspace)
-	if result.Error != nil {
-		r.logger.Error("Failed to update workspace", zap.Error(result.Error))
+	updates := map[string]interface{}{
+		"tenant_id":   workspace.TenantID,
+		"name":        workspace.Name,
+		"description": workspace.Description,
+	}
+	if workspace.Settings != nil {
+		updates["settings"] = workspace.Settings
+	}
+
+	var result *gorm.DB
+	if err := retryWrite(ctx, r.retry, func() error {
+		result = r.db.WithContext(ctx).Model(workspace).Updates(updates)
 		return result.Error
+	}); err != nil {
+		if err = uniqueWorkspaceName.violation(err); err == ErrDuplicateWorkspace {
+			return err
+		}
+		r.logger.Error("Failed to update workspace", zap.Error(err))
+		return err
 	}
 
 	if result.RowsAffected == 0 {
@@ -113,10 +161,33 @@ func (r *workspaceRepository) Update(ctx context.Context, workspace *models.Work
 
 // Delete soft deletes a workspace
 func (r *workspaceRepository) Delete(ctx context.Context, id string) error {
-	result := r.db.WithContext(ctx).Where("id = ?", id).Delete(&models.Workspace{})
-	if result.Error != nil {
-		r.logger.Error("Failed to delete workspace", zap.Error(result.Error))
+	var result *gorm.DB
+	if err := retryWrite(ctx, r.retry, func() error {
+		result = r.db.WithContext(ctx).Where("id = ?", id).Delete(&models.Workspace{})
+		return result.Error
+	}); err != nil {
+		r.logger.Error("Failed to delete workspace", zap.Error(err))
+		return err
+	}
+
+	if result.RowsAffected == 0 {
+		return ErrWorkspaceNotFound
+	}
+
+	return nil
+}
+
+// SetScheduledDeletion sets or, with a nil time, clears when a live workspace is to be deleted
+func (r *workspaceRepository) SetScheduledDeletion(ctx context.Context, id string, at *time.Time) error {
+	var result *gorm.DB
+	if err := retryWrite(ctx, r.retry, func() error {
+		result = r.db.WithContext(ctx).Model(&models.Workspace{}).
+			Where("id = ? AND deleted_at IS NULL", id).
+			Updates(map[string]interface{}{"scheduled_deletion_at": at})
 		return result.Error
+	}); err != nil {
+		r.logger.Error("Failed to set scheduled deletion", zap.Error(err), zap.String("id", id))
+		return err
 	}
 
 	if result.RowsAffected == 0 {
@@ -126,6 +197,83 @@ func (r *workspaceRepository) Delete(ctx context.Context, id string) error {
 	return nil
 }
 
+// ListDueForDeletion returns up to limit live workspaces whose scheduled deletion is at or
+// before now, earliest first
+func (r *workspaceRepository) ListDueForDeletion(ctx context.Context, now time.Time, limit int) ([]*models.Workspace, error) {
+	var workspaces []*models.Workspace
+	if err := retryRead(ctx, r.retry, func() error {
+		return r.db.WithContext(ctx).
+			Where("scheduled_deletion_at <= ? AND deleted_at IS NULL", now).
+			Order(orderWithTiebreaker("scheduled_deletion_at", "ASC")).
+			Limit(limit).
+			Find(&workspaces).Error
+	}); err != nil {
+		r.logger.Error("Failed to list workspaces due for deletion", zap.Error(err))
+		return nil, err
+	}
+
+	return workspaces, nil
+}
+
+// ListWithSetting returns the ID and settings of every live workspace whose settings contain key
+func (r *workspaceRepository) ListWithSetting(ctx context.Context, key string) ([]*models.Workspace, error) {
+	var workspaces []*models.Workspace
+	if err := retryRead(ctx, r.retry, func() error {
+		// jsonb_exists backs the ? operator, which GORM would read as a placeholder
+		return r.db.WithContext(ctx).
+			Select("id", "settings").
+			Where("jsonb_exists(settings, ?) AND deleted_at IS NULL", key).
+			Find(&workspaces).Error
+	}); err != nil {
+		r.logger.Error("Failed to list workspaces with setting", zap.Error(err), zap.String("key", key))
+		return nil, err
+	}
+
+	return workspaces, nil
+}
+
+// FindByIDs returns the live workspaces among ids, in no particular order
+func (r *workspaceRepository) FindByIDs(ctx context.Context, ids []string) ([]*models.Workspace, error) {
+	var workspaces []*models.Workspace
+	if err := retryRead(ctx, r.retry, func() error {
+		return r.db.WithContext(ctx).
+			Where("id IN ? AND deleted_at IS NULL", ids).
+			Find(&workspaces).Error
+	}); err != nil {
+		r.logger.Error("Failed to find workspaces by ID", zap.Error(err))
+		return nil, err
+	}
+
+	return workspaces, nil
+}
+
+// ResolveNames maps the live workspaces among ids to their names in one query. A non-empty
+// memberID keeps only the workspaces that user is a member of.
+func (r *workspaceRepository) ResolveNames(ctx context.Context, ids []string, memberID string) (map[string]string, error) {
+	var rows []struct {
+		ID   string
+		Name string
+	}
+	if err := retryRead(ctx, r.retry, func() error {
+		query := r.db.WithContext(ctx).Model(&models.Workspace{}).
+			Select("workspaces.id", "workspaces.name").
+			Where("workspaces.id IN ? AND workspaces.deleted_at IS NULL", ids)
+		if memberID != "" {
+			query = query.Joins("JOIN workspace_members ON workspace_members.workspace_id = workspaces.id AND workspace_members.user_id = ?", memberID)
+		}
+		return query.Scan(&rows).Error
+	}); err != nil {
+		r.logger.Error("Failed to resolve workspace names", zap.Error(err))
+		return nil, err
+	}
+
+	names := make(map[string]string, len(rows))
+	for _, row := range rows {
+		names[row.ID] = row.Name
+	}
+	return names, nil
+}
+
 // List retrieves workspaces based on filter
 func (r *workspaceRepository) List(ctx context.Context, filter *models.WorkspaceFilter) ([]*models.Workspace, int64, error) {
 	query := r.db.WithContext(ctx).Model(&models.Workspace{})
@@ -144,29 +292,52 @@ func (r *workspaceRepository) List(ctx context.Context, filter *models.Workspace
 		query = query.Where("LOWER(name) LIKE ? OR LOWER(description) LIKE ?", search, search)
 	}
 
-	if !filter.IncludeDeleted {
+	if filter.IncludeDeleted {
+		// Bypass the soft-delete scope so deleted rows are listed too
+		query = query.Unscoped()
+		if filter.DeletedVisibleTo != "" {
+			query = query.Where("workspaces.deleted_at IS NULL OR workspaces.id IN (?)", adminWorkspaceIDs(r.db, filter.DeletedVisibleTo))
+		}
+	} else {
 		query = query.Where("deleted_at IS NULL")
 	}
 
+	if filter.ModifiedAfter != nil {
+		// Soft deletes only set deleted_at, so deletions are matched on it separately
+		if filter.IncludeDeleted {
+			query = query.Where("(workspaces.updated_at > ? OR workspaces.deleted_at > ?)", *filter.ModifiedAfter, *filter.ModifiedAfter)
+		} else {
+			query = query.Where("workspaces.updated_at > ?", *filter.ModifiedAfter)
+		}
+	}
+
 	// Count total records
 	var total int64
-	if err := query.Count(&total).Error; err != nil {
+	if err := retryRead(ctx, r.retry, func() error {
+		return query.Count(&total).Error
+	}); err != nil {
 		r.logger.Error("Failed to count workspaces", zap.Error(err))
 		return nil, 0, err
 	}
 
-	// Apply sorting
-	sortBy := "created_at"
-	if filter.SortBy != "" {
-		sortBy = filter.SortBy
+	if filter.CountOnly {
+		return nil, total, nil
 	}
-	
-	sortOrder := "DESC"
-	if filter.SortOrder != "" && strings.ToUpper(filter.SortOrder) == "ASC" {
-		sortOrder = "ASC"
+
+	// Apply sorting
+	if filter.SortBy == models.WorkspaceSortLastAccessed && filter.AccessedBy != "" {
+		// Joined after counting; the membership key matches at most one row per workspace
+		order := "DESC"
+		if strings.ToUpper(filter.SortOrder) == "ASC" {
+			order = "ASC"
+		}
+		query = query.Select("workspaces.*").
+			Joins("LEFT JOIN workspace_members AS last_access ON last_access.workspace_id = workspaces.id AND last_access.user_id = ?", filter.AccessedBy).
+			Order("last_access.last_accessed_at " + order + " NULLS LAST, workspaces.id ASC")
+	} else {
+		sortBy, sortOrder := listSort(r.sorts, config.SortListWorkspaces, filter.SortBy, filter.SortOrder)
+		query = query.Order(orderWithTiebreaker(sortBy, sortOrder))
 	}
-	
-	query = query.Order(fmt.Sprintf("%s %s", sortBy, sortOrder))
 
 	// Apply pagination
 	page := filter.Page
@@ -187,7 +358,9 @@ func (r *workspaceRepository) List(ctx context.Context, filter *models.Workspace
 
 	// Fetch workspaces
 	var workspaces []*models.Workspace
-	if err := query.Find(&workspaces).Error; err != nil {
+	if err := retryRead(ctx, r.retry, func() error {
+		return query.Find(&workspaces).Error
+	}); err != nil {
 		r.logger.Error("Failed to list workspaces", zap.Error(err))
 		return nil, 0, err
 	}
@@ -195,16 +368,25 @@ func (r *workspaceRepository) List(ctx context.Context, filter *models.Workspace
 	return workspaces, total, nil
 }
 
-// GetStats retrieves workspace statistics for a tenant
-func (r *workspaceRepository) GetStats(ctx context.Context, tenantID string) (*models.WorkspaceStats, error) {
+// GetStats retrieves workspace statistics for a tenant. An empty tenantID computes platform-wide
+// statistics, with the per-tenant breakdown limited to one page of the tenants with the most workspaces.
+func (r *workspaceRepository) GetStats(ctx context.Context, tenantID string, filter *models.WorkspaceStatsFilter) (*models.WorkspaceStats, error) {
 	stats := &models.WorkspaceStats{
 		WorkspacesByTenant: make(map[string]int64),
 		ProjectsByStatus:   make(map[string]int64),
 	}
 
+	// Restrict a query to the tenant unless global statistics were requested
+	scoped := func(query *gorm.DB, column string) *gorm.DB {
+		if tenantID == "" {
+			return query
+		}
+		return query.Where(column+" = ?", tenantID)
+	}
+
 	// Total workspaces
-	if err := r.db.WithContext(ctx).Model(&models.Workspace{}).
-		Where("tenant_id = ? AND deleted_at IS NULL", tenantID).
+	if err := scoped(r.db.WithContext(ctx).Model(&models.Workspace{}), "tenant_id").
+		Where("deleted_at IS NULL").
 		Count(&stats.TotalWorkspaces).Error; err != nil {
 		r.logger.Error("Failed to count total workspaces", zap.Error(err))
 		return nil, err
@@ -214,31 +396,31 @@ func (r *workspaceRepository) GetStats(ctx context.Context, tenantID string) (*m
 	stats.ActiveWorkspaces = stats.TotalWorkspaces
 
 	// Total projects
-	if err := r.db.WithContext(ctx).
+	if err := scoped(r.db.WithContext(ctx).
 		Table("projects").
-		Joins("JOIN workspaces ON projects.workspace_id = workspaces.id").
-		Where("workspaces.tenant_id = ? AND projects.deleted_at IS NULL", tenantID).
+		Joins("JOIN workspaces ON projects.workspace_id = workspaces.id"), "workspaces.tenant_id").
+		Where("projects.deleted_at IS NULL").
 		Count(&stats.TotalProjects).Error; err != nil {
 		r.logger.Error("Failed to count total projects", zap.Error(err))
 		return nil, err
 	}
 
 	// Active projects
-	if err := r.db.WithContext(ctx).
+	if err := scoped(r.db.WithContext(ctx).
 		Table("projects").
-		Joins("JOIN workspaces ON projects.workspace_id = workspaces.id").
-		Where("workspaces.tenant_id = ? AND projects.status = 'active' AND projects.deleted_at IS NULL", tenantID).
+		Joins("JOIN workspaces ON projects.workspace_id = workspaces.id"), "workspaces.tenant_id").
+		Where("projects.status = 'active' AND projects.deleted_at IS NULL").
 		Count(&stats.ActiveProjects).Error; err != nil {
 		r.logger.Error("Failed to count active projects", zap.Error(err))
 		return nil, err
 	}
 
 	// Total Airtable bases
-	if err := r.db.WithContext(ctx).
+	if err := scoped(r.db.WithContext(ctx).
 		Table("airtable_bases").
 		Joins("JOIN projects ON airtable_bases.project_id = projects.id").
-		Joins("JOIN workspaces ON projects.workspace_id = workspaces.id").
-		Where("workspaces.tenant_id = ? AND airtable_bases.deleted_at IS NULL", tenantID).
+		Joins("JOIN workspaces ON projects.workspace_id = workspaces.id"), "workspaces.tenant_id").
+		Where("airtable_bases.deleted_at IS NULL").
 		Count(&stats.TotalAirtableBases).Error; err != nil {
 		r.logger.Error("Failed to count total airtable bases", zap.Error(err))
 		return nil, err
@@ -250,11 +432,11 @@ func (r *workspaceRepository) GetStats(ctx context.Context, tenantID string) (*m
 		Count  int64
 	}
 	var statusCounts []statusCount
-	if err := r.db.WithContext(ctx).
+	if err := scoped(r.db.WithContext(ctx).
 		Table("projects").
 		Select("projects.status, COUNT(*) as count").
-		Joins("JOIN workspaces ON projects.workspace_id = workspaces.id").
-		Where("workspaces.tenant_id = ? AND projects.deleted_at IS NULL", tenantID).
+		Joins("JOIN workspaces ON projects.workspace_id = workspaces.id"), "workspaces.tenant_id").
+		Where("projects.deleted_at IS NULL").
 		Group("projects.status").
 		Scan(&statusCounts).Error; err != nil {
 		r.logger.Error("Failed to count projects by status", zap.Error(err))
@@ -265,7 +447,155 @@ func (r *workspaceRepository) GetStats(ctx context.Context, tenantID string) (*m
 		stats.ProjectsByStatus[sc.Status] = sc.Count
 	}
 
-	stats.LastUpdated = time.Now()
+	// Workspaces by tenant, one page at a time so the response stays bounded
+	if tenantID == "" {
+		if err := r.countWorkspacesByTenant(ctx, filter, stats); err != nil {
+			return nil, err
+		}
+	}
+
+	stats.LastUpdated = time.Now().UTC()
 
 	return stats, nil
-}
\ No newline at end of file
+}
+
+// GetTrends computes the tenant's average projects and median members per live workspace, and
+// the workspaces and projects created on each of the last days UTC days including today.
+// Creation counts include rows deleted since, as they describe when growth happened.
+func (r *workspaceRepository) GetTrends(ctx context.Context, tenantID string, days int) (*models.WorkspaceTrends, error) {
+	trends := &models.WorkspaceTrends{Days: days}
+
+	if err := r.db.WithContext(ctx).Raw(`
+		SELECT COALESCE(AVG(project_count), 0) FROM (
+			SELECT COUNT(projects.id) AS project_count
+			FROM workspaces
+			LEFT JOIN projects ON projects.workspace_id = workspaces.id AND projects.deleted_at IS NULL
+			WHERE workspaces.tenant_id = ? AND workspaces.deleted_at IS NULL
+			GROUP BY workspaces.id
+		) sizes`, tenantID).
+		Scan(&trends.AvgProjectsPerWorkspace).Error; err != nil {
+		r.logger.Error("Failed to average projects per workspace", zap.Error(err))
+		return nil, err
+	}
+
+	if err := r.db.WithContext(ctx).Raw(`
+		SELECT COALESCE(percentile_cont(0.5) WITHIN GROUP (ORDER BY member_count), 0) FROM (
+			SELECT COUNT(workspace_members.user_id) AS member_count
+			FROM workspaces
+			LEFT JOIN workspace_members ON workspace_members.workspace_id = workspaces.id
+			WHERE workspaces.tenant_id = ? AND workspaces.deleted_at IS NULL
+			GROUP BY workspaces.id
+		) sizes`, tenantID).
+		Scan(&trends.MedianMembersPerWorkspace).Error; err != nil {
+		r.logger.Error("Failed to compute median members per workspace", zap.Error(err))
+		return nil, err
+	}
+
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	since := today.AddDate(0, 0, -(days - 1))
+
+	type dayCount struct {
+		Day   time.Time
+		Count int64
+	}
+	countByDay := func(query *gorm.DB, column string) (map[string]int64, error) {
+		var rows []dayCount
+		day := "(" + column + " AT TIME ZONE 'UTC')::date"
+		if err := query.
+			Select(day+" AS day, COUNT(*) AS count").
+			Where(column+" >= ?", since).
+			Group("day").
+			Scan(&rows).Error; err != nil {
+			return nil, err
+		}
+
+		counts := make(map[string]int64, len(rows))
+		for _, row := range rows {
+			counts[row.Day.Format("2006-01-02")] = row.Count
+		}
+		return counts, nil
+	}
+
+	workspaceCounts, err := countByDay(r.db.WithContext(ctx).
+		Table("workspaces").
+		Where("tenant_id = ?", tenantID), "created_at")
+	if err != nil {
+		r.logger.Error("Failed to count workspaces created per day", zap.Error(err))
+		return nil, err
+	}
+
+	projectCounts, err := countByDay(r.db.WithContext(ctx).
+		Table("projects").
+		Joins("JOIN workspaces ON projects.workspace_id = workspaces.id").
+		Where("workspaces.tenant_id = ?", tenantID), "projects.created_at")
+	if err != nil {
+		r.logger.Error("Failed to count projects created per day", zap.Error(err))
+		return nil, err
+	}
+
+	// Every day in the window is listed, including days with no creations
+	trends.Series = make([]models.DailyCreations, 0, days)
+	for day := since; !day.After(today); day = day.AddDate(0, 0, 1) {
+		date := day.Format("2006-01-02")
+		trends.Series = append(trends.Series, models.DailyCreations{
+			Date:       date,
+			Workspaces: workspaceCounts[date],
+			Projects:   projectCounts[date],
+		})
+	}
+
+	trends.LastUpdated = time.Now().UTC()
+
+	return trends, nil
+}
+
+// countWorkspacesByTenant fills the per-tenant breakdown with the requested page of tenants,
+// ordered by workspace count
+func (r *workspaceRepository) countWorkspacesByTenant(ctx context.Context, filter *models.WorkspaceStatsFilter, stats *models.WorkspaceStats) error {
+	page := 1
+	pageSize := 50
+	if filter != nil {
+		if filter.TenantPage > 0 {
+			page = filter.TenantPage
+		}
+		if filter.TenantPageSize > 0 {
+			pageSize = filter.TenantPageSize
+		}
+	}
+	if pageSize > 100 {
+		pageSize = 100
+	}
+
+	if err := r.db.WithContext(ctx).Model(&models.Workspace{}).
+		Where("deleted_at IS NULL").
+		Distinct("tenant_id").
+		Count(&stats.TotalTenants).Error; err != nil {
+		r.logger.Error("Failed to count tenants", zap.Error(err))
+		return err
+	}
+
+	type tenantCount struct {
+		TenantID string
+		Count    int64
+	}
+	var tenantCounts []tenantCount
+	if err := r.db.WithContext(ctx).Model(&models.Workspace{}).
+		Select("tenant_id, COUNT(*) as count").
+		Where("deleted_at IS NULL").
+		Group("tenant_id").
+		Order("count DESC, tenant_id ASC").
+		Offset((page - 1) * pageSize).
+		Limit(pageSize).
+		Scan(&tenantCounts).Error; err != nil {
+		r.logger.Error("Failed to count workspaces by tenant", zap.Error(err))
+		return err
+	}
+
+	for _, tc := range tenantCounts {
+		stats.WorkspacesByTenant[tc.TenantID] = tc.Count
+	}
+	stats.TenantPage = page
+	stats.TenantPageSize = pageSize
+
+	return nil
+}
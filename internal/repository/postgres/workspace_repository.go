@@ -36,13 +36,13 @@ func (r *workspaceRepository) Create(ctx context.Context, workspace *models.Work
 	return nil
 }
 
-// FindByID finds a workspace by ID
+// FindByID finds a workspace by ID. Members are not preloaded since a workspace can
+// have thousands of them; they are served by the paginated members listing instead.
 func (r *workspaceRepository) FindByID(ctx context.Context, id string) (*models.Workspace, error) {
 	var workspace models.Workspace
 	
 	if err := r.db.WithContext(ctx).
 		Preload("Projects").
-		Preload("Members").
 		First(&workspace, "id = ?", id).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
 			return nil, fmt.Errorf("workspace not found")
@@ -2,6 +2,7 @@ package services
 
 import (
 	"context"
+	"strings"
 	"time"
 
 	"go.uber.org/zap"
@@ -16,19 +17,21 @@ type airtableBaseService struct {
 	config       *config.Config
 	logger       *zap.Logger
 	auditService AuditService
+	gateway      AirtableGateway
 }
 
 // NewAirtableBaseService creates a new Airtable base service
-func NewAirtableBaseService(repos *repositories.Repositories, config *config.Config, logger *zap.Logger, auditService AuditService) AirtableBaseService {
+func NewAirtableBaseService(repos *repositories.Repositories, config *config.Config, logger *zap.Logger, auditService AuditService, gateway AirtableGateway) AirtableBaseService {
 	return &airtableBaseService{
 		repos:        repos,
 		config:       config,
 		logger:       logger,
 		auditService: auditService,
+		gateway:      gateway,
 	}
 }
 
-// ConnectBase connects an Airtable base to a project
+// ConnectBase connects an Airtable base to a project, rejecting a base already connected to it
 func (s *airtableBaseService) ConnectBase(ctx context.Context, projectID, userID string, req *models.CreateAirtableBaseRequest) (*models.AirtableBase, error) {
 	// Get project and check access
 	project, err := s.repos.Project.GetByID(ctx, projectID)
@@ -41,34 +44,260 @@ func (s *airtableBaseService) ConnectBase(ctx context.Context, projectID, userID
 		return nil, err
 	}
 
-	// TODO: Validate base exists in Airtable via Airtable Gateway
-	// For now, we'll trust the base ID
+	return s.connectBase(ctx, project, userID, req)
+}
+
+// UpsertBase connects an Airtable base to a project or, when it is already connected, updates
+// the existing connection from the request instead, keeping the stored values of fields the
+// request leaves out. It reports whether a connection was created.
+func (s *airtableBaseService) UpsertBase(ctx context.Context, projectID, userID string, req *models.UpsertAirtableBaseRequest) (*models.AirtableBase, bool, error) {
+	project, err := s.repos.Project.GetByID(ctx, projectID)
+	if err != nil {
+		return nil, false, err
+	}
+
+	if err := s.checkProjectAccess(ctx, project, userID, models.WorkspaceRoleMember); err != nil {
+		return nil, false, err
+	}
+
+	base, err := s.repos.AirtableBase.GetByProjectAndBaseID(ctx, projectID, req.BaseID)
+	if err == repositories.ErrAirtableBaseNotFound {
+		base, err = s.connectBase(ctx, project, userID, req.CreateRequest())
+		if err != ErrAirtableBaseExists {
+			return base, err == nil, err
+		}
+		// Connected concurrently since the lookup; update that connection instead
+		base, err = s.repos.AirtableBase.GetByProjectAndBaseID(ctx, projectID, req.BaseID)
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	base.Project = project
+
+	changes := make(map[string]interface{})
+	if base.Name != req.Name {
+		changes["name"] = map[string]interface{}{"old": base.Name, "new": req.Name}
+		base.Name = req.Name
+	}
+	if req.Description != nil && base.Description != *req.Description {
+		changes["description"] = map[string]interface{}{"old": base.Description, "new": *req.Description}
+		base.Description = *req.Description
+	}
+	if req.SyncEnabled != nil && base.SyncEnabled != *req.SyncEnabled {
+		changes["sync_enabled"] = map[string]interface{}{"old": base.SyncEnabled, "new": *req.SyncEnabled}
+		base.SyncEnabled = *req.SyncEnabled
+	}
+	// Settings are only replaced when sent, as on update
+	if req.Settings != nil {
+		changes["settings"] = map[string]interface{}{"old": base.Settings, "new": req.Settings}
+		base.Settings = req.Settings
+	}
+
+	if len(changes) == 0 {
+		return base, false, nil
+	}
+
+	if err := s.repos.AirtableBase.Update(ctx, base); err != nil {
+		return nil, false, err
+	}
+
+	_ = s.auditService.LogAction(ctx, project.WorkspaceID, userID, models.AuditActionBaseUpdated, models.AuditResourceAirtableBase, base.ID, changes)
+
+	return base, false, nil
+}
+
+// connectBase creates a connection of an Airtable base to a project the caller may edit, once
+// the Airtable gateway, when one is configured, confirms the base exists
+func (s *airtableBaseService) connectBase(ctx context.Context, project *models.Project, userID string, req *models.CreateAirtableBaseRequest) (*models.AirtableBase, error) {
+	if s.gateway != nil {
+		if _, err := s.refreshMetadata(ctx, req.BaseID); err != nil {
+			s.logger.Warn("Failed to verify base with gateway",
+				zap.String("base_id", req.BaseID),
+				zap.Error(err))
+			return nil, ErrAirtableBaseUnverified
+		}
+	}
+
+	// Check base quota for workspace
+	if limit := quotaLimits(s.config).BasesPerWorkspace; limit > 0 {
+		baseCount, err := s.repos.AirtableBase.CountByWorkspace(ctx, project.WorkspaceID)
+		if err != nil {
+			return nil, err
+		}
+		if overQuota(baseCount, limit) {
+			return nil, ErrQuotaExceeded
+		}
+	}
 
 	// Create Airtable base connection
+	base := newAirtableBase(project, userID, req)
+	if err := s.repos.AirtableBase.Create(ctx, base); err != nil {
+		if err == repositories.ErrDuplicateAirtableBase {
+			return nil, ErrAirtableBaseExists
+		}
+		return nil, err
+	}
+
+	// Set project reference
+	base.Project = project
+
+	invalidateWorkspaceStats(ctx, s.repos, project.WorkspaceID)
+	s.auditConnected(ctx, project, userID, base)
+
+	return base, nil
+}
+
+// ConnectBases connects several Airtable bases to a project in one transaction. Each base is
+// first verified with the Airtable gateway when one is configured. Duplicates, whether already
+// connected or repeated in the request, are skipped and reported as ErrAirtableBaseExists; a
+// base connected concurrently while the batch is inserted fails it with ErrAirtableBaseExists.
+// The results line up with reqs. Connecting more bases than the quota has left fails the
+// whole request with ErrQuotaExceeded.
+func (s *airtableBaseService) ConnectBases(ctx context.Context, projectID, userID string, reqs []models.CreateAirtableBaseRequest) ([]*ConnectBaseResult, error) {
+	project, err := s.repos.Project.GetByID(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.checkProjectAccess(ctx, project, userID, models.WorkspaceRoleMember); err != nil {
+		return nil, err
+	}
+
+	results := make([]*ConnectBaseResult, len(reqs))
+	requested := make(map[string]bool, len(reqs))
+	for i := range reqs {
+		results[i] = &ConnectBaseResult{}
+		if requested[reqs[i].BaseID] {
+			results[i].Err = ErrAirtableBaseExists
+			continue
+		}
+		requested[reqs[i].BaseID] = true
+
+		if s.gateway != nil {
+			if _, err := s.refreshMetadata(ctx, reqs[i].BaseID); err != nil {
+				s.logger.Warn("Failed to verify base with gateway",
+					zap.String("base_id", reqs[i].BaseID),
+					zap.Error(err))
+				results[i].Err = ErrAirtableBaseUnverified
+			}
+		}
+	}
+
+	var tenantID string
+	err = s.repos.Transaction(ctx, func(tx *repositories.Repositories) error {
+		// The workspace lock keeps concurrent batches from overrunning the quota together
+		workspace, err := tx.Workspace.Lock(ctx, project.WorkspaceID, true)
+		if err != nil {
+			return err
+		}
+		tenantID = workspace.TenantID
+
+		var pending []int
+		for i, result := range results {
+			if result.Err != nil {
+				continue
+			}
+			if _, err := tx.AirtableBase.GetByProjectAndBaseID(ctx, project.ID, reqs[i].BaseID); err == nil {
+				result.Err = ErrAirtableBaseExists
+				continue
+			} else if err != repositories.ErrAirtableBaseNotFound {
+				return err
+			}
+			pending = append(pending, i)
+		}
+
+		if limit := quotaLimits(s.config).BasesPerWorkspace; limit > 0 && len(pending) > 0 {
+			baseCount, err := tx.AirtableBase.CountByWorkspace(ctx, project.WorkspaceID)
+			if err != nil {
+				return err
+			}
+			if baseCount+int64(len(pending)) > int64(limit) {
+				return ErrQuotaExceeded
+			}
+		}
+
+		for _, i := range pending {
+			base := newAirtableBase(project, userID, &reqs[i])
+			if err := tx.AirtableBase.Create(ctx, base); err != nil {
+				// A failed insert aborts the transaction, so a base connected since the
+				// check above fails the whole batch
+				if err == repositories.ErrDuplicateAirtableBase {
+					return ErrAirtableBaseExists
+				}
+				return err
+			}
+			results[i].Base = base
+		}
+		return nil
+	})
+	if err != nil {
+		if err == repositories.ErrWorkspaceNotFound {
+			return nil, ErrWorkspaceNotFound
+		}
+		return nil, err
+	}
+
+	connected := false
+	for _, result := range results {
+		if result.Base != nil {
+			result.Base.Project = project
+			s.auditConnected(ctx, project, userID, result.Base)
+			connected = true
+		}
+	}
+	if connected {
+		_ = s.repos.Cache.InvalidateTenantStats(ctx, tenantID)
+	}
+
+	return results, nil
+}
+
+// newAirtableBase builds the connection of an Airtable base to a project
+func newAirtableBase(project *models.Project, userID string, req *models.CreateAirtableBaseRequest) *models.AirtableBase {
 	base := &models.AirtableBase{
-		ProjectID:   projectID,
+		ProjectID:   project.ID,
 		BaseID:      req.BaseID,
 		Name:        req.Name,
 		Description: req.Description,
 		SyncEnabled: req.SyncEnabled,
+		SyncStatus:  models.AirtableSyncStatusIdle,
+		Settings:    req.Settings,
+		CreatedBy:   userID,
 	}
 
-	if err := s.repos.AirtableBase.Create(ctx, base); err != nil {
-		return nil, err
+	if base.Settings == nil {
+		base.Settings = make(models.JSONMap)
 	}
+	return base
+}
 
-	// Set project reference
-	base.Project = project
-
-	// Log audit
-	_ = s.auditService.LogAction(ctx, project.WorkspaceID, userID, "airtable_base.connected", "airtable_base", base.ID, map[string]interface{}{
-		"base_id":      req.BaseID,
-		"name":         req.Name,
-		"project_id":   projectID,
-		"sync_enabled": req.SyncEnabled,
+// auditConnected records a base connection
+func (s *airtableBaseService) auditConnected(ctx context.Context, project *models.Project, userID string, base *models.AirtableBase) {
+	_ = s.auditService.LogAction(ctx, project.WorkspaceID, userID, models.AuditActionBaseConnected, models.AuditResourceAirtableBase, base.ID, map[string]interface{}{
+		"base_id":      base.BaseID,
+		"name":         base.Name,
+		"project_id":   project.ID,
+		"sync_enabled": base.SyncEnabled,
 	})
+}
 
-	return base, nil
+// ResolveWorkspace returns the ID of the workspace an Airtable base's project belongs to,
+// without checking the caller's access. It is meant for routing decisions such as the write
+// concurrency cap.
+func (s *airtableBaseService) ResolveWorkspace(ctx context.Context, baseID string) (string, error) {
+	base, err := s.repos.AirtableBase.GetByID(ctx, baseID)
+	if err != nil {
+		return "", err
+	}
+	if base.Project != nil {
+		return base.Project.WorkspaceID, nil
+	}
+
+	project, err := s.repos.Project.GetByID(ctx, base.ProjectID)
+	if err != nil {
+		return "", err
+	}
+	return project.WorkspaceID, nil
 }
 
 // GetBase retrieves an Airtable base by ID
@@ -118,6 +347,11 @@ func (s *airtableBaseService) UpdateBase(ctx context.Context, baseID, userID str
 		return nil, err
 	}
 
+	// Nothing to write or audit
+	if req.IsEmpty() {
+		return base, nil
+	}
+
 	// Track changes for audit
 	changes := make(map[string]interface{})
 
@@ -146,6 +380,15 @@ func (s *airtableBaseService) UpdateBase(ctx context.Context, baseID, userID str
 		base.SyncEnabled = *req.SyncEnabled
 	}
 
+	if req.Settings != nil {
+		settings := replacementSettings(req.Settings)
+		changes["settings"] = map[string]interface{}{
+			"old": base.Settings,
+			"new": settings,
+		}
+		base.Settings = settings
+	}
+
 	// Update in database
 	if err := s.repos.AirtableBase.Update(ctx, base); err != nil {
 		return nil, err
@@ -153,14 +396,14 @@ func (s *airtableBaseService) UpdateBase(ctx context.Context, baseID, userID str
 
 	// Log audit
 	if len(changes) > 0 {
-		_ = s.auditService.LogAction(ctx, base.Project.WorkspaceID, userID, "airtable_base.updated", "airtable_base", baseID, changes)
+		_ = s.auditService.LogAction(ctx, base.Project.WorkspaceID, userID, models.AuditActionBaseUpdated, models.AuditResourceAirtableBase, baseID, changes)
 	}
 
 	return base, nil
 }
 
 // DisconnectBase disconnects an Airtable base from a project
-func (s *airtableBaseService) DisconnectBase(ctx context.Context, baseID, userID string) error {
+func (s *airtableBaseService) DisconnectBase(ctx context.Context, baseID, userID string, force bool) error {
 	// Get base
 	base, err := s.repos.AirtableBase.GetByID(ctx, baseID)
 	if err != nil {
@@ -181,22 +424,37 @@ func (s *airtableBaseService) DisconnectBase(ctx context.Context, baseID, userID
 		return err
 	}
 
+	// Disconnecting mid-sync can leave partially copied data behind
+	midSync := base.SyncStatus == models.AirtableSyncStatusPending
+	if midSync && !force {
+		return ErrSyncInProgress
+	}
+
 	// Delete base connection
 	if err := s.repos.AirtableBase.Delete(ctx, baseID); err != nil {
 		return err
 	}
+	invalidateWorkspaceStats(ctx, s.repos, base.Project.WorkspaceID)
 
 	// Log audit
-	_ = s.auditService.LogAction(ctx, base.Project.WorkspaceID, userID, "airtable_base.disconnected", "airtable_base", baseID, map[string]interface{}{
+	changes := map[string]interface{}{
 		"base_id": base.BaseID,
 		"name":    base.Name,
-	})
+	}
+	if midSync {
+		changes["forced_during_sync"] = true
+	}
+	_ = s.auditService.LogAction(ctx, base.Project.WorkspaceID, userID, models.AuditActionBaseDisconnected, models.AuditResourceAirtableBase, baseID, changes)
 
 	return nil
 }
 
 // ListBases lists Airtable bases based on filter
 func (s *airtableBaseService) ListBases(ctx context.Context, filter *models.AirtableBaseFilter, userID string) (*models.AirtableBaseListResponse, error) {
+	if err := validateSearch(s.config, filter.Search); err != nil {
+		return nil, err
+	}
+
 	// If project ID is provided, check access
 	if filter.ProjectID != "" {
 		project, err := s.repos.Project.GetByID(ctx, filter.ProjectID)
@@ -245,11 +503,91 @@ func (s *airtableBaseService) ListBases(ctx context.Context, filter *models.Airt
 	}, nil
 }
 
+// ListUserBases lists the bases in every workspace the user is a member of; access is
+// filtered in the query so pagination counts only accessible bases
+func (s *airtableBaseService) ListUserBases(ctx context.Context, userID string, filter *models.AirtableBaseFilter) (*models.AirtableBaseListResponse, error) {
+	filter.AccessibleBy = userID
+	return s.ListBases(ctx, filter, userID)
+}
+
+// LookupBases finds the connections of an Airtable base, by its Airtable base_id, in the
+// workspaces the user can view. Connections elsewhere are left out, and finding none is
+// reported as not found so the lookup does not reveal other tenants' connections.
+func (s *airtableBaseService) LookupBases(ctx context.Context, airtableBaseID, userID string) ([]*models.AirtableBase, error) {
+	airtableBaseID = strings.TrimSpace(airtableBaseID)
+	if airtableBaseID == "" {
+		return nil, ErrInvalidInput
+	}
+
+	bases, err := s.repos.AirtableBase.FindByBaseID(ctx, airtableBaseID)
+	if err != nil {
+		return nil, err
+	}
+
+	visible := make([]*models.AirtableBase, 0, len(bases))
+	access := make(map[string]bool)
+	for _, base := range bases {
+		// The project preload is empty when the project has been deleted
+		if base.Project == nil {
+			continue
+		}
+
+		allowed, checked := access[base.Project.WorkspaceID]
+		if !checked {
+			err := s.checkProjectAccess(ctx, base.Project, userID, models.WorkspaceRoleViewer)
+			if err != nil && err != ErrUnauthorized {
+				return nil, err
+			}
+			allowed = err == nil
+			access[base.Project.WorkspaceID] = allowed
+		}
+		if allowed {
+			visible = append(visible, base)
+		}
+	}
+
+	if len(visible) == 0 {
+		return nil, ErrAirtableBaseNotFound
+	}
+
+	return visible, nil
+}
+
+// SetWorkspaceSync pauses or resumes syncing for every base across the workspace's projects
+// in a single update, returning the number of bases changed
+func (s *airtableBaseService) SetWorkspaceSync(ctx context.Context, workspaceID, userID string, enabled bool) (int64, error) {
+	// Check user has admin role in workspace
+	member, err := getMember(ctx, s.repos, workspaceID, userID)
+	if err != nil {
+		if err == repositories.ErrMemberNotFound {
+			return 0, ErrUnauthorized
+		}
+		return 0, err
+	}
+
+	if !hasRequiredRole(member.Role, models.WorkspaceRoleAdmin) {
+		return 0, ErrUnauthorized
+	}
+
+	updated, err := s.repos.AirtableBase.SetSyncEnabledForWorkspace(ctx, workspaceID, enabled)
+	if err != nil {
+		return 0, err
+	}
+
+	// Log audit
+	_ = s.auditService.LogAction(ctx, workspaceID, userID, models.AuditActionWorkspaceSyncUpdated, models.AuditResourceWorkspace, workspaceID, map[string]interface{}{
+		"sync_enabled":  enabled,
+		"bases_updated": updated,
+	})
+
+	return updated, nil
+}
+
 // UpdateSyncStatus updates the sync status of an Airtable base
 func (s *airtableBaseService) UpdateSyncStatus(ctx context.Context, baseID string) error {
 	// This would typically be called by a sync service
 	// For now, just update the last sync time
-	now := time.Now()
+	now := time.Now().UTC()
 	if err := s.repos.AirtableBase.UpdateSyncTime(ctx, baseID, now); err != nil {
 		return err
 	}
@@ -258,12 +596,103 @@ func (s *airtableBaseService) UpdateSyncStatus(ctx context.Context, baseID strin
 		zap.String("base_id", baseID),
 		zap.Time("sync_time", now))
 
+	return s.refreshAfterSync(ctx, baseID)
+}
+
+// RecordSync records the outcome of a sync worker's run in one update. A nil syncErr marks the
+// base idle and advances its last sync time; otherwise the base is marked failed with the error.
+func (s *airtableBaseService) RecordSync(ctx context.Context, baseID string, syncErr error, duration time.Duration) error {
+	status, message := models.AirtableSyncStatusIdle, ""
+	if syncErr != nil {
+		status, message = models.AirtableSyncStatusFailed, syncErr.Error()
+	}
+
+	now := time.Now().UTC()
+	if err := s.repos.AirtableBase.RecordSync(ctx, baseID, status, message, int(duration.Milliseconds()), now); err != nil {
+		return err
+	}
+
+	s.logger.Info("Recorded Airtable base sync",
+		zap.String("base_id", baseID),
+		zap.String("sync_status", status),
+		zap.Duration("duration", duration))
+
+	if syncErr != nil {
+		return nil
+	}
+	return s.refreshAfterSync(ctx, baseID)
+}
+
+// refreshAfterSync refreshes a base's cached metadata after a successful sync. A sync is the
+// natural point to pick up new tables and record counts.
+func (s *airtableBaseService) refreshAfterSync(ctx context.Context, baseID string) error {
+	if s.gateway == nil {
+		return nil
+	}
+
+	base, err := s.repos.AirtableBase.GetByID(ctx, baseID)
+	if err != nil {
+		return err
+	}
+	if _, err := s.refreshMetadata(ctx, base.BaseID); err != nil {
+		s.logger.Warn("Failed to refresh base metadata after sync",
+			zap.String("base_id", base.BaseID),
+			zap.Error(err))
+	}
+
 	return nil
 }
 
+// GetBaseMetadata returns the base's metadata, preferring a fresh cache entry and falling back
+// to a stale one flagged is_stale when the gateway is unavailable. Callers must have already
+// checked access to the base. Returns nil when no metadata is available.
+func (s *airtableBaseService) GetBaseMetadata(ctx context.Context, base *models.AirtableBase) (*models.AirtableBaseMetadata, error) {
+	// Cache errors are treated as a miss so Redis problems don't fail the read
+	cached, _ := s.repos.Cache.GetBaseMetadata(ctx, base.BaseID)
+	if cached != nil && time.Since(cached.FetchedAt) < s.metadataTTL() {
+		return cached, nil
+	}
+
+	if s.gateway != nil {
+		fresh, err := s.refreshMetadata(ctx, base.BaseID)
+		if err == nil {
+			return fresh, nil
+		}
+		s.logger.Warn("Failed to fetch base metadata from gateway",
+			zap.String("base_id", base.BaseID),
+			zap.Error(err))
+	}
+
+	if cached != nil {
+		cached.IsStale = true
+	}
+	return cached, nil
+}
+
+// refreshMetadata fetches metadata from the gateway and caches it
+func (s *airtableBaseService) refreshMetadata(ctx context.Context, baseID string) (*models.AirtableBaseMetadata, error) {
+	metadata, err := s.gateway.GetBaseMetadata(ctx, baseID)
+	if err != nil {
+		return nil, err
+	}
+
+	metadata.BaseID = baseID
+	metadata.FetchedAt = time.Now().UTC()
+	metadata.IsStale = false
+
+	_ = s.repos.Cache.SetBaseMetadata(ctx, metadata, time.Duration(s.config.Airtable.MetadataRetention)*time.Second)
+
+	return metadata, nil
+}
+
+// metadataTTL returns how long cached metadata is considered fresh
+func (s *airtableBaseService) metadataTTL() time.Duration {
+	return time.Duration(s.config.Airtable.MetadataTTL) * time.Second
+}
+
 // checkProjectAccess checks if user has required access to a project
 func (s *airtableBaseService) checkProjectAccess(ctx context.Context, project *models.Project, userID string, requiredRole models.WorkspaceMemberRole) error {
-	member, err := s.repos.Member.GetByWorkspaceAndUser(ctx, project.WorkspaceID, userID)
+	member, err := getMember(ctx, s.repos, project.WorkspaceID, userID)
 	if err != nil {
 		if err == repositories.ErrMemberNotFound {
 			return ErrUnauthorized
@@ -1,42 +1,97 @@
 package services
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
 
 	"go.uber.org/zap"
 
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/config"
 	"github.com/Reg-Kris/pyairtable-workspace-service/internal/models"
 	"github.com/Reg-Kris/pyairtable-workspace-service/internal/repositories"
 )
 
 type auditService struct {
-	repos  *repositories.Repositories
-	logger *zap.Logger
+	repos           *repositories.Repositories
+	logger          *zap.Logger
+	vocabulary      *AuditVocabulary
+	maxChangesBytes int
+	archive         config.AuditArchiveConfig
+	archiver        AuditArchiver
+	platform        config.PlatformConfig
+	ingest          *auditIngestWriter
 }
 
-// NewAuditService creates a new audit service
-func NewAuditService(repos *repositories.Repositories, logger *zap.Logger) AuditService {
+// NewAuditService creates a new audit service. archiver may be nil unless audit archival
+// is enabled, in which case cleanup refuses to delete anything without it. Ingested entries
+// are written on the request path; New queues them for a background writer instead.
+func NewAuditService(repos *repositories.Repositories, config *config.Config, logger *zap.Logger, archiver AuditArchiver) AuditService {
+	return newAuditService(repos, config, logger, archiver, nil)
+}
+
+// newAuditService creates an audit service that hands ingested entries to ingest when it is set
+func newAuditService(repos *repositories.Repositories, config *config.Config, logger *zap.Logger, archiver AuditArchiver, ingest *auditIngestWriter) *auditService {
 	return &auditService{
-		repos:  repos,
-		logger: logger,
+		repos:           repos,
+		logger:          logger,
+		vocabulary:      NewAuditVocabulary(config.Audit),
+		maxChangesBytes: config.Audit.MaxChangesBytes,
+		archive:         config.Audit.Archive,
+		archiver:        archiver,
+		platform:        config.Platform,
+		ingest:          ingest,
 	}
 }
 
-// LogAction logs an action to the audit log
+// Vocabulary returns the allowed audit actions and resource types
+func (s *auditService) Vocabulary() *AuditVocabulary {
+	return s.vocabulary
+}
+
+// LogAction logs an action to the audit log. Actions or resource types outside the vocabulary
+// are stored as "unknown" with the original value kept in changes, so typos don't fragment reporting.
 func (s *auditService) LogAction(ctx context.Context, workspaceID, userID, action, resourceType, resourceID string, changes map[string]interface{}) error {
+	if !s.vocabulary.IsKnownAction(action) || !s.vocabulary.IsKnownResourceType(resourceType) {
+		s.logger.Warn("Audit entry outside the known vocabulary",
+			zap.String("action", action),
+			zap.String("resource_type", resourceType))
+
+		flagged := make(map[string]interface{}, len(changes)+1)
+		for key, value := range changes {
+			flagged[key] = value
+		}
+		if !s.vocabulary.IsKnownAction(action) {
+			flagged["original_action"] = action
+			action = models.AuditUnknown
+		}
+		if !s.vocabulary.IsKnownResourceType(resourceType) {
+			flagged["original_resource_type"] = resourceType
+			resourceType = models.AuditUnknown
+		}
+		changes = flagged
+	}
+
 	log := &models.WorkspaceAuditLog{
-		WorkspaceID:  workspaceID,
-		UserID:       userID,
-		Action:       action,
-		ResourceType: resourceType,
-		ResourceID:   resourceID,
-		Changes:      changes,
+		WorkspaceID:    workspaceID,
+		UserID:         userID,
+		ImpersonatedBy: impersonator(ctx),
+		Action:         action,
+		ResourceType:   resourceType,
+		ResourceID:     resourceID,
+		Changes:        s.capChanges(action, changes),
+		OperationID:    OperationFrom(ctx).OperationID(),
 	}
 
 	if err := s.repos.AuditLog.Create(ctx, log); err != nil {
 		// Log error but don't fail the operation
 		s.logger.Error("Failed to create audit log",
 			zap.Error(err),
+			zap.String("request_id", OperationFrom(ctx).RequestID()),
 			zap.String("workspace_id", workspaceID),
 			zap.String("action", action))
 		return nil // Don't propagate audit log errors
@@ -45,32 +100,237 @@ func (s *auditService) LogAction(ctx context.Context, workspaceID, userID, actio
 	return nil
 }
 
-// GetAuditLogs retrieves audit logs based on filter
+// capChanges replaces a changes map whose JSON encoding exceeds the configured limit with a
+// summary naming the changed fields, so one oversized diff cannot bloat the audit table
+func (s *auditService) capChanges(action string, changes map[string]interface{}) map[string]interface{} {
+	if s.maxChangesBytes <= 0 || len(changes) == 0 {
+		return changes
+	}
+
+	data, err := json.Marshal(changes)
+	if err != nil || len(data) <= s.maxChangesBytes {
+		return changes
+	}
+
+	fields := make([]string, 0, len(changes))
+	for field := range changes {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+
+	s.logger.Warn("Truncated oversized audit changes",
+		zap.String("action", action),
+		zap.Int("size", len(data)),
+		zap.Int("limit", s.maxChangesBytes))
+
+	summary := map[string]interface{}{
+		"_truncated":    true,
+		"original_size": len(data),
+		"fields":        fields,
+	}
+	// Markers the service itself adds stay queryable
+	for _, key := range []string{"original_action", "original_resource_type", "source_service"} {
+		if value, ok := changes[key]; ok {
+			summary[key] = value
+		}
+	}
+	return summary
+}
+
+// maxAuditWorkspaces caps how many workspaces one audit log query may span
+const maxAuditWorkspaces = 50
+
+// GetAuditLogs retrieves audit logs based on filter. WorkspaceID may list several workspaces
+// separated by commas; those where the user is not an admin are dropped from the query. Only
+// platform admins may leave WorkspaceID empty to read every workspace's logs.
 func (s *auditService) GetAuditLogs(ctx context.Context, filter *models.AuditLogFilter, userID string) (*models.AuditLogListResponse, error) {
+	workspaceIDs := splitWorkspaceIDs(filter.WorkspaceID)
+	if len(workspaceIDs) > maxAuditWorkspaces {
+		return nil, ErrInvalidInput
+	}
+
+	if len(workspaceIDs) > 1 {
+		authorized, err := s.adminWorkspaces(ctx, workspaceIDs, userID)
+		if err != nil {
+			return nil, err
+		}
+		if len(authorized) == 0 {
+			return emptyAuditLogs(filter), nil
+		}
+
+		filter.WorkspaceID = ""
+		filter.WorkspaceIDs = authorized
+		return s.listLogs(ctx, filter)
+	}
+
+	// Stray commas and spaces leave at most one ID to query by
+	filter.WorkspaceID = ""
+	if len(workspaceIDs) == 1 {
+		filter.WorkspaceID = workspaceIDs[0]
+	}
+
+	if filter.WorkspaceID == "" {
+		if !s.platform.IsAdmin(userID) {
+			return nil, ErrInvalidInput
+		}
+		return s.listLogs(ctx, filter)
+	}
+
 	// Check if user has access to the workspace
-	if filter.WorkspaceID != "" {
-		member, err := s.repos.Member.GetByWorkspaceAndUser(ctx, filter.WorkspaceID, userID)
+	member, err := getMember(ctx, s.repos, filter.WorkspaceID, userID)
+	if err != nil {
+		if err == repositories.ErrMemberNotFound {
+			return emptyAuditLogs(filter), nil
+		}
+		return nil, err
+	}
+
+	// Only admins and owners can view audit logs
+	if !hasRequiredRole(member.Role, models.WorkspaceRoleAdmin) {
+		return nil, ErrUnauthorized
+	}
+
+	return s.listLogs(ctx, filter)
+}
+
+// adminWorkspaces returns the workspaces, in request order, where the user is an admin or owner
+func (s *auditService) adminWorkspaces(ctx context.Context, workspaceIDs []string, userID string) ([]string, error) {
+	authorized := make([]string, 0, len(workspaceIDs))
+	for _, workspaceID := range workspaceIDs {
+		member, err := getMember(ctx, s.repos, workspaceID, userID)
+		if err == repositories.ErrMemberNotFound {
+			continue
+		}
 		if err != nil {
-			if err == repositories.ErrMemberNotFound {
-				return &models.AuditLogListResponse{
-					Logs:       []*models.WorkspaceAuditLog{},
-					Total:      0,
-					Page:       1,
-					PageSize:   filter.PageSize,
-					TotalPages: 0,
-				}, nil
-			}
 			return nil, err
 		}
+		if hasRequiredRole(member.Role, models.WorkspaceRoleAdmin) {
+			authorized = append(authorized, workspaceID)
+		}
+	}
+	return authorized, nil
+}
+
+// splitWorkspaceIDs parses a comma-separated workspace list, skipping blanks and repeats
+func splitWorkspaceIDs(value string) []string {
+	var ids []string
+	seen := map[string]bool{}
+	for _, part := range strings.Split(value, ",") {
+		id := strings.TrimSpace(part)
+		if id == "" || seen[id] {
+			continue
+		}
+		seen[id] = true
+		ids = append(ids, id)
+	}
+	return ids
+}
+
+// emptyAuditLogs is the response for a query the user may not see any entries of
+func emptyAuditLogs(filter *models.AuditLogFilter) *models.AuditLogListResponse {
+	return &models.AuditLogListResponse{
+		Logs:       []*models.WorkspaceAuditLog{},
+		Total:      0,
+		Page:       1,
+		PageSize:   filter.PageSize,
+		TotalPages: 0,
+	}
+}
 
-		// Only admins and owners can view audit logs
-		if !hasRequiredRole(member.Role, models.WorkspaceRoleAdmin) {
+// GetAuditLogFacets returns the distinct actions and acting users in a workspace's audit log
+// with their entry counts. Only admins and owners may view them.
+func (s *auditService) GetAuditLogFacets(ctx context.Context, workspaceID, userID string) (*models.AuditLogFacets, error) {
+	member, err := getMember(ctx, s.repos, workspaceID, userID)
+	if err != nil {
+		if err == repositories.ErrMemberNotFound {
 			return nil, ErrUnauthorized
 		}
+		return nil, err
+	}
+	if !hasRequiredRole(member.Role, models.WorkspaceRoleAdmin) {
+		return nil, ErrUnauthorized
+	}
+
+	return s.repos.AuditLog.Facets(ctx, workspaceID)
+}
+
+// GetResourceHistory returns the audit entries for a single workspace, project or Airtable
+// base, oldest first unless the filter asks otherwise. Members and above may view it.
+func (s *auditService) GetResourceHistory(ctx context.Context, resourceType, resourceID, userID string, filter *models.AuditLogFilter) (*models.AuditLogListResponse, error) {
+	workspaceID, err := s.resourceWorkspace(ctx, resourceType, resourceID)
+	if err != nil {
+		return nil, err
 	}
 
+	member, err := getMember(ctx, s.repos, workspaceID, userID)
+	if err != nil {
+		if err == repositories.ErrMemberNotFound {
+			return nil, ErrUnauthorized
+		}
+		return nil, err
+	}
+	if !hasRequiredRole(member.Role, models.WorkspaceRoleMember) {
+		return nil, ErrUnauthorized
+	}
+
+	filter.WorkspaceID = workspaceID
+	filter.ResourceType = resourceType
+	filter.ResourceID = resourceID
+	filter.SortBy = "created_at"
+	if filter.SortOrder == "" {
+		filter.SortOrder = "ASC"
+	}
+
+	return s.listLogs(ctx, filter)
+}
+
+// resourceWorkspace resolves the workspace an audited resource belongs to
+func (s *auditService) resourceWorkspace(ctx context.Context, resourceType, resourceID string) (string, error) {
+	switch resourceType {
+	case models.AuditResourceWorkspace:
+		workspace, err := s.repos.Workspace.GetByID(ctx, resourceID)
+		if err == repositories.ErrWorkspaceNotFound {
+			return "", ErrWorkspaceNotFound
+		}
+		if err != nil {
+			return "", err
+		}
+		return workspace.ID, nil
+	case models.AuditResourceProject:
+		return s.projectWorkspace(ctx, resourceID)
+	case models.AuditResourceAirtableBase:
+		base, err := s.repos.AirtableBase.GetByID(ctx, resourceID)
+		if err == repositories.ErrAirtableBaseNotFound {
+			return "", ErrAirtableBaseNotFound
+		}
+		if err != nil {
+			return "", err
+		}
+		return s.projectWorkspace(ctx, base.ProjectID)
+	default:
+		return "", ErrInvalidInput
+	}
+}
+
+// projectWorkspace resolves the workspace a project belongs to
+func (s *auditService) projectWorkspace(ctx context.Context, projectID string) (string, error) {
+	project, err := s.repos.Project.GetByID(ctx, projectID)
+	if err == repositories.ErrProjectNotFound {
+		return "", ErrProjectNotFound
+	}
+	if err != nil {
+		return "", err
+	}
+	return project.WorkspaceID, nil
+}
+
+// listLogs runs an already authorized filter and builds the paginated response
+func (s *auditService) listLogs(ctx context.Context, filter *models.AuditLogFilter) (*models.AuditLogListResponse, error) {
 	logs, total, err := s.repos.AuditLog.List(ctx, filter)
 	if err != nil {
+		if err == repositories.ErrInvalidCursor {
+			return nil, ErrInvalidInput
+		}
 		return nil, err
 	}
 
@@ -85,31 +345,271 @@ func (s *auditService) GetAuditLogs(ctx context.Context, filter *models.AuditLog
 		pageSize = 50
 	}
 	
+	if pageSize > 100 {
+		pageSize = 100
+	}
+	
 	totalPages := int(total) / pageSize
 	if int(total)%pageSize > 0 {
 		totalPages++
 	}
 
+	// A full page means there may be more rows after the last entry
+	nextCursor := ""
+	if len(logs) == pageSize {
+		nextCursor = repositories.EncodeAuditLogCursor(logs[len(logs)-1])
+	}
+
 	return &models.AuditLogListResponse{
 		Logs:       logs,
 		Total:      total,
 		Page:       page,
 		PageSize:   pageSize,
 		TotalPages: totalPages,
+		NextCursor: nextCursor,
 	}, nil
 }
 
-// CleanupOldLogs deletes audit logs older than specified days
+// activityFeedActions are the audit actions shown in a user's activity feed: changes people
+// notice, leaving out routine ones such as syncs, exports and settings edits
+var activityFeedActions = []string{
+	models.AuditActionWorkspaceCreated,
+	models.AuditActionWorkspaceRenamed,
+	models.AuditActionWorkspaceDeletionScheduled,
+	models.AuditActionWorkspaceDeletionCancelled,
+	models.AuditActionProjectCreated,
+	models.AuditActionProjectUpdated,
+	models.AuditActionProjectDeleted,
+	models.AuditActionProjectOwnerChanged,
+	models.AuditActionBaseConnected,
+	models.AuditActionBaseDisconnected,
+	models.AuditActionMemberAdded,
+	models.AuditActionMemberRoleUpdated,
+	models.AuditActionMemberRemoved,
+	models.AuditActionMemberPrimaryOwnerChanged,
+}
+
+// ListUserActivity pages through the feed-worthy audit entries of every workspace the user is
+// a member of, at any role, latest first. Each entry carries its workspace's name but not the
+// recorded changes.
+func (s *auditService) ListUserActivity(ctx context.Context, userID string, page, pageSize int) (*models.ActivityFeedResponse, error) {
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 {
+		pageSize = 20
+	}
+	if pageSize > 100 {
+		pageSize = 100
+	}
+
+	entries, total, err := s.repos.AuditLog.ListActivity(ctx, userID, activityFeedActions, page, pageSize)
+	if err != nil {
+		return nil, err
+	}
+
+	activity := make([]*models.ActivityEntry, 0, len(entries))
+	if len(entries) > 0 {
+		workspaceIDs := make([]string, 0, len(entries))
+		for _, entry := range entries {
+			workspaceIDs = append(workspaceIDs, entry.WorkspaceID)
+		}
+		names, err := s.repos.Workspace.ResolveNames(ctx, uniqueStrings(workspaceIDs), userID)
+		if err != nil {
+			return nil, err
+		}
+		for _, entry := range entries {
+			activity = append(activity, &models.ActivityEntry{
+				ID:            entry.ID,
+				WorkspaceID:   entry.WorkspaceID,
+				WorkspaceName: names[entry.WorkspaceID],
+				UserID:        entry.UserID,
+				Action:        entry.Action,
+				ResourceType:  entry.ResourceType,
+				ResourceID:    entry.ResourceID,
+				CreatedAt:     entry.CreatedAt,
+			})
+		}
+	}
+
+	totalPages := int(total) / pageSize
+	if int(total)%pageSize > 0 {
+		totalPages++
+	}
+
+	return &models.ActivityFeedResponse{
+		Entries:    activity,
+		Total:      total,
+		Page:       page,
+		PageSize:   pageSize,
+		TotalPages: totalPages,
+	}, nil
+}
+
+// minAuditRetentionDays is the shortest audit retention, global or per workspace
+const minAuditRetentionDays = 30
+
+// CleanupOldLogs deletes audit logs older than specified days. Workspaces with an
+// audit_retention_days setting are pruned on that retention instead; both are held to the
+// minimum retention period. With archival enabled, entries are uploaded before deletion and
+// a failed upload stops the cleanup with those entries kept.
 func (s *auditService) CleanupOldLogs(ctx context.Context, days int) error {
-	if days < 30 {
-		days = 30 // Minimum retention period
+	if days < minAuditRetentionDays {
+		days = minAuditRetentionDays
+	}
+	if s.archive.Enabled && s.archiver == nil {
+		return ErrArchiverMissing
+	}
+
+	workspaces, err := s.repos.Workspace.ListWithSetting(ctx, models.WorkspaceSettingAuditRetentionDays)
+	if err != nil {
+		s.logger.Error("Failed to load audit retention overrides", zap.Error(err))
+		return err
+	}
+
+	var overridden []string
+	for _, workspace := range workspaces {
+		retention, ok := workspace.AuditRetentionDays()
+		if !ok {
+			s.logger.Warn("Ignoring invalid audit retention override", zap.String("workspace_id", workspace.ID))
+			continue
+		}
+		if retention < minAuditRetentionDays {
+			retention = minAuditRetentionDays
+		}
+
+		if err := s.deleteOlderThan(ctx, workspace.ID, nil, retention); err != nil {
+			s.logger.Error("Failed to cleanup old workspace audit logs", zap.Error(err), zap.String("workspace_id", workspace.ID))
+			return err
+		}
+		overridden = append(overridden, workspace.ID)
 	}
 
-	if err := s.repos.AuditLog.DeleteOlderThan(ctx, days); err != nil {
+	if err := s.deleteOlderThan(ctx, "", overridden, days); err != nil {
 		s.logger.Error("Failed to cleanup old audit logs", zap.Error(err))
 		return err
 	}
 
-	s.logger.Info("Cleaned up old audit logs", zap.Int("days", days))
+	s.logger.Info("Cleaned up old audit logs", zap.Int("days", days), zap.Int("overrides", len(overridden)))
 	return nil
-}
\ No newline at end of file
+}
+
+// auditArchiveBatchSize is how many audit log entries go into one archive object
+const auditArchiveBatchSize = 1000
+
+// deleteOlderThan deletes the audit logs older than days of one workspace, or of all but except
+// when workspaceID is empty, archiving them first when archival is enabled
+func (s *auditService) deleteOlderThan(ctx context.Context, workspaceID string, except []string, days int) error {
+	if !s.archive.Enabled {
+		if workspaceID != "" {
+			return s.repos.AuditLog.DeleteWorkspaceOlderThan(ctx, workspaceID, days)
+		}
+		return s.repos.AuditLog.DeleteOlderThan(ctx, days, except)
+	}
+
+	before := time.Now().AddDate(0, 0, -days)
+	for {
+		logs, err := s.repos.AuditLog.ListOlderThan(ctx, before, workspaceID, except, auditArchiveBatchSize)
+		if err != nil {
+			return err
+		}
+		if len(logs) == 0 {
+			return nil
+		}
+
+		if err := s.archiveLogs(ctx, workspaceID, logs); err != nil {
+			return err
+		}
+
+		ids := make([]string, len(logs))
+		for i, log := range logs {
+			ids[i] = log.ID
+		}
+		if _, err := s.repos.AuditLog.DeleteByIDs(ctx, ids); err != nil {
+			return err
+		}
+
+		if len(logs) < auditArchiveBatchSize {
+			return nil
+		}
+	}
+}
+
+// archiveLogs uploads logs as one NDJSON object, named after the scope and the first entry so a
+// retried batch overwrites its own earlier upload
+func (s *auditService) archiveLogs(ctx context.Context, workspaceID string, logs []*models.WorkspaceAuditLog) error {
+	var body bytes.Buffer
+	encoder := json.NewEncoder(&body)
+	for _, log := range logs {
+		if err := encoder.Encode(log); err != nil {
+			return err
+		}
+	}
+
+	scope := workspaceID
+	if scope == "" {
+		scope = "all"
+	}
+	first := logs[0]
+	key := fmt.Sprintf("%s%s/%s-%s.ndjson", s.archive.Prefix, scope, first.CreatedAt.UTC().Format("20060102T150405.000000000Z"), first.ID)
+
+	if err := s.archiver.Upload(ctx, s.archive.Bucket, key, body.Bytes()); err != nil {
+		s.logger.Error("Failed to archive audit logs; keeping them",
+			zap.Error(err), zap.String("key", key), zap.Int("count", len(logs)))
+		return err
+	}
+
+	s.logger.Info("Archived audit logs", zap.String("key", key), zap.Int("count", len(logs)))
+	return nil
+}
+
+// IngestLogs stores a batch of audit entries written by another service. The principal must be
+// authorized for the workspace's tenant; entries are expected to have been validated. With a
+// background writer the batch is queued and written after the call returns, and a full queue
+// fails it with ErrIngestQueueFull.
+func (s *auditService) IngestLogs(ctx context.Context, workspaceID string, principal *config.ServicePrincipal, entries []models.IngestAuditLogEntry) (int, error) {
+	workspace, err := s.repos.Workspace.GetByID(ctx, workspaceID)
+	if err != nil {
+		if err == repositories.ErrWorkspaceNotFound {
+			return 0, ErrWorkspaceNotFound
+		}
+		return 0, err
+	}
+
+	if principal == nil || !principal.AllowsTenant(workspace.TenantID) {
+		return 0, ErrUnauthorized
+	}
+
+	logs := make([]*models.WorkspaceAuditLog, 0, len(entries))
+	for _, entry := range entries {
+		changes := entry.Changes
+		if changes == nil {
+			changes = models.JSONMap{}
+		}
+		// Record which service wrote the entry
+		changes["source_service"] = principal.Name
+
+		logs = append(logs, &models.WorkspaceAuditLog{
+			WorkspaceID:  workspaceID,
+			UserID:       entry.UserID,
+			Action:       entry.Action,
+			ResourceType: entry.ResourceType,
+			ResourceID:   entry.ResourceID,
+			Changes:      s.capChanges(entry.Action, changes),
+			OperationID:  OperationFrom(ctx).OperationID(),
+		})
+	}
+
+	if s.ingest != nil {
+		if !s.ingest.enqueue(logs) {
+			return 0, ErrIngestQueueFull
+		}
+		return len(logs), nil
+	}
+
+	if err := s.repos.AuditLog.CreateBatch(ctx, logs); err != nil {
+		return 0, err
+	}
+
+	return len(logs), nil
+}
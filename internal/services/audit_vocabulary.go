@@ -0,0 +1,109 @@
+package services
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/config"
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/models"
+)
+
+// defaultAuditActions are the actions this service writes plus those other services may ingest
+var defaultAuditActions = []string{
+	models.AuditActionWorkspaceCreated,
+	models.AuditActionWorkspaceUpdated,
+	models.AuditActionWorkspaceRenamed,
+	models.AuditActionWorkspaceDescriptionChanged,
+	models.AuditActionWorkspaceSettingsChanged,
+	models.AuditActionWorkspaceDeleted,
+	models.AuditActionWorkspaceSyncUpdated,
+	models.AuditActionWorkspaceTenantChanged,
+	models.AuditActionWorkspaceDeletionScheduled,
+	models.AuditActionWorkspaceDeletionCancelled,
+	models.AuditActionProjectCreated,
+	models.AuditActionProjectUpdated,
+	models.AuditActionProjectDeleted,
+	models.AuditActionProjectOwnerChanged,
+	models.AuditActionBaseConnected,
+	models.AuditActionBaseUpdated,
+	models.AuditActionBaseDisconnected,
+	models.AuditActionBaseSynced,
+	models.AuditActionMemberAdded,
+	models.AuditActionMemberRoleUpdated,
+	models.AuditActionMemberRemoved,
+	models.AuditActionMemberResourcesReassigned,
+	models.AuditActionMemberPrimaryOwnerChanged,
+	models.AuditActionDataExported,
+	models.AuditActionDataImported,
+	models.AuditActionReportGenerated,
+	models.AuditActionAutomationTriggered,
+	models.AuditActionServiceAccountCreated,
+	models.AuditActionServiceAccountRevoked,
+}
+
+// defaultAuditResourceTypes are the audited resource types
+var defaultAuditResourceTypes = []string{
+	models.AuditResourceWorkspace,
+	models.AuditResourceProject,
+	models.AuditResourceAirtableBase,
+	models.AuditResourceWorkspaceMember,
+	models.AuditResourceReport,
+	models.AuditResourceAutomation,
+	models.AuditResourceServiceAccount,
+}
+
+// AuditVocabulary is the allowlist of audit actions and resource types
+type AuditVocabulary struct {
+	actions       map[string]bool
+	resourceTypes map[string]bool
+}
+
+// NewAuditVocabulary builds the default vocabulary extended with any configured entries
+func NewAuditVocabulary(cfg config.AuditConfig) *AuditVocabulary {
+	return &AuditVocabulary{
+		actions:       vocabularySet(defaultAuditActions, cfg.ExtraActions),
+		resourceTypes: vocabularySet(defaultAuditResourceTypes, cfg.ExtraResourceTypes),
+	}
+}
+
+// IsKnownAction reports whether action is in the vocabulary
+func (v *AuditVocabulary) IsKnownAction(action string) bool {
+	return v.actions[action]
+}
+
+// IsKnownResourceType reports whether resourceType is in the vocabulary
+func (v *AuditVocabulary) IsKnownResourceType(resourceType string) bool {
+	return v.resourceTypes[resourceType]
+}
+
+// Actions returns the known actions in sorted order
+func (v *AuditVocabulary) Actions() []string {
+	return sortedKeys(v.actions)
+}
+
+// ResourceTypes returns the known resource types in sorted order
+func (v *AuditVocabulary) ResourceTypes() []string {
+	return sortedKeys(v.resourceTypes)
+}
+
+func vocabularySet(defaults []string, extra string) map[string]bool {
+	set := make(map[string]bool, len(defaults))
+	for _, entry := range defaults {
+		set[entry] = true
+	}
+	for _, entry := range strings.Split(extra, ",") {
+		if entry = strings.TrimSpace(entry); entry != "" {
+			set[entry] = true
+		}
+	}
+	return set
+}
+
+func sortedKeys(set map[string]bool) []string {
+	keys := make([]string, 0, len(set))
+	for key := range set {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,72 @@
+package services
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/models"
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/repositories"
+)
+
+// auditIngestWriter writes audit batches ingested from other services off the request path.
+// Batches wait in a bounded queue; when it is full, enqueue refuses the batch rather than
+// letting the backlog grow without limit.
+type auditIngestWriter struct {
+	repos  *repositories.Repositories
+	logger *zap.Logger
+	queue  chan []*models.WorkspaceAuditLog
+}
+
+// newAuditIngestWriter creates a writer queueing up to size batches, or returns nil when size
+// is not positive so ingestion writes on the request path
+func newAuditIngestWriter(repos *repositories.Repositories, logger *zap.Logger, size int) *auditIngestWriter {
+	if size <= 0 {
+		return nil
+	}
+	return &auditIngestWriter{
+		repos:  repos,
+		logger: logger,
+		queue:  make(chan []*models.WorkspaceAuditLog, size),
+	}
+}
+
+// enqueue hands a batch to the writer, reporting false when the queue is full
+func (w *auditIngestWriter) enqueue(logs []*models.WorkspaceAuditLog) bool {
+	select {
+	case w.queue <- logs:
+		return true
+	default:
+		return false
+	}
+}
+
+// run writes queued batches until ctx is done, then writes the batches still queued
+func (w *auditIngestWriter) run(ctx context.Context) {
+	for {
+		select {
+		case logs := <-w.queue:
+			w.write(logs)
+		case <-ctx.Done():
+			for {
+				select {
+				case logs := <-w.queue:
+					w.write(logs)
+				default:
+					return
+				}
+			}
+		}
+	}
+}
+
+// write stores one batch. The request that queued it has already been answered, so failures
+// can only be logged.
+func (w *auditIngestWriter) write(logs []*models.WorkspaceAuditLog) {
+	if err := w.repos.AuditLog.CreateBatch(context.Background(), logs); err != nil {
+		w.logger.Error("Failed to write ingested audit logs",
+			zap.String("workspace_id", logs[0].WorkspaceID),
+			zap.Int("count", len(logs)),
+			zap.Error(err))
+	}
+}
@@ -0,0 +1,38 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"github.com/Reg-Kris/pyairtable-workspace-service/pkg/health"
+)
+
+// cacheWarmPollInterval is how often startup cache warming rechecks readiness
+const cacheWarmPollInterval = time.Second
+
+// StartBackgroundJobs starts the background jobs the config enables, each in its own goroutine,
+// and returns right away; the jobs stop when ctx is done. Scheduled deletions run every
+// Deletion.Interval seconds, active users' caches are refreshed when CacheRefresh is enabled,
+// the cache is warmed once readiness passes when CacheWarm is enabled, and audit entries
+// ingested from other services are written while Audit.IngestQueueSize is positive.
+func (s *Services) StartBackgroundJobs(ctx context.Context, readiness *health.Registry) {
+	if s.config == nil {
+		return
+	}
+
+	if interval := s.config.Deletion.Interval; interval > 0 {
+		go RunScheduledDeletionJob(ctx, s.Workspace, time.Duration(interval)*time.Second, s.logger)
+	}
+
+	if refresh := s.config.CacheRefresh; refresh.Enabled && refresh.Interval > 0 {
+		go RunUserCacheRefreshJob(ctx, s.Member, time.Duration(refresh.Interval)*time.Second, s.logger)
+	}
+
+	if s.config.CacheWarm.Enabled {
+		go RunStartupCacheWarming(ctx, s.Workspace, readiness, cacheWarmPollInterval, s.logger)
+	}
+
+	if s.ingest != nil {
+		go s.ingest.run(ctx)
+	}
+}
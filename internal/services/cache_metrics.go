@@ -0,0 +1,53 @@
+package services
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/Reg-Kris/pyairtable-workspace-service/pkg/metrics"
+)
+
+// Entity labels for cache hit and miss counters
+const (
+	cacheEntityWorkspace      = "workspace"
+	cacheEntityProject        = "project"
+	cacheEntityUserWorkspaces = "user_workspaces"
+)
+
+// cacheMetrics counts cache lookups by entity. The zero value counts nothing.
+type cacheMetrics struct {
+	hits   *prometheus.CounterVec
+	misses *prometheus.CounterVec
+}
+
+// record counts one lookup of entity. Reads that skip the cache are not lookups and are not
+// recorded.
+func (m cacheMetrics) record(entity string, hit bool) {
+	counter := m.misses
+	if hit {
+		counter = m.hits
+	}
+	if counter != nil {
+		counter.WithLabelValues(entity).Inc()
+	}
+}
+
+// cacheInstrumented is implemented by services whose reads go through the cache
+type cacheInstrumented interface {
+	useCacheMetrics(m cacheMetrics)
+}
+
+// UseMetrics reports cache hits and misses to the registry's cache counters
+func (s *Services) UseMetrics(registry *metrics.Registry) {
+	m := cacheMetrics{hits: registry.CacheHitsTotal, misses: registry.CacheMissesTotal}
+	for _, service := range []interface{}{s.Workspace, s.Project, s.Member} {
+		if instrumented, ok := service.(cacheInstrumented); ok {
+			instrumented.useCacheMetrics(m)
+		}
+	}
+}
+
+func (s *workspaceService) useCacheMetrics(m cacheMetrics) { s.cacheMetrics = m }
+
+func (s *projectService) useCacheMetrics(m cacheMetrics) { s.cacheMetrics = m }
+
+func (s *memberService) useCacheMetrics(m cacheMetrics) { s.cacheMetrics = m }
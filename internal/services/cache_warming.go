@@ -0,0 +1,64 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/Reg-Kris/pyairtable-workspace-service/pkg/health"
+)
+
+// WarmCache loads the workspaces with the most audit activity within the configured window
+// into the cache, at most TopWorkspaces of them, and returns how many were cached. Without a
+// config there is nothing to select and nothing is cached.
+func (s *workspaceService) WarmCache(ctx context.Context, now time.Time) (int, error) {
+	if s.config == nil {
+		return 0, nil
+	}
+
+	window := time.Duration(s.config.CacheWarm.ActivityWindow) * time.Second
+	workspaceIDs, err := s.repos.AuditLog.MostActiveWorkspaces(ctx, now.Add(-window), s.config.CacheWarm.TopWorkspaces)
+	if err != nil || len(workspaceIDs) == 0 {
+		return 0, err
+	}
+
+	// Workspaces deleted since their activity are left out
+	workspaces, err := s.repos.Workspace.FindByIDs(ctx, workspaceIDs)
+	if err != nil || len(workspaces) == 0 {
+		return 0, err
+	}
+
+	if err := s.repos.Cache.WarmWorkspaceCache(ctx, workspaces); err != nil {
+		return 0, err
+	}
+	return len(workspaces), nil
+}
+
+// RunStartupCacheWarming warms the cache once, as soon as readiness reports the service ready,
+// so warming never competes with an unhealthy database or Redis. Readiness is checked every
+// pollInterval until then. It blocks, so callers start it in its own goroutine after the
+// server is listening, and only when CacheWarm is enabled; readiness itself is never held back
+// by it. It gives up when ctx is done.
+func RunStartupCacheWarming(ctx context.Context, workspaces WorkspaceService, readiness *health.Registry, pollInterval time.Duration, logger *zap.Logger) {
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+
+	for !readiness.Run(ctx).Ready() {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+
+	started := time.Now()
+	warmed, err := workspaces.WarmCache(ctx, started)
+	if err != nil {
+		if ctx.Err() == nil {
+			logger.Error("Startup cache warming failed", zap.Error(err))
+		}
+		return
+	}
+	logger.Info("Warmed workspace cache", zap.Int("count", warmed), zap.Duration("took", time.Since(started)))
+}
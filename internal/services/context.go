@@ -0,0 +1,196 @@
+package services
+
+import (
+	"context"
+	"sync"
+
+	"github.com/google/uuid"
+
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/models"
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/repositories"
+)
+
+type contextKey string
+
+const (
+	cacheBypassKey    contextKey = "cache_bypass"
+	impersonatedByKey contextKey = "impersonated_by"
+	operationKey      contextKey = "operation"
+)
+
+// WithCacheBypass marks ctx so reads skip the cache and go to the database.
+// Results are still written back to the cache.
+func WithCacheBypass(ctx context.Context) context.Context {
+	return context.WithValue(ctx, cacheBypassKey, true)
+}
+
+// cacheBypassed reports whether ctx was marked with WithCacheBypass
+func cacheBypassed(ctx context.Context) bool {
+	bypass, _ := ctx.Value(cacheBypassKey).(bool)
+	return bypass
+}
+
+// WithImpersonator records the real caller acting on behalf of the request's user.
+// Audit entries written under ctx carry it as ImpersonatedBy.
+func WithImpersonator(ctx context.Context, actorID string) context.Context {
+	return context.WithValue(ctx, impersonatedByKey, actorID)
+}
+
+// impersonator returns the real caller recorded with WithImpersonator, if any
+func impersonator(ctx context.Context) string {
+	actorID, _ := ctx.Value(impersonatedByKey).(string)
+	return actorID
+}
+
+// OperationContext describes the request a service call runs for: the acting user, their
+// tenant, the request ID, an operation ID shared by the audit entries it writes and the user's
+// workspace memberships resolved so far. It is built once per request and its accessors are
+// safe on a nil value, so services work the same when it is missing.
+type OperationContext struct {
+	userID      string
+	tenantID    string
+	requestID   string
+	operationID string
+	// serviceAccount is set when the request authenticated with a service account token
+	serviceAccount *models.ServiceAccount
+
+	mu      sync.Mutex
+	members map[string]*models.WorkspaceMember
+}
+
+// NewOperationContext creates the operation context for a request
+func NewOperationContext(userID, tenantID, requestID string) *OperationContext {
+	return &OperationContext{
+		userID:      userID,
+		tenantID:    tenantID,
+		requestID:   requestID,
+		operationID: uuid.NewString(),
+		members:     make(map[string]*models.WorkspaceMember),
+	}
+}
+
+// NewServiceAccountOperationContext creates the operation context for a request made with a
+// service account token. The account acts with its fixed role in its own workspace and is not
+// a member of any other.
+func NewServiceAccountOperationContext(account *models.ServiceAccount, requestID string) *OperationContext {
+	op := NewOperationContext(account.PrincipalID(), account.TenantID, requestID)
+	op.serviceAccount = account
+	op.members[account.WorkspaceID] = &models.WorkspaceMember{
+		WorkspaceID: account.WorkspaceID,
+		UserID:      op.userID,
+		Role:        account.Role,
+		JoinedAt:    account.CreatedAt,
+	}
+	return op
+}
+
+// WithOperation attaches op to ctx
+func WithOperation(ctx context.Context, op *OperationContext) context.Context {
+	return context.WithValue(ctx, operationKey, op)
+}
+
+// OperationFrom returns the operation context attached to ctx, or nil
+func OperationFrom(ctx context.Context) *OperationContext {
+	op, _ := ctx.Value(operationKey).(*OperationContext)
+	return op
+}
+
+// UserID returns the acting user
+func (o *OperationContext) UserID() string {
+	if o == nil {
+		return ""
+	}
+	return o.userID
+}
+
+// TenantID returns the acting user's tenant
+func (o *OperationContext) TenantID() string {
+	if o == nil {
+		return ""
+	}
+	return o.tenantID
+}
+
+// RequestID returns the ID of the request being served
+func (o *OperationContext) RequestID() string {
+	if o == nil {
+		return ""
+	}
+	return o.requestID
+}
+
+// OperationID returns the ID correlating the audit entries written for the request. Unlike
+// the request ID, which a client may supply, it is always generated here.
+func (o *OperationContext) OperationID() string {
+	if o == nil {
+		return ""
+	}
+	return o.operationID
+}
+
+// ServiceAccount returns the service account making the request, or nil for users
+func (o *OperationContext) ServiceAccount() *models.ServiceAccount {
+	if o == nil {
+		return nil
+	}
+	return o.serviceAccount
+}
+
+// Role returns the acting user's role in a workspace, if it was resolved earlier in the request
+func (o *OperationContext) Role(workspaceID string) (models.WorkspaceMemberRole, bool) {
+	member, ok := o.member(workspaceID, o.UserID())
+	if !ok {
+		return "", false
+	}
+	return member.Role, true
+}
+
+// member returns the membership remembered for userID, which is only ever the acting user
+func (o *OperationContext) member(workspaceID, userID string) (*models.WorkspaceMember, bool) {
+	if o == nil || userID == "" || userID != o.userID {
+		return nil, false
+	}
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	member, ok := o.members[workspaceID]
+	return member, ok
+}
+
+// remember records the acting user's membership so later checks in the request skip the lookup
+func (o *OperationContext) remember(member *models.WorkspaceMember) {
+	if o == nil || member == nil || member.UserID != o.userID {
+		return
+	}
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.members[member.WorkspaceID] = member
+}
+
+// forget drops a remembered membership after the request changed or removed it
+func (o *OperationContext) forget(workspaceID, userID string) {
+	if o == nil || userID != o.userID {
+		return
+	}
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	delete(o.members, workspaceID)
+}
+
+// getMember looks up a workspace membership, reusing the acting user's memberships already
+// resolved by the request's operation context
+func getMember(ctx context.Context, repos *repositories.Repositories, workspaceID, userID string) (*models.WorkspaceMember, error) {
+	op := OperationFrom(ctx)
+	if member, ok := op.member(workspaceID, userID); ok {
+		return member, nil
+	}
+	if op.ServiceAccount() != nil && userID == op.UserID() {
+		return nil, repositories.ErrMemberNotFound
+	}
+
+	member, err := repos.Member.GetByWorkspaceAndUser(ctx, workspaceID, userID)
+	if err != nil {
+		return nil, err
+	}
+	op.remember(member)
+	return member, nil
+}
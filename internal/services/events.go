@@ -0,0 +1,33 @@
+package services
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/models"
+)
+
+// EventPublisher delivers domain events to downstream consumers
+type EventPublisher interface {
+	Publish(ctx context.Context, event *models.Event) error
+}
+
+// logPublisher is the default publisher until a broker or webhook delivery is configured
+type logPublisher struct {
+	logger *zap.Logger
+}
+
+// NewLogPublisher creates a publisher that only logs events
+func NewLogPublisher(logger *zap.Logger) EventPublisher {
+	return &logPublisher{logger: logger}
+}
+
+// Publish logs the event
+func (p *logPublisher) Publish(ctx context.Context, event *models.Event) error {
+	p.logger.Info("Published event",
+		zap.String("type", event.Type),
+		zap.String("workspace_id", event.WorkspaceID),
+		zap.String("actor_id", event.ActorID))
+	return nil
+}
@@ -0,0 +1,62 @@
+package services
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/config"
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/models"
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/repositories"
+)
+
+type maintenanceService struct {
+	repos  *repositories.Repositories
+	config *config.Config
+	logger *zap.Logger
+}
+
+// NewMaintenanceService creates a new maintenance service
+func NewMaintenanceService(repos *repositories.Repositories, config *config.Config, logger *zap.Logger) MaintenanceService {
+	return &maintenanceService{
+		repos:  repos,
+		config: config,
+		logger: logger,
+	}
+}
+
+// Status returns the maintenance mode in effect. A mode set by configuration takes precedence
+// over the admin toggle stored in Redis.
+func (s *maintenanceService) Status(ctx context.Context) (*models.MaintenanceStatus, error) {
+	if s.config != nil && s.config.Maintenance.Mode != "" {
+		return &models.MaintenanceStatus{Mode: s.config.Maintenance.Mode, Configured: true}, nil
+	}
+
+	mode, err := s.repos.Cache.GetMaintenanceMode(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &models.MaintenanceStatus{Mode: mode}, nil
+}
+
+// SetMode sets or, when mode is empty, clears the admin maintenance toggle; platform admins
+// only. It cannot lift a mode set by configuration.
+func (s *maintenanceService) SetMode(ctx context.Context, actorID, mode string) (*models.MaintenanceStatus, error) {
+	if s.config == nil || !s.config.Platform.IsAdmin(actorID) {
+		return nil, ErrUnauthorized
+	}
+
+	if mode != "" && mode != config.MaintenanceReadOnly {
+		return nil, ErrInvalidInput
+	}
+
+	if err := s.repos.Cache.SetMaintenanceMode(ctx, mode); err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("Set maintenance mode",
+		zap.String("mode", mode),
+		zap.String("actor_id", actorID))
+
+	return s.Status(ctx)
+}
@@ -2,6 +2,10 @@ package services
 
 import (
 	"context"
+	"errors"
+	"sort"
+	"strings"
+	"time"
 
 	"go.uber.org/zap"
 
@@ -15,22 +19,28 @@ type memberService struct {
 	config       *config.Config
 	logger       *zap.Logger
 	auditService AuditService
+	events       EventPublisher
+	directory    UserDirectory
+	cacheMetrics cacheMetrics
 }
 
-// NewMemberService creates a new member service
-func NewMemberService(repos *repositories.Repositories, config *config.Config, logger *zap.Logger, auditService AuditService) MemberService {
+// NewMemberService creates a new member service. directory may be nil, in which case
+// members are listed and searched by user ID only.
+func NewMemberService(repos *repositories.Repositories, config *config.Config, logger *zap.Logger, auditService AuditService, events EventPublisher, directory UserDirectory) MemberService {
 	return &memberService{
 		repos:        repos,
 		config:       config,
 		logger:       logger,
 		auditService: auditService,
+		events:       events,
+		directory:    directory,
 	}
 }
 
 // AddMember adds a member to a workspace
 func (s *memberService) AddMember(ctx context.Context, workspaceID, userID string, req *models.AddWorkspaceMemberRequest) (*models.WorkspaceMember, error) {
 	// Check if requester has admin access
-	requesterMember, err := s.repos.Member.GetByWorkspaceAndUser(ctx, workspaceID, userID)
+	requesterMember, err := getMember(ctx, s.repos, workspaceID, userID)
 	if err != nil {
 		if err == repositories.ErrMemberNotFound {
 			return nil, ErrUnauthorized
@@ -44,14 +54,44 @@ func (s *memberService) AddMember(ctx context.Context, workspaceID, userID strin
 	}
 
 	// Validate role assignment rules
-	// - Only owners can add other owners
-	if req.Role == models.WorkspaceRoleOwner && requesterMember.Role != models.WorkspaceRoleOwner {
+	// - Nobody can grant a role above their own
+	if !canAssignRole(requesterMember.Role, req.Role) {
 		return nil, ErrUnauthorized
 	}
 
 	// TODO: Verify target user exists via User Service
 	// For now, we'll trust the user ID
 
+	// A rapid repeat of the same add, such as a double click, gets the first add's result
+	if window := s.addDedupWindow(); window > 0 {
+		key := "member.add:" + workspaceID + ":" + req.UserID
+		existing, claimed := s.joinInFlightAdd(ctx, workspaceID, req.UserID, key, req.Role, window)
+		if existing != nil {
+			return existing, nil
+		}
+		if claimed {
+			member, err := s.addMember(ctx, workspaceID, userID, req)
+			s.finishInFlightAdd(ctx, key, member, window)
+			return member, err
+		}
+	}
+
+	return s.addMember(ctx, workspaceID, userID, req)
+}
+
+// addMember checks the member quota and adds the member once the requester is authorized
+func (s *memberService) addMember(ctx context.Context, workspaceID, userID string, req *models.AddWorkspaceMemberRequest) (*models.WorkspaceMember, error) {
+	// Check member quota for workspace
+	if limit := quotaLimits(s.config).MembersPerWorkspace; limit > 0 {
+		memberCount, err := countWorkspaceMembers(ctx, s.repos, workspaceID)
+		if err != nil {
+			return nil, err
+		}
+		if overQuota(memberCount, limit) {
+			return nil, ErrQuotaExceeded
+		}
+	}
+
 	// Create member
 	member := &models.WorkspaceMember{
 		WorkspaceID: workspaceID,
@@ -60,6 +100,9 @@ func (s *memberService) AddMember(ctx context.Context, workspaceID, userID strin
 	}
 
 	if err := s.repos.Member.Add(ctx, member); err != nil {
+		if err == repositories.ErrDuplicateMember {
+			return nil, ErrMemberExists
+		}
 		return nil, err
 	}
 
@@ -67,7 +110,7 @@ func (s *memberService) AddMember(ctx context.Context, workspaceID, userID strin
 	_ = s.repos.Cache.InvalidateUserCache(ctx, req.UserID)
 
 	// Log audit
-	_ = s.auditService.LogAction(ctx, workspaceID, userID, "member.added", "workspace_member", req.UserID, map[string]interface{}{
+	_ = s.auditService.LogAction(ctx, workspaceID, userID, models.AuditActionMemberAdded, models.AuditResourceWorkspaceMember, req.UserID, map[string]interface{}{
 		"user_id": req.UserID,
 		"role":    req.Role,
 	})
@@ -75,47 +118,123 @@ func (s *memberService) AddMember(ctx context.Context, workspaceID, userID strin
 	return member, nil
 }
 
+// A repeated add checks every addDedupPollInterval, for up to addDedupWaitLimit, whether the
+// first one has finished
+const (
+	addDedupWaitLimit    = 2 * time.Second
+	addDedupPollInterval = 20 * time.Millisecond
+)
+
+// addDedupWindow is how long an add stays deduplicated, or 0 when deduplication is off
+func (s *memberService) addDedupWindow() time.Duration {
+	if s.config == nil {
+		return 0
+	}
+	return time.Duration(s.config.Members.AddDedupWindow) * time.Second
+}
+
+// joinInFlightAdd either claims the add identified by key for this request (claimed true) or
+// returns the member an identical earlier add created. When the earlier add is still running it
+// waits, up to addDedupWaitLimit, for it to finish. nil and false mean the add is not
+// deduplicated: it failed, was for another role, the member has since been removed or changed,
+// or the cache is unavailable.
+func (s *memberService) joinInFlightAdd(ctx context.Context, workspaceID, memberUserID, key string, role models.WorkspaceMemberRole, window time.Duration) (*models.WorkspaceMember, bool) {
+	deadline := time.Now().Add(addDedupWaitLimit)
+	for {
+		claimed, err := s.repos.Cache.ClaimRequest(ctx, key, window)
+		if err != nil {
+			return nil, false
+		}
+		if claimed {
+			return nil, true
+		}
+
+		result, inFlight, err := s.repos.Cache.RequestResult(ctx, key)
+		if err != nil {
+			return nil, false
+		}
+		if result != nil {
+			if models.WorkspaceMemberRole(result) != role {
+				return nil, false
+			}
+			// The membership is read back rather than replayed, in case it changed since
+			member, err := s.repos.Member.GetByWorkspaceAndUser(ctx, workspaceID, memberUserID)
+			if err != nil || member.Role != role {
+				return nil, false
+			}
+			return member, false
+		}
+		if !inFlight {
+			// The earlier add failed and released its claim between the two reads
+			continue
+		}
+
+		if time.Now().After(deadline) {
+			return nil, false
+		}
+		select {
+		case <-ctx.Done():
+			return nil, false
+		case <-time.After(addDedupPollInterval):
+		}
+	}
+}
+
+// finishInFlightAdd publishes the outcome of a claimed add to identical requests: the member
+// when it was added, otherwise the claim is dropped so a repeat runs (and fails) on its own
+func (s *memberService) finishInFlightAdd(ctx context.Context, key string, member *models.WorkspaceMember, window time.Duration) {
+	if member != nil && s.repos.Cache.CompleteRequest(ctx, key, []byte(member.Role), window) == nil {
+		return
+	}
+	_ = s.repos.Cache.ReleaseRequest(ctx, key)
+}
+
 // UpdateMemberRole updates a member's role in a workspace
-func (s *memberService) UpdateMemberRole(ctx context.Context, workspaceID, memberUserID, userID string, req *models.UpdateWorkspaceMemberRequest) error {
+func (s *memberService) UpdateMemberRole(ctx context.Context, workspaceID, memberUserID, userID string, req *models.UpdateWorkspaceMemberRequest) (*models.WorkspaceMember, error) {
 	// Check if requester has admin access
-	requesterMember, err := s.repos.Member.GetByWorkspaceAndUser(ctx, workspaceID, userID)
+	requesterMember, err := getMember(ctx, s.repos, workspaceID, userID)
 	if err != nil {
 		if err == repositories.ErrMemberNotFound {
-			return ErrUnauthorized
+			return nil, ErrUnauthorized
 		}
-		return err
+		return nil, err
 	}
 
 	// Only admins and owners can update roles
 	if !hasRequiredRole(requesterMember.Role, models.WorkspaceRoleAdmin) {
-		return ErrUnauthorized
+		return nil, ErrUnauthorized
 	}
 
 	// Get target member
-	targetMember, err := s.repos.Member.GetByWorkspaceAndUser(ctx, workspaceID, memberUserID)
+	targetMember, err := getMember(ctx, s.repos, workspaceID, memberUserID)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	// Validate role change rules
 	// - Only owners can change owner roles
 	if targetMember.Role == models.WorkspaceRoleOwner && requesterMember.Role != models.WorkspaceRoleOwner {
-		return ErrUnauthorized
+		return nil, ErrUnauthorized
 	}
 
-	// - Only owners can promote to owner
-	if req.Role == models.WorkspaceRoleOwner && requesterMember.Role != models.WorkspaceRoleOwner {
-		return ErrUnauthorized
+	// - Nobody can grant a role above their own, which includes raising their own role
+	if !canAssignRole(requesterMember.Role, req.Role) {
+		return nil, ErrUnauthorized
+	}
+
+	// - The primary owner stays an owner until another owner is made primary
+	if targetMember.IsPrimary && req.Role != models.WorkspaceRoleOwner {
+		return nil, ErrPrimaryOwner
 	}
 
 	// - Cannot demote yourself if you're the last owner
 	if userID == memberUserID && targetMember.Role == models.WorkspaceRoleOwner && req.Role != models.WorkspaceRoleOwner {
 		isLastOwner, err := s.repos.Member.IsLastOwner(ctx, workspaceID, userID)
 		if err != nil {
-			return err
+			return nil, err
 		}
 		if isLastOwner {
-			return repositories.ErrLastOwner
+			return nil, repositories.ErrLastOwner
 		}
 	}
 
@@ -123,26 +242,39 @@ func (s *memberService) UpdateMemberRole(ctx context.Context, workspaceID, membe
 
 	// Update role
 	if err := s.repos.Member.UpdateRole(ctx, workspaceID, memberUserID, req.Role); err != nil {
-		return err
+		return nil, err
 	}
+	targetMember.Role = req.Role
+	OperationFrom(ctx).forget(workspaceID, memberUserID)
 
 	// Invalidate user's workspace cache
 	_ = s.repos.Cache.InvalidateUserCache(ctx, memberUserID)
 
 	// Log audit
-	_ = s.auditService.LogAction(ctx, workspaceID, userID, "member.role_updated", "workspace_member", memberUserID, map[string]interface{}{
+	_ = s.auditService.LogAction(ctx, workspaceID, userID, models.AuditActionMemberRoleUpdated, models.AuditResourceWorkspaceMember, memberUserID, map[string]interface{}{
 		"user_id":  memberUserID,
 		"old_role": oldRole,
 		"new_role": req.Role,
 	})
 
-	return nil
+	// Publish events; a downgrade gets its own event so the affected user can be notified
+	roles := map[string]interface{}{
+		"user_id":  memberUserID,
+		"old_role": oldRole,
+		"new_role": req.Role,
+	}
+	s.publish(ctx, models.EventMemberRoleUpdated, workspaceID, userID, roles)
+	if !hasRequiredRole(req.Role, oldRole) {
+		s.publish(ctx, models.EventMemberRoleDowngraded, workspaceID, userID, roles)
+	}
+
+	return targetMember, nil
 }
 
 // RemoveMember removes a member from a workspace
 func (s *memberService) RemoveMember(ctx context.Context, workspaceID, memberUserID, userID string) error {
 	// Check if requester has admin access
-	requesterMember, err := s.repos.Member.GetByWorkspaceAndUser(ctx, workspaceID, userID)
+	requesterMember, err := getMember(ctx, s.repos, workspaceID, userID)
 	if err != nil {
 		if err == repositories.ErrMemberNotFound {
 			return ErrUnauthorized
@@ -151,7 +283,7 @@ func (s *memberService) RemoveMember(ctx context.Context, workspaceID, memberUse
 	}
 
 	// Get target member
-	targetMember, err := s.repos.Member.GetByWorkspaceAndUser(ctx, workspaceID, memberUserID)
+	targetMember, err := getMember(ctx, s.repos, workspaceID, memberUserID)
 	if err != nil {
 		return err
 	}
@@ -172,16 +304,43 @@ func (s *memberService) RemoveMember(ctx context.Context, workspaceID, memberUse
 		}
 	}
 
+	// The primary owner stays until another owner is made primary
+	if targetMember.IsPrimary {
+		return ErrPrimaryOwner
+	}
+
+	// What the member created is orphaned, blocks removal or goes to the primary owner
+	switch s.removalPolicy() {
+	case config.MemberRemovalBlock:
+		owns, err := s.ownsResources(ctx, workspaceID, memberUserID)
+		if err != nil {
+			return err
+		}
+		if owns {
+			return ErrMemberOwnsResources
+		}
+	case config.MemberRemovalReassignToOwner:
+		primary, err := s.repos.Member.GetPrimary(ctx, workspaceID)
+		if err != nil {
+			if err == repositories.ErrMemberNotFound {
+				return ErrInvalidReassignment
+			}
+			return err
+		}
+		return s.removeAndReassign(ctx, workspaceID, targetMember, primary.UserID, userID)
+	}
+
 	// Remove member
 	if err := s.repos.Member.Remove(ctx, workspaceID, memberUserID); err != nil {
 		return err
 	}
+	OperationFrom(ctx).forget(workspaceID, memberUserID)
 
 	// Invalidate user's workspace cache
 	_ = s.repos.Cache.InvalidateUserCache(ctx, memberUserID)
 
 	// Log audit
-	_ = s.auditService.LogAction(ctx, workspaceID, userID, "member.removed", "workspace_member", memberUserID, map[string]interface{}{
+	_ = s.auditService.LogAction(ctx, workspaceID, userID, models.AuditActionMemberRemoved, models.AuditResourceWorkspaceMember, memberUserID, map[string]interface{}{
 		"user_id": memberUserID,
 		"role":    targetMember.Role,
 	})
@@ -189,19 +348,190 @@ func (s *memberService) RemoveMember(ctx context.Context, workspaceID, memberUse
 	return nil
 }
 
-// ListMembers lists members of a workspace
-func (s *memberService) ListMembers(ctx context.Context, workspaceID, userID string, page, pageSize int) (*models.WorkspaceMemberListResponse, error) {
+// RemoveMemberAndReassign removes a member from a workspace after transferring
+// attribution of the projects and bases they created to another member
+func (s *memberService) RemoveMemberAndReassign(ctx context.Context, workspaceID, memberUserID, reassignToUserID, userID string) error {
+	// Check if requester has admin access
+	requesterMember, err := getMember(ctx, s.repos, workspaceID, userID)
+	if err != nil {
+		if err == repositories.ErrMemberNotFound {
+			return ErrUnauthorized
+		}
+		return err
+	}
+
+	// Reassigning another member's resources requires admin access, even when removing yourself
+	if !hasRequiredRole(requesterMember.Role, models.WorkspaceRoleAdmin) {
+		return ErrUnauthorized
+	}
+
+	// Get target member
+	targetMember, err := getMember(ctx, s.repos, workspaceID, memberUserID)
+	if err != nil {
+		if err == repositories.ErrMemberNotFound {
+			return ErrMemberNotFound
+		}
+		return err
+	}
+
+	// Admins cannot remove owners
+	if userID != memberUserID && targetMember.Role == models.WorkspaceRoleOwner && requesterMember.Role != models.WorkspaceRoleOwner {
+		return ErrUnauthorized
+	}
+
+	// The primary owner stays until another owner is made primary
+	if targetMember.IsPrimary {
+		return ErrPrimaryOwner
+	}
+
+	// The reassignment target must be a different, current member
+	if reassignToUserID == "" || reassignToUserID == memberUserID {
+		return ErrInvalidReassignment
+	}
+	if _, err := getMember(ctx, s.repos, workspaceID, reassignToUserID); err != nil {
+		if err == repositories.ErrMemberNotFound {
+			return ErrInvalidReassignment
+		}
+		return err
+	}
+
+	return s.removeAndReassign(ctx, workspaceID, targetMember, reassignToUserID, userID)
+}
+
+// removalPolicy is the configured policy for what a removed member created
+func (s *memberService) removalPolicy() string {
+	if s.config == nil || s.config.Members.RemovalPolicy == "" {
+		return config.MemberRemovalOrphan
+	}
+	return s.config.Members.RemovalPolicy
+}
+
+// ownsResources reports whether the member created any project or connected any base in the workspace
+func (s *memberService) ownsResources(ctx context.Context, workspaceID, memberUserID string) (bool, error) {
+	projects, err := s.repos.Project.CountByCreator(ctx, workspaceID, memberUserID)
+	if err != nil || projects > 0 {
+		return projects > 0, err
+	}
+	bases, err := s.repos.AirtableBase.CountByCreator(ctx, workspaceID, memberUserID)
+	return bases > 0, err
+}
+
+// removeAndReassign moves what targetMember created to reassignToUserID and removes them, in
+// one transaction. The caller has already checked both the removal and the target.
+func (s *memberService) removeAndReassign(ctx context.Context, workspaceID string, targetMember *models.WorkspaceMember, reassignToUserID, userID string) error {
+	memberUserID := targetMember.UserID
+
+	var projectsReassigned, basesReassigned int64
+	err := s.repos.Transaction(ctx, func(tx *repositories.Repositories) error {
+		var err error
+		if projectsReassigned, err = tx.Project.ReassignCreator(ctx, workspaceID, memberUserID, reassignToUserID); err != nil {
+			return err
+		}
+		if basesReassigned, err = tx.AirtableBase.ReassignCreator(ctx, workspaceID, memberUserID, reassignToUserID); err != nil {
+			return err
+		}
+		return tx.Member.Remove(ctx, workspaceID, memberUserID)
+	})
+	if err != nil {
+		return err
+	}
+	OperationFrom(ctx).forget(workspaceID, memberUserID)
+
+	// Cached projects still carry the old creator
+	_ = s.repos.Cache.InvalidateWorkspaceCache(ctx, workspaceID)
+	_ = s.repos.Cache.InvalidateUserCache(ctx, memberUserID)
+
+	// Log audit
+	_ = s.auditService.LogAction(ctx, workspaceID, userID, models.AuditActionMemberResourcesReassigned, models.AuditResourceWorkspaceMember, memberUserID, map[string]interface{}{
+		"from_user_id":        memberUserID,
+		"to_user_id":          reassignToUserID,
+		"projects_reassigned": projectsReassigned,
+		"bases_reassigned":    basesReassigned,
+	})
+	_ = s.auditService.LogAction(ctx, workspaceID, userID, models.AuditActionMemberRemoved, models.AuditResourceWorkspaceMember, memberUserID, map[string]interface{}{
+		"user_id":     memberUserID,
+		"role":        targetMember.Role,
+		"reassign_to": reassignToUserID,
+	})
+
+	return nil
+}
+
+// SetPrimaryOwner makes another owner the workspace's primary owner; only owners may reassign
+// it. The previous primary owner stays an owner.
+func (s *memberService) SetPrimaryOwner(ctx context.Context, workspaceID, memberUserID, userID string) (*models.WorkspaceMember, error) {
+	if memberUserID == "" {
+		return nil, ErrInvalidInput
+	}
+
+	requesterMember, err := getMember(ctx, s.repos, workspaceID, userID)
+	if err != nil {
+		if err == repositories.ErrMemberNotFound {
+			return nil, ErrUnauthorized
+		}
+		return nil, err
+	}
+
+	if requesterMember.Role != models.WorkspaceRoleOwner {
+		return nil, ErrUnauthorized
+	}
+
+	targetMember, err := getMember(ctx, s.repos, workspaceID, memberUserID)
+	if err != nil {
+		if err == repositories.ErrMemberNotFound {
+			return nil, ErrMemberNotFound
+		}
+		return nil, err
+	}
+
+	// Only owners can be the primary owner
+	if targetMember.Role != models.WorkspaceRoleOwner {
+		return nil, ErrInvalidInput
+	}
+	if targetMember.IsPrimary {
+		return targetMember, nil
+	}
+
+	// The workspace lock serializes reassignments so exactly one primary remains
+	var previousUserID string
+	err = s.repos.Transaction(ctx, func(tx *repositories.Repositories) error {
+		if _, err := tx.Workspace.Lock(ctx, workspaceID, true); err != nil {
+			return err
+		}
+		previousUserID, err = tx.Member.SetPrimary(ctx, workspaceID, memberUserID)
+		return err
+	})
+	if err != nil {
+		if err == repositories.ErrWorkspaceNotFound {
+			return nil, ErrWorkspaceNotFound
+		}
+		if err == repositories.ErrMemberNotFound {
+			return nil, ErrMemberNotFound
+		}
+		return nil, err
+	}
+	targetMember.IsPrimary = true
+	OperationFrom(ctx).forget(workspaceID, previousUserID)
+	OperationFrom(ctx).forget(workspaceID, memberUserID)
+
+	_ = s.auditService.LogAction(ctx, workspaceID, userID, models.AuditActionMemberPrimaryOwnerChanged, models.AuditResourceWorkspaceMember, memberUserID, map[string]interface{}{
+		"user_id":          memberUserID,
+		"previous_user_id": previousUserID,
+	})
+
+	return targetMember, nil
+}
+
+// ListMembers lists members of a workspace with their directory names, narrowed to those
+// matching search when it is set. A missing workspace is ErrWorkspaceNotFound and a workspace
+// the user is not a member of is ErrUnauthorized, so an empty page always means the workspace
+// has no (more) matching members.
+func (s *memberService) ListMembers(ctx context.Context, workspaceID, userID string, page, pageSize int, search string) (*models.WorkspaceMemberListResponse, error) {
 	// Check if user has access to workspace
-	member, err := s.repos.Member.GetByWorkspaceAndUser(ctx, workspaceID, userID)
+	member, err := getMember(ctx, s.repos, workspaceID, userID)
 	if err != nil {
 		if err == repositories.ErrMemberNotFound {
-			return &models.WorkspaceMemberListResponse{
-				Members:    []*models.WorkspaceMember{},
-				Total:      0,
-				Page:       page,
-				PageSize:   pageSize,
-				TotalPages: 0,
-			}, nil
+			return nil, s.nonMemberError(ctx, workspaceID)
 		}
 		return nil, err
 	}
@@ -211,25 +541,172 @@ func (s *memberService) ListMembers(ctx context.Context, workspaceID, userID str
 		return nil, ErrUnauthorized
 	}
 
-	members, total, err := s.repos.Member.List(ctx, workspaceID, page, pageSize)
+	// Calculate pagination
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 {
+		pageSize = 20
+	}
+	if pageSize > 100 {
+		pageSize = 100
+	}
+
+	var members []*models.WorkspaceMember
+	var total int64
+	if search = strings.TrimSpace(search); search != "" {
+		members, total, err = s.searchMembers(ctx, workspaceID, search, page, pageSize)
+	} else {
+		members, total, err = s.repos.Member.List(ctx, workspaceID, page, pageSize)
+		if err == nil {
+			s.resolveMembers(ctx, members)
+		}
+	}
 	if err != nil {
 		return nil, err
 	}
 
-	// Calculate pagination
+	totalPages := int(total) / pageSize
+	if int(total)%pageSize > 0 {
+		totalPages++
+	}
+
+	return &models.WorkspaceMemberListResponse{
+		Members:    members,
+		Total:      total,
+		Page:       page,
+		PageSize:   pageSize,
+		TotalPages: totalPages,
+	}, nil
+}
+
+// errSearchPageFilled stops a member search's roster scan once the requested page is filled
+var errSearchPageFilled = errors.New("search page filled")
+
+// searchMembers pages through the members whose user ID, display name or email contains
+// search, ignoring case. Names live in the user directory rather than the database, so the
+// roster is read and resolved batch by batch; without a directory only IDs match. Reading
+// stops at the first match past the requested page, so the total counts the matches up to
+// there and is exact only when the scan reaches the end of the roster.
+func (s *memberService) searchMembers(ctx context.Context, workspaceID, search string, page, pageSize int) ([]*models.WorkspaceMember, int64, error) {
+	search = strings.ToLower(search)
+	limit := page * pageSize
+	var matches []*models.WorkspaceMember
+	err := s.repos.Member.Scan(ctx, workspaceID, memberExportBatchSize, func(batch []*models.WorkspaceMember) error {
+		s.resolveMembers(ctx, batch)
+		for _, member := range batch {
+			if strings.Contains(strings.ToLower(member.UserID), search) ||
+				strings.Contains(strings.ToLower(member.DisplayName), search) ||
+				strings.Contains(strings.ToLower(member.Email), search) {
+				matches = append(matches, member)
+			}
+		}
+		if len(matches) > limit {
+			return errSearchPageFilled
+		}
+		return nil
+	})
+	if err != nil && err != errSearchPageFilled {
+		return nil, 0, err
+	}
+
+	total := int64(len(matches))
+	start := (page - 1) * pageSize
+	if start >= len(matches) {
+		return []*models.WorkspaceMember{}, total, nil
+	}
+	end := start + pageSize
+	if end > len(matches) {
+		end = len(matches)
+	}
+	return matches[start:end], total, nil
+}
+
+// resolveMembers fills in members' display names and emails from the user directory. When
+// the directory is missing or unavailable the members are left identified by user ID only.
+func (s *memberService) resolveMembers(ctx context.Context, members []*models.WorkspaceMember) {
+	if s.directory == nil || len(members) == 0 {
+		return
+	}
+
+	userIDs := make([]string, 0, len(members))
+	for _, member := range members {
+		userIDs = append(userIDs, member.UserID)
+	}
+	users, err := s.directory.Resolve(ctx, userIDs)
+	if err != nil {
+		s.logger.Warn("Failed to resolve members from user directory",
+			zap.Int("members", len(members)),
+			zap.Error(err))
+		return
+	}
+
+	for _, member := range members {
+		if user, ok := users[member.UserID]; ok {
+			member.DisplayName = user.DisplayName
+			member.Email = user.Email
+		}
+	}
+}
+
+// memberExportBatchSize is how many members ExportMembers reads per query
+const memberExportBatchSize = 500
+
+// ExportMembers passes the workspace's full member roster to fn in batches ordered by user ID.
+// Only admins and owners may export it; access is checked before the first batch.
+func (s *memberService) ExportMembers(ctx context.Context, workspaceID, userID string, fn func(batch []*models.WorkspaceMember) error) error {
+	member, err := getMember(ctx, s.repos, workspaceID, userID)
+	if err != nil {
+		if err == repositories.ErrMemberNotFound {
+			return s.nonMemberError(ctx, workspaceID)
+		}
+		return err
+	}
+
+	if !hasRequiredRole(member.Role, models.WorkspaceRoleAdmin) {
+		return ErrUnauthorized
+	}
+
+	return s.repos.Member.Scan(ctx, workspaceID, memberExportBatchSize, fn)
+}
+
+// ListMemberHistory lists the workspace's former members with when they joined and left, to
+// workspace admins and platform admins
+func (s *memberService) ListMemberHistory(ctx context.Context, workspaceID, userID string, page, pageSize int) (*models.PastWorkspaceMemberListResponse, error) {
+	if s.config == nil || !s.config.Platform.IsAdmin(userID) {
+		member, err := getMember(ctx, s.repos, workspaceID, userID)
+		if err != nil {
+			if err == repositories.ErrMemberNotFound {
+				return nil, s.nonMemberError(ctx, workspaceID)
+			}
+			return nil, err
+		}
+		if !hasRequiredRole(member.Role, models.WorkspaceRoleAdmin) {
+			return nil, ErrUnauthorized
+		}
+	}
+
 	if page < 1 {
 		page = 1
 	}
 	if pageSize < 1 {
 		pageSize = 20
 	}
-	
+	if pageSize > 100 {
+		pageSize = 100
+	}
+
+	members, total, err := s.repos.Member.ListHistory(ctx, workspaceID, page, pageSize)
+	if err != nil {
+		return nil, err
+	}
+
 	totalPages := int(total) / pageSize
 	if int(total)%pageSize > 0 {
 		totalPages++
 	}
 
-	return &models.WorkspaceMemberListResponse{
+	return &models.PastWorkspaceMemberListResponse{
 		Members:    members,
 		Total:      total,
 		Page:       page,
@@ -238,20 +715,103 @@ func (s *memberService) ListMembers(ctx context.Context, workspaceID, userID str
 	}, nil
 }
 
-// GetUserWorkspaces retrieves all workspaces a user is a member of
-func (s *memberService) GetUserWorkspaces(ctx context.Context, userID string) ([]*models.Workspace, error) {
+// GetMemberImpact counts the resources a member created, to inform removal and reassignment
+func (s *memberService) GetMemberImpact(ctx context.Context, workspaceID, memberUserID, userID string) (*models.MemberImpact, error) {
+	// Check if requester has admin access
+	requesterMember, err := getMember(ctx, s.repos, workspaceID, userID)
+	if err != nil {
+		if err == repositories.ErrMemberNotFound {
+			return nil, ErrUnauthorized
+		}
+		return nil, err
+	}
+
+	if !hasRequiredRole(requesterMember.Role, models.WorkspaceRoleAdmin) {
+		return nil, ErrUnauthorized
+	}
+
+	targetMember, err := getMember(ctx, s.repos, workspaceID, memberUserID)
+	if err != nil {
+		if err == repositories.ErrMemberNotFound {
+			return nil, ErrMemberNotFound
+		}
+		return nil, err
+	}
+
+	projectsCreated, err := s.repos.Project.CountByCreator(ctx, workspaceID, memberUserID)
+	if err != nil {
+		return nil, err
+	}
+
+	basesConnected, err := s.repos.AirtableBase.CountByCreator(ctx, workspaceID, memberUserID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.MemberImpact{
+		WorkspaceID:            workspaceID,
+		UserID:                 memberUserID,
+		Role:                   targetMember.Role,
+		ProjectsCreated:        projectsCreated,
+		AirtableBasesConnected: basesConnected,
+	}, nil
+}
+
+// GetUserWorkspaces retrieves all workspaces a user is a member of. With sortBy set to
+// last_accessed they are ordered by the user's last access, never-opened workspaces last.
+func (s *memberService) GetUserWorkspaces(ctx context.Context, userID, sortBy string) ([]*models.Workspace, error) {
+	// Keep the user's cached list warm while they are active
+	if s.config != nil && s.config.CacheRefresh.Enabled {
+		_ = s.repos.Cache.MarkUserActive(ctx, userID, time.Now())
+	}
+
+	workspaces, err := s.userWorkspaces(ctx, userID)
+	if err != nil || sortBy != models.WorkspaceSortLastAccessed {
+		return workspaces, err
+	}
+
+	workspaceIDs := make([]string, len(workspaces))
+	for i, workspace := range workspaces {
+		workspaceIDs[i] = workspace.ID
+	}
+	accessed, err := s.repos.Member.LastAccessed(ctx, userID, workspaceIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(workspaces, func(i, j int) bool {
+		left, leftOK := accessed[workspaces[i].ID]
+		right, rightOK := accessed[workspaces[j].ID]
+		if leftOK != rightOK {
+			return leftOK
+		}
+		return left.After(right)
+	})
+	return workspaces, nil
+}
+
+// userWorkspaces lists the workspaces a user is a member of, from the cache when possible
+func (s *memberService) userWorkspaces(ctx context.Context, userID string) ([]*models.Workspace, error) {
 	// Check cache first
 	workspaceIDs, err := s.repos.Cache.GetUserWorkspaces(ctx, userID)
 	if err == nil && workspaceIDs != nil {
-		// Load workspaces by IDs
-		workspaces := make([]*models.Workspace, 0, len(workspaceIDs))
-		for _, id := range workspaceIDs {
-			workspace, err := s.repos.Workspace.GetByID(ctx, id)
-			if err == nil {
-				workspaces = append(workspaces, workspace)
-			}
+		workspaces, stale := s.loadWorkspaces(ctx, workspaceIDs)
+		// A stale list is counted as a miss: the cache could not answer on its own
+		s.cacheMetrics.record(cacheEntityUserWorkspaces, !stale)
+		if !stale {
+			return workspaces, nil
 		}
-		return workspaces, nil
+
+		// A cached ID no longer resolves, so the list is rebuilt from memberships
+		s.logger.Info("Rebuilding stale user workspace cache", zap.String("user_id", userID))
+		rebuiltIDs, err := s.repos.Cache.RebuildUserWorkspaces(ctx, userID)
+		if err == nil {
+			workspaces, _ = s.loadWorkspaces(ctx, rebuiltIDs)
+			return workspaces, nil
+		}
+		s.logger.Warn("Failed to rebuild user workspace cache", zap.Error(err), zap.String("user_id", userID))
+	} else {
+		s.cacheMetrics.record(cacheEntityUserWorkspaces, false)
 	}
 
 	// Query all workspaces where user is a member
@@ -270,7 +830,7 @@ func (s *memberService) GetUserWorkspaces(ctx context.Context, userID string) ([
 	workspaceIDs = make([]string, 0)
 
 	for _, workspace := range allWorkspaces {
-		member, err := s.repos.Member.GetByWorkspaceAndUser(ctx, workspace.ID, userID)
+		member, err := getMember(ctx, s.repos, workspace.ID, userID)
 		if err == nil && member != nil {
 			userWorkspaces = append(userWorkspaces, workspace)
 			workspaceIDs = append(workspaceIDs, workspace.ID)
@@ -283,4 +843,85 @@ func (s *memberService) GetUserWorkspaces(ctx context.Context, userID string) ([
 	}
 
 	return userWorkspaces, nil
-}
\ No newline at end of file
+}
+
+// RebuildUserWorkspaceCache recomputes the user's cached workspace list from the database
+func (s *memberService) RebuildUserWorkspaceCache(ctx context.Context, userID string) ([]string, error) {
+	return s.repos.Cache.RebuildUserWorkspaces(ctx, userID)
+}
+
+// RefreshActiveUserCaches rebuilds the cached workspace list of each user active within the
+// configured window, most recent first and at most MaxUsers of them, and returns how many were
+// refreshed. A failed rebuild is logged and skipped so one user can't stall the rest.
+func (s *memberService) RefreshActiveUserCaches(ctx context.Context, now time.Time) (int, error) {
+	window := time.Duration(s.config.CacheRefresh.ActiveWindow) * time.Second
+	userIDs, err := s.repos.Cache.ActiveUsers(ctx, now.Add(-window), s.config.CacheRefresh.MaxUsers)
+	if err != nil {
+		return 0, err
+	}
+
+	refreshed := 0
+	for _, userID := range userIDs {
+		if ctx.Err() != nil {
+			return refreshed, ctx.Err()
+		}
+		if _, err := s.repos.Cache.RebuildUserWorkspaces(ctx, userID); err != nil {
+			s.logger.Warn("Failed to refresh user workspace cache", zap.String("user_id", userID), zap.Error(err))
+			continue
+		}
+		refreshed++
+	}
+	return refreshed, nil
+}
+
+// loadWorkspaces resolves cached workspace IDs, reporting whether any no longer exist
+func (s *memberService) loadWorkspaces(ctx context.Context, workspaceIDs []string) ([]*models.Workspace, bool) {
+	workspaces := make([]*models.Workspace, 0, len(workspaceIDs))
+	stale := false
+	for _, id := range workspaceIDs {
+		workspace, err := s.repos.Workspace.GetByID(ctx, id)
+		if err == repositories.ErrWorkspaceNotFound {
+			stale = true
+			continue
+		}
+		if err == nil {
+			workspaces = append(workspaces, workspace)
+		}
+	}
+	return workspaces, stale
+}
+
+// nonMemberError tells a workspace that does not exist apart from one the caller cannot see
+func (s *memberService) nonMemberError(ctx context.Context, workspaceID string) error {
+	if _, err := s.repos.Workspace.GetByID(ctx, workspaceID); err != nil {
+		if err == repositories.ErrWorkspaceNotFound {
+			return ErrWorkspaceNotFound
+		}
+		return err
+	}
+	return ErrUnauthorized
+}
+
+// publish sends a domain event, logging rather than failing the operation on delivery errors
+func (s *memberService) publish(ctx context.Context, eventType, workspaceID, actorID string, data map[string]interface{}) {
+	event := &models.Event{
+		Type:        eventType,
+		WorkspaceID: workspaceID,
+		ActorID:     actorID,
+		Data:        data,
+		OccurredAt:  time.Now().UTC(),
+	}
+
+	if err := s.events.Publish(ctx, event); err != nil {
+		s.logger.Error("Failed to publish event",
+			zap.Error(err),
+			zap.String("type", eventType),
+			zap.String("workspace_id", workspaceID))
+	}
+}
+
+// canAssignRole reports whether an actor holding actorRole may give role to a member. An actor
+// can never grant more access than they hold themselves.
+func canAssignRole(actorRole, role models.WorkspaceMemberRole) bool {
+	return hasRequiredRole(actorRole, role)
+}
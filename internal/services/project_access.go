@@ -0,0 +1,61 @@
+package services
+
+import (
+	"context"
+
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/models"
+)
+
+// GetProjectAccess resolves who can see a project. Projects have no members of their own, so
+// access is whatever the workspace grants: every workspace member and every unrevoked service
+// account, each at its workspace role. Only workspace admins and platform admins may ask.
+func (s *projectService) GetProjectAccess(ctx context.Context, projectID, userID string) (*models.ProjectAccess, error) {
+	project, err := s.repos.Project.GetByID(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	if s.config == nil || !s.config.Platform.IsAdmin(userID) {
+		if err := s.checkProjectAccess(ctx, project, userID, models.WorkspaceRoleAdmin); err != nil {
+			return nil, err
+		}
+	}
+
+	access := &models.ProjectAccess{
+		ProjectID:   project.ID,
+		WorkspaceID: project.WorkspaceID,
+		Users:       []*models.ProjectAccessEntry{},
+	}
+
+	err = s.repos.Member.Scan(ctx, project.WorkspaceID, memberExportBatchSize, func(batch []*models.WorkspaceMember) error {
+		for _, member := range batch {
+			access.Users = append(access.Users, &models.ProjectAccessEntry{
+				UserID: member.UserID,
+				Role:   member.Role,
+				Source: models.ProjectAccessSourceWorkspaceMember,
+			})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	accounts, err := s.repos.ServiceAccount.ListByWorkspace(ctx, project.WorkspaceID)
+	if err != nil {
+		return nil, err
+	}
+	for _, account := range accounts {
+		if account.RevokedAt != nil {
+			continue
+		}
+		access.Users = append(access.Users, &models.ProjectAccessEntry{
+			UserID: account.PrincipalID(),
+			Role:   account.Role,
+			Source: models.ProjectAccessSourceServiceAccount,
+		})
+	}
+
+	access.Total = len(access.Users)
+	return access, nil
+}
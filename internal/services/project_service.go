@@ -3,6 +3,7 @@ package services
 import (
 	"context"
 	"fmt"
+	"strings"
 
 	"go.uber.org/zap"
 
@@ -16,6 +17,7 @@ type projectService struct {
 	config       *config.Config
 	logger       *zap.Logger
 	auditService AuditService
+	cacheMetrics cacheMetrics
 }
 
 // NewProjectService creates a new project service
@@ -33,11 +35,14 @@ func (s *projectService) CreateProject(ctx context.Context, workspaceID, userID
 	// Check workspace access
 	workspace, err := s.repos.Workspace.GetByID(ctx, workspaceID)
 	if err != nil {
+		if err == repositories.ErrWorkspaceNotFound {
+			return nil, ErrWorkspaceNotFound
+		}
 		return nil, err
 	}
 
 	// Check user has at least member role in workspace
-	member, err := s.repos.Member.GetByWorkspaceAndUser(ctx, workspaceID, userID)
+	member, err := getMember(ctx, s.repos, workspaceID, userID)
 	if err != nil {
 		if err == repositories.ErrMemberNotFound {
 			return nil, ErrUnauthorized
@@ -49,15 +54,13 @@ func (s *projectService) CreateProject(ctx context.Context, workspaceID, userID
 		return nil, ErrUnauthorized
 	}
 
-	// TODO: Check project quota for workspace
-	const maxProjectsPerWorkspace = 50
-	
+	// Check project quota for workspace
 	projectCount, err := s.repos.Project.CountByWorkspace(ctx, workspaceID)
 	if err != nil {
 		return nil, err
 	}
 
-	if projectCount >= maxProjectsPerWorkspace {
+	if overQuota(projectCount, quotaLimits(s.config).ProjectsPerWorkspace) {
 		return nil, ErrQuotaExceeded
 	}
 
@@ -68,6 +71,7 @@ func (s *projectService) CreateProject(ctx context.Context, workspaceID, userID
 		Description: req.Description,
 		Status:      "active",
 		Settings:    req.Settings,
+		Tags:        models.StringList{},
 		CreatedBy:   userID,
 	}
 
@@ -75,7 +79,16 @@ func (s *projectService) CreateProject(ctx context.Context, workspaceID, userID
 		project.Settings = make(models.JSONMap)
 	}
 
-	if err := s.repos.Project.Create(ctx, project); err != nil {
+	// The share lock keeps a concurrent delete-if-empty from removing the workspace mid-create
+	if err := s.repos.Transaction(ctx, func(tx *repositories.Repositories) error {
+		if _, err := tx.Workspace.Lock(ctx, workspaceID, false); err != nil {
+			return err
+		}
+		return tx.Project.Create(ctx, project)
+	}); err != nil {
+		if err == repositories.ErrDuplicateProject {
+			return nil, ErrProjectNameTaken
+		}
 		return nil, err
 	}
 
@@ -84,9 +97,10 @@ func (s *projectService) CreateProject(ctx context.Context, workspaceID, userID
 
 	// Cache the project
 	_ = s.repos.Cache.SetProject(ctx, project)
+	_ = s.repos.Cache.InvalidateTenantStats(ctx, workspace.TenantID)
 
 	// Log audit
-	_ = s.auditService.LogAction(ctx, workspaceID, userID, "project.created", "project", project.ID, map[string]interface{}{
+	_ = s.auditService.LogAction(ctx, workspaceID, userID, models.AuditActionProjectCreated, models.AuditResourceProject, project.ID, map[string]interface{}{
 		"name":         project.Name,
 		"description":  project.Description,
 		"workspace_id": workspaceID,
@@ -98,7 +112,12 @@ func (s *projectService) CreateProject(ctx context.Context, workspaceID, userID
 // GetProject retrieves a project by ID
 func (s *projectService) GetProject(ctx context.Context, projectID, userID string) (*models.Project, error) {
 	// Check cache first
-	project, err := s.repos.Cache.GetProject(ctx, projectID)
+	var project *models.Project
+	var err error
+	if !cacheBypassed(ctx) {
+		project, err = s.repos.Cache.GetProject(ctx, projectID)
+		s.cacheMetrics.record(cacheEntityProject, err == nil && project != nil)
+	}
 	if err == nil && project != nil {
 		// Verify user has access to the workspace
 		if err := s.checkProjectAccess(ctx, project, userID, models.WorkspaceRoleViewer); err != nil {
@@ -137,6 +156,11 @@ func (s *projectService) UpdateProject(ctx context.Context, projectID, userID st
 		return nil, err
 	}
 
+	// Nothing to write or audit
+	if req.IsEmpty() {
+		return project, nil
+	}
+
 	// Track changes for audit
 	changes := make(map[string]interface{})
 
@@ -179,11 +203,12 @@ func (s *projectService) UpdateProject(ctx context.Context, projectID, userID st
 	}
 
 	if req.Settings != nil {
+		settings := replacementSettings(req.Settings)
 		changes["settings"] = map[string]interface{}{
 			"old": project.Settings,
-			"new": *req.Settings,
+			"new": settings,
 		}
-		project.Settings = *req.Settings
+		project.Settings = settings
 	}
 
 	// Update in database
@@ -196,12 +221,65 @@ func (s *projectService) UpdateProject(ctx context.Context, projectID, userID st
 
 	// Log audit
 	if len(changes) > 0 {
-		_ = s.auditService.LogAction(ctx, project.WorkspaceID, userID, "project.updated", "project", projectID, changes)
+		_ = s.auditService.LogAction(ctx, project.WorkspaceID, userID, models.AuditActionProjectUpdated, models.AuditResourceProject, projectID, changes)
+	}
+
+	return project, nil
+}
+
+// SetProjectOwner changes the user a project is attributed to; the new owner must be a workspace member
+func (s *projectService) SetProjectOwner(ctx context.Context, projectID, newOwnerUserID, actorID string) (*models.Project, error) {
+	project, err := s.repos.Project.GetByID(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Check access - need at least admin role
+	if err := s.checkProjectAccess(ctx, project, actorID, models.WorkspaceRoleAdmin); err != nil {
+		return nil, err
+	}
+
+	if _, err := getMember(ctx, s.repos, project.WorkspaceID, newOwnerUserID); err != nil {
+		if err == repositories.ErrMemberNotFound {
+			return nil, ErrInvalidOwner
+		}
+		return nil, err
+	}
+
+	if project.CreatedBy == newOwnerUserID {
+		return project, nil
+	}
+
+	oldOwner := project.CreatedBy
+	project.CreatedBy = newOwnerUserID
+	if err := s.repos.Project.Update(ctx, project); err != nil {
+		return nil, err
 	}
 
+	// Invalidate cache
+	_ = s.repos.Cache.DeleteProject(ctx, projectID)
+
+	// Log audit
+	_ = s.auditService.LogAction(ctx, project.WorkspaceID, actorID, models.AuditActionProjectOwnerChanged, models.AuditResourceProject, projectID, map[string]interface{}{
+		"created_by": map[string]interface{}{
+			"old": oldOwner,
+			"new": newOwnerUserID,
+		},
+	})
+
 	return project, nil
 }
 
+// ResolveWorkspace returns the ID of the workspace a project belongs to, without checking the
+// caller's access. It is meant for routing decisions such as the write concurrency cap.
+func (s *projectService) ResolveWorkspace(ctx context.Context, projectID string) (string, error) {
+	project, err := s.repos.Project.GetByID(ctx, projectID)
+	if err != nil {
+		return "", err
+	}
+	return project.WorkspaceID, nil
+}
+
 // DeleteProject deletes a project
 func (s *projectService) DeleteProject(ctx context.Context, projectID, userID string) error {
 	// Get project
@@ -237,20 +315,251 @@ func (s *projectService) DeleteProject(ctx context.Context, projectID, userID st
 
 	// Invalidate cache
 	_ = s.repos.Cache.DeleteProject(ctx, projectID)
+	invalidateWorkspaceStats(ctx, s.repos, project.WorkspaceID)
 
 	// Log audit
-	_ = s.auditService.LogAction(ctx, project.WorkspaceID, userID, "project.deleted", "project", projectID, map[string]interface{}{
+	_ = s.auditService.LogAction(ctx, project.WorkspaceID, userID, models.AuditActionProjectDeleted, models.AuditResourceProject, projectID, map[string]interface{}{
 		"name": project.Name,
 	})
 
 	return nil
 }
 
+// BulkDeleteProjects deletes the projects in a workspace selected by explicit IDs or by status.
+// Active projects need ConfirmActive, and projects with connected bases need Force, which
+// disconnects those bases in the same transaction.
+func (s *projectService) BulkDeleteProjects(ctx context.Context, workspaceID, userID string, req *models.BulkDeleteProjectsRequest) (*models.BulkDeleteProjectsResponse, error) {
+	// Exactly one selector keeps an empty request from matching the whole workspace
+	if (len(req.ProjectIDs) == 0) == (req.Status == "") {
+		return nil, ErrInvalidInput
+	}
+
+	// Check user has admin role in workspace
+	member, err := getMember(ctx, s.repos, workspaceID, userID)
+	if err != nil {
+		if err == repositories.ErrMemberNotFound {
+			return nil, ErrUnauthorized
+		}
+		return nil, err
+	}
+
+	if !hasRequiredRole(member.Role, models.WorkspaceRoleAdmin) {
+		return nil, ErrUnauthorized
+	}
+
+	var projects []*models.Project
+	var tenantID string
+	result := &models.BulkDeleteProjectsResponse{}
+	err = s.repos.Transaction(ctx, func(tx *repositories.Repositories) error {
+		// Hold the workspace so projects and bases can't change between the checks and the delete
+		workspace, err := tx.Workspace.Lock(ctx, workspaceID, true)
+		if err != nil {
+			return err
+		}
+		tenantID = workspace.TenantID
+
+		projects, err = tx.Project.FindByWorkspace(ctx, workspaceID, req.ProjectIDs, req.Status)
+		if err != nil {
+			return err
+		}
+
+		// Every listed ID must be a live project in this workspace
+		if len(req.ProjectIDs) > 0 && len(projects) != len(uniqueStrings(req.ProjectIDs)) {
+			return ErrProjectNotFound
+		}
+
+		projectIDs := make([]string, len(projects))
+		for i, project := range projects {
+			if project.Status == "active" && !req.ConfirmActive {
+				return ErrConfirmationRequired
+			}
+			projectIDs[i] = project.ID
+		}
+
+		if !req.Force {
+			baseCounts, err := tx.Project.CountBases(ctx, projectIDs)
+			if err != nil {
+				return err
+			}
+			if len(baseCounts) > 0 {
+				return ErrProjectHasBases
+			}
+		}
+
+		for _, project := range projects {
+			if req.Force {
+				deleted, err := tx.AirtableBase.DeleteByProject(ctx, project.ID)
+				if err != nil {
+					return err
+				}
+				result.BasesDeleted += deleted
+			}
+			if err := tx.Project.Delete(ctx, project.ID); err != nil {
+				return err
+			}
+			result.ProjectsDeleted++
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(projects) > 0 {
+		_ = s.repos.Cache.InvalidateTenantStats(ctx, tenantID)
+	}
+
+	for _, project := range projects {
+		// Invalidate cache
+		_ = s.repos.Cache.DeleteProject(ctx, project.ID)
+
+		// Log audit
+		_ = s.auditService.LogAction(ctx, workspaceID, userID, models.AuditActionProjectDeleted, models.AuditResourceProject, project.ID, map[string]interface{}{
+			"name": project.Name,
+			"bulk": true,
+		})
+	}
+
+	return result, nil
+}
+
+// TagProjects adds and removes tags on projects of a workspace in one transaction. Every listed
+// project must be live in the workspace, and no project may end up with more than maxProjectTags
+// tags; otherwise nothing changes.
+func (s *projectService) TagProjects(ctx context.Context, workspaceID, userID string, req *models.TagProjectsRequest) (*models.TagProjectsResponse, error) {
+	// Check user has at least member role in workspace
+	member, err := getMember(ctx, s.repos, workspaceID, userID)
+	if err != nil {
+		if err == repositories.ErrMemberNotFound {
+			return nil, ErrUnauthorized
+		}
+		return nil, err
+	}
+
+	if !hasRequiredRole(member.Role, models.WorkspaceRoleMember) {
+		return nil, ErrUnauthorized
+	}
+
+	add := normalizeTags(req.Add)
+	remove := normalizeTags(req.Remove)
+	projectIDs := uniqueStrings(req.ProjectIDs)
+
+	results := make(map[string]*models.ProjectTagsResult, len(projectIDs))
+	previous := make(map[string]models.StringList, len(projectIDs))
+	err = s.repos.Transaction(ctx, func(tx *repositories.Repositories) error {
+		// Serialize with other tag changes so concurrent requests don't drop each other's tags
+		if _, err := tx.Workspace.Lock(ctx, workspaceID, true); err != nil {
+			return err
+		}
+
+		projects, err := tx.Project.FindByWorkspace(ctx, workspaceID, projectIDs, "")
+		if err != nil {
+			return err
+		}
+		if len(projects) != len(projectIDs) {
+			return ErrProjectNotFound
+		}
+
+		for _, project := range projects {
+			tags, added, removed := applyTags(project.Tags, add, remove)
+			if len(tags) > maxProjectTags {
+				return ErrTooManyTags
+			}
+			if len(added) > 0 || len(removed) > 0 {
+				if err := tx.Project.SetTags(ctx, project.ID, tags); err != nil {
+					return err
+				}
+				previous[project.ID] = project.Tags
+			}
+			results[project.ID] = &models.ProjectTagsResult{
+				ProjectID: project.ID,
+				Tags:      tags,
+				Added:     added,
+				Removed:   removed,
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	response := &models.TagProjectsResponse{Projects: make([]*models.ProjectTagsResult, 0, len(projectIDs))}
+	for _, projectID := range projectIDs {
+		result := results[projectID]
+		response.Projects = append(response.Projects, result)
+
+		old, changed := previous[projectID]
+		if !changed {
+			continue
+		}
+
+		// Invalidate cache
+		_ = s.repos.Cache.DeleteProject(ctx, projectID)
+
+		// Log audit
+		_ = s.auditService.LogAction(ctx, workspaceID, userID, models.AuditActionProjectUpdated, models.AuditResourceProject, projectID, map[string]interface{}{
+			"tags": map[string]interface{}{
+				"old": old,
+				"new": result.Tags,
+			},
+			"bulk": true,
+		})
+	}
+
+	return response, nil
+}
+
+// normalizeTags returns tags in their stored form with duplicates removed
+func normalizeTags(tags []string) []string {
+	normalized := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		normalized = append(normalized, models.NormalizeTag(tag))
+	}
+	return uniqueStrings(normalized)
+}
+
+// applyTags returns current with add appended and remove taken out, along with the tags that
+// actually changed
+func applyTags(current models.StringList, add, remove []string) (models.StringList, []string, []string) {
+	has := make(map[string]bool, len(current))
+	for _, tag := range current {
+		has[tag] = true
+	}
+	removing := make(map[string]bool, len(remove))
+	removed := []string{}
+	for _, tag := range remove {
+		if has[tag] {
+			removing[tag] = true
+			removed = append(removed, tag)
+		}
+	}
+
+	tags := models.StringList{}
+	for _, tag := range current {
+		if !removing[tag] {
+			tags = append(tags, tag)
+		}
+	}
+	added := []string{}
+	for _, tag := range add {
+		if !has[tag] {
+			tags = append(tags, tag)
+			added = append(added, tag)
+		}
+	}
+	return tags, added, removed
+}
+
 // ListProjects lists projects based on filter
 func (s *projectService) ListProjects(ctx context.Context, filter *models.ProjectFilter, userID string) (*models.ProjectListResponse, error) {
+	if err := validateSearch(s.config, filter.Search); err != nil {
+		return nil, err
+	}
+
 	// If workspace ID is provided, check access
 	if filter.WorkspaceID != "" {
-		member, err := s.repos.Member.GetByWorkspaceAndUser(ctx, filter.WorkspaceID, userID)
+		member, err := getMember(ctx, s.repos, filter.WorkspaceID, userID)
 		if err != nil {
 			if err == repositories.ErrMemberNotFound {
 				return &models.ProjectListResponse{
@@ -268,6 +577,17 @@ func (s *projectService) ListProjects(ctx context.Context, filter *models.Projec
 		if !hasRequiredRole(member.Role, models.WorkspaceRoleViewer) {
 			return nil, ErrUnauthorized
 		}
+
+		// Only admins may list deleted projects
+		if !hasRequiredRole(member.Role, models.WorkspaceRoleAdmin) {
+			filter.IncludeDeleted = false
+		}
+	}
+
+	// Across workspaces, deleted projects are only listed from those the caller administers
+	filter.DeletedVisibleTo = ""
+	if filter.IncludeDeleted && (s.config == nil || !s.config.Platform.IsAdmin(userID)) {
+		filter.DeletedVisibleTo = userID
 	}
 
 	projects, total, err := s.repos.Project.List(ctx, filter)
@@ -306,9 +626,90 @@ func (s *projectService) ListProjects(ctx context.Context, filter *models.Projec
 	}, nil
 }
 
+// ListProjectsByWorkspace lists the projects of the user's workspaces grouped under each
+// workspace, in workspace name order. Workspaces without a matching project are left out.
+func (s *projectService) ListProjectsByWorkspace(ctx context.Context, filter *models.ProjectFilter, userID string) (*models.GroupedProjectListResponse, error) {
+	if err := validateSearch(s.config, filter.Search); err != nil {
+		return nil, err
+	}
+
+	projects, total, err := s.repos.Project.ListForMember(ctx, userID, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	// Projects arrive ordered by workspace, so each group is a run of consecutive rows
+	groups := []*models.ProjectWorkspaceGroup{}
+	for _, project := range projects {
+		if len(groups) == 0 || groups[len(groups)-1].WorkspaceID != project.WorkspaceID {
+			groups = append(groups, &models.ProjectWorkspaceGroup{
+				WorkspaceID:   project.WorkspaceID,
+				WorkspaceName: project.WorkspaceName,
+				Projects:      []*models.Project{},
+			})
+		}
+		group := groups[len(groups)-1]
+		group.Projects = append(group.Projects, project)
+	}
+
+	page := filter.Page
+	if page < 1 {
+		page = 1
+	}
+
+	pageSize := filter.PageSize
+	if pageSize < 1 {
+		pageSize = 20
+	}
+	if pageSize > 100 {
+		pageSize = 100
+	}
+
+	totalPages := int(total) / pageSize
+	if int(total)%pageSize > 0 {
+		totalPages++
+	}
+
+	return &models.GroupedProjectListResponse{
+		Workspaces: groups,
+		Total:      total,
+		Page:       page,
+		PageSize:   pageSize,
+		TotalPages: totalPages,
+	}, nil
+}
+
+// IsNameAvailable reports whether no live project in the workspace uses the name, compared as on create
+func (s *projectService) IsNameAvailable(ctx context.Context, workspaceID, userID, name string) (bool, error) {
+	if strings.TrimSpace(name) == "" {
+		return false, ErrInvalidInput
+	}
+
+	member, err := getMember(ctx, s.repos, workspaceID, userID)
+	if err != nil {
+		if err == repositories.ErrMemberNotFound {
+			return false, ErrUnauthorized
+		}
+		return false, err
+	}
+
+	if !hasRequiredRole(member.Role, models.WorkspaceRoleViewer) {
+		return false, ErrUnauthorized
+	}
+
+	_, err = s.repos.Project.GetByWorkspaceAndName(ctx, workspaceID, name)
+	if err == repositories.ErrProjectNotFound {
+		return true, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return false, nil
+}
+
 // checkProjectAccess checks if user has required access to a project
 func (s *projectService) checkProjectAccess(ctx context.Context, project *models.Project, userID string, requiredRole models.WorkspaceMemberRole) error {
-	member, err := s.repos.Member.GetByWorkspaceAndUser(ctx, project.WorkspaceID, userID)
+	member, err := getMember(ctx, s.repos, project.WorkspaceID, userID)
 	if err != nil {
 		if err == repositories.ErrMemberNotFound {
 			return ErrUnauthorized
@@ -321,4 +722,17 @@ func (s *projectService) checkProjectAccess(ctx context.Context, project *models
 	}
 
 	return nil
-}
\ No newline at end of file
+}
+
+// uniqueStrings returns values with duplicates removed, preserving order
+func uniqueStrings(values []string) []string {
+	seen := make(map[string]bool, len(values))
+	unique := make([]string, 0, len(values))
+	for _, v := range values {
+		if !seen[v] {
+			seen[v] = true
+			unique = append(unique, v)
+		}
+	}
+	return unique
+}
@@ -0,0 +1,81 @@
+package services
+
+import (
+	"context"
+
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/config"
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/models"
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/repositories"
+)
+
+// GetTenantQuota reports how many more workspaces a tenant may create. Callers may read their
+// own tenant's quota; platform admins may read any tenant's.
+func (s *workspaceService) GetTenantQuota(ctx context.Context, tenantID, callerTenantID, userID string) (*models.TenantQuota, error) {
+	if tenantID != callerTenantID && (s.config == nil || !s.config.Platform.IsAdmin(userID)) {
+		return nil, ErrUnauthorized
+	}
+
+	count, err := countTenantWorkspaces(ctx, s.repos, tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.TenantQuota{
+		TenantID:   tenantID,
+		Workspaces: models.NewQuotaUsage(count, quotaLimits(s.config).WorkspacesPerTenant),
+	}, nil
+}
+
+// GetWorkspaceQuota reports how many more projects, members and Airtable bases a workspace may
+// hold, to any of its members
+func (s *workspaceService) GetWorkspaceQuota(ctx context.Context, workspaceID, userID string) (*models.WorkspaceQuota, error) {
+	if err := s.CheckUserAccess(ctx, workspaceID, userID, models.WorkspaceRoleViewer); err != nil {
+		return nil, err
+	}
+
+	projects, err := s.repos.Project.CountByWorkspace(ctx, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+	members, err := countWorkspaceMembers(ctx, s.repos, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+	bases, err := s.repos.AirtableBase.CountByWorkspace(ctx, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+
+	limits := quotaLimits(s.config)
+	return &models.WorkspaceQuota{
+		WorkspaceID:   workspaceID,
+		Projects:      models.NewQuotaUsage(projects, limits.ProjectsPerWorkspace),
+		Members:       models.NewQuotaUsage(members, limits.MembersPerWorkspace),
+		AirtableBases: models.NewQuotaUsage(bases, limits.BasesPerWorkspace),
+	}, nil
+}
+
+// quotaLimits returns the configured quotas, all unlimited without a config
+func quotaLimits(cfg *config.Config) config.QuotaConfig {
+	if cfg == nil {
+		return config.QuotaConfig{}
+	}
+	return cfg.Quota
+}
+
+// overQuota reports whether count already fills limit, where a limit of zero is unlimited
+func overQuota(count int64, limit int) bool {
+	return limit > 0 && count >= int64(limit)
+}
+
+// countTenantWorkspaces counts a tenant's live workspaces
+func countTenantWorkspaces(ctx context.Context, repos *repositories.Repositories, tenantID string) (int64, error) {
+	_, count, err := repos.Workspace.List(ctx, &models.WorkspaceFilter{TenantID: tenantID, PageSize: 1})
+	return count, err
+}
+
+// countWorkspaceMembers counts a workspace's members
+func countWorkspaceMembers(ctx context.Context, repos *repositories.Repositories, workspaceID string) (int64, error) {
+	_, count, err := repos.Member.List(ctx, workspaceID, 1, 1)
+	return count, err
+}
@@ -0,0 +1,31 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// RunScheduledDeletionJob calls RunScheduledDeletions every interval until ctx is done. It
+// blocks, so callers start it in its own goroutine.
+func RunScheduledDeletionJob(ctx context.Context, workspaces WorkspaceService, interval time.Duration, logger *zap.Logger) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			deleted, err := workspaces.RunScheduledDeletions(ctx, now.UTC())
+			if err != nil {
+				logger.Error("Scheduled workspace deletion run failed", zap.Error(err))
+				continue
+			}
+			if deleted > 0 {
+				logger.Info("Deleted workspaces past their scheduled deletion", zap.Int("count", deleted))
+			}
+		}
+	}
+}
@@ -0,0 +1,177 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/config"
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/models"
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/repositories"
+)
+
+// serviceAccountTokenBytes is the amount of randomness in a service account token
+const serviceAccountTokenBytes = 32
+
+type serviceAccountService struct {
+	repos        *repositories.Repositories
+	config       *config.Config
+	logger       *zap.Logger
+	auditService AuditService
+}
+
+// NewServiceAccountService creates a new service account service
+func NewServiceAccountService(repos *repositories.Repositories, config *config.Config, logger *zap.Logger, auditService AuditService) ServiceAccountService {
+	return &serviceAccountService{
+		repos:        repos,
+		config:       config,
+		logger:       logger,
+		auditService: auditService,
+	}
+}
+
+// CreateServiceAccount creates a service account in a workspace and returns it with its token.
+// Only owners may create service accounts.
+func (s *serviceAccountService) CreateServiceAccount(ctx context.Context, workspaceID, userID string, req *models.CreateServiceAccountRequest) (*models.CreatedServiceAccount, error) {
+	if err := s.requireOwner(ctx, workspaceID, userID); err != nil {
+		return nil, err
+	}
+
+	name := strings.TrimSpace(req.Name)
+	if name == "" || len(name) > maxNameLength || !assignableServiceAccountRole(req.Role) {
+		return nil, ErrInvalidInput
+	}
+
+	workspace, err := s.repos.Workspace.GetByID(ctx, workspaceID)
+	if err != nil {
+		if err == repositories.ErrWorkspaceNotFound {
+			return nil, ErrWorkspaceNotFound
+		}
+		return nil, err
+	}
+
+	token, err := newServiceAccountToken()
+	if err != nil {
+		return nil, err
+	}
+
+	account := &models.ServiceAccount{
+		WorkspaceID: workspaceID,
+		TenantID:    workspace.TenantID,
+		Name:        name,
+		Role:        req.Role,
+		TokenHash:   hashServiceAccountToken(token),
+		CreatedBy:   userID,
+	}
+	if err := s.repos.ServiceAccount.Create(ctx, account); err != nil {
+		return nil, err
+	}
+
+	_ = s.auditService.LogAction(ctx, workspaceID, userID, models.AuditActionServiceAccountCreated, models.AuditResourceServiceAccount, account.ID, map[string]interface{}{
+		"name": account.Name,
+		"role": account.Role,
+	})
+
+	return &models.CreatedServiceAccount{ServiceAccount: account, Token: token}, nil
+}
+
+// ListServiceAccounts lists a workspace's service accounts, including revoked ones. Only
+// owners may list service accounts.
+func (s *serviceAccountService) ListServiceAccounts(ctx context.Context, workspaceID, userID string) ([]*models.ServiceAccount, error) {
+	if err := s.requireOwner(ctx, workspaceID, userID); err != nil {
+		return nil, err
+	}
+	return s.repos.ServiceAccount.ListByWorkspace(ctx, workspaceID)
+}
+
+// RevokeServiceAccount revokes a workspace's service account so its token stops
+// authenticating. Only owners may revoke service accounts.
+func (s *serviceAccountService) RevokeServiceAccount(ctx context.Context, workspaceID, accountID, userID string) error {
+	if err := s.requireOwner(ctx, workspaceID, userID); err != nil {
+		return err
+	}
+
+	account, err := s.repos.ServiceAccount.GetByID(ctx, accountID)
+	if err != nil {
+		if err == repositories.ErrServiceAccountNotFound {
+			return ErrServiceAccountNotFound
+		}
+		return err
+	}
+	if account.WorkspaceID != workspaceID {
+		return ErrServiceAccountNotFound
+	}
+	if account.RevokedAt != nil {
+		return nil
+	}
+
+	if err := s.repos.ServiceAccount.Revoke(ctx, accountID, time.Now()); err != nil {
+		return err
+	}
+
+	_ = s.auditService.LogAction(ctx, workspaceID, userID, models.AuditActionServiceAccountRevoked, models.AuditResourceServiceAccount, accountID, map[string]interface{}{
+		"name": account.Name,
+	})
+
+	return nil
+}
+
+// Authenticate resolves a service account token to its unrevoked account
+func (s *serviceAccountService) Authenticate(ctx context.Context, token string) (*models.ServiceAccount, error) {
+	if !strings.HasPrefix(token, models.ServiceAccountTokenPrefix) {
+		return nil, ErrInvalidToken
+	}
+
+	account, err := s.repos.ServiceAccount.GetActiveByTokenHash(ctx, hashServiceAccountToken(token))
+	if err != nil {
+		if err == repositories.ErrServiceAccountNotFound {
+			return nil, ErrInvalidToken
+		}
+		return nil, err
+	}
+	return account, nil
+}
+
+// requireOwner refuses callers that are not owners of the workspace
+func (s *serviceAccountService) requireOwner(ctx context.Context, workspaceID, userID string) error {
+	member, err := getMember(ctx, s.repos, workspaceID, userID)
+	if err != nil {
+		if err == repositories.ErrMemberNotFound {
+			return ErrUnauthorized
+		}
+		return err
+	}
+	if member.Role != models.WorkspaceRoleOwner {
+		return ErrUnauthorized
+	}
+	return nil
+}
+
+// assignableServiceAccountRole reports whether role may be given to a service account
+func assignableServiceAccountRole(role models.WorkspaceMemberRole) bool {
+	switch role {
+	case models.WorkspaceRoleAdmin, models.WorkspaceRoleMember, models.WorkspaceRoleViewer:
+		return true
+	}
+	return false
+}
+
+// newServiceAccountToken generates a random token carrying the service account prefix
+func newServiceAccountToken() (string, error) {
+	raw := make([]byte, serviceAccountTokenBytes)
+	if _, err := rand.Read(raw); err != nil {
+		return "", err
+	}
+	return models.ServiceAccountTokenPrefix + hex.EncodeToString(raw), nil
+}
+
+// hashServiceAccountToken returns the stored form of a token
+func hashServiceAccountToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -3,22 +3,43 @@ package services
 import (
 	"context"
 	"errors"
+	"time"
 
 	"go.uber.org/zap"
 
 	"github.com/Reg-Kris/pyairtable-workspace-service/internal/config"
 	"github.com/Reg-Kris/pyairtable-workspace-service/internal/models"
 	"github.com/Reg-Kris/pyairtable-workspace-service/internal/repositories"
+	"github.com/Reg-Kris/pyairtable-workspace-service/pkg/health"
 )
 
 // Common errors
 var (
-	ErrWorkspaceNotFound    = errors.New("workspace not found")
-	ErrProjectNotFound      = errors.New("project not found")
-	ErrAirtableBaseNotFound = errors.New("airtable base not found")
-	ErrUnauthorized         = errors.New("unauthorized")
-	ErrQuotaExceeded        = errors.New("quota exceeded")
-	ErrInvalidInput         = errors.New("invalid input")
+	ErrWorkspaceNotFound      = errors.New("workspace not found")
+	ErrProjectNotFound        = errors.New("project not found")
+	ErrAirtableBaseNotFound   = errors.New("airtable base not found")
+	ErrMemberNotFound         = errors.New("member not found")
+	ErrUnauthorized           = errors.New("unauthorized")
+	ErrQuotaExceeded          = errors.New("quota exceeded")
+	ErrInvalidInput           = errors.New("invalid input")
+	ErrInvalidReassignment    = errors.New("reassignment target must be another workspace member")
+	ErrConfirmationRequired   = errors.New("deleting active projects requires confirmation")
+	ErrProjectHasBases        = errors.New("project has connected Airtable bases")
+	ErrWorkspaceNotEmpty      = errors.New("workspace has projects")
+	ErrSyncInProgress         = errors.New("airtable base sync in progress")
+	ErrInvalidOwner           = errors.New("project owner must be a workspace member")
+	ErrWorkspaceNameTaken     = errors.New("workspace name already used in tenant")
+	ErrProjectNameTaken       = errors.New("project name already used in workspace")
+	ErrMemberExists           = errors.New("user is already a workspace member")
+	ErrAirtableBaseExists     = errors.New("airtable base already connected to project")
+	ErrServiceAccountNotFound = errors.New("service account not found")
+	ErrInvalidToken           = errors.New("invalid service account token")
+	ErrPrimaryOwner           = errors.New("primary owner must be reassigned first")
+	ErrAirtableBaseUnverified = errors.New("airtable base could not be verified with the gateway")
+	ErrTooManyTags            = errors.New("project would exceed the maximum number of tags")
+	ErrMemberOwnsResources    = errors.New("member created projects or bases that must be reassigned first")
+	ErrArchiverMissing        = errors.New("audit archival is enabled but no archiver is configured")
+	ErrIngestQueueFull        = errors.New("audit ingest queue is full")
 )
 
 // WorkspaceService interface
@@ -26,10 +47,24 @@ type WorkspaceService interface {
 	CreateWorkspace(ctx context.Context, tenantID, userID string, req *models.CreateWorkspaceRequest) (*models.Workspace, error)
 	GetWorkspace(ctx context.Context, workspaceID, userID string) (*models.Workspace, error)
 	UpdateWorkspace(ctx context.Context, workspaceID, userID string, req *models.UpdateWorkspaceRequest) (*models.Workspace, error)
+	GetNotificationSettings(ctx context.Context, workspaceID, userID string) (*models.NotificationSettings, error)
+	UpdateNotificationSettings(ctx context.Context, workspaceID, userID string, settings *models.NotificationSettings) (*models.NotificationSettings, error)
 	DeleteWorkspace(ctx context.Context, workspaceID, userID string) error
+	DeleteWorkspaceIfEmpty(ctx context.Context, workspaceID, userID string) error
 	ListWorkspaces(ctx context.Context, filter *models.WorkspaceFilter, userID string) (*models.WorkspaceListResponse, error)
-	GetWorkspaceStats(ctx context.Context, tenantID, userID string) (*models.WorkspaceStats, error)
+	GetWorkspaceStats(ctx context.Context, tenantID, userID string, filter *models.WorkspaceStatsFilter) (*models.WorkspaceStats, error)
+	RefreshWorkspaceStats(ctx context.Context, tenantID, actorID string) (*models.WorkspaceStats, error)
+	ScheduleWorkspaceDeletion(ctx context.Context, workspaceID, userID string, days int) (*models.Workspace, error)
+	CancelScheduledDeletion(ctx context.Context, workspaceID, userID string) (*models.Workspace, error)
+	RunScheduledDeletions(ctx context.Context, now time.Time) (int, error)
+	GetWorkspaceTrends(ctx context.Context, tenantID, userID string, filter *models.WorkspaceTrendsFilter) (*models.WorkspaceTrends, error)
+	IsNameAvailable(ctx context.Context, tenantID, name string) (bool, error)
+	ChangeTenant(ctx context.Context, workspaceID, newTenantID, actorID string) (*models.Workspace, error)
+	GetTenantQuota(ctx context.Context, tenantID, callerTenantID, userID string) (*models.TenantQuota, error)
+	GetWorkspaceQuota(ctx context.Context, workspaceID, userID string) (*models.WorkspaceQuota, error)
 	CheckUserAccess(ctx context.Context, workspaceID, userID string, requiredRole models.WorkspaceMemberRole) error
+	ResolveNames(ctx context.Context, workspaceIDs []string, userID string) (map[string]string, error)
+	WarmCache(ctx context.Context, now time.Time) (int, error)
 }
 
 // ProjectService interface
@@ -39,60 +74,147 @@ type ProjectService interface {
 	UpdateProject(ctx context.Context, projectID, userID string, req *models.UpdateProjectRequest) (*models.Project, error)
 	DeleteProject(ctx context.Context, projectID, userID string) error
 	ListProjects(ctx context.Context, filter *models.ProjectFilter, userID string) (*models.ProjectListResponse, error)
+	ListProjectsByWorkspace(ctx context.Context, filter *models.ProjectFilter, userID string) (*models.GroupedProjectListResponse, error)
+	IsNameAvailable(ctx context.Context, workspaceID, userID, name string) (bool, error)
+	BulkDeleteProjects(ctx context.Context, workspaceID, userID string, req *models.BulkDeleteProjectsRequest) (*models.BulkDeleteProjectsResponse, error)
+	SetProjectOwner(ctx context.Context, projectID, newOwnerUserID, actorID string) (*models.Project, error)
+	GetProjectAccess(ctx context.Context, projectID, userID string) (*models.ProjectAccess, error)
+	TagProjects(ctx context.Context, workspaceID, userID string, req *models.TagProjectsRequest) (*models.TagProjectsResponse, error)
+	ResolveWorkspace(ctx context.Context, projectID string) (string, error)
 }
 
 // AirtableBaseService interface
 type AirtableBaseService interface {
 	ConnectBase(ctx context.Context, projectID, userID string, req *models.CreateAirtableBaseRequest) (*models.AirtableBase, error)
+	ConnectBases(ctx context.Context, projectID, userID string, reqs []models.CreateAirtableBaseRequest) ([]*ConnectBaseResult, error)
+	UpsertBase(ctx context.Context, projectID, userID string, req *models.UpsertAirtableBaseRequest) (*models.AirtableBase, bool, error)
 	GetBase(ctx context.Context, baseID, userID string) (*models.AirtableBase, error)
 	UpdateBase(ctx context.Context, baseID, userID string, req *models.UpdateAirtableBaseRequest) (*models.AirtableBase, error)
-	DisconnectBase(ctx context.Context, baseID, userID string) error
+	DisconnectBase(ctx context.Context, baseID, userID string, force bool) error
 	ListBases(ctx context.Context, filter *models.AirtableBaseFilter, userID string) (*models.AirtableBaseListResponse, error)
+	ListUserBases(ctx context.Context, userID string, filter *models.AirtableBaseFilter) (*models.AirtableBaseListResponse, error)
+	LookupBases(ctx context.Context, airtableBaseID, userID string) ([]*models.AirtableBase, error)
+	SetWorkspaceSync(ctx context.Context, workspaceID, userID string, enabled bool) (int64, error)
 	UpdateSyncStatus(ctx context.Context, baseID string) error
+	RecordSync(ctx context.Context, baseID string, syncErr error, duration time.Duration) error
+	GetBaseMetadata(ctx context.Context, base *models.AirtableBase) (*models.AirtableBaseMetadata, error)
+	ResolveWorkspace(ctx context.Context, baseID string) (string, error)
+}
+
+// ConnectBaseResult is the outcome of one base of ConnectBases: the connected base, or the
+// error that kept it from being connected
+type ConnectBaseResult struct {
+	Base *models.AirtableBase
+	Err  error
+}
+
+// AirtableGateway fetches base details from the Airtable gateway service
+type AirtableGateway interface {
+	GetBaseMetadata(ctx context.Context, baseID string) (*models.AirtableBaseMetadata, error)
+}
+
+// AuditArchiver stores audit logs in an external object store such as S3 or GCS before
+// they are deleted. Upload returns nil only once the object is durably stored.
+type AuditArchiver interface {
+	Upload(ctx context.Context, bucket, key string, body []byte) error
+}
+
+// UserDirectory resolves user IDs to the names and emails held by the user service.
+// Users it does not know are left out of the result.
+type UserDirectory interface {
+	Resolve(ctx context.Context, userIDs []string) (map[string]models.UserInfo, error)
 }
 
 // MemberService interface
 type MemberService interface {
 	AddMember(ctx context.Context, workspaceID, userID string, req *models.AddWorkspaceMemberRequest) (*models.WorkspaceMember, error)
-	UpdateMemberRole(ctx context.Context, workspaceID, memberUserID, userID string, req *models.UpdateWorkspaceMemberRequest) error
+	UpdateMemberRole(ctx context.Context, workspaceID, memberUserID, userID string, req *models.UpdateWorkspaceMemberRequest) (*models.WorkspaceMember, error)
 	RemoveMember(ctx context.Context, workspaceID, memberUserID, userID string) error
-	ListMembers(ctx context.Context, workspaceID, userID string, page, pageSize int) (*models.WorkspaceMemberListResponse, error)
-	GetUserWorkspaces(ctx context.Context, userID string) ([]*models.Workspace, error)
+	RemoveMemberAndReassign(ctx context.Context, workspaceID, memberUserID, reassignToUserID, userID string) error
+	SetPrimaryOwner(ctx context.Context, workspaceID, memberUserID, userID string) (*models.WorkspaceMember, error)
+	ListMembers(ctx context.Context, workspaceID, userID string, page, pageSize int, search string) (*models.WorkspaceMemberListResponse, error)
+	ExportMembers(ctx context.Context, workspaceID, userID string, fn func(batch []*models.WorkspaceMember) error) error
+	ListMemberHistory(ctx context.Context, workspaceID, userID string, page, pageSize int) (*models.PastWorkspaceMemberListResponse, error)
+	GetMemberImpact(ctx context.Context, workspaceID, memberUserID, userID string) (*models.MemberImpact, error)
+	GetUserWorkspaces(ctx context.Context, userID, sortBy string) ([]*models.Workspace, error)
+	RebuildUserWorkspaceCache(ctx context.Context, userID string) ([]string, error)
+	RefreshActiveUserCaches(ctx context.Context, now time.Time) (int, error)
+}
+
+// ServiceAccountService interface
+type ServiceAccountService interface {
+	CreateServiceAccount(ctx context.Context, workspaceID, userID string, req *models.CreateServiceAccountRequest) (*models.CreatedServiceAccount, error)
+	ListServiceAccounts(ctx context.Context, workspaceID, userID string) ([]*models.ServiceAccount, error)
+	RevokeServiceAccount(ctx context.Context, workspaceID, accountID, userID string) error
+	Authenticate(ctx context.Context, token string) (*models.ServiceAccount, error)
 }
 
 // AuditService interface
 type AuditService interface {
 	LogAction(ctx context.Context, workspaceID, userID, action, resourceType, resourceID string, changes map[string]interface{}) error
 	GetAuditLogs(ctx context.Context, filter *models.AuditLogFilter, userID string) (*models.AuditLogListResponse, error)
+	GetResourceHistory(ctx context.Context, resourceType, resourceID, userID string, filter *models.AuditLogFilter) (*models.AuditLogListResponse, error)
+	GetAuditLogFacets(ctx context.Context, workspaceID, userID string) (*models.AuditLogFacets, error)
+	ListUserActivity(ctx context.Context, userID string, page, pageSize int) (*models.ActivityFeedResponse, error)
 	CleanupOldLogs(ctx context.Context, days int) error
+	IngestLogs(ctx context.Context, workspaceID string, principal *config.ServicePrincipal, entries []models.IngestAuditLogEntry) (int, error)
+	Vocabulary() *AuditVocabulary
+}
+
+// MaintenanceService reads and sets the service-wide maintenance mode
+type MaintenanceService interface {
+	Status(ctx context.Context) (*models.MaintenanceStatus, error)
+	SetMode(ctx context.Context, actorID, mode string) (*models.MaintenanceStatus, error)
 }
 
 // Services aggregates all service interfaces
 type Services struct {
-	Workspace    WorkspaceService
-	Project      ProjectService
-	AirtableBase AirtableBaseService
-	Member       MemberService
-	Audit        AuditService
+	Workspace      WorkspaceService
+	Project        ProjectService
+	AirtableBase   AirtableBaseService
+	Member         MemberService
+	ServiceAccount ServiceAccountService
+	Audit          AuditService
+	Maintenance    MaintenanceService
 
 	config *config.Config
 	logger *zap.Logger
 	repos  *repositories.Repositories
+	ingest *auditIngestWriter
 }
 
-// New creates a new Services instance
-func New(repos *repositories.Repositories, config *config.Config, logger *zap.Logger) *Services {
+// New creates a new Services instance. gateway may be nil, in which case base metadata
+// is only served from cache; events may be nil, in which case events are only logged;
+// directory may be nil, in which case members are listed and searched by user ID only;
+// archiver may be nil unless audit archival is enabled.
+func New(repos *repositories.Repositories, config *config.Config, logger *zap.Logger, gateway AirtableGateway, events EventPublisher, directory UserDirectory, archiver AuditArchiver) *Services {
+	if events == nil {
+		events = NewLogPublisher(logger)
+	}
+
 	// Create audit service first as other services depend on it
-	auditService := NewAuditService(repos, logger)
+	ingest := newAuditIngestWriter(repos, logger, config.Audit.IngestQueueSize)
+	auditService := newAuditService(repos, config, logger, archiver, ingest)
 	
 	return &Services{
-		Workspace:    NewWorkspaceService(repos, config, logger, auditService),
-		Project:      NewProjectService(repos, config, logger, auditService),
-		AirtableBase: NewAirtableBaseService(repos, config, logger, auditService),
-		Member:       NewMemberService(repos, config, logger, auditService),
-		Audit:        auditService,
-		config:       config,
-		logger:       logger,
-		repos:        repos,
+		Workspace:      NewWorkspaceService(repos, config, logger, auditService, events),
+		Project:        NewProjectService(repos, config, logger, auditService),
+		AirtableBase:   NewAirtableBaseService(repos, config, logger, auditService, gateway),
+		Member:         NewMemberService(repos, config, logger, auditService, events, directory),
+		ServiceAccount: NewServiceAccountService(repos, config, logger, auditService),
+		Audit:          auditService,
+		Maintenance:    NewMaintenanceService(repos, config, logger),
+		config:         config,
+		logger:         logger,
+		repos:          repos,
+		ingest:         ingest,
+	}
+}
+
+// HealthChecks returns the readiness checks of the stores the services depend on
+func (s *Services) HealthChecks() []health.Check {
+	if s == nil || s.repos == nil {
+		return nil
 	}
+	return s.repos.HealthChecks()
 }
\ No newline at end of file
@@ -0,0 +1,34 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// RunUserCacheRefreshJob calls RefreshActiveUserCaches every interval until ctx is done, so
+// active users find their workspace list cached without a request having to rebuild it. It
+// blocks, so callers start it in its own goroutine, and only when CacheRefresh is enabled.
+func RunUserCacheRefreshJob(ctx context.Context, members MemberService, interval time.Duration, logger *zap.Logger) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			refreshed, err := members.RefreshActiveUserCaches(ctx, now)
+			if err != nil {
+				if ctx.Err() == nil {
+					logger.Error("User workspace cache refresh run failed", zap.Error(err))
+				}
+				continue
+			}
+			if refreshed > 0 {
+				logger.Debug("Refreshed active users' workspace caches", zap.Int("count", refreshed))
+			}
+		}
+	}
+}
@@ -0,0 +1,311 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/config"
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/models"
+)
+
+// Validation limits; descriptions and settings beyond the recommended size are accepted with a warning
+const (
+	maxNameLength                = 255
+	recommendedNameLength        = 100
+	maxDescriptionLength         = 10000
+	recommendedDescriptionLength = 1000
+	recommendedSettingsKeys      = 50
+	maxAuditIngestBatch          = 500
+	maxTagLength                 = 50
+	maxProjectTags               = 20
+	maxTagProjectsBatch          = 100
+	maxResolveWorkspaceIDs       = 100
+)
+
+// FieldError is a blocking problem with one request field. Field is the JSON path of the
+// field, such as "name" or "entries[2].user_id".
+type FieldError = models.FieldError
+
+// ValidationResult separates blocking errors from advisory warnings. Errors holds each of
+// FieldErrors as "<field> <message>".
+type ValidationResult struct {
+	Errors      []string
+	FieldErrors []FieldError
+	Warnings    []string
+}
+
+// addError records a problem with field; only the first problem found with a field is kept
+func (r *ValidationResult) addError(field, format string, args ...interface{}) {
+	for _, existing := range r.FieldErrors {
+		if existing.Field == field {
+			return
+		}
+	}
+	message := fmt.Sprintf(format, args...)
+	r.FieldErrors = append(r.FieldErrors, FieldError{Field: field, Message: message})
+	r.Errors = append(r.Errors, field+" "+message)
+}
+
+func (r *ValidationResult) addWarning(format string, args ...interface{}) {
+	r.Warnings = append(r.Warnings, fmt.Sprintf(format, args...))
+}
+
+// Rejected reports whether the request must be refused; strict mode promotes warnings to errors
+func (r *ValidationResult) Rejected(strict bool) bool {
+	return len(r.Errors) > 0 || (strict && len(r.Warnings) > 0)
+}
+
+// Problems returns the messages that caused rejection
+func (r *ValidationResult) Problems(strict bool) []string {
+	if !strict {
+		return r.Errors
+	}
+	return append(append([]string{}, r.Errors...), r.Warnings...)
+}
+
+// ValidateCreateWorkspace validates a workspace creation request
+func ValidateCreateWorkspace(req *models.CreateWorkspaceRequest) *ValidationResult {
+	result := ValidateRequest(req)
+	validateName(result, &req.Name, true)
+	validateDescription(result, &req.Description)
+	validateWorkspaceSettings(result, req.Settings)
+	return result
+}
+
+// ValidateUpdateWorkspace validates a workspace update request
+func ValidateUpdateWorkspace(req *models.UpdateWorkspaceRequest) *ValidationResult {
+	result := ValidateRequest(req)
+	validateName(result, req.Name, false)
+	validateDescription(result, req.Description)
+	if req.Settings != nil {
+		validateWorkspaceSettings(result, *req.Settings)
+	}
+	return result
+}
+
+// ValidateCreateProject validates a project creation request
+func ValidateCreateProject(req *models.CreateProjectRequest) *ValidationResult {
+	result := ValidateRequest(req)
+	validateName(result, &req.Name, true)
+	validateDescription(result, &req.Description)
+	validateSettings(result, req.Settings)
+	return result
+}
+
+// ValidateUpdateProject validates a project update request
+func ValidateUpdateProject(req *models.UpdateProjectRequest) *ValidationResult {
+	result := ValidateRequest(req)
+	validateName(result, req.Name, false)
+	validateDescription(result, req.Description)
+	if req.Status != nil && *req.Status != "active" && *req.Status != "archived" {
+		result.addError("status", "must be one of: active, archived")
+	}
+	if req.Settings != nil {
+		validateSettings(result, *req.Settings)
+	}
+	return result
+}
+
+// ValidateCreateAirtableBase validates an Airtable base connection request
+func ValidateCreateAirtableBase(req *models.CreateAirtableBaseRequest) *ValidationResult {
+	result := ValidateRequest(req)
+	if strings.TrimSpace(req.BaseID) == "" {
+		result.addError("base_id", "is required")
+	} else if !strings.HasPrefix(req.BaseID, "app") {
+		result.addWarning("base_id does not look like an Airtable base ID (expected an \"app\" prefix)")
+	}
+	validateName(result, &req.Name, true)
+	validateDescription(result, &req.Description)
+	validateSettings(result, req.Settings)
+	return result
+}
+
+// ValidateUpdateAirtableBase validates an Airtable base update request
+func ValidateUpdateAirtableBase(req *models.UpdateAirtableBaseRequest) *ValidationResult {
+	result := ValidateRequest(req)
+	validateName(result, req.Name, false)
+	validateDescription(result, req.Description)
+	if req.Settings != nil {
+		validateSettings(result, *req.Settings)
+	}
+	return result
+}
+
+// ValidateIngestAuditLogs validates a batch of audit entries from another service.
+// Every entry must use an action and resource type from the vocabulary.
+func ValidateIngestAuditLogs(req *models.IngestAuditLogsRequest, vocabulary *AuditVocabulary) *ValidationResult {
+	result := ValidateRequest(req)
+	switch {
+	case len(req.Entries) == 0:
+		result.addError("entries", "must not be empty")
+	case len(req.Entries) > maxAuditIngestBatch:
+		result.addError("entries", "exceeds maximum batch size of %d", maxAuditIngestBatch)
+	}
+
+	for i, entry := range req.Entries {
+		if strings.TrimSpace(entry.UserID) == "" {
+			result.addError(fmt.Sprintf("entries[%d].user_id", i), "is required")
+		}
+		if !vocabulary.IsKnownAction(entry.Action) {
+			result.addError(fmt.Sprintf("entries[%d].action", i), "%q is not a known audit action", entry.Action)
+		}
+		if !vocabulary.IsKnownResourceType(entry.ResourceType) {
+			result.addError(fmt.Sprintf("entries[%d].resource_type", i), "%q is not a known resource type", entry.ResourceType)
+		}
+	}
+	return result
+}
+
+// ValidateResolveWorkspaceNames validates a workspace name lookup
+func ValidateResolveWorkspaceNames(req *models.ResolveWorkspaceNamesRequest) *ValidationResult {
+	result := &ValidationResult{}
+	switch {
+	case len(req.WorkspaceIDs) == 0:
+		result.addError("workspace_ids", "must not be empty")
+	case len(req.WorkspaceIDs) > maxResolveWorkspaceIDs:
+		result.addError("workspace_ids", "exceeds maximum batch size of %d", maxResolveWorkspaceIDs)
+	}
+	for i, id := range req.WorkspaceIDs {
+		if _, err := uuid.Parse(id); err != nil || len(id) != 36 {
+			result.addError(fmt.Sprintf("workspace_ids[%d]", i), "is not a valid ID")
+		}
+	}
+	return result
+}
+
+// ValidateTagProjects validates a bulk project tag change
+func ValidateTagProjects(req *models.TagProjectsRequest) *ValidationResult {
+	result := &ValidationResult{}
+	switch {
+	case len(req.ProjectIDs) == 0:
+		result.addError("project_ids", "must not be empty")
+	case len(req.ProjectIDs) > maxTagProjectsBatch:
+		result.addError("project_ids", "exceeds maximum batch size of %d", maxTagProjectsBatch)
+	}
+	for i, id := range req.ProjectIDs {
+		if _, err := uuid.Parse(id); err != nil || len(id) != 36 {
+			result.addError(fmt.Sprintf("project_ids[%d]", i), "is not a valid ID")
+		}
+	}
+	if len(req.Add) == 0 && len(req.Remove) == 0 {
+		result.addError("add", "must list at least one tag when remove lists none")
+	}
+
+	added := make(map[string]bool, len(req.Add))
+	for i, tag := range req.Add {
+		validateTag(result, "add", i, tag)
+		added[models.NormalizeTag(tag)] = true
+	}
+	for i, tag := range req.Remove {
+		validateTag(result, "remove", i, tag)
+		if normalized := models.NormalizeTag(tag); normalized != "" && added[normalized] {
+			result.addError(fmt.Sprintf("remove[%d]", i), "%q is also listed in add", normalized)
+		}
+	}
+	return result
+}
+
+func validateTag(result *ValidationResult, field string, i int, tag string) {
+	normalized := models.NormalizeTag(tag)
+	switch {
+	case normalized == "":
+		result.addError(fmt.Sprintf("%s[%d]", field, i), "must not be empty")
+	case utf8.RuneCountInString(normalized) > maxTagLength:
+		result.addError(fmt.Sprintf("%s[%d]", field, i), "exceeds maximum length of %d characters", maxTagLength)
+	}
+}
+
+func validateName(result *ValidationResult, name *string, required bool) {
+	if name == nil {
+		if required {
+			result.addError("name", "is required")
+		}
+		return
+	}
+
+	trimmed := strings.TrimSpace(*name)
+	switch {
+	case trimmed == "":
+		result.addError("name", "must not be empty")
+	case len(*name) > maxNameLength:
+		result.addError("name", "exceeds maximum length of %d characters", maxNameLength)
+	case len(*name) > recommendedNameLength:
+		result.addWarning("name exceeds recommended length of %d characters", recommendedNameLength)
+	}
+
+	if trimmed != "" && trimmed != *name {
+		result.addWarning("name has leading or trailing whitespace")
+	}
+}
+
+func validateDescription(result *ValidationResult, description *string) {
+	if description == nil {
+		return
+	}
+
+	switch {
+	case len(*description) > maxDescriptionLength:
+		result.addError("description", "exceeds maximum length of %d characters", maxDescriptionLength)
+	case len(*description) > recommendedDescriptionLength:
+		result.addWarning("description exceeds recommended length of %d characters", recommendedDescriptionLength)
+	}
+}
+
+func validateSettings(result *ValidationResult, settings models.JSONMap) {
+	if len(settings) > recommendedSettingsKeys {
+		result.addWarning("settings has %d keys, more than the recommended %d", len(settings), recommendedSettingsKeys)
+	}
+}
+
+// validateWorkspaceSettings checks the settings keys that only workspaces give meaning to
+func validateWorkspaceSettings(result *ValidationResult, settings models.JSONMap) {
+	validateSettings(result, settings)
+
+	if value, ok := settings[models.WorkspaceSettingNotifications]; ok {
+		notifications, err := models.ParseNotificationSettings(value)
+		if err != nil {
+			result.addError("settings."+models.WorkspaceSettingNotifications, "must hold notification settings")
+			return
+		}
+		validateNotificationSettings(result, "settings."+models.WorkspaceSettingNotifications+".", &notifications)
+	}
+}
+
+// ValidateNotificationSettings validates a notification settings update
+func ValidateNotificationSettings(settings *models.NotificationSettings) *ValidationResult {
+	result := &ValidationResult{}
+	validateNotificationSettings(result, "", settings)
+	return result
+}
+
+// validateNotificationSettings checks notification settings, naming their fields under prefix
+func validateNotificationSettings(result *ValidationResult, prefix string, settings *models.NotificationSettings) {
+	switch settings.Digest {
+	case "", models.NotificationDigestOff, models.NotificationDigestDaily, models.NotificationDigestWeekly:
+	default:
+		result.addError(prefix+"digest", "must be one of %s, %s or %s", models.NotificationDigestOff, models.NotificationDigestDaily, models.NotificationDigestWeekly)
+	}
+}
+
+// validateSearch rejects search terms too short to be served efficiently by the trigram indexes
+func validateSearch(cfg *config.Config, search string) error {
+	if search == "" || cfg == nil {
+		return nil
+	}
+	if utf8.RuneCountInString(strings.TrimSpace(search)) < cfg.API.MinSearchLength {
+		return ErrInvalidInput
+	}
+	return nil
+}
+
+// replacementSettings returns the settings an update stores; a present but nil map clears them to {}
+// rather than being skipped, since settings columns are never null
+func replacementSettings(settings *models.JSONMap) models.JSONMap {
+	if *settings == nil {
+		return models.JSONMap{}
+	}
+	return *settings
+}
@@ -2,7 +2,11 @@ package services
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"reflect"
+	"strings"
+	"time"
 
 	"go.uber.org/zap"
 
@@ -16,40 +20,54 @@ type workspaceService struct {
 	config       *config.Config
 	logger       *zap.Logger
 	auditService AuditService
+	events       EventPublisher
+	cacheMetrics cacheMetrics
 }
 
-// NewWorkspaceService creates a new workspace service
-func NewWorkspaceService(repos *repositories.Repositories, config *config.Config, logger *zap.Logger, auditService AuditService) WorkspaceService {
+// NewWorkspaceService creates a new workspace service. Events are only logged when no
+// publisher is given.
+func NewWorkspaceService(repos *repositories.Repositories, config *config.Config, logger *zap.Logger, auditService AuditService, events EventPublisher) WorkspaceService {
+	if events == nil {
+		events = NewLogPublisher(logger)
+	}
+
 	return &workspaceService{
 		repos:        repos,
 		config:       config,
 		logger:       logger,
 		auditService: auditService,
+		events:       events,
 	}
 }
 
 // CreateWorkspace creates a new workspace
 func (s *workspaceService) CreateWorkspace(ctx context.Context, tenantID, userID string, req *models.CreateWorkspaceRequest) (*models.Workspace, error) {
-	// TODO: Check tenant quota via Tenant Service
-	// For now, we'll use a hardcoded limit
-	const maxWorkspacesPerTenant = 10
-	
-	// Check current workspace count
-	filter := &models.WorkspaceFilter{
-		TenantID: tenantID,
-		PageSize: 1,
+	// A service account belongs to one workspace and may not create others
+	if OperationFrom(ctx).ServiceAccount() != nil {
+		return nil, ErrUnauthorized
 	}
-	
-	_, count, err := s.repos.Workspace.List(ctx, filter)
+
+	// TODO: Check tenant quota via Tenant Service
+	// For now, we'll use the configured limit
+	count, err := countTenantWorkspaces(ctx, s.repos, tenantID)
 	if err != nil {
 		s.logger.Error("Failed to count workspaces", zap.Error(err))
 		return nil, err
 	}
-	
-	if count >= maxWorkspacesPerTenant {
+
+	if overQuota(count, quotaLimits(s.config).WorkspacesPerTenant) {
 		return nil, ErrQuotaExceeded
 	}
 
+	// The caller owns the workspace unless a provisioner creates it for someone else
+	ownerID := userID
+	if requested := strings.TrimSpace(req.OwnerUserID); requested != "" && requested != userID {
+		if s.config == nil || !s.config.Platform.CanProvision(userID) {
+			return nil, ErrUnauthorized
+		}
+		ownerID = requested
+	}
+
 	// Create workspace
 	workspace := &models.Workspace{
 		TenantID:    tenantID,
@@ -78,11 +96,12 @@ func (s *workspaceService) CreateWorkspace(ctx context.Context, tenantID, userID
 		return nil, err
 	}
 
-	// Add creator as owner
+	// Add the owner member, who starts out as the primary owner
 	member := &models.WorkspaceMember{
 		WorkspaceID: workspace.ID,
-		UserID:      userID,
+		UserID:      ownerID,
 		Role:        models.WorkspaceRoleOwner,
+		IsPrimary:   true,
 	}
 
 	if err := s.repos.Member.Add(ctx, member); err != nil {
@@ -96,15 +115,21 @@ func (s *workspaceService) CreateWorkspace(ctx context.Context, tenantID, userID
 		return nil, err
 	}
 
-	// Cache the workspace
+	// Cache the workspace; the owner's cached workspace list and the tenant's stats are now stale
 	_ = s.repos.Cache.SetWorkspace(ctx, workspace)
+	_ = s.repos.Cache.InvalidateUserCache(ctx, ownerID)
+	_ = s.repos.Cache.InvalidateTenantStats(ctx, tenantID)
 
 	// Log audit
-	_ = s.auditService.LogAction(ctx, workspace.ID, userID, "workspace.created", "workspace", workspace.ID, map[string]interface{}{
+	changes := map[string]interface{}{
 		"name":        workspace.Name,
 		"description": workspace.Description,
 		"tenant_id":   workspace.TenantID,
-	})
+	}
+	if ownerID != userID {
+		changes["owner_user_id"] = ownerID
+	}
+	_ = s.auditService.LogAction(ctx, workspace.ID, userID, models.AuditActionWorkspaceCreated, models.AuditResourceWorkspace, workspace.ID, changes)
 
 	return workspace, nil
 }
@@ -112,12 +137,18 @@ func (s *workspaceService) CreateWorkspace(ctx context.Context, tenantID, userID
 // GetWorkspace retrieves a workspace by ID
 func (s *workspaceService) GetWorkspace(ctx context.Context, workspaceID, userID string) (*models.Workspace, error) {
 	// Check cache first
-	workspace, err := s.repos.Cache.GetWorkspace(ctx, workspaceID)
+	var workspace *models.Workspace
+	var err error
+	if !cacheBypassed(ctx) {
+		workspace, err = s.repos.Cache.GetWorkspace(ctx, workspaceID)
+		s.cacheMetrics.record(cacheEntityWorkspace, err == nil && workspace != nil)
+	}
 	if err == nil && workspace != nil {
 		// Check access
 		if err := s.CheckUserAccess(ctx, workspaceID, userID, models.WorkspaceRoleViewer); err != nil {
 			return nil, err
 		}
+		s.recordAccess(ctx, workspaceID, userID)
 		return workspace, nil
 	}
 
@@ -131,6 +162,7 @@ func (s *workspaceService) GetWorkspace(ctx context.Context, workspaceID, userID
 	if err := s.CheckUserAccess(ctx, workspaceID, userID, models.WorkspaceRoleViewer); err != nil {
 		return nil, err
 	}
+	s.recordAccess(ctx, workspaceID, userID)
 
 	// Cache the workspace
 	_ = s.repos.Cache.SetWorkspace(ctx, workspace)
@@ -138,6 +170,14 @@ func (s *workspaceService) GetWorkspace(ctx context.Context, workspaceID, userID
 	return workspace, nil
 }
 
+// recordAccess notes that the user opened the workspace, for sorting by last access. Failures
+// are logged rather than failing the read.
+func (s *workspaceService) recordAccess(ctx context.Context, workspaceID, userID string) {
+	if err := s.repos.Member.TouchLastAccessed(ctx, workspaceID, userID, time.Now()); err != nil {
+		s.logger.Warn("Failed to record workspace access", zap.Error(err), zap.String("workspace_id", workspaceID))
+	}
+}
+
 // UpdateWorkspace updates a workspace
 func (s *workspaceService) UpdateWorkspace(ctx context.Context, workspaceID, userID string, req *models.UpdateWorkspaceRequest) (*models.Workspace, error) {
 	// Check access
@@ -151,32 +191,30 @@ func (s *workspaceService) UpdateWorkspace(ctx context.Context, workspaceID, use
 		return nil, err
 	}
 
-	// Track changes for audit
-	changes := make(map[string]interface{})
+	// Nothing to write or audit
+	if req.IsEmpty() {
+		return workspace, nil
+	}
+
+	// Track changes for audit, one granular action per changed field
+	var changes []fieldChange
 
 	// Update fields
-	if req.Name != nil {
-		changes["name"] = map[string]interface{}{
-			"old": workspace.Name,
-			"new": *req.Name,
-		}
+	if req.Name != nil && *req.Name != workspace.Name {
+		changes = append(changes, fieldChange{models.AuditActionWorkspaceRenamed, "name", workspace.Name, *req.Name})
 		workspace.Name = *req.Name
 	}
 
-	if req.Description != nil {
-		changes["description"] = map[string]interface{}{
-			"old": workspace.Description,
-			"new": *req.Description,
-		}
+	if req.Description != nil && *req.Description != workspace.Description {
+		changes = append(changes, fieldChange{models.AuditActionWorkspaceDescriptionChanged, "description", workspace.Description, *req.Description})
 		workspace.Description = *req.Description
 	}
 
 	if req.Settings != nil {
-		changes["settings"] = map[string]interface{}{
-			"old": workspace.Settings,
-			"new": *req.Settings,
+		if settings := replacementSettings(req.Settings); !reflect.DeepEqual(settings, workspace.Settings) {
+			changes = append(changes, fieldChange{models.AuditActionWorkspaceSettingsChanged, "settings", workspace.Settings, settings})
+			workspace.Settings = settings
 		}
-		workspace.Settings = *req.Settings
 	}
 
 	// Update in database
@@ -188,13 +226,82 @@ func (s *workspaceService) UpdateWorkspace(ctx context.Context, workspaceID, use
 	_ = s.repos.Cache.DeleteWorkspace(ctx, workspaceID)
 
 	// Log audit
-	if len(changes) > 0 {
-		_ = s.auditService.LogAction(ctx, workspaceID, userID, "workspace.updated", "workspace", workspaceID, changes)
+	for _, change := range changes {
+		_ = s.auditService.LogAction(ctx, workspaceID, userID, change.action, models.AuditResourceWorkspace, workspaceID, change.payload())
 	}
 
 	return workspace, nil
 }
 
+// GetNotificationSettings returns the workspace's notification preferences to any member
+func (s *workspaceService) GetNotificationSettings(ctx context.Context, workspaceID, userID string) (*models.NotificationSettings, error) {
+	workspace, err := s.GetWorkspace(ctx, workspaceID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	settings := workspace.NotificationSettings()
+	return &settings, nil
+}
+
+// UpdateNotificationSettings replaces the workspace's notification preferences; admins only.
+// The workspace's other settings are kept.
+func (s *workspaceService) UpdateNotificationSettings(ctx context.Context, workspaceID, userID string, settings *models.NotificationSettings) (*models.NotificationSettings, error) {
+	if err := s.CheckUserAccess(ctx, workspaceID, userID, models.WorkspaceRoleAdmin); err != nil {
+		return nil, err
+	}
+
+	workspace, err := s.repos.Workspace.GetByID(ctx, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+
+	updated := *settings
+	if updated.Digest == "" {
+		updated.Digest = models.NotificationDigestOff
+	}
+	old, err := models.ParseNotificationSettings(workspace.Settings[models.WorkspaceSettingNotifications])
+	if err == nil && updated == old {
+		return &updated, nil
+	}
+
+	// Write into a copy so the loaded settings map is never shared with the update
+	stored := make(models.JSONMap, len(workspace.Settings)+1)
+	for key, value := range workspace.Settings {
+		stored[key] = value
+	}
+	workspace.Settings = stored
+	workspace.SetNotificationSettings(updated)
+	if err := s.repos.Workspace.Update(ctx, workspace); err != nil {
+		return nil, err
+	}
+
+	_ = s.repos.Cache.DeleteWorkspace(ctx, workspaceID)
+
+	change := fieldChange{models.AuditActionWorkspaceSettingsChanged, "settings." + models.WorkspaceSettingNotifications, old, updated}
+	_ = s.auditService.LogAction(ctx, workspaceID, userID, change.action, models.AuditResourceWorkspace, workspaceID, change.payload())
+
+	return &updated, nil
+}
+
+// fieldChange is a single audited field update
+type fieldChange struct {
+	action string
+	field  string
+	old    interface{}
+	new    interface{}
+}
+
+// payload renders the change in the audit diff shape keyed by field name
+func (c fieldChange) payload() map[string]interface{} {
+	return map[string]interface{}{
+		c.field: map[string]interface{}{
+			"old": c.old,
+			"new": c.new,
+		},
+	}
+}
+
 // DeleteWorkspace deletes a workspace
 func (s *workspaceService) DeleteWorkspace(ctx context.Context, workspaceID, userID string) error {
 	// Check access - only owners can delete
@@ -212,6 +319,11 @@ func (s *workspaceService) DeleteWorkspace(ctx context.Context, workspaceID, use
 		return fmt.Errorf("cannot delete workspace with %d active projects", projectCount)
 	}
 
+	workspace, err := s.repos.Workspace.GetByID(ctx, workspaceID)
+	if err != nil {
+		return err
+	}
+
 	// Delete workspace
 	if err := s.repos.Workspace.Delete(ctx, workspaceID); err != nil {
 		return err
@@ -219,23 +331,95 @@ func (s *workspaceService) DeleteWorkspace(ctx context.Context, workspaceID, use
 
 	// Invalidate cache
 	_ = s.repos.Cache.InvalidateWorkspaceCache(ctx, workspaceID)
+	_ = s.repos.Cache.InvalidateTenantStats(ctx, workspace.TenantID)
+
+	// Log audit
+	_ = s.auditService.LogAction(ctx, workspaceID, userID, models.AuditActionWorkspaceDeleted, models.AuditResourceWorkspace, workspaceID, nil)
+
+	return nil
+}
+
+// DeleteWorkspaceIfEmpty deletes a workspace only when it has no live projects. The check and
+// the delete run in one transaction holding the workspace row lock, and project creation takes
+// a share lock on the same row, so no project can be added in between.
+func (s *workspaceService) DeleteWorkspaceIfEmpty(ctx context.Context, workspaceID, userID string) error {
+	if err := s.CheckUserAccess(ctx, workspaceID, userID, models.WorkspaceRoleOwner); err != nil {
+		return err
+	}
+
+	var tenantID string
+	err := s.repos.Transaction(ctx, func(tx *repositories.Repositories) error {
+		workspace, err := tx.Workspace.Lock(ctx, workspaceID, true)
+		if err != nil {
+			return err
+		}
+		tenantID = workspace.TenantID
+
+		projectCount, err := tx.Project.CountByWorkspace(ctx, workspaceID)
+		if err != nil {
+			return err
+		}
+		if projectCount > 0 {
+			return ErrWorkspaceNotEmpty
+		}
+
+		return tx.Workspace.Delete(ctx, workspaceID)
+	})
+	if err == repositories.ErrWorkspaceNotFound {
+		return ErrWorkspaceNotFound
+	}
+	if err != nil {
+		return err
+	}
+
+	// Invalidate cache
+	_ = s.repos.Cache.InvalidateWorkspaceCache(ctx, workspaceID)
+	_ = s.repos.Cache.InvalidateTenantStats(ctx, tenantID)
 
 	// Log audit
-	_ = s.auditService.LogAction(ctx, workspaceID, userID, "workspace.deleted", "workspace", workspaceID, nil)
+	_ = s.auditService.LogAction(ctx, workspaceID, userID, models.AuditActionWorkspaceDeleted, models.AuditResourceWorkspace, workspaceID, map[string]interface{}{
+		"if_empty": true,
+	})
 
 	return nil
 }
 
 // ListWorkspaces lists workspaces accessible to the user
 func (s *workspaceService) ListWorkspaces(ctx context.Context, filter *models.WorkspaceFilter, userID string) (*models.WorkspaceListResponse, error) {
+	if err := validateSearch(s.config, filter.Search); err != nil {
+		return nil, err
+	}
+
+	if filter.ModifiedSince != "" {
+		since, err := time.Parse(time.RFC3339, filter.ModifiedSince)
+		if err != nil {
+			return nil, ErrInvalidInput
+		}
+		filter.ModifiedAfter = &since
+	}
+
 	// Get user's workspace IDs from cache
-	workspaceIDs, err := s.repos.Cache.GetUserWorkspaces(ctx, userID)
+	var workspaceIDs []string
+	var err error
+	if !cacheBypassed(ctx) {
+		workspaceIDs, err = s.repos.Cache.GetUserWorkspaces(ctx, userID)
+	}
 	if err != nil || workspaceIDs == nil {
 		// Get from database - this would typically query workspace_members table
 		// For now, we'll return all workspaces in the tenant (simplified)
 		// In production, implement proper member-based filtering
 	}
 
+	if filter.SortBy == models.WorkspaceSortLastAccessed {
+		filter.AccessedBy = userID
+	}
+
+	// Deleted workspaces are only listed to their admins, or to platform admins
+	filter.DeletedVisibleTo = ""
+	if filter.IncludeDeleted && (s.config == nil || !s.config.Platform.IsAdmin(userID)) {
+		filter.DeletedVisibleTo = userID
+	}
+
 	workspaces, total, err := s.repos.Workspace.List(ctx, filter)
 	if err != nil {
 		return nil, err
@@ -266,22 +450,348 @@ func (s *workspaceService) ListWorkspaces(ctx context.Context, filter *models.Wo
 	}, nil
 }
 
-// GetWorkspaceStats retrieves workspace statistics for a tenant
-func (s *workspaceService) GetWorkspaceStats(ctx context.Context, tenantID, userID string) (*models.WorkspaceStats, error) {
+// GetWorkspaceStats retrieves workspace statistics for a tenant, serving cached results unless
+// the request bypasses the cache. Platform-wide statistics are paginated and never cached.
+func (s *workspaceService) GetWorkspaceStats(ctx context.Context, tenantID, userID string, filter *models.WorkspaceStatsFilter) (*models.WorkspaceStats, error) {
 	// TODO: Check if user has access to tenant stats
 	// For now, we'll allow any authenticated user from the tenant
 	
-	stats, err := s.repos.Workspace.GetStats(ctx, tenantID)
+	if tenantID != "" && !cacheBypassed(ctx) {
+		stats, err := s.repos.Cache.GetWorkspaceStats(ctx, tenantID)
+		if err == nil && stats != nil {
+			return stats, nil
+		}
+	}
+
+	stats, err := s.repos.Workspace.GetStats(ctx, tenantID, filter)
 	if err != nil {
 		return nil, err
 	}
 
+	if tenantID != "" {
+		_ = s.repos.Cache.SetWorkspaceStats(ctx, tenantID, stats)
+	}
+
+	return stats, nil
+}
+
+// RefreshWorkspaceStats recomputes a tenant's statistics from the database and overwrites the
+// cached copy, for recovering from missed invalidations. Only platform admins may refresh.
+func (s *workspaceService) RefreshWorkspaceStats(ctx context.Context, tenantID, actorID string) (*models.WorkspaceStats, error) {
+	if s.config == nil || !s.config.Platform.IsAdmin(actorID) {
+		return nil, ErrUnauthorized
+	}
+
+	tenantID = strings.TrimSpace(tenantID)
+	if tenantID == "" {
+		return nil, ErrInvalidInput
+	}
+
+	stats, err := s.repos.Workspace.GetStats(ctx, tenantID, &models.WorkspaceStatsFilter{})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.repos.Cache.SetWorkspaceStats(ctx, tenantID, stats); err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("Refreshed workspace stats",
+		zap.String("tenant_id", tenantID),
+		zap.String("actor_id", actorID))
+
 	return stats, nil
 }
 
+// Scheduled deletions wait at most a year and the job deletes them in bounded batches
+const (
+	maxScheduledDeletionDays   = 365
+	scheduledDeletionBatchSize = 100
+	scheduledDeletionActor     = "system:scheduled-deletion"
+)
+
+// errDeletionNoLongerDue aborts a scheduled deletion whose schedule changed since it was listed
+var errDeletionNoLongerDue = errors.New("scheduled deletion no longer due")
+
+// Trend windows default to a month and are capped at a year
+const (
+	defaultTrendDays = 30
+	maxTrendDays     = 365
+)
+
+// GetWorkspaceTrends retrieves a tenant's workspace size averages and daily creation series,
+// serving cached results unless the request bypasses the cache
+func (s *workspaceService) GetWorkspaceTrends(ctx context.Context, tenantID, userID string, filter *models.WorkspaceTrendsFilter) (*models.WorkspaceTrends, error) {
+	days := defaultTrendDays
+	if filter != nil && filter.Days > 0 {
+		days = filter.Days
+	}
+	if days > maxTrendDays {
+		days = maxTrendDays
+	}
+
+	if !cacheBypassed(ctx) {
+		trends, err := s.repos.Cache.GetWorkspaceTrends(ctx, tenantID, days)
+		if err == nil && trends != nil {
+			return trends, nil
+		}
+	}
+
+	trends, err := s.repos.Workspace.GetTrends(ctx, tenantID, days)
+	if err != nil {
+		return nil, err
+	}
+
+	_ = s.repos.Cache.SetWorkspaceTrends(ctx, tenantID, trends)
+
+	return trends, nil
+}
+
+// ResolveNames maps the given workspace IDs to names, leaving out those the user cannot access.
+// Platform admins resolve any live workspace.
+func (s *workspaceService) ResolveNames(ctx context.Context, workspaceIDs []string, userID string) (map[string]string, error) {
+	memberID := userID
+	if s.config != nil && s.config.Platform.IsAdmin(userID) {
+		memberID = ""
+	}
+	return s.repos.Workspace.ResolveNames(ctx, workspaceIDs, memberID)
+}
+
+// IsNameAvailable reports whether no live workspace in the tenant uses the name, compared as on create
+func (s *workspaceService) IsNameAvailable(ctx context.Context, tenantID, name string) (bool, error) {
+	if strings.TrimSpace(name) == "" {
+		return false, ErrInvalidInput
+	}
+
+	_, err := s.repos.Workspace.GetByTenantAndName(ctx, tenantID, name)
+	if err == repositories.ErrWorkspaceNotFound {
+		return true, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return false, nil
+}
+
+// ChangeTenant moves a workspace to another tenant, keeping its projects and bases attached.
+// Only platform admins may move workspaces, and the name must be free in the target tenant.
+func (s *workspaceService) ChangeTenant(ctx context.Context, workspaceID, newTenantID, actorID string) (*models.Workspace, error) {
+	if s.config == nil || !s.config.Platform.IsAdmin(actorID) {
+		return nil, ErrUnauthorized
+	}
+
+	newTenantID = strings.TrimSpace(newTenantID)
+	if newTenantID == "" {
+		return nil, ErrInvalidInput
+	}
+
+	workspace, err := s.repos.Workspace.GetByID(ctx, workspaceID)
+	if err != nil {
+		if err == repositories.ErrWorkspaceNotFound {
+			return nil, ErrWorkspaceNotFound
+		}
+		return nil, err
+	}
+
+	oldTenantID := workspace.TenantID
+	if oldTenantID == newTenantID {
+		return workspace, nil
+	}
+
+	// Update re-checks name uniqueness against the workspace's (new) tenant
+	workspace.TenantID = newTenantID
+	if err := s.repos.Workspace.Update(ctx, workspace); err != nil {
+		switch err {
+		case repositories.ErrDuplicateWorkspace:
+			return nil, ErrWorkspaceNameTaken
+		case repositories.ErrWorkspaceNotFound:
+			return nil, ErrWorkspaceNotFound
+		}
+		return nil, err
+	}
+
+	// Invalidate cache; statistics of both tenants now count differently
+	_ = s.repos.Cache.InvalidateWorkspaceCache(ctx, workspaceID)
+	_ = s.repos.Cache.InvalidateTenantStats(ctx, oldTenantID)
+	_ = s.repos.Cache.InvalidateTenantStats(ctx, newTenantID)
+
+	// Log audit
+	change := fieldChange{models.AuditActionWorkspaceTenantChanged, "tenant_id", oldTenantID, newTenantID}
+	_ = s.auditService.LogAction(ctx, workspaceID, actorID, change.action, models.AuditResourceWorkspace, workspaceID, change.payload())
+
+	return workspace, nil
+}
+
+// ScheduleWorkspaceDeletion marks a workspace to be deleted by the scheduled deletion job after
+// the given number of days. Owners may reschedule; the latest schedule wins.
+func (s *workspaceService) ScheduleWorkspaceDeletion(ctx context.Context, workspaceID, userID string, days int) (*models.Workspace, error) {
+	if days < 1 || days > maxScheduledDeletionDays {
+		return nil, ErrInvalidInput
+	}
+
+	if err := s.CheckUserAccess(ctx, workspaceID, userID, models.WorkspaceRoleOwner); err != nil {
+		return nil, err
+	}
+
+	at := time.Now().UTC().AddDate(0, 0, days)
+	if err := s.repos.Workspace.SetScheduledDeletion(ctx, workspaceID, &at); err != nil {
+		if err == repositories.ErrWorkspaceNotFound {
+			return nil, ErrWorkspaceNotFound
+		}
+		return nil, err
+	}
+
+	workspace, err := s.repos.Workspace.GetByID(ctx, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+
+	_ = s.repos.Cache.InvalidateWorkspaceCache(ctx, workspaceID)
+
+	_ = s.auditService.LogAction(ctx, workspaceID, userID, models.AuditActionWorkspaceDeletionScheduled, models.AuditResourceWorkspace, workspaceID, map[string]interface{}{
+		"scheduled_deletion_at": at,
+		"days":                  days,
+	})
+	s.publish(ctx, models.EventWorkspaceDeletionScheduled, workspaceID, userID, map[string]interface{}{
+		"scheduled_deletion_at": at,
+	})
+
+	return workspace, nil
+}
+
+// CancelScheduledDeletion clears a workspace's scheduled deletion. Cancelling a workspace that
+// is not scheduled is a no-op.
+func (s *workspaceService) CancelScheduledDeletion(ctx context.Context, workspaceID, userID string) (*models.Workspace, error) {
+	if err := s.CheckUserAccess(ctx, workspaceID, userID, models.WorkspaceRoleOwner); err != nil {
+		return nil, err
+	}
+
+	workspace, err := s.repos.Workspace.GetByID(ctx, workspaceID)
+	if err != nil {
+		if err == repositories.ErrWorkspaceNotFound {
+			return nil, ErrWorkspaceNotFound
+		}
+		return nil, err
+	}
+
+	if workspace.ScheduledDeletionAt == nil {
+		return workspace, nil
+	}
+
+	scheduledAt := *workspace.ScheduledDeletionAt
+	if err := s.repos.Workspace.SetScheduledDeletion(ctx, workspaceID, nil); err != nil {
+		if err == repositories.ErrWorkspaceNotFound {
+			return nil, ErrWorkspaceNotFound
+		}
+		return nil, err
+	}
+	workspace.ScheduledDeletionAt = nil
+
+	_ = s.repos.Cache.InvalidateWorkspaceCache(ctx, workspaceID)
+
+	_ = s.auditService.LogAction(ctx, workspaceID, userID, models.AuditActionWorkspaceDeletionCancelled, models.AuditResourceWorkspace, workspaceID, map[string]interface{}{
+		"scheduled_deletion_at": scheduledAt,
+	})
+	s.publish(ctx, models.EventWorkspaceDeletionCancelled, workspaceID, userID, map[string]interface{}{
+		"scheduled_deletion_at": scheduledAt,
+	})
+
+	return workspace, nil
+}
+
+// RunScheduledDeletions deletes up to one batch of workspaces whose scheduled deletion is due
+// at now, together with their projects and bases, and returns how many were deleted. Each
+// workspace is re-checked under its row lock so a cancellation that lands first wins.
+func (s *workspaceService) RunScheduledDeletions(ctx context.Context, now time.Time) (int, error) {
+	due, err := s.repos.Workspace.ListDueForDeletion(ctx, now, scheduledDeletionBatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	deleted := 0
+	for _, candidate := range due {
+		var scheduledAt time.Time
+		err := s.repos.Transaction(ctx, func(tx *repositories.Repositories) error {
+			workspace, err := tx.Workspace.Lock(ctx, candidate.ID, true)
+			if err != nil {
+				return err
+			}
+			if workspace.ScheduledDeletionAt == nil || workspace.ScheduledDeletionAt.After(now) {
+				return errDeletionNoLongerDue
+			}
+			scheduledAt = *workspace.ScheduledDeletionAt
+
+			projects, err := tx.Project.FindByWorkspace(ctx, workspace.ID, nil, "")
+			if err != nil {
+				return err
+			}
+			for _, project := range projects {
+				if _, err := tx.AirtableBase.DeleteByProject(ctx, project.ID); err != nil {
+					return err
+				}
+				if err := tx.Project.Delete(ctx, project.ID); err != nil {
+					return err
+				}
+			}
+
+			return tx.Workspace.Delete(ctx, workspace.ID)
+		})
+		if err == errDeletionNoLongerDue || err == repositories.ErrWorkspaceNotFound {
+			continue
+		}
+		if err != nil {
+			s.logger.Error("Failed to run scheduled workspace deletion",
+				zap.Error(err),
+				zap.String("workspace_id", candidate.ID))
+			continue
+		}
+
+		deleted++
+		_ = s.repos.Cache.InvalidateWorkspaceCache(ctx, candidate.ID)
+		_ = s.repos.Cache.InvalidateTenantStats(ctx, candidate.TenantID)
+		_ = s.auditService.LogAction(ctx, candidate.ID, scheduledDeletionActor, models.AuditActionWorkspaceDeleted, models.AuditResourceWorkspace, candidate.ID, map[string]interface{}{
+			"scheduled_deletion_at": scheduledAt,
+		})
+		s.publish(ctx, models.EventWorkspaceDeletionExecuted, candidate.ID, scheduledDeletionActor, map[string]interface{}{
+			"scheduled_deletion_at": scheduledAt,
+		})
+	}
+
+	return deleted, nil
+}
+
+// invalidateWorkspaceStats drops the cached statistics of the tenant owning a workspace after
+// one of its projects or bases was created or deleted. Like other invalidations it is best
+// effort; on failure the stats catch up when the cached entry expires.
+func invalidateWorkspaceStats(ctx context.Context, repos *repositories.Repositories, workspaceID string) {
+	workspace, err := repos.Workspace.GetByID(ctx, workspaceID)
+	if err != nil {
+		return
+	}
+	_ = repos.Cache.InvalidateTenantStats(ctx, workspace.TenantID)
+}
+
+// publish sends a domain event, logging rather than failing the operation on delivery errors
+func (s *workspaceService) publish(ctx context.Context, eventType, workspaceID, actorID string, data map[string]interface{}) {
+	event := &models.Event{
+		Type:        eventType,
+		WorkspaceID: workspaceID,
+		ActorID:     actorID,
+		Data:        data,
+		OccurredAt:  time.Now().UTC(),
+	}
+
+	if err := s.events.Publish(ctx, event); err != nil {
+		s.logger.Error("Failed to publish event",
+			zap.Error(err),
+			zap.String("type", eventType),
+			zap.String("workspace_id", workspaceID))
+	}
+}
+
 // CheckUserAccess checks if a user has the required role in a workspace
 func (s *workspaceService) CheckUserAccess(ctx context.Context, workspaceID, userID string, requiredRole models.WorkspaceMemberRole) error {
-	member, err := s.repos.Member.GetByWorkspaceAndUser(ctx, workspaceID, userID)
+	member, err := getMember(ctx, s.repos, workspaceID, userID)
 	if err != nil {
 		if err == repositories.ErrMemberNotFound {
 			return ErrUnauthorized
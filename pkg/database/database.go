@@ -2,6 +2,7 @@ package database
 
 import (
 	"fmt"
+	"time"
 
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
@@ -10,7 +11,32 @@ import (
 	"github.com/Reg-Kris/pyairtable-workspace-service/internal/config"
 )
 
+// Options holds the connection settings NewWithOptions needs
+type Options struct {
+	Host     string
+	Port     int
+	User     string
+	Password string
+	Name     string
+	SSLMode  string
+	// ReplicaDSN optionally names a read replica; reads outside transactions are served from it
+	ReplicaDSN string
+}
+
 func New(cfg config.DatabaseConfig) (*gorm.DB, error) {
+	return NewWithOptions(Options{
+		Host:       cfg.Host,
+		Port:       cfg.Port,
+		User:       cfg.User,
+		Password:   cfg.Password,
+		Name:       cfg.Name,
+		SSLMode:    cfg.SSLMode,
+		ReplicaDSN: cfg.ReplicaDSN,
+	})
+}
+
+// NewWithOptions connects like New for callers that don't hold the service's configuration
+func NewWithOptions(cfg Options) (*gorm.DB, error) {
 	dsn := fmt.Sprintf(
 		"host=%s user=%s password=%s dbname=%s port=%d sslmode=%s",
 		cfg.Host, cfg.User, cfg.Password, cfg.Name, cfg.Port, cfg.SSLMode,
@@ -18,11 +44,19 @@ func New(cfg config.DatabaseConfig) (*gorm.DB, error) {
 
 	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{
 		Logger: logger.Default.LogMode(logger.Info),
+		// Timestamps GORM assigns are stored and returned in UTC
+		NowFunc: func() time.Time { return time.Now().UTC() },
 	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to connect to database: %w", err)
 	}
 
+	if cfg.ReplicaDSN != "" {
+		if err := UseReplica(db, postgres.Open(cfg.ReplicaDSN)); err != nil {
+			return nil, fmt.Errorf("failed to register read replica: %w", err)
+		}
+	}
+
 	sqlDB, err := db.DB()
 	if err != nil {
 		return nil, fmt.Errorf("failed to get underlying sql.DB: %w", err)
@@ -0,0 +1,51 @@
+package database
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+	"gorm.io/plugin/dbresolver"
+)
+
+type contextKey string
+
+const primaryKey contextKey = "force_primary"
+
+// WithPrimary marks ctx so reads run on the primary even when a replica is configured,
+// for read-after-write consistency and for reads that feed a write
+func WithPrimary(ctx context.Context) context.Context {
+	return context.WithValue(ctx, primaryKey, true)
+}
+
+// primaryForced reports whether ctx was marked with WithPrimary
+func primaryForced(ctx context.Context) bool {
+	if ctx == nil {
+		return false
+	}
+	forced, _ := ctx.Value(primaryKey).(bool)
+	return forced
+}
+
+// UseReplica routes reads outside transactions to replica and keeps writes, transactions and
+// locking reads on the primary. Reads under a WithPrimary context stay on the primary too.
+func UseReplica(db *gorm.DB, replica gorm.Dialector) error {
+	// Registered ahead of the resolver, whose callbacks also run before all others
+	forcePrimary := func(tx *gorm.DB) {
+		if primaryForced(tx.Statement.Context) {
+			dbresolver.Write.ModifyStatement(tx.Statement)
+		}
+	}
+	if err := db.Callback().Query().Before("*").Register("workspace:force_primary", forcePrimary); err != nil {
+		return err
+	}
+	if err := db.Callback().Row().Before("*").Register("workspace:force_primary", forcePrimary); err != nil {
+		return err
+	}
+	if err := db.Callback().Raw().Before("*").Register("workspace:force_primary", forcePrimary); err != nil {
+		return err
+	}
+
+	return db.Use(dbresolver.Register(dbresolver.Config{
+		Replicas: []gorm.Dialector{replica},
+	}))
+}
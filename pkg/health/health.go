@@ -0,0 +1,127 @@
+package health
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Subsystem status values reported by readiness
+const (
+	StatusOK       = "ok"
+	StatusDegraded = "degraded"
+	StatusDown     = "down"
+)
+
+// Check probes one subsystem. A failing critical check makes the service unready;
+// a failing non-critical check only marks it degraded.
+type Check struct {
+	Name     string
+	Critical bool
+	Probe    func(ctx context.Context) error
+}
+
+// SubsystemReport is the outcome of a single check
+type SubsystemReport struct {
+	Name     string `json:"name"`
+	Status   string `json:"status"`
+	Critical bool   `json:"critical"`
+	Error    string `json:"error,omitempty"`
+}
+
+// Report aggregates all subsystem checks
+type Report struct {
+	Status     string            `json:"status"`
+	Subsystems []SubsystemReport `json:"subsystems"`
+}
+
+// Ready reports whether every critical subsystem is healthy
+func (r *Report) Ready() bool {
+	return r.Status != StatusDown
+}
+
+// Registry holds the checks subsystems register as they start
+type Registry struct {
+	mu     sync.RWMutex
+	checks []Check
+}
+
+// NewRegistry creates an empty check registry
+func NewRegistry() *Registry {
+	return &Registry{}
+}
+
+// Register adds a subsystem check
+func (r *Registry) Register(check Check) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.checks = append(r.checks, check)
+}
+
+// Run executes every registered check and summarises the results
+func (r *Registry) Run(ctx context.Context) *Report {
+	r.mu.RLock()
+	checks := append([]Check(nil), r.checks...)
+	r.mu.RUnlock()
+
+	report := &Report{
+		Status:     StatusOK,
+		Subsystems: make([]SubsystemReport, 0, len(checks)),
+	}
+
+	for _, check := range checks {
+		sub := SubsystemReport{Name: check.Name, Status: StatusOK, Critical: check.Critical}
+		if err := check.Probe(ctx); err != nil {
+			sub.Error = err.Error()
+			if check.Critical {
+				sub.Status = StatusDown
+				report.Status = StatusDown
+			} else {
+				sub.Status = StatusDegraded
+				if report.Status == StatusOK {
+					report.Status = StatusDegraded
+				}
+			}
+		}
+		report.Subsystems = append(report.Subsystems, sub)
+	}
+
+	return report
+}
+
+// QueueProbe fails when a bounded queue has no free capacity
+func QueueProbe(length, capacity func() int) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		l, c := length(), capacity()
+		if c > 0 && l >= c {
+			return fmt.Errorf("queue saturated (%d/%d)", l, c)
+		}
+		return nil
+	}
+}
+
+// Heartbeat records the last time a background worker made progress
+type Heartbeat struct {
+	last atomic.Int64
+}
+
+// Beat marks the worker as alive
+func (h *Heartbeat) Beat() {
+	h.last.Store(time.Now().UnixNano())
+}
+
+// Probe fails when the worker has never beaten or has been silent for longer than maxAge
+func (h *Heartbeat) Probe(maxAge time.Duration) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		last := h.last.Load()
+		if last == 0 {
+			return fmt.Errorf("worker not started")
+		}
+		if age := time.Since(time.Unix(0, last)); age > maxAge {
+			return fmt.Errorf("worker silent for %s", age.Round(time.Second))
+		}
+		return nil
+	}
+}
@@ -10,6 +10,9 @@ type Registry struct {
 	HTTPRequestDuration  *prometheus.HistogramVec
 	DatabaseConnections  prometheus.Gauge
 	RedisConnections     prometheus.Gauge
+	AuthzDeniedTotal     prometheus.Counter
+	CacheHitsTotal       *prometheus.CounterVec
+	CacheMissesTotal     *prometheus.CounterVec
 }
 
 func NewRegistry() *Registry {
@@ -41,5 +44,25 @@ func NewRegistry() *Registry {
 				Help: "Number of active Redis connections",
 			},
 		),
+		AuthzDeniedTotal: promauto.NewCounter(
+			prometheus.CounterOpts{
+				Name: "workspaceservice_authz_denied_total",
+				Help: "Total number of requests refused for lack of access",
+			},
+		),
+		CacheHitsTotal: promauto.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "workspaceservice_cache_hits_total",
+				Help: "Total number of reads served from the Redis cache",
+			},
+			[]string{"entity"},
+		),
+		CacheMissesTotal: promauto.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "workspaceservice_cache_misses_total",
+				Help: "Total number of reads the Redis cache could not serve",
+			},
+			[]string{"entity"},
+		),
 	}
 }
\ No newline at end of file
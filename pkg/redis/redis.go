@@ -10,10 +10,23 @@ import (
 )
 
 func New(cfg config.RedisConfig) (*redis.Client, error) {
+	return NewWithOptions(Options{Host: cfg.Host, Port: cfg.Port, Password: cfg.Password, DB: cfg.DB})
+}
+
+// Options holds the connection settings NewWithOptions needs
+type Options struct {
+	Host     string
+	Port     int
+	Password string
+	DB       int
+}
+
+// NewWithOptions connects to the Redis server at host:port and checks it answers
+func NewWithOptions(opts Options) (*redis.Client, error) {
 	client := redis.NewClient(&redis.Options{
-		Addr:     fmt.Sprintf("%s:%d", cfg.Host, cfg.Port),
-		Password: cfg.Password,
-		DB:       cfg.DB,
+		Addr:     fmt.Sprintf("%s:%d", opts.Host, opts.Port),
+		Password: opts.Password,
+		DB:       opts.DB,
 	})
 
 	// Test connection
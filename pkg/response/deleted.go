@@ -0,0 +1,67 @@
+package response
+
+const deletedAtField = "deleted_at"
+
+// HideDeletedAt removes deleted_at from v, or from each item under listKey when listKey is set.
+// The field is kept only when it is set and the item's id is marked true in visible, so live rows
+// never carry it and callers without access never learn deletion metadata.
+func HideDeletedAt(v interface{}, listKey string, visible map[string]bool) (interface{}, error) {
+	obj, err := toObject(v)
+	if err != nil {
+		return nil, err
+	}
+
+	if listKey == "" {
+		hideDeletedAt(obj, visible)
+		return obj, nil
+	}
+
+	items, ok := obj[listKey].([]interface{})
+	if !ok {
+		return obj, nil
+	}
+
+	for _, item := range items {
+		if itemObj, ok := item.(map[string]interface{}); ok {
+			hideDeletedAt(itemObj, visible)
+		}
+	}
+	return obj, nil
+}
+
+func hideDeletedAt(obj map[string]interface{}, visible map[string]bool) {
+	value, ok := obj[deletedAtField]
+	if !ok {
+		return
+	}
+
+	id, _ := obj[idField].(string)
+	if value == nil || !visible[id] {
+		delete(obj, deletedAtField)
+	}
+}
+
+// Tombstones replaces each item under listKey whose id is marked true in ids with just its id and
+// "deleted": true, so callers can drop a deleted row without learning anything else about it
+func Tombstones(v interface{}, listKey string, ids map[string]bool) (interface{}, error) {
+	obj, err := toObject(v)
+	if err != nil {
+		return nil, err
+	}
+
+	items, ok := obj[listKey].([]interface{})
+	if !ok {
+		return obj, nil
+	}
+
+	for i, item := range items {
+		itemObj, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if id, _ := itemObj[idField].(string); ids[id] {
+			items[i] = map[string]interface{}{idField: id, "deleted": true}
+		}
+	}
+	return obj, nil
+}
@@ -0,0 +1,223 @@
+package response
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+)
+
+// idField is always kept so trimmed objects stay addressable
+const idField = "id"
+
+// UnknownFieldError is returned in strict mode when a requested field does not exist
+type UnknownFieldError struct {
+	Field string
+}
+
+func (e *UnknownFieldError) Error() string {
+	return "unknown field: " + e.Field
+}
+
+// ParseFields splits a comma-separated fields parameter, dropping empty entries
+func ParseFields(raw string) []string {
+	if raw == "" {
+		return nil
+	}
+
+	fields := make([]string, 0)
+	for _, part := range strings.Split(raw, ",") {
+		if field := strings.TrimSpace(part); field != "" {
+			fields = append(fields, field)
+		}
+	}
+	return fields
+}
+
+// SelectFields trims the JSON object form of v to the requested top-level fields.
+// Unknown fields are ignored unless strict is set, in which case an *UnknownFieldError is returned.
+func SelectFields(v interface{}, fields []string, strict bool) (interface{}, error) {
+	if len(fields) == 0 {
+		return v, nil
+	}
+
+	if strict {
+		if err := CheckFields(v, fields); err != nil {
+			return nil, err
+		}
+	}
+
+	obj, err := toObject(v)
+	if err != nil {
+		return nil, err
+	}
+
+	return pick(obj, fields), nil
+}
+
+// SelectListFields applies field selection to every item under listKey,
+// leaving the pagination envelope untouched.
+func SelectListFields(v interface{}, listKey string, fields []string, strict bool) (interface{}, error) {
+	if len(fields) == 0 {
+		return v, nil
+	}
+
+	if strict {
+		if err := CheckListFields(v, listKey, fields); err != nil {
+			return nil, err
+		}
+	}
+
+	obj, err := toObject(v)
+	if err != nil {
+		return nil, err
+	}
+
+	items, ok := obj[listKey].([]interface{})
+	if !ok {
+		return obj, nil
+	}
+
+	for i, item := range items {
+		if itemObj, ok := item.(map[string]interface{}); ok {
+			items[i] = pick(itemObj, fields)
+		}
+	}
+
+	return obj, nil
+}
+
+// toObject round-trips v through JSON so the result honours the model's json tags
+func toObject(v interface{}) (map[string]interface{}, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	var obj map[string]interface{}
+	if err := json.Unmarshal(data, &obj); err != nil {
+		return nil, err
+	}
+	return obj, nil
+}
+
+// pick returns a copy of obj containing only the requested fields plus id
+func pick(obj map[string]interface{}, fields []string) map[string]interface{} {
+	picked := make(map[string]interface{}, len(fields)+1)
+	if id, ok := obj[idField]; ok {
+		picked[idField] = id
+	}
+
+	for _, field := range fields {
+		if value, ok := obj[field]; ok {
+			picked[field] = value
+		}
+	}
+	return picked
+}
+
+// CheckFields returns an *UnknownFieldError for the first requested field that is not a json
+// field of v's type. Check before v is turned into a JSON object, which no longer has one.
+func CheckFields(v interface{}, fields []string) error {
+	return checkFields(reflect.TypeOf(v), fields)
+}
+
+// CheckListFields is CheckFields for the items listed under listKey in list response v
+func CheckListFields(v interface{}, listKey string, fields []string) error {
+	return checkFields(listElemType(reflect.TypeOf(v), listKey), fields)
+}
+
+// checkFields verifies each requested field maps to a json field of t
+func checkFields(t reflect.Type, fields []string) error {
+	if t == nil {
+		return nil
+	}
+
+	known := FieldNames(t)
+	for _, field := range fields {
+		if !known[field] {
+			return &UnknownFieldError{Field: field}
+		}
+	}
+	return nil
+}
+
+// FieldNames returns the json field names exposed by struct type t, including embedded structs
+func FieldNames(t reflect.Type) map[string]bool {
+	names := make(map[string]bool)
+	collectFieldNames(t, names)
+	return names
+}
+
+func collectFieldNames(t reflect.Type, names map[string]bool) {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return
+	}
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+
+		name := strings.Split(tag, ",")[0]
+		if field.Anonymous && name == "" {
+			collectFieldNames(field.Type, names)
+			continue
+		}
+		if !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		names[name] = true
+	}
+}
+
+// listElemType finds the element type of the slice field tagged listKey in list response type t
+func listElemType(t reflect.Type, listKey string) reflect.Type {
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct {
+		return nil
+	}
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if strings.Split(field.Tag.Get("json"), ",")[0] == listKey && field.Type.Kind() == reflect.Slice {
+			return field.Type.Elem()
+		}
+	}
+	return nil
+}
+
+// Unchanged returns the JSON object form of v marked as left unchanged by a no-op update
+func Unchanged(v interface{}) (interface{}, error) {
+	obj, err := toObject(v)
+	if err != nil {
+		return nil, err
+	}
+
+	obj["unchanged"] = true
+	return obj, nil
+}
+
+// WithWarnings returns the JSON object form of v with a warnings array attached
+func WithWarnings(v interface{}, warnings []string) (interface{}, error) {
+	if len(warnings) == 0 {
+		return v, nil
+	}
+
+	obj, err := toObject(v)
+	if err != nil {
+		return nil, err
+	}
+
+	obj["warnings"] = warnings
+	return obj, nil
+}
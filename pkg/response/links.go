@@ -0,0 +1,53 @@
+package response
+
+import (
+	"net/url"
+	"strconv"
+)
+
+// Links holds the navigation URLs of one page; Prev and Next are empty when there is no such page
+type Links struct {
+	First string
+	Prev  string
+	Next  string
+	Last  string
+}
+
+// PageLinks builds first/prev/next/last URLs for a page-based list by rewriting the page
+// parameter of the original query. prev is omitted on the first page and next on the last.
+func PageLinks(baseURL string, query url.Values, page, totalPages int) Links {
+	if page < 1 {
+		page = 1
+	}
+	lastPage := totalPages
+	if lastPage < 1 {
+		lastPage = 1
+	}
+
+	pageURL := func(p int) string {
+		q := url.Values{}
+		for key, values := range query {
+			q[key] = append([]string(nil), values...)
+		}
+		q.Set("page", strconv.Itoa(p))
+		return baseURL + "?" + q.Encode()
+	}
+
+	links := Links{
+		First: pageURL(1),
+		Last:  pageURL(lastPage),
+	}
+	if page > 1 {
+		// A page past the end links back to the last real page
+		prev := page - 1
+		if prev > lastPage {
+			prev = lastPage
+		}
+		links.Prev = pageURL(prev)
+	}
+	if page < lastPage {
+		links.Next = pageURL(page + 1)
+	}
+
+	return links
+}
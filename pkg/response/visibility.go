@@ -0,0 +1,76 @@
+package response
+
+import (
+	"strings"
+)
+
+// FieldVisibility declares which top-level fields of a resource are reserved for a minimum
+// workspace role, named as the RoleCheck understands it. Fields it doesn't name are visible to
+// every role.
+type FieldVisibility struct {
+	// WorkspaceKey names the field that holds the workspace an object belongs to, as a dotted
+	// path when it sits in a nested object
+	WorkspaceKey string
+	Fields       map[string]string
+}
+
+// RoleCheck reports whether the caller holds at least role in a workspace
+type RoleCheck func(workspaceID, role string) bool
+
+// RestrictFields removes from v, or from the objects listKey leads to, the fields the caller's
+// role in each object's workspace doesn't reach. listKey is a dotted path that steps through
+// nested objects and into every item of the lists along it. An object whose workspace can't be
+// read keeps none of the restricted fields, so restrict before selecting fields.
+func RestrictFields(v interface{}, listKey string, visibility FieldVisibility, allowed RoleCheck) (interface{}, error) {
+	obj, err := toObject(v)
+	if err != nil {
+		return nil, err
+	}
+
+	var path []string
+	if listKey != "" {
+		path = strings.Split(listKey, ".")
+	}
+	restrictPath(obj, path, visibility, allowed)
+	return obj, nil
+}
+
+func restrictPath(node interface{}, path []string, visibility FieldVisibility, allowed RoleCheck) {
+	switch value := node.(type) {
+	case []interface{}:
+		for _, item := range value {
+			restrictPath(item, path, visibility, allowed)
+		}
+	case map[string]interface{}:
+		if len(path) == 0 {
+			restrict(value, visibility, allowed)
+			return
+		}
+		restrictPath(value[path[0]], path[1:], visibility, allowed)
+	}
+}
+
+func restrict(obj map[string]interface{}, visibility FieldVisibility, allowed RoleCheck) {
+	workspaceID := lookupString(obj, strings.Split(visibility.WorkspaceKey, "."))
+	for field, role := range visibility.Fields {
+		if _, ok := obj[field]; !ok {
+			continue
+		}
+		if workspaceID == "" || !allowed(workspaceID, role) {
+			delete(obj, field)
+		}
+	}
+}
+
+// lookupString returns the string at path in obj, or empty when any step of it is missing
+func lookupString(obj map[string]interface{}, path []string) string {
+	for _, key := range path[:len(path)-1] {
+		next, ok := obj[key].(map[string]interface{})
+		if !ok {
+			return ""
+		}
+		obj = next
+	}
+	value, _ := obj[path[len(path)-1]].(string)
+	return value
+}
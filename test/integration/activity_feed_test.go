@@ -0,0 +1,91 @@
+package integration
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/models"
+)
+
+func TestUserActivityFeed(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration tests in short mode")
+	}
+
+	db := openTestDB(t)
+	svc := newTestServices(db)
+	ctx := context.Background()
+
+	current := seedWorkspace(t, db)
+	left := seedWorkspace(t, db)
+	deleted := seedWorkspace(t, db)
+	for _, workspace := range []*models.Workspace{current, left, deleted} {
+		seedMembers(t, db, workspace.ID, map[string]models.WorkspaceMemberRole{
+			"owner":  models.WorkspaceRoleOwner,
+			"reader": models.WorkspaceRoleViewer,
+		})
+	}
+
+	start := time.Now().Add(-time.Hour)
+	seed := func(workspaceID, action string, minutes int) {
+		require.NoError(t, db.Create(&models.WorkspaceAuditLog{
+			WorkspaceID:  workspaceID,
+			UserID:       "owner",
+			Action:       action,
+			ResourceType: models.AuditResourceWorkspace,
+			ResourceID:   workspaceID,
+			CreatedAt:    start.Add(time.Duration(minutes) * time.Minute),
+		}).Error)
+	}
+	seed(current.ID, models.AuditActionMemberAdded, 1)
+	seed(current.ID, models.AuditActionBaseSynced, 2)
+	seed(current.ID, models.AuditActionProjectUpdated, 3)
+	seed(left.ID, models.AuditActionProjectCreated, 4)
+	seed(deleted.ID, models.AuditActionProjectCreated, 5)
+	seed(current.ID, models.AuditActionWorkspaceRenamed, 6)
+
+	require.NoError(t, svc.Member.RemoveMember(ctx, left.ID, "reader", "owner"))
+	require.NoError(t, db.Delete(deleted).Error)
+
+	t.Run("only relevant entries of current workspaces, latest first", func(t *testing.T) {
+		feed, err := svc.Audit.ListUserActivity(ctx, "reader", 1, 20)
+		require.NoError(t, err)
+
+		actions := make([]string, 0, len(feed.Entries))
+		for _, entry := range feed.Entries {
+			assert.Equal(t, current.ID, entry.WorkspaceID)
+			assert.Equal(t, current.Name, entry.WorkspaceName)
+			actions = append(actions, entry.Action)
+		}
+		assert.Equal(t, []string{
+			models.AuditActionWorkspaceRenamed,
+			models.AuditActionProjectUpdated,
+			models.AuditActionMemberAdded,
+		}, actions)
+		assert.Equal(t, int64(3), feed.Total)
+	})
+
+	t.Run("pages through the feed", func(t *testing.T) {
+		feed, err := svc.Audit.ListUserActivity(ctx, "reader", 2, 2)
+		require.NoError(t, err)
+		require.Len(t, feed.Entries, 1)
+		assert.Equal(t, models.AuditActionMemberAdded, feed.Entries[0].Action)
+		assert.Equal(t, 2, feed.TotalPages)
+	})
+
+	t.Run("the remaining members still see the workspace the reader left", func(t *testing.T) {
+		feed, err := svc.Audit.ListUserActivity(ctx, "owner", 1, 20)
+		require.NoError(t, err)
+
+		workspaces := map[string]bool{}
+		for _, entry := range feed.Entries {
+			workspaces[entry.WorkspaceID] = true
+		}
+		assert.True(t, workspaces[left.ID])
+		assert.False(t, workspaces[deleted.ID])
+	})
+}
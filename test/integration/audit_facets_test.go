@@ -0,0 +1,76 @@
+package integration
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/models"
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/services"
+)
+
+func TestGetAuditLogFacets(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration tests in short mode")
+	}
+
+	db := openTestDB(t)
+	svc := newTestServices(db)
+	ctx := context.Background()
+
+	workspace := seedWorkspace(t, db)
+	other := seedWorkspace(t, db)
+	seedMembers(t, db, workspace.ID, map[string]models.WorkspaceMemberRole{
+		"admin":  models.WorkspaceRoleAdmin,
+		"member": models.WorkspaceRoleMember,
+	})
+
+	seed := func(workspaceID, userID, action string, times int) {
+		for i := 0; i < times; i++ {
+			require.NoError(t, db.Create(&models.WorkspaceAuditLog{
+				WorkspaceID:  workspaceID,
+				UserID:       userID,
+				Action:       action,
+				ResourceType: models.AuditResourceWorkspace,
+				ResourceID:   workspaceID,
+			}).Error)
+		}
+	}
+	seed(workspace.ID, "admin", models.AuditActionWorkspaceRenamed, 3)
+	seed(workspace.ID, "member", models.AuditActionProjectCreated, 2)
+	seed(workspace.ID, "admin", models.AuditActionProjectCreated, 1)
+	// Entries in other workspaces never appear
+	seed(other.ID, "outsider", models.AuditActionWorkspaceDeleted, 5)
+
+	facets, err := svc.Audit.GetAuditLogFacets(ctx, workspace.ID, "admin")
+	require.NoError(t, err)
+	assert.Equal(t, []models.AuditFacetCount{
+		{Value: models.AuditActionProjectCreated, Count: 3},
+		{Value: models.AuditActionWorkspaceRenamed, Count: 3},
+	}, facets.Actions)
+	assert.Equal(t, []models.AuditFacetCount{
+		{Value: "admin", Count: 4},
+		{Value: "member", Count: 2},
+	}, facets.Users)
+
+	t.Run("an empty log has empty facets", func(t *testing.T) {
+		empty := seedWorkspace(t, db)
+		seedMembers(t, db, empty.ID, map[string]models.WorkspaceMemberRole{"owner": models.WorkspaceRoleOwner})
+
+		facets, err := svc.Audit.GetAuditLogFacets(ctx, empty.ID, "owner")
+		require.NoError(t, err)
+		assert.Empty(t, facets.Actions)
+		assert.NotNil(t, facets.Actions)
+		assert.Empty(t, facets.Users)
+	})
+
+	t.Run("members below admin are refused", func(t *testing.T) {
+		_, err := svc.Audit.GetAuditLogFacets(ctx, workspace.ID, "member")
+		assert.Equal(t, services.ErrUnauthorized, err)
+
+		_, err = svc.Audit.GetAuditLogFacets(ctx, workspace.ID, "outsider")
+		assert.Equal(t, services.ErrUnauthorized, err)
+	})
+}
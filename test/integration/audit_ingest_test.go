@@ -0,0 +1,51 @@
+package integration
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/config"
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/models"
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/services"
+)
+
+func TestIngestAuditLogs(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration tests in short mode")
+	}
+
+	db := openTestDB(t)
+	workspace := seedWorkspace(t, db)
+	svc := newTestServices(db)
+	ctx := context.Background()
+
+	entries := []models.IngestAuditLogEntry{
+		{UserID: "user-1", Action: "data.exported", ResourceType: "report", ResourceID: "r-1"},
+		{UserID: "user-2", Action: "report.generated", ResourceType: "report", ResourceID: "r-2"},
+	}
+
+	t.Run("rejects a principal without access to the tenant", func(t *testing.T) {
+		principal := &config.ServicePrincipal{Name: "report-service", Tenants: []string{"other-tenant"}}
+
+		_, err := svc.Audit.IngestLogs(ctx, workspace.ID, principal, entries)
+		assert.ErrorIs(t, err, services.ErrUnauthorized)
+	})
+
+	t.Run("stores the batch attributed to the calling service", func(t *testing.T) {
+		principal := &config.ServicePrincipal{Name: "report-service", Tenants: []string{workspace.TenantID}}
+
+		accepted, err := svc.Audit.IngestLogs(ctx, workspace.ID, principal, entries)
+		require.NoError(t, err)
+		assert.Equal(t, 2, accepted)
+
+		var logs []*models.WorkspaceAuditLog
+		require.NoError(t, db.Where("workspace_id = ?", workspace.ID).Order("resource_id").Find(&logs).Error)
+		require.Len(t, logs, 2)
+		assert.Equal(t, "data.exported", logs[0].Action)
+		assert.Equal(t, "report-service", logs[0].Changes["source_service"])
+		assert.Equal(t, "user-2", logs[1].UserID)
+	})
+}
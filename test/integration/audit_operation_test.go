@@ -0,0 +1,56 @@
+package integration
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/models"
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/services"
+)
+
+func TestCascadeDeleteEntriesShareOperationID(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration tests in short mode")
+	}
+
+	db := openTestDB(t)
+	svc := newTestServices(db)
+
+	workspace := seedWorkspace(t, db)
+	seedMembers(t, db, workspace.ID, map[string]models.WorkspaceMemberRole{"owner": models.WorkspaceRoleOwner})
+	first := seedProject(t, db, workspace.ID, "archived-1", "archived")
+	second := seedProject(t, db, workspace.ID, "archived-2", "archived")
+	require.NoError(t, db.Create(&models.AirtableBase{ProjectID: first.ID, BaseID: "appCascade", Name: "base", CreatedBy: "owner"}).Error)
+
+	op := services.NewOperationContext("owner", workspace.TenantID, "req-cascade")
+	ctx := services.WithOperation(context.Background(), op)
+	_, err := svc.Project.BulkDeleteProjects(ctx, workspace.ID, "owner", &models.BulkDeleteProjectsRequest{
+		ProjectIDs: []string{first.ID, second.ID},
+		Force:      true,
+	})
+	require.NoError(t, err)
+
+	// A later request gets an operation of its own
+	later := services.WithOperation(context.Background(), services.NewOperationContext("owner", workspace.TenantID, "req-later"))
+	description := "after the cleanup"
+	_, err = svc.Workspace.UpdateWorkspace(later, workspace.ID, "owner", &models.UpdateWorkspaceRequest{Description: &description})
+	require.NoError(t, err)
+
+	logs, err := svc.Audit.GetAuditLogs(context.Background(), &models.AuditLogFilter{WorkspaceID: workspace.ID, OperationID: op.OperationID()}, "owner")
+	require.NoError(t, err)
+	require.Len(t, logs.Logs, 2)
+	deleted := []string{}
+	for _, log := range logs.Logs {
+		assert.Equal(t, op.OperationID(), log.OperationID)
+		assert.Equal(t, models.AuditActionProjectDeleted, log.Action)
+		deleted = append(deleted, log.ResourceID)
+	}
+	assert.ElementsMatch(t, []string{first.ID, second.ID}, deleted)
+
+	all, err := svc.Audit.GetAuditLogs(context.Background(), &models.AuditLogFilter{WorkspaceID: workspace.ID}, "owner")
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), all.Total)
+}
@@ -0,0 +1,65 @@
+package integration
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/models"
+)
+
+// seedAgedLogs inserts one audit log per age, in days, tagging each with its age
+func seedAgedLogs(t *testing.T, db *gorm.DB, workspaceID string, ages ...int) {
+	for _, age := range ages {
+		require.NoError(t, db.Create(&models.WorkspaceAuditLog{
+			WorkspaceID:  workspaceID,
+			UserID:       "auditor",
+			Action:       models.AuditActionWorkspaceUpdated,
+			ResourceType: models.AuditResourceWorkspace,
+			ResourceID:   workspaceID,
+			Changes:      models.JSONMap{"age": age},
+			CreatedAt:    time.Now().AddDate(0, 0, -age),
+		}).Error)
+	}
+}
+
+// survivingAges returns the ages of a workspace's remaining audit logs, youngest first
+func survivingAges(t *testing.T, db *gorm.DB, workspaceID string) []int {
+	var logs []*models.WorkspaceAuditLog
+	require.NoError(t, db.Where("workspace_id = ?", workspaceID).Order("created_at DESC").Find(&logs).Error)
+	ages := make([]int, len(logs))
+	for i, log := range logs {
+		ages[i] = int(log.Changes["age"].(float64))
+	}
+	return ages
+}
+
+func TestCleanupOldLogsHonorsWorkspaceRetention(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration tests in short mode")
+	}
+
+	db := openTestDB(t)
+	svc := newTestServices(db)
+
+	regulated := seedWorkspace(t, db)
+	require.NoError(t, db.Model(regulated).Update("settings", models.JSONMap{models.WorkspaceSettingAuditRetentionDays: 365}).Error)
+	standard := seedWorkspace(t, db)
+	// Overrides below the global minimum are raised to it
+	eager := seedWorkspace(t, db)
+	require.NoError(t, db.Model(eager).Update("settings", models.JSONMap{models.WorkspaceSettingAuditRetentionDays: 7}).Error)
+
+	for _, workspace := range []*models.Workspace{regulated, standard, eager} {
+		seedAgedLogs(t, db, workspace.ID, 10, 45, 200, 400)
+	}
+
+	require.NoError(t, svc.Audit.CleanupOldLogs(context.Background(), 90))
+
+	assert.Equal(t, []int{10, 45, 200}, survivingAges(t, db, regulated.ID))
+	assert.Equal(t, []int{10, 45}, survivingAges(t, db, standard.ID))
+	assert.Equal(t, []int{10}, survivingAges(t, db, eager.ID))
+}
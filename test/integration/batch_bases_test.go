@@ -0,0 +1,83 @@
+package integration
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/config"
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/models"
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/repositories"
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/services"
+)
+
+// knownBasesGateway knows only the bases it lists
+type knownBasesGateway map[string]bool
+
+func (g knownBasesGateway) GetBaseMetadata(ctx context.Context, baseID string) (*models.AirtableBaseMetadata, error) {
+	if !g[baseID] {
+		return nil, errors.New("base not found")
+	}
+	return &models.AirtableBaseMetadata{BaseID: baseID}, nil
+}
+
+func TestConnectBases(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration tests in short mode")
+	}
+
+	db := openTestDB(t)
+	newService := func(cfg *config.Config) services.AirtableBaseService {
+		repos := repositories.New(db, nil, cfg, zap.NewNop())
+		repos.Cache = noopCache{}
+		gateway := knownBasesGateway{"appExisting": true, "appFirst": true, "appSecond": true}
+		return services.New(repos, cfg, zap.NewNop(), gateway, nil, nil, nil).AirtableBase
+	}
+	ctx := context.Background()
+
+	workspace := seedWorkspace(t, db)
+	seedMembers(t, db, workspace.ID, map[string]models.WorkspaceMemberRole{"member": models.WorkspaceRoleMember})
+	project := seedProject(t, db, workspace.ID, "onboarding", "active")
+	require.NoError(t, db.Create(&models.AirtableBase{ProjectID: project.ID, BaseID: "appExisting", Name: "existing", CreatedBy: "member"}).Error)
+
+	t.Run("a batch over the quota connects nothing", func(t *testing.T) {
+		cfg := testConfig()
+		cfg.Quota.BasesPerWorkspace = 2
+
+		_, err := newService(cfg).ConnectBases(ctx, project.ID, "member", []models.CreateAirtableBaseRequest{
+			{BaseID: "appFirst", Name: "first"},
+			{BaseID: "appSecond", Name: "second"},
+		})
+		assert.ErrorIs(t, err, services.ErrQuotaExceeded)
+
+		var count int64
+		require.NoError(t, db.Model(&models.AirtableBase{}).Where("project_id = ?", project.ID).Count(&count).Error)
+		assert.Equal(t, int64(1), count)
+	})
+
+	t.Run("a mixed batch connects the new bases and skips the duplicate", func(t *testing.T) {
+		results, err := newService(testConfig()).ConnectBases(ctx, project.ID, "member", []models.CreateAirtableBaseRequest{
+			{BaseID: "appFirst", Name: "first"},
+			{BaseID: "appExisting", Name: "existing again"},
+			{BaseID: "appUnknown", Name: "unknown"},
+			{BaseID: "appSecond", Name: "second"},
+		})
+		require.NoError(t, err)
+		require.Len(t, results, 4)
+
+		require.NoError(t, results[0].Err)
+		assert.Equal(t, "appFirst", results[0].Base.BaseID)
+		assert.NotEmpty(t, results[0].Base.ID)
+		assert.ErrorIs(t, results[1].Err, services.ErrAirtableBaseExists)
+		assert.ErrorIs(t, results[2].Err, services.ErrAirtableBaseUnverified)
+		require.NoError(t, results[3].Err)
+
+		var baseIDs []string
+		require.NoError(t, db.Model(&models.AirtableBase{}).Where("project_id = ?", project.ID).Order("base_id").Pluck("base_id", &baseIDs).Error)
+		assert.Equal(t, []string{"appExisting", "appFirst", "appSecond"}, baseIDs)
+	})
+}
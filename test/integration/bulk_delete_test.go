@@ -0,0 +1,137 @@
+package integration
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/models"
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/services"
+)
+
+// seedProject inserts a project with the given status and removes it when the test ends
+func seedProject(t *testing.T, db *gorm.DB, workspaceID, name, status string) *models.Project {
+	project := &models.Project{
+		WorkspaceID: workspaceID,
+		Name:        name,
+		Status:      status,
+		Settings:    models.JSONMap{},
+		CreatedBy:   "owner",
+	}
+	require.NoError(t, db.Create(project).Error)
+	t.Cleanup(func() {
+		db.Unscoped().Where("project_id = ?", project.ID).Delete(&models.AirtableBase{})
+		db.Unscoped().Delete(project)
+	})
+	return project
+}
+
+func TestBulkDeleteProjects(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration tests in short mode")
+	}
+
+	db := openTestDB(t)
+	svc := newTestServices(db)
+	ctx := context.Background()
+
+	setup := func(t *testing.T) (*models.Workspace, []*models.Project) {
+		workspace := seedWorkspace(t, db)
+		seedMembers(t, db, workspace.ID, map[string]models.WorkspaceMemberRole{
+			"owner":  models.WorkspaceRoleOwner,
+			"member": models.WorkspaceRoleMember,
+		})
+		projects := []*models.Project{
+			seedProject(t, db, workspace.ID, "archived-1", "archived"),
+			seedProject(t, db, workspace.ID, "archived-2", "archived"),
+			seedProject(t, db, workspace.ID, "live", "active"),
+		}
+		return workspace, projects
+	}
+
+	liveCount := func(t *testing.T, workspaceID string) int64 {
+		var count int64
+		require.NoError(t, db.Model(&models.Project{}).
+			Where("workspace_id = ? AND deleted_at IS NULL", workspaceID).
+			Count(&count).Error)
+		return count
+	}
+
+	t.Run("deletes by status filter", func(t *testing.T) {
+		workspace, _ := setup(t)
+
+		result, err := svc.Project.BulkDeleteProjects(ctx, workspace.ID, "owner", &models.BulkDeleteProjectsRequest{Status: "archived"})
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), result.ProjectsDeleted)
+		assert.Equal(t, int64(1), liveCount(t, workspace.ID))
+	})
+
+	t.Run("deletes by ID with bases when forced", func(t *testing.T) {
+		workspace, projects := setup(t)
+		require.NoError(t, db.Create(&models.AirtableBase{
+			ProjectID: projects[0].ID,
+			BaseID:    fmt.Sprintf("appBulk%s", projects[0].ID[:8]),
+			Name:      "base",
+			CreatedBy: "owner",
+		}).Error)
+
+		req := &models.BulkDeleteProjectsRequest{ProjectIDs: []string{projects[0].ID, projects[1].ID}}
+		_, err := svc.Project.BulkDeleteProjects(ctx, workspace.ID, "owner", req)
+		assert.ErrorIs(t, err, services.ErrProjectHasBases)
+		assert.Equal(t, int64(3), liveCount(t, workspace.ID))
+
+		req.Force = true
+		result, err := svc.Project.BulkDeleteProjects(ctx, workspace.ID, "owner", req)
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), result.ProjectsDeleted)
+		assert.Equal(t, int64(1), result.BasesDeleted)
+		assert.Equal(t, int64(1), liveCount(t, workspace.ID))
+	})
+
+	t.Run("active projects need confirmation", func(t *testing.T) {
+		workspace, projects := setup(t)
+
+		req := &models.BulkDeleteProjectsRequest{ProjectIDs: []string{projects[2].ID}}
+		_, err := svc.Project.BulkDeleteProjects(ctx, workspace.ID, "owner", req)
+		assert.ErrorIs(t, err, services.ErrConfirmationRequired)
+
+		req.ConfirmActive = true
+		result, err := svc.Project.BulkDeleteProjects(ctx, workspace.ID, "owner", req)
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), result.ProjectsDeleted)
+	})
+
+	t.Run("requires admin role", func(t *testing.T) {
+		workspace, _ := setup(t)
+
+		_, err := svc.Project.BulkDeleteProjects(ctx, workspace.ID, "member", &models.BulkDeleteProjectsRequest{Status: "archived"})
+		assert.ErrorIs(t, err, services.ErrUnauthorized)
+		assert.Equal(t, int64(3), liveCount(t, workspace.ID))
+	})
+
+	t.Run("rejects IDs from another workspace", func(t *testing.T) {
+		workspace, _ := setup(t)
+		_, others := setup(t)
+
+		req := &models.BulkDeleteProjectsRequest{ProjectIDs: []string{others[0].ID}}
+		_, err := svc.Project.BulkDeleteProjects(ctx, workspace.ID, "owner", req)
+		assert.ErrorIs(t, err, services.ErrProjectNotFound)
+	})
+
+	t.Run("requires exactly one selector", func(t *testing.T) {
+		workspace, projects := setup(t)
+
+		_, err := svc.Project.BulkDeleteProjects(ctx, workspace.ID, "owner", &models.BulkDeleteProjectsRequest{})
+		assert.ErrorIs(t, err, services.ErrInvalidInput)
+
+		_, err = svc.Project.BulkDeleteProjects(ctx, workspace.ID, "owner", &models.BulkDeleteProjectsRequest{
+			ProjectIDs: []string{projects[0].ID},
+			Status:     "archived",
+		})
+		assert.ErrorIs(t, err, services.ErrInvalidInput)
+	})
+}
@@ -0,0 +1,59 @@
+package integration
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/models"
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/repositories"
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/services"
+)
+
+func TestChangeTenantNameCollision(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration tests in short mode")
+	}
+
+	db := openTestDB(t)
+	ctx := context.Background()
+
+	cfg := testConfig()
+	cfg.Platform.Admins = "platform-admin"
+	repos := repositories.New(db, nil, cfg, zap.NewNop())
+	repos.Cache = noopCache{}
+	svc := services.New(repos, cfg, zap.NewNop(), nil, nil, nil, nil)
+
+	moving := seedWorkspace(t, db)
+	target := "target-" + t.Name()
+	existing := &models.Workspace{
+		TenantID:  target,
+		Name:      moving.Name,
+		Settings:  models.JSONMap{},
+		CreatedBy: "seed-user",
+	}
+	require.NoError(t, db.Create(existing).Error)
+	t.Cleanup(func() { db.Unscoped().Delete(existing) })
+
+	project := seedProject(t, db, moving.ID, "stays-attached", "active")
+
+	_, err := svc.Workspace.ChangeTenant(ctx, moving.ID, target, "platform-admin")
+	assert.Equal(t, services.ErrWorkspaceNameTaken, err)
+
+	var reloaded models.Workspace
+	require.NoError(t, db.First(&reloaded, "id = ?", moving.ID).Error)
+	assert.Equal(t, moving.TenantID, reloaded.TenantID)
+
+	// Once the name is free the move goes through and children stay attached
+	require.NoError(t, db.Delete(existing).Error)
+	moved, err := svc.Workspace.ChangeTenant(ctx, moving.ID, target, "platform-admin")
+	require.NoError(t, err)
+	assert.Equal(t, target, moved.TenantID)
+
+	var reloadedProject models.Project
+	require.NoError(t, db.First(&reloadedProject, "id = ?", project.ID).Error)
+	assert.Equal(t, moving.ID, reloadedProject.WorkspaceID)
+}
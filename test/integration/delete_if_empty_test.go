@@ -0,0 +1,94 @@
+package integration
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/models"
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/services"
+)
+
+func TestDeleteWorkspaceIfEmpty(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration tests in short mode")
+	}
+
+	db := openTestDB(t)
+	svc := newTestServices(db)
+	ctx := context.Background()
+
+	isLive := func(t *testing.T, workspaceID string) bool {
+		var count int64
+		require.NoError(t, db.Model(&models.Workspace{}).
+			Where("id = ? AND deleted_at IS NULL", workspaceID).
+			Count(&count).Error)
+		return count == 1
+	}
+
+	liveProjects := func(t *testing.T, workspaceID string) int64 {
+		var count int64
+		require.NoError(t, db.Model(&models.Project{}).
+			Where("workspace_id = ? AND deleted_at IS NULL", workspaceID).
+			Count(&count).Error)
+		return count
+	}
+
+	t.Run("refuses a workspace with projects", func(t *testing.T) {
+		workspace := seedWorkspace(t, db)
+		seedMembers(t, db, workspace.ID, map[string]models.WorkspaceMemberRole{"owner": models.WorkspaceRoleOwner})
+		seedProject(t, db, workspace.ID, "keep", "active")
+
+		err := svc.Workspace.DeleteWorkspaceIfEmpty(ctx, workspace.ID, "owner")
+		assert.Equal(t, services.ErrWorkspaceNotEmpty, err)
+		assert.True(t, isLive(t, workspace.ID))
+	})
+
+	t.Run("deletes an empty workspace", func(t *testing.T) {
+		workspace := seedWorkspace(t, db)
+		seedMembers(t, db, workspace.ID, map[string]models.WorkspaceMemberRole{"owner": models.WorkspaceRoleOwner})
+
+		require.NoError(t, svc.Workspace.DeleteWorkspaceIfEmpty(ctx, workspace.ID, "owner"))
+		assert.False(t, isLive(t, workspace.ID))
+	})
+
+	t.Run("never deletes a workspace while a project is being created", func(t *testing.T) {
+		for round := 0; round < 20; round++ {
+			workspace := seedWorkspace(t, db)
+			seedMembers(t, db, workspace.ID, map[string]models.WorkspaceMemberRole{"owner": models.WorkspaceRoleOwner})
+			t.Cleanup(func() {
+				db.Unscoped().Where("workspace_id = ?", workspace.ID).Delete(&models.Project{})
+			})
+
+			var wg sync.WaitGroup
+			var deleteErr, createErr error
+			wg.Add(2)
+			go func() {
+				defer wg.Done()
+				deleteErr = svc.Workspace.DeleteWorkspaceIfEmpty(ctx, workspace.ID, "owner")
+			}()
+			go func() {
+				defer wg.Done()
+				_, createErr = svc.Project.CreateProject(ctx, workspace.ID, "owner", &models.CreateProjectRequest{
+					Name: fmt.Sprintf("racing-%d", round),
+				})
+			}()
+			wg.Wait()
+
+			// Exactly one side wins: a deleted workspace is left without live projects
+			if deleteErr == nil {
+				assert.Error(t, createErr, "round %d", round)
+				assert.Zero(t, liveProjects(t, workspace.ID), "round %d", round)
+			} else {
+				assert.Equal(t, services.ErrWorkspaceNotEmpty, deleteErr, "round %d", round)
+				assert.NoError(t, createErr, "round %d", round)
+				assert.True(t, isLive(t, workspace.ID), "round %d", round)
+				assert.Equal(t, int64(1), liveProjects(t, workspace.ID), "round %d", round)
+			}
+		}
+	})
+}
@@ -0,0 +1,43 @@
+package integration
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/models"
+)
+
+func TestDeletedProjectsAcrossWorkspaces(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration tests in short mode")
+	}
+
+	db := openTestDB(t)
+	svc := newTestServices(db)
+	ctx := context.Background()
+
+	administered := seedWorkspace(t, db)
+	viewed := seedWorkspace(t, db)
+	seedMembers(t, db, administered.ID, map[string]models.WorkspaceMemberRole{"caller": models.WorkspaceRoleAdmin})
+	seedMembers(t, db, viewed.ID, map[string]models.WorkspaceMemberRole{"caller": models.WorkspaceRoleViewer})
+
+	ownDeleted := seedProject(t, db, administered.ID, "deleted-administered", "active")
+	otherDeleted := seedProject(t, db, viewed.ID, "deleted-viewed", "active")
+	live := seedProject(t, db, viewed.ID, "live-viewed", "active")
+	require.NoError(t, db.Delete(ownDeleted).Error)
+	require.NoError(t, db.Delete(otherDeleted).Error)
+
+	response, err := svc.Project.ListProjects(ctx, &models.ProjectFilter{IncludeDeleted: true, PageSize: 100}, "caller")
+	require.NoError(t, err)
+
+	listed := make(map[string]bool)
+	for _, project := range response.Projects {
+		listed[project.ID] = true
+	}
+	assert.True(t, listed[ownDeleted.ID], "deleted project of an administered workspace")
+	assert.True(t, listed[live.ID])
+	assert.False(t, listed[otherDeleted.ID], "deleted project of a workspace the caller only views")
+}
@@ -0,0 +1,52 @@
+package integration
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/models"
+)
+
+func TestListProjectsGroupedByWorkspace(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration tests in short mode")
+	}
+
+	db := openTestDB(t)
+	svc := newTestServices(db)
+	ctx := context.Background()
+	userID := "grouped-" + t.Name()
+
+	// Seeded names are timestamps, so workspaces group in creation order
+	first := seedWorkspace(t, db)
+	empty := seedWorkspace(t, db)
+	second := seedWorkspace(t, db)
+	other := seedWorkspace(t, db)
+	for _, workspace := range []*models.Workspace{first, empty, second} {
+		seedMembers(t, db, workspace.ID, map[string]models.WorkspaceMemberRole{userID: models.WorkspaceRoleViewer})
+	}
+
+	seedProject(t, db, first.ID, "Roadmap", "active")
+	seedProject(t, db, first.ID, "Budget", "active")
+	seedProject(t, db, second.ID, "Hiring", "active")
+	seedProject(t, db, other.ID, "Not mine", "active")
+
+	grouped, err := svc.Project.ListProjectsByWorkspace(ctx, &models.ProjectFilter{}, userID)
+	require.NoError(t, err)
+
+	assert.Equal(t, int64(3), grouped.Total)
+	require.Len(t, grouped.Workspaces, 2, "the empty and non-member workspaces are omitted")
+
+	assert.Equal(t, first.ID, grouped.Workspaces[0].WorkspaceID)
+	assert.Equal(t, first.Name, grouped.Workspaces[0].WorkspaceName)
+	require.Len(t, grouped.Workspaces[0].Projects, 2)
+	assert.Equal(t, "Budget", grouped.Workspaces[0].Projects[0].Name)
+	assert.Equal(t, "Roadmap", grouped.Workspaces[0].Projects[1].Name)
+
+	assert.Equal(t, second.ID, grouped.Workspaces[1].WorkspaceID)
+	require.Len(t, grouped.Workspaces[1].Projects, 1)
+	assert.Equal(t, "Hiring", grouped.Workspaces[1].Projects[0].Name)
+}
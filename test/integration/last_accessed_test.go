@@ -0,0 +1,87 @@
+package integration
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/models"
+)
+
+func TestWorkspacesSortedByLastAccess(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration tests in short mode")
+	}
+
+	db := openTestDB(t)
+	svc := newTestServices(db)
+	ctx := context.Background()
+
+	var workspaces []*models.Workspace
+	for i := 0; i < 3; i++ {
+		workspace := seedWorkspace(t, db)
+		seedMembers(t, db, workspace.ID, map[string]models.WorkspaceMemberRole{
+			"alice": models.WorkspaceRoleMember,
+			"bob":   models.WorkspaceRoleMember,
+		})
+		workspaces = append(workspaces, workspace)
+	}
+	first, second, third := workspaces[0], workspaces[1], workspaces[2]
+
+	accessedAt := func(workspace *models.Workspace, userID string, at time.Time) {
+		require.NoError(t, db.Model(&models.WorkspaceMember{}).
+			Where("workspace_id = ? AND user_id = ?", workspace.ID, userID).
+			UpdateColumn("last_accessed_at", at).Error)
+	}
+	now := time.Now().UTC()
+	// alice opened the third workspace most recently and never opened the second
+	accessedAt(first, "alice", now.Add(-2*time.Hour))
+	accessedAt(third, "alice", now.Add(-time.Hour))
+	// bob's accesses run the other way
+	accessedAt(second, "bob", now.Add(-3*time.Hour))
+	accessedAt(first, "bob", now.Add(-time.Hour))
+
+	ids := func(list []*models.Workspace) []string {
+		result := make([]string, len(list))
+		for i, workspace := range list {
+			result[i] = workspace.ID
+		}
+		return result
+	}
+
+	listed := func(t *testing.T, userID string) []string {
+		response, err := svc.Workspace.ListWorkspaces(ctx, &models.WorkspaceFilter{
+			TenantID: first.TenantID,
+			SortBy:   models.WorkspaceSortLastAccessed,
+		}, userID)
+		require.NoError(t, err)
+		assert.Equal(t, int64(3), response.Total)
+		return ids(response.Workspaces)
+	}
+
+	t.Run("list orders by the caller's own access", func(t *testing.T) {
+		assert.Equal(t, []string{third.ID, first.ID, second.ID}, listed(t, "alice"))
+		assert.Equal(t, []string{first.ID, second.ID, third.ID}, listed(t, "bob"))
+	})
+
+	t.Run("user workspaces order by the caller's own access", func(t *testing.T) {
+		aliceWorkspaces, err := svc.Member.GetUserWorkspaces(ctx, "alice", models.WorkspaceSortLastAccessed)
+		require.NoError(t, err)
+		assert.Equal(t, []string{third.ID, first.ID, second.ID}, ids(aliceWorkspaces))
+
+		bobWorkspaces, err := svc.Member.GetUserWorkspaces(ctx, "bob", models.WorkspaceSortLastAccessed)
+		require.NoError(t, err)
+		assert.Equal(t, []string{first.ID, second.ID, third.ID}, ids(bobWorkspaces))
+	})
+
+	t.Run("opening a workspace records the access", func(t *testing.T) {
+		_, err := svc.Workspace.GetWorkspace(ctx, second.ID, "alice")
+		require.NoError(t, err)
+
+		assert.Equal(t, []string{second.ID, third.ID, first.ID}, listed(t, "alice"))
+		assert.Equal(t, []string{first.ID, second.ID, third.ID}, listed(t, "bob"))
+	})
+}
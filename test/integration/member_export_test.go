@@ -0,0 +1,50 @@
+package integration
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/models"
+)
+
+func TestExportMembersCoversWholeRoster(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration tests in short mode")
+	}
+
+	db := openTestDB(t)
+	svc := newTestServices(db)
+	ctx := context.Background()
+
+	workspace := seedWorkspace(t, db)
+	members := []*models.WorkspaceMember{{WorkspaceID: workspace.ID, UserID: "admin", Role: models.WorkspaceRoleAdmin}}
+	for i := 0; i < 1100; i++ {
+		members = append(members, &models.WorkspaceMember{
+			WorkspaceID: workspace.ID,
+			UserID:      fmt.Sprintf("member-%04d", i),
+			Role:        models.WorkspaceRoleMember,
+		})
+	}
+	require.NoError(t, db.CreateInBatches(members, 500).Error)
+
+	page, err := svc.Member.ListMembers(ctx, workspace.ID, "admin", 1, 100, "")
+	require.NoError(t, err)
+
+	seen := make(map[string]bool)
+	previous := ""
+	require.NoError(t, svc.Member.ExportMembers(ctx, workspace.ID, "admin", func(batch []*models.WorkspaceMember) error {
+		for _, member := range batch {
+			assert.Greater(t, member.UserID, previous, "members are exported in user ID order")
+			previous = member.UserID
+			seen[member.UserID] = true
+		}
+		return nil
+	}))
+
+	assert.Equal(t, page.Total, int64(len(seen)))
+	assert.Len(t, seen, len(members))
+}
@@ -0,0 +1,80 @@
+package integration
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/models"
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/services"
+)
+
+func TestMemberRemovalKeepsHistory(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration tests in short mode")
+	}
+
+	db := openTestDB(t)
+	workspace := seedWorkspace(t, db)
+	svc := newTestServices(db)
+	ctx := context.Background()
+
+	seedMembers(t, db, workspace.ID, map[string]models.WorkspaceMemberRole{
+		"owner":  models.WorkspaceRoleOwner,
+		"leaver": models.WorkspaceRoleMember,
+		"viewer": models.WorkspaceRoleViewer,
+	})
+	joined, err := svc.Member.ListMembers(ctx, workspace.ID, "owner", 1, 20, "")
+	require.NoError(t, err)
+	var joinedAt time.Time
+	for _, member := range joined.Members {
+		if member.UserID == "leaver" {
+			joinedAt = member.JoinedAt
+		}
+	}
+
+	require.NoError(t, svc.Member.RemoveMember(ctx, workspace.ID, "leaver", "owner"))
+
+	t.Run("removed members leave the active list", func(t *testing.T) {
+		active, err := svc.Member.ListMembers(ctx, workspace.ID, "owner", 1, 20, "")
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), active.Total)
+		for _, member := range active.Members {
+			assert.NotEqual(t, "leaver", member.UserID)
+		}
+
+		_, err = svc.Member.ListMembers(ctx, workspace.ID, "leaver", 1, 20, "")
+		assert.Error(t, err)
+	})
+
+	t.Run("history records the membership", func(t *testing.T) {
+		history, err := svc.Member.ListMemberHistory(ctx, workspace.ID, "owner", 1, 20)
+		require.NoError(t, err)
+		require.Equal(t, int64(1), history.Total)
+
+		past := history.Members[0]
+		assert.Equal(t, "leaver", past.UserID)
+		assert.Equal(t, models.WorkspaceRoleMember, past.Role)
+		assert.True(t, joinedAt.Equal(past.JoinedAt), "joined_at is carried over")
+		assert.False(t, past.LeftAt.Before(past.JoinedAt))
+	})
+
+	t.Run("rejoining and leaving again adds a second record", func(t *testing.T) {
+		_, err := svc.Member.AddMember(ctx, workspace.ID, "owner", &models.AddWorkspaceMemberRequest{UserID: "leaver", Role: models.WorkspaceRoleViewer})
+		require.NoError(t, err)
+		require.NoError(t, svc.Member.RemoveMember(ctx, workspace.ID, "leaver", "leaver"))
+
+		history, err := svc.Member.ListMemberHistory(ctx, workspace.ID, "owner", 1, 20)
+		require.NoError(t, err)
+		require.Equal(t, int64(2), history.Total)
+		assert.Equal(t, models.WorkspaceRoleViewer, history.Members[0].Role, "latest to leave first")
+	})
+
+	t.Run("only admins see the history", func(t *testing.T) {
+		_, err := svc.Member.ListMemberHistory(ctx, workspace.ID, "viewer", 1, 20)
+		assert.Equal(t, services.ErrUnauthorized, err)
+	})
+}
@@ -0,0 +1,103 @@
+package integration
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/config"
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/models"
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/repositories"
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/services"
+)
+
+func TestMemberRemovalPolicies(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration tests in short mode")
+	}
+
+	db := openTestDB(t)
+	ctx := context.Background()
+
+	// setup seeds a workspace whose primary owner is "owner" and whose project and base were
+	// created by "leaver", returning services applying policy
+	setup := func(t *testing.T, policy string) (*services.Services, *models.Workspace, *models.Project, *models.AirtableBase) {
+		workspace := seedWorkspace(t, db)
+		seedMembers(t, db, workspace.ID, map[string]models.WorkspaceMemberRole{
+			"owner":  models.WorkspaceRoleOwner,
+			"leaver": models.WorkspaceRoleMember,
+		})
+		require.NoError(t, db.Model(&models.WorkspaceMember{}).
+			Where("workspace_id = ? AND user_id = ?", workspace.ID, "owner").
+			Update("is_primary", true).Error)
+
+		project := &models.Project{WorkspaceID: workspace.ID, Name: "policy", Status: "active", Settings: models.JSONMap{}, CreatedBy: "leaver"}
+		require.NoError(t, db.Create(project).Error)
+		base := &models.AirtableBase{ProjectID: project.ID, BaseID: "appPolicy", Name: "base", CreatedBy: "leaver"}
+		require.NoError(t, db.Create(base).Error)
+		t.Cleanup(func() {
+			db.Unscoped().Delete(base)
+			db.Unscoped().Delete(project)
+		})
+
+		cfg := testConfig()
+		cfg.Members.RemovalPolicy = policy
+		repos := repositories.New(db, nil, cfg, zap.NewNop())
+		repos.Cache = noopCache{}
+		return services.New(repos, cfg, zap.NewNop(), nil, nil, nil, nil), workspace, project, base
+	}
+
+	creatorOf := func(t *testing.T, model interface{}, id string) string {
+		var createdBy string
+		require.NoError(t, db.Model(model).Where("id = ?", id).Pluck("created_by", &createdBy).Error)
+		return createdBy
+	}
+	isMember := func(t *testing.T, workspaceID, userID string) bool {
+		var count int64
+		require.NoError(t, db.Model(&models.WorkspaceMember{}).
+			Where("workspace_id = ? AND user_id = ?", workspaceID, userID).
+			Count(&count).Error)
+		return count > 0
+	}
+
+	t.Run("orphan leaves created_by as it was", func(t *testing.T) {
+		svc, workspace, project, base := setup(t, config.MemberRemovalOrphan)
+
+		require.NoError(t, svc.Member.RemoveMember(ctx, workspace.ID, "leaver", "owner"))
+		assert.False(t, isMember(t, workspace.ID, "leaver"))
+		assert.Equal(t, "leaver", creatorOf(t, &models.Project{}, project.ID))
+		assert.Equal(t, "leaver", creatorOf(t, &models.AirtableBase{}, base.ID))
+	})
+
+	t.Run("block keeps the member while they own resources", func(t *testing.T) {
+		svc, workspace, project, _ := setup(t, config.MemberRemovalBlock)
+
+		assert.ErrorIs(t, svc.Member.RemoveMember(ctx, workspace.ID, "leaver", "owner"), services.ErrMemberOwnsResources)
+		assert.True(t, isMember(t, workspace.ID, "leaver"))
+
+		// Once their project is someone else's, removal goes through (the base is theirs too)
+		require.NoError(t, db.Model(&models.Project{}).Where("id = ?", project.ID).Update("created_by", "owner").Error)
+		assert.ErrorIs(t, svc.Member.RemoveMember(ctx, workspace.ID, "leaver", "owner"), services.ErrMemberOwnsResources)
+		require.NoError(t, db.Model(&models.AirtableBase{}).Where("project_id = ?", project.ID).Update("created_by", "owner").Error)
+		require.NoError(t, svc.Member.RemoveMember(ctx, workspace.ID, "leaver", "owner"))
+		assert.False(t, isMember(t, workspace.ID, "leaver"))
+	})
+
+	t.Run("reassign_to_owner hands resources to the primary owner", func(t *testing.T) {
+		svc, workspace, project, base := setup(t, config.MemberRemovalReassignToOwner)
+
+		require.NoError(t, svc.Member.RemoveMember(ctx, workspace.ID, "leaver", "owner"))
+		assert.False(t, isMember(t, workspace.ID, "leaver"))
+		assert.Equal(t, "owner", creatorOf(t, &models.Project{}, project.ID))
+		assert.Equal(t, "owner", creatorOf(t, &models.AirtableBase{}, base.ID))
+
+		var actions []string
+		require.NoError(t, db.Model(&models.WorkspaceAuditLog{}).
+			Where("workspace_id = ?", workspace.ID).
+			Pluck("action", &actions).Error)
+		assert.ElementsMatch(t, []string{models.AuditActionMemberResourcesReassigned, models.AuditActionMemberRemoved}, actions)
+	})
+}
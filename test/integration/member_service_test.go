@@ -0,0 +1,175 @@
+package integration
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/models"
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/repositories"
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/services"
+)
+
+// noopCache satisfies the cache repository without Redis; every lookup is a miss
+type noopCache struct{}
+
+func (noopCache) SetWorkspace(ctx context.Context, workspace *models.Workspace) error { return nil }
+func (noopCache) GetWorkspace(ctx context.Context, id string) (*models.Workspace, error) {
+	return nil, nil
+}
+func (noopCache) DeleteWorkspace(ctx context.Context, id string) error          { return nil }
+func (noopCache) WarmWorkspaceCache(ctx context.Context, workspaces []*models.Workspace) error {
+	return nil
+}
+func (noopCache) SetProject(ctx context.Context, project *models.Project) error { return nil }
+func (noopCache) GetProject(ctx context.Context, id string) (*models.Project, error) {
+	return nil, nil
+}
+func (noopCache) DeleteProject(ctx context.Context, id string) error                     { return nil }
+func (noopCache) InvalidateWorkspaceCache(ctx context.Context, workspaceID string) error { return nil }
+func (noopCache) SetUserWorkspaces(ctx context.Context, userID string, workspaceIDs []string) error {
+	return nil
+}
+func (noopCache) GetUserWorkspaces(ctx context.Context, userID string) ([]string, error) {
+	return nil, nil
+}
+func (noopCache) InvalidateUserCache(ctx context.Context, userID string) error { return nil }
+func (noopCache) RebuildUserWorkspaces(ctx context.Context, userID string) ([]string, error) {
+	return nil, nil
+}
+func (noopCache) SetBaseMetadata(ctx context.Context, metadata *models.AirtableBaseMetadata, retention time.Duration) error {
+	return nil
+}
+func (noopCache) GetBaseMetadata(ctx context.Context, baseID string) (*models.AirtableBaseMetadata, error) {
+	return nil, nil
+}
+func (noopCache) SetWorkspaceStats(ctx context.Context, tenantID string, stats *models.WorkspaceStats) error {
+	return nil
+}
+func (noopCache) GetWorkspaceStats(ctx context.Context, tenantID string) (*models.WorkspaceStats, error) {
+	return nil, nil
+}
+func (noopCache) SetWorkspaceTrends(ctx context.Context, tenantID string, trends *models.WorkspaceTrends) error {
+	return nil
+}
+func (noopCache) GetWorkspaceTrends(ctx context.Context, tenantID string, days int) (*models.WorkspaceTrends, error) {
+	return nil, nil
+}
+func (noopCache) InvalidateTenantStats(ctx context.Context, tenantID string) error { return nil }
+func (noopCache) GetMaintenanceMode(ctx context.Context) (string, error)           { return "", nil }
+func (noopCache) SetMaintenanceMode(ctx context.Context, mode string) error        { return nil }
+func (noopCache) ClaimRequest(ctx context.Context, key string, ttl time.Duration) (bool, error) {
+	return true, nil
+}
+func (noopCache) CompleteRequest(ctx context.Context, key string, result []byte, ttl time.Duration) error {
+	return nil
+}
+func (noopCache) RequestResult(ctx context.Context, key string) ([]byte, bool, error) {
+	return nil, false, nil
+}
+func (noopCache) ReleaseRequest(ctx context.Context, key string) error { return nil }
+func (noopCache) MarkUserActive(ctx context.Context, userID string, at time.Time) error {
+	return nil
+}
+func (noopCache) ActiveUsers(ctx context.Context, since time.Time, limit int) ([]string, error) {
+	return nil, nil
+}
+
+// newTestServices builds services over db with caching disabled
+func newTestServices(db *gorm.DB) *services.Services {
+	repos := repositories.New(db, nil, testConfig(), zap.NewNop())
+	repos.Cache = noopCache{}
+	return services.New(repos, testConfig(), zap.NewNop(), nil, nil, nil, nil)
+}
+
+// seedMembers adds each user to the workspace with the given role
+func seedMembers(t *testing.T, db *gorm.DB, workspaceID string, roles map[string]models.WorkspaceMemberRole) {
+	for userID, role := range roles {
+		require.NoError(t, db.Create(&models.WorkspaceMember{
+			WorkspaceID: workspaceID,
+			UserID:      userID,
+			Role:        role,
+		}).Error)
+	}
+}
+
+func TestRemoveMemberAndReassign(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration tests in short mode")
+	}
+
+	db := openTestDB(t)
+	workspace := seedWorkspace(t, db)
+	svc := newTestServices(db)
+	ctx := context.Background()
+
+	seedMembers(t, db, workspace.ID, map[string]models.WorkspaceMemberRole{
+		"owner":    models.WorkspaceRoleOwner,
+		"leaver":   models.WorkspaceRoleMember,
+		"receiver": models.WorkspaceRoleMember,
+	})
+
+	project := &models.Project{
+		WorkspaceID: workspace.ID,
+		Name:        "reassign-me",
+		Status:      "active",
+		Settings:    models.JSONMap{},
+		CreatedBy:   "leaver",
+	}
+	require.NoError(t, db.Create(project).Error)
+	base := &models.AirtableBase{
+		ProjectID: project.ID,
+		BaseID:    "appReassign",
+		Name:      "base",
+		CreatedBy: "leaver",
+	}
+	require.NoError(t, db.Create(base).Error)
+	t.Cleanup(func() {
+		db.Unscoped().Delete(base)
+		db.Unscoped().Delete(project)
+	})
+
+	t.Run("rejects a target outside the workspace", func(t *testing.T) {
+		err := svc.Member.RemoveMemberAndReassign(ctx, workspace.ID, "leaver", "stranger", "owner")
+		assert.ErrorIs(t, err, services.ErrInvalidReassignment)
+	})
+
+	t.Run("rejects reassigning to the departing member", func(t *testing.T) {
+		err := svc.Member.RemoveMemberAndReassign(ctx, workspace.ID, "leaver", "leaver", "owner")
+		assert.ErrorIs(t, err, services.ErrInvalidReassignment)
+	})
+
+	t.Run("requires admin access", func(t *testing.T) {
+		err := svc.Member.RemoveMemberAndReassign(ctx, workspace.ID, "leaver", "receiver", "receiver")
+		assert.ErrorIs(t, err, services.ErrUnauthorized)
+	})
+
+	t.Run("reassigns resources and removes the member", func(t *testing.T) {
+		require.NoError(t, svc.Member.RemoveMemberAndReassign(ctx, workspace.ID, "leaver", "receiver", "owner"))
+
+		var reloadedProject models.Project
+		require.NoError(t, db.First(&reloadedProject, "id = ?", project.ID).Error)
+		assert.Equal(t, "receiver", reloadedProject.CreatedBy)
+
+		var reloadedBase models.AirtableBase
+		require.NoError(t, db.First(&reloadedBase, "id = ?", base.ID).Error)
+		assert.Equal(t, "receiver", reloadedBase.CreatedBy)
+
+		var remaining int64
+		require.NoError(t, db.Model(&models.WorkspaceMember{}).
+			Where("workspace_id = ? AND user_id = ?", workspace.ID, "leaver").
+			Count(&remaining).Error)
+		assert.Zero(t, remaining)
+
+		var actions []string
+		require.NoError(t, db.Model(&models.WorkspaceAuditLog{}).
+			Where("workspace_id = ? AND resource_id = ?", workspace.ID, "leaver").
+			Pluck("action", &actions).Error)
+		assert.ElementsMatch(t, []string{"member.resources_reassigned", "member.removed"}, actions)
+	})
+}
@@ -0,0 +1,85 @@
+package integration
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/models"
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/services"
+)
+
+func TestNameAvailability(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration tests in short mode")
+	}
+
+	db := openTestDB(t)
+	workspace := seedWorkspace(t, db)
+	svc := newTestServices(db)
+	ctx := context.Background()
+
+	seedMembers(t, db, workspace.ID, map[string]models.WorkspaceMemberRole{
+		"viewer": models.WorkspaceRoleViewer,
+	})
+
+	project := &models.Project{
+		WorkspaceID: workspace.ID,
+		Name:        "Roadmap",
+		Status:      "active",
+		Settings:    models.JSONMap{},
+		CreatedBy:   "viewer",
+	}
+	require.NoError(t, db.Create(project).Error)
+	t.Cleanup(func() { db.Unscoped().Delete(project) })
+
+	t.Run("workspace names", func(t *testing.T) {
+		tests := []struct {
+			name      string
+			candidate string
+			available bool
+		}{
+			{name: "exact match is taken", candidate: workspace.Name, available: false},
+			{name: "case and whitespace variant is taken", candidate: "  " + workspace.Name + " ", available: false},
+			{name: "unused name is available", candidate: "unused-" + workspace.Name, available: true},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				available, err := svc.Workspace.IsNameAvailable(ctx, workspace.TenantID, tt.candidate)
+				require.NoError(t, err)
+				assert.Equal(t, tt.available, available)
+			})
+		}
+	})
+
+	t.Run("project names", func(t *testing.T) {
+		tests := []struct {
+			name      string
+			candidate string
+			available bool
+		}{
+			{name: "exact match is taken", candidate: "Roadmap", available: false},
+			{name: "case variant is taken", candidate: "ROADMAP", available: false},
+			{name: "unused name is available", candidate: "Backlog", available: true},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				available, err := svc.Project.IsNameAvailable(ctx, workspace.ID, "viewer", tt.candidate)
+				require.NoError(t, err)
+				assert.Equal(t, tt.available, available)
+			})
+		}
+	})
+
+	t.Run("project check requires membership", func(t *testing.T) {
+		_, err := svc.Project.IsNameAvailable(ctx, workspace.ID, "stranger", "Roadmap")
+		assert.ErrorIs(t, err, services.ErrUnauthorized)
+	})
+
+	t.Run("empty name is rejected", func(t *testing.T) {
+		_, err := svc.Workspace.IsNameAvailable(ctx, workspace.TenantID, " ")
+		assert.ErrorIs(t, err, services.ErrInvalidInput)
+	})
+}
@@ -0,0 +1,92 @@
+package integration
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/models"
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/services"
+)
+
+// primaryOwners returns the user IDs marked primary in a workspace
+func primaryOwners(t *testing.T, db *gorm.DB, workspaceID string) []string {
+	var userIDs []string
+	require.NoError(t, db.Model(&models.WorkspaceMember{}).
+		Where("workspace_id = ? AND is_primary", workspaceID).
+		Pluck("user_id", &userIDs).Error)
+	return userIDs
+}
+
+func TestPrimaryOwner(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration tests in short mode")
+	}
+
+	db := openTestDB(t)
+	workspace := seedWorkspace(t, db)
+	svc := newTestServices(db)
+	ctx := context.Background()
+
+	seedMembers(t, db, workspace.ID, map[string]models.WorkspaceMemberRole{
+		"first":  models.WorkspaceRoleOwner,
+		"second": models.WorkspaceRoleOwner,
+		"admin":  models.WorkspaceRoleAdmin,
+	})
+	require.NoError(t, db.Model(&models.WorkspaceMember{}).
+		Where("workspace_id = ? AND user_id = ?", workspace.ID, "first").
+		Update("is_primary", true).Error)
+
+	t.Run("a workspace cannot hold two primaries", func(t *testing.T) {
+		err := db.Model(&models.WorkspaceMember{}).
+			Where("workspace_id = ? AND user_id = ?", workspace.ID, "second").
+			Update("is_primary", true).Error
+		assert.Error(t, err)
+		assert.Equal(t, []string{"first"}, primaryOwners(t, db, workspace.ID))
+	})
+
+	t.Run("the primary cannot be demoted or removed", func(t *testing.T) {
+		_, err := svc.Member.UpdateMemberRole(ctx, workspace.ID, "first", "second", &models.UpdateWorkspaceMemberRequest{Role: models.WorkspaceRoleAdmin})
+		assert.ErrorIs(t, err, services.ErrPrimaryOwner)
+
+		assert.ErrorIs(t, svc.Member.RemoveMember(ctx, workspace.ID, "first", "second"), services.ErrPrimaryOwner)
+		assert.ErrorIs(t, svc.Member.RemoveMember(ctx, workspace.ID, "first", "first"), services.ErrPrimaryOwner)
+	})
+
+	t.Run("only owners can be or choose the primary", func(t *testing.T) {
+		_, err := svc.Member.SetPrimaryOwner(ctx, workspace.ID, "admin", "first")
+		assert.ErrorIs(t, err, services.ErrInvalidInput)
+
+		_, err = svc.Member.SetPrimaryOwner(ctx, workspace.ID, "second", "admin")
+		assert.ErrorIs(t, err, services.ErrUnauthorized)
+
+		_, err = svc.Member.SetPrimaryOwner(ctx, workspace.ID, "stranger", "first")
+		assert.ErrorIs(t, err, services.ErrMemberNotFound)
+	})
+
+	t.Run("reassignment moves the single primary", func(t *testing.T) {
+		member, err := svc.Member.SetPrimaryOwner(ctx, workspace.ID, "second", "first")
+		require.NoError(t, err)
+		assert.True(t, member.IsPrimary)
+		assert.Equal(t, []string{"second"}, primaryOwners(t, db, workspace.ID))
+
+		// The former primary is an ordinary owner again
+		_, err = svc.Member.UpdateMemberRole(ctx, workspace.ID, "first", "second", &models.UpdateWorkspaceMemberRequest{Role: models.WorkspaceRoleAdmin})
+		assert.NoError(t, err)
+	})
+
+	t.Run("the creator of a new workspace is primary", func(t *testing.T) {
+		created, err := svc.Workspace.CreateWorkspace(ctx, workspace.TenantID, "creator", &models.CreateWorkspaceRequest{Name: "primary-" + workspace.Name})
+		require.NoError(t, err)
+		t.Cleanup(func() {
+			db.Unscoped().Where("workspace_id = ?", created.ID).Delete(&models.WorkspaceAuditLog{})
+			db.Unscoped().Where("workspace_id = ?", created.ID).Delete(&models.WorkspaceMember{})
+			db.Unscoped().Delete(created)
+		})
+
+		assert.Equal(t, []string{"creator"}, primaryOwners(t, db, created.ID))
+	})
+}
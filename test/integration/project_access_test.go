@@ -0,0 +1,87 @@
+package integration
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/models"
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/services"
+)
+
+func TestGetProjectAccess(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration tests in short mode")
+	}
+
+	db := openTestDB(t)
+	workspace := seedWorkspace(t, db)
+	svc := newTestServices(db)
+	ctx := context.Background()
+
+	seedMembers(t, db, workspace.ID, map[string]models.WorkspaceMemberRole{
+		"owner":  models.WorkspaceRoleOwner,
+		"admin":  models.WorkspaceRoleAdmin,
+		"member": models.WorkspaceRoleMember,
+		"viewer": models.WorkspaceRoleViewer,
+	})
+	project := seedProject(t, db, workspace.ID, "access-"+workspace.Name, "active")
+
+	account := &models.ServiceAccount{
+		WorkspaceID: workspace.ID,
+		TenantID:    workspace.TenantID,
+		Name:        "sync",
+		Role:        models.WorkspaceRoleMember,
+		TokenHash:   "access-" + workspace.ID,
+		CreatedBy:   "owner",
+	}
+	require.NoError(t, db.Create(account).Error)
+	t.Cleanup(func() { db.Unscoped().Delete(account) })
+
+	t.Run("the list matches the workspace memberships", func(t *testing.T) {
+		access, err := svc.Project.GetProjectAccess(ctx, project.ID, "admin")
+		require.NoError(t, err)
+		assert.Equal(t, project.ID, access.ProjectID)
+		assert.Equal(t, workspace.ID, access.WorkspaceID)
+
+		roles := map[string]models.WorkspaceMemberRole{}
+		sources := map[string]string{}
+		for _, entry := range access.Users {
+			roles[entry.UserID] = entry.Role
+			sources[entry.UserID] = entry.Source
+		}
+		assert.Equal(t, map[string]models.WorkspaceMemberRole{
+			"owner":               models.WorkspaceRoleOwner,
+			"admin":               models.WorkspaceRoleAdmin,
+			"member":              models.WorkspaceRoleMember,
+			"viewer":              models.WorkspaceRoleViewer,
+			account.PrincipalID(): models.WorkspaceRoleMember,
+		}, roles)
+		assert.Equal(t, models.ProjectAccessSourceServiceAccount, sources[account.PrincipalID()])
+		assert.Equal(t, models.ProjectAccessSourceWorkspaceMember, sources["viewer"])
+		assert.Equal(t, 5, access.Total)
+	})
+
+	t.Run("removed members and revoked accounts lose access", func(t *testing.T) {
+		require.NoError(t, svc.Member.RemoveMember(ctx, workspace.ID, "viewer", "owner"))
+		require.NoError(t, db.Model(account).Update("revoked_at", db.NowFunc()).Error)
+
+		access, err := svc.Project.GetProjectAccess(ctx, project.ID, "owner")
+		require.NoError(t, err)
+		for _, entry := range access.Users {
+			assert.NotEqual(t, "viewer", entry.UserID)
+			assert.NotEqual(t, account.PrincipalID(), entry.UserID)
+		}
+		assert.Equal(t, 3, access.Total)
+	})
+
+	t.Run("only admins may look", func(t *testing.T) {
+		_, err := svc.Project.GetProjectAccess(ctx, project.ID, "member")
+		assert.ErrorIs(t, err, services.ErrUnauthorized)
+
+		_, err = svc.Project.GetProjectAccess(ctx, project.ID, "stranger")
+		assert.ErrorIs(t, err, services.ErrUnauthorized)
+	})
+}
@@ -0,0 +1,85 @@
+package integration
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/models"
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/services"
+)
+
+// projectTags reads a project's stored tags
+func projectTags(t *testing.T, db *gorm.DB, projectID string) models.StringList {
+	var project models.Project
+	require.NoError(t, db.First(&project, "id = ?", projectID).Error)
+	return project.Tags
+}
+
+func TestTagProjects(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration tests in short mode")
+	}
+
+	db := openTestDB(t)
+	workspace := seedWorkspace(t, db)
+	svc := newTestServices(db)
+	ctx := context.Background()
+
+	seedMembers(t, db, workspace.ID, map[string]models.WorkspaceMemberRole{
+		"member": models.WorkspaceRoleMember,
+		"viewer": models.WorkspaceRoleViewer,
+	})
+	first := seedProject(t, db, workspace.ID, "tags-first", "active")
+	second := seedProject(t, db, workspace.ID, "tags-second", "active")
+	untouched := seedProject(t, db, workspace.ID, "tags-untouched", "active")
+
+	t.Run("tags are added to every listed project", func(t *testing.T) {
+		response, err := svc.Project.TagProjects(ctx, workspace.ID, "member", &models.TagProjectsRequest{
+			ProjectIDs: []string{second.ID, first.ID},
+			Add:        []string{"Q3", "roadmap"},
+		})
+		require.NoError(t, err)
+		require.Len(t, response.Projects, 2)
+		assert.Equal(t, second.ID, response.Projects[0].ProjectID)
+		assert.Equal(t, []string{"q3", "roadmap"}, response.Projects[0].Added)
+
+		assert.Equal(t, models.StringList{"q3", "roadmap"}, projectTags(t, db, first.ID))
+		assert.Equal(t, models.StringList{"q3", "roadmap"}, projectTags(t, db, second.ID))
+		assert.Empty(t, projectTags(t, db, untouched.ID))
+	})
+
+	t.Run("tags are removed and already-present tags are not duplicated", func(t *testing.T) {
+		response, err := svc.Project.TagProjects(ctx, workspace.ID, "member", &models.TagProjectsRequest{
+			ProjectIDs: []string{first.ID, second.ID},
+			Add:        []string{"q3", "shipped"},
+			Remove:     []string{"roadmap", "never-present"},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"shipped"}, response.Projects[0].Added)
+		assert.Equal(t, []string{"roadmap"}, response.Projects[0].Removed)
+
+		assert.Equal(t, models.StringList{"q3", "shipped"}, projectTags(t, db, first.ID))
+		assert.Equal(t, models.StringList{"q3", "shipped"}, projectTags(t, db, second.ID))
+	})
+
+	t.Run("an unknown project leaves every project unchanged", func(t *testing.T) {
+		_, err := svc.Project.TagProjects(ctx, workspace.ID, "member", &models.TagProjectsRequest{
+			ProjectIDs: []string{first.ID, "7c9e6679-7425-40de-944b-e07fc1f90ae7"},
+			Add:        []string{"lost"},
+		})
+		assert.ErrorIs(t, err, services.ErrProjectNotFound)
+		assert.Equal(t, models.StringList{"q3", "shipped"}, projectTags(t, db, first.ID))
+	})
+
+	t.Run("viewers cannot tag", func(t *testing.T) {
+		_, err := svc.Project.TagProjects(ctx, workspace.ID, "viewer", &models.TagProjectsRequest{
+			ProjectIDs: []string{first.ID},
+			Add:        []string{"viewer"},
+		})
+		assert.ErrorIs(t, err, services.ErrUnauthorized)
+	})
+}
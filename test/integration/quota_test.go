@@ -0,0 +1,76 @@
+package integration
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/config"
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/models"
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/repositories"
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/services"
+)
+
+func TestQuotaHeadroom(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration tests in short mode")
+	}
+
+	db := openTestDB(t)
+	cfg := testConfig()
+	cfg.Quota = config.QuotaConfig{WorkspacesPerTenant: 3, ProjectsPerWorkspace: 4, MembersPerWorkspace: 2}
+	repos := repositories.New(db, nil, cfg, zap.NewNop())
+	repos.Cache = noopCache{}
+	svc := services.New(repos, cfg, zap.NewNop(), nil, nil, nil, nil)
+	ctx := context.Background()
+
+	workspace := seedWorkspace(t, db)
+	seedMembers(t, db, workspace.ID, map[string]models.WorkspaceMemberRole{
+		"owner":  models.WorkspaceRoleOwner,
+		"viewer": models.WorkspaceRoleViewer,
+	})
+	first := seedProject(t, db, workspace.ID, "quota-first", "active")
+	seedProject(t, db, workspace.ID, "quota-second", "active")
+	deleted := seedProject(t, db, workspace.ID, "quota-deleted", "active")
+	require.NoError(t, db.Create(&models.AirtableBase{ProjectID: first.ID, BaseID: "appQuotaOne", Name: "one", CreatedBy: "owner"}).Error)
+	require.NoError(t, db.Create(&models.AirtableBase{ProjectID: deleted.ID, BaseID: "appQuotaTwo", Name: "two", CreatedBy: "owner"}).Error)
+	require.NoError(t, db.Delete(deleted).Error)
+
+	t.Run("tenant headroom counts live workspaces", func(t *testing.T) {
+		quota, err := svc.Workspace.GetTenantQuota(ctx, workspace.TenantID, workspace.TenantID, "viewer")
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), quota.Workspaces.Used)
+		require.NotNil(t, quota.Workspaces.Remaining)
+		assert.Equal(t, int64(3), *quota.Workspaces.Limit)
+		assert.Equal(t, int64(2), *quota.Workspaces.Remaining)
+
+		_, err = svc.Workspace.GetTenantQuota(ctx, workspace.TenantID, "tenant-other", "viewer")
+		assert.Equal(t, services.ErrUnauthorized, err)
+	})
+
+	t.Run("workspace headroom counts live projects, members and bases", func(t *testing.T) {
+		quota, err := svc.Workspace.GetWorkspaceQuota(ctx, workspace.ID, "viewer")
+		require.NoError(t, err)
+
+		assert.Equal(t, int64(2), quota.Projects.Used)
+		assert.Equal(t, int64(2), *quota.Projects.Remaining)
+		assert.Equal(t, int64(2), quota.Members.Used)
+		assert.Equal(t, int64(0), *quota.Members.Remaining)
+
+		// Bases have no configured limit; the base in the deleted project is not counted
+		assert.Equal(t, int64(1), quota.AirtableBases.Used)
+		assert.Nil(t, quota.AirtableBases.Limit)
+		assert.Nil(t, quota.AirtableBases.Remaining)
+	})
+
+	t.Run("a full quota rejects further members", func(t *testing.T) {
+		_, err := svc.Member.AddMember(ctx, workspace.ID, "owner", &models.AddWorkspaceMemberRequest{
+			UserID: "newcomer",
+			Role:   models.WorkspaceRoleMember,
+		})
+		assert.Equal(t, services.ErrQuotaExceeded, err)
+	})
+}
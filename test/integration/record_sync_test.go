@@ -0,0 +1,50 @@
+package integration
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/models"
+)
+
+func TestRecordSync(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration tests in short mode")
+	}
+
+	db := openTestDB(t)
+	svc := newTestServices(db)
+	ctx := context.Background()
+
+	workspace := seedWorkspace(t, db)
+	project := seedProject(t, db, workspace.ID, "Sync", "active")
+	base := &models.AirtableBase{ProjectID: project.ID, BaseID: "appSync", Name: "Sync", Settings: models.JSONMap{}, CreatedBy: "owner"}
+	require.NoError(t, db.Create(base).Error)
+
+	reload := func() *models.AirtableBase {
+		var stored models.AirtableBase
+		require.NoError(t, db.First(&stored, "id = ?", base.ID).Error)
+		return &stored
+	}
+
+	require.NoError(t, svc.AirtableBase.RecordSync(ctx, base.ID, nil, 1500*time.Millisecond))
+	succeeded := reload()
+	assert.Equal(t, models.AirtableSyncStatusIdle, succeeded.SyncStatus)
+	assert.Empty(t, succeeded.LastSyncError)
+	assert.Equal(t, 1500, succeeded.LastSyncDurationMs)
+	require.NotNil(t, succeeded.LastSyncAt)
+	assert.Equal(t, succeeded.LastSyncAt, succeeded.LastSyncAttemptAt)
+
+	require.NoError(t, svc.AirtableBase.RecordSync(ctx, base.ID, errors.New("gateway timeout"), 320*time.Millisecond))
+	failed := reload()
+	assert.Equal(t, models.AirtableSyncStatusFailed, failed.SyncStatus)
+	assert.Equal(t, "gateway timeout", failed.LastSyncError)
+	assert.Equal(t, 320, failed.LastSyncDurationMs)
+	assert.Equal(t, succeeded.LastSyncAt, failed.LastSyncAt, "a failed sync keeps the last successful sync time")
+	assert.True(t, failed.LastSyncAttemptAt.After(*succeeded.LastSyncAttemptAt))
+}
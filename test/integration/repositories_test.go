@@ -0,0 +1,738 @@
+package integration
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/config"
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/models"
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/repositories"
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/services"
+)
+
+// testConfig returns the configuration repositories are built with in integration tests
+func testConfig() *config.Config {
+	return &config.Config{
+		Names: config.NamesConfig{CaseInsensitive: true},
+	}
+}
+
+// openTestDB connects to the database named by TEST_DATABASE_DSN, skipping when unset
+func openTestDB(t *testing.T) *gorm.DB {
+	dsn := os.Getenv("TEST_DATABASE_DSN")
+	if dsn == "" {
+		t.Skip("TEST_DATABASE_DSN not set")
+	}
+
+	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{})
+	require.NoError(t, err)
+
+	repos := repositories.New(db, nil, testConfig(), zap.NewNop())
+	require.NoError(t, repos.AutoMigrate())
+
+	return db
+}
+
+// seedWorkspace inserts a throwaway workspace and removes it when the test ends
+func seedWorkspace(t *testing.T, db *gorm.DB) *models.Workspace {
+	workspace := &models.Workspace{
+		TenantID:  "tenant-" + t.Name(),
+		Name:      "workspace-" + time.Now().Format(time.RFC3339Nano),
+		Settings:  models.JSONMap{},
+		CreatedBy: "seed-user",
+	}
+	require.NoError(t, db.Create(workspace).Error)
+
+	t.Cleanup(func() {
+		db.Unscoped().Where("workspace_id = ?", workspace.ID).Delete(&models.WorkspaceAuditLog{})
+		db.Unscoped().Where("workspace_id = ?", workspace.ID).Delete(&models.WorkspaceMember{})
+		db.Unscoped().Where("workspace_id = ?", workspace.ID).Delete(&models.PastWorkspaceMember{})
+		db.Unscoped().Delete(workspace)
+	})
+
+	return workspace
+}
+
+func TestAuditLogPaginationWithTimestampTies(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration tests in short mode")
+	}
+
+	db := openTestDB(t)
+	workspace := seedWorkspace(t, db)
+	repo := repositories.NewAuditLogRepository(db, testConfig(), zap.NewNop())
+	ctx := context.Background()
+
+	const total = 25
+	burst := time.Now().UTC().Truncate(time.Microsecond)
+	for i := 0; i < total; i++ {
+		require.NoError(t, repo.Create(ctx, &models.WorkspaceAuditLog{
+			WorkspaceID:  workspace.ID,
+			UserID:       "user-1",
+			Action:       "workspace.updated",
+			ResourceType: "workspace",
+			ResourceID:   workspace.ID,
+			CreatedAt:    burst,
+		}))
+	}
+
+	t.Run("offset pages", func(t *testing.T) {
+		seen := make(map[string]bool)
+		for page := 1; page <= 4; page++ {
+			logs, count, err := repo.List(ctx, &models.AuditLogFilter{
+				WorkspaceID: workspace.ID,
+				Page:        page,
+				PageSize:    7,
+			})
+			require.NoError(t, err)
+			assert.Equal(t, int64(total), count)
+
+			for _, log := range logs {
+				assert.False(t, seen[log.ID], "duplicate log %s on page %d", log.ID, page)
+				seen[log.ID] = true
+			}
+		}
+		assert.Len(t, seen, total)
+	})
+
+	t.Run("keyset cursor", func(t *testing.T) {
+		seen := make(map[string]bool)
+		cursor := ""
+		for {
+			logs, _, err := repo.List(ctx, &models.AuditLogFilter{
+				WorkspaceID: workspace.ID,
+				Cursor:      cursor,
+				PageSize:    7,
+			})
+			require.NoError(t, err)
+			if len(logs) == 0 {
+				break
+			}
+
+			for _, log := range logs {
+				assert.False(t, seen[log.ID], "duplicate log %s", log.ID)
+				seen[log.ID] = true
+			}
+			cursor = repositories.EncodeAuditLogCursor(logs[len(logs)-1])
+		}
+		assert.Len(t, seen, total)
+	})
+
+	t.Run("count_only totals a full read", func(t *testing.T) {
+		logs, count, err := repo.List(ctx, &models.AuditLogFilter{WorkspaceID: workspace.ID, CountOnly: true})
+		require.NoError(t, err)
+		assert.Empty(t, logs)
+
+		read := 0
+		cursor := ""
+		for {
+			page, _, err := repo.List(ctx, &models.AuditLogFilter{WorkspaceID: workspace.ID, Cursor: cursor, PageSize: 100})
+			require.NoError(t, err)
+			if len(page) == 0 {
+				break
+			}
+			read += len(page)
+			cursor = repositories.EncodeAuditLogCursor(page[len(page)-1])
+		}
+		assert.Equal(t, int64(read), count)
+	})
+}
+
+func TestAuditLogChangedFieldFilter(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration tests in short mode")
+	}
+
+	db := openTestDB(t)
+	workspace := seedWorkspace(t, db)
+	repo := repositories.NewAuditLogRepository(db, testConfig(), zap.NewNop())
+	ctx := context.Background()
+
+	seeded := []models.JSONMap{
+		{"user_id": "user-2", "old_role": "member", "new_role": "admin"},
+		{"name": "Renamed"},
+		{"name": "Renamed again", "description": "Updated"},
+		{"role": "viewer", "user_id": "user-3"},
+	}
+	for _, changes := range seeded {
+		require.NoError(t, repo.Create(ctx, &models.WorkspaceAuditLog{
+			WorkspaceID:  workspace.ID,
+			UserID:       "user-1",
+			Action:       models.AuditActionWorkspaceUpdated,
+			ResourceType: models.AuditResourceWorkspace,
+			ResourceID:   workspace.ID,
+			Changes:      changes,
+		}))
+	}
+
+	logs, count, err := repo.List(ctx, &models.AuditLogFilter{
+		WorkspaceID:  workspace.ID,
+		ChangedField: "name",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), count)
+	for _, log := range logs {
+		assert.Contains(t, log.Changes, "name")
+	}
+
+	// Only top-level keys match, not values
+	_, count, err = repo.List(ctx, &models.AuditLogFilter{
+		WorkspaceID:  workspace.ID,
+		ChangedField: "admin",
+	})
+	require.NoError(t, err)
+	assert.Zero(t, count)
+}
+
+func TestNameUniquenessIsCaseInsensitive(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration tests in short mode")
+	}
+
+	db := openTestDB(t)
+	workspace := seedWorkspace(t, db)
+	ctx := context.Background()
+
+	t.Run("workspace", func(t *testing.T) {
+		repo := repositories.NewWorkspaceRepository(db, testConfig(), zap.NewNop())
+
+		err := repo.Create(ctx, &models.Workspace{
+			TenantID:  workspace.TenantID,
+			Name:      "  " + strings.ToUpper(workspace.Name) + " ",
+			Settings:  models.JSONMap{},
+			CreatedBy: "seed-user",
+		})
+		assert.Equal(t, repositories.ErrDuplicateWorkspace, err)
+
+		found, err := repo.GetByTenantAndName(ctx, workspace.TenantID, strings.ToUpper(workspace.Name))
+		require.NoError(t, err)
+		assert.Equal(t, workspace.Name, found.Name, "original casing is preserved")
+	})
+
+	t.Run("project", func(t *testing.T) {
+		repo := repositories.NewProjectRepository(db, testConfig(), zap.NewNop())
+
+		project := &models.Project{
+			WorkspaceID: workspace.ID,
+			Name:        "Acme",
+			Status:      "active",
+			Settings:    models.JSONMap{},
+			CreatedBy:   "seed-user",
+		}
+		require.NoError(t, repo.Create(ctx, project))
+		t.Cleanup(func() { db.Unscoped().Delete(project) })
+
+		err := repo.Create(ctx, &models.Project{
+			WorkspaceID: workspace.ID,
+			Name:        "acme",
+			Status:      "active",
+			Settings:    models.JSONMap{},
+			CreatedBy:   "seed-user",
+		})
+		assert.Equal(t, repositories.ErrDuplicateProject, err)
+	})
+}
+
+func TestNameUniquenessIsAccentInsensitive(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration tests in short mode")
+	}
+
+	db := openTestDB(t)
+	workspace := seedWorkspace(t, db)
+	ctx := context.Background()
+
+	cafe := &models.Workspace{TenantID: workspace.TenantID, Name: "Café", Settings: models.JSONMap{}, CreatedBy: "seed-user"}
+	require.NoError(t, db.Create(cafe).Error)
+	t.Cleanup(func() { db.Unscoped().Delete(cafe) })
+
+	cfg := testConfig()
+	cfg.Names.AccentInsensitive = true
+	repo := repositories.NewWorkspaceRepository(db, cfg, zap.NewNop())
+
+	t.Run("accented duplicates are rejected", func(t *testing.T) {
+		err := repo.Create(ctx, &models.Workspace{TenantID: workspace.TenantID, Name: "CAFE", Settings: models.JSONMap{}, CreatedBy: "seed-user"})
+		assert.Equal(t, repositories.ErrDuplicateWorkspace, err)
+
+		renamed := *workspace
+		renamed.Name = "cafe\u0301"
+		assert.Equal(t, repositories.ErrDuplicateWorkspace, repo.Update(ctx, &renamed))
+
+		found, err := repo.GetByTenantAndName(ctx, workspace.TenantID, "cafe")
+		require.NoError(t, err)
+		assert.Equal(t, "Café", found.Name, "the stored name keeps its accent")
+	})
+
+	t.Run("without the flag accents tell names apart", func(t *testing.T) {
+		plain := &models.Workspace{TenantID: workspace.TenantID, Name: "cafe", Settings: models.JSONMap{}, CreatedBy: "seed-user"}
+		require.NoError(t, repositories.NewWorkspaceRepository(db, testConfig(), zap.NewNop()).Create(ctx, plain))
+		t.Cleanup(func() { db.Unscoped().Delete(plain) })
+	})
+}
+
+func TestListProjectsIncludesBaseCounts(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration tests in short mode")
+	}
+
+	db := openTestDB(t)
+	workspace := seedWorkspace(t, db)
+	ctx := context.Background()
+
+	expected := map[string]int64{}
+	for i, bases := range []int{0, 1, 3} {
+		project := &models.Project{
+			WorkspaceID: workspace.ID,
+			Name:        fmt.Sprintf("project-%d", i),
+			Status:      "active",
+			Settings:    models.JSONMap{},
+			CreatedBy:   "seed-user",
+		}
+		require.NoError(t, db.Create(project).Error)
+		for j := 0; j < bases; j++ {
+			require.NoError(t, db.Create(&models.AirtableBase{
+				ProjectID: project.ID,
+				BaseID:    fmt.Sprintf("app%d%d", i, j),
+				Name:      "base",
+			}).Error)
+		}
+		t.Cleanup(func() {
+			db.Unscoped().Where("project_id = ?", project.ID).Delete(&models.AirtableBase{})
+			db.Unscoped().Delete(project)
+		})
+		expected[project.ID] = int64(bases)
+	}
+
+	// Count queries against airtable_bases to prove counts come from a single grouped query
+	var baseQueries int
+	countBaseQueries := func(tx *gorm.DB) {
+		if tx.Statement.Table == "airtable_bases" {
+			baseQueries++
+		}
+	}
+	require.NoError(t, db.Callback().Query().After("gorm:query").Register("test:count_base_queries", countBaseQueries))
+	require.NoError(t, db.Callback().Row().After("gorm:row").Register("test:count_base_rows", countBaseQueries))
+	t.Cleanup(func() {
+		_ = db.Callback().Query().Remove("test:count_base_queries")
+		_ = db.Callback().Row().Remove("test:count_base_rows")
+	})
+
+	repo := repositories.NewProjectRepository(db, testConfig(), zap.NewNop())
+	projects, _, err := repo.List(ctx, &models.ProjectFilter{
+		WorkspaceID: workspace.ID,
+		Include:     "base_count",
+	})
+	require.NoError(t, err)
+	require.Len(t, projects, len(expected))
+
+	for _, project := range projects {
+		require.NotNil(t, project.BaseCount)
+		assert.Equal(t, expected[project.ID], *project.BaseCount)
+	}
+	assert.Equal(t, 1, baseQueries)
+}
+
+func TestListProjectsCarriesWorkspaceName(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration tests in short mode")
+	}
+
+	db := openTestDB(t)
+	workspace := seedWorkspace(t, db)
+	seedProject(t, db, workspace.ID, "named", "active")
+
+	repo := repositories.NewProjectRepository(db, testConfig(), zap.NewNop())
+	projects, _, err := repo.List(context.Background(), &models.ProjectFilter{WorkspaceID: workspace.ID})
+	require.NoError(t, err)
+	require.Len(t, projects, 1)
+
+	assert.Equal(t, workspace.Name, projects[0].WorkspaceName)
+	assert.Nil(t, projects[0].Workspace)
+
+	payload, err := json.Marshal(projects[0])
+	require.NoError(t, err)
+	var decoded map[string]interface{}
+	require.NoError(t, json.Unmarshal(payload, &decoded))
+	assert.Equal(t, workspace.ID, decoded["workspace_id"])
+	assert.Equal(t, workspace.Name, decoded["workspace_name"])
+	assert.NotContains(t, decoded, "workspace")
+}
+
+func TestCountByCreator(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration tests in short mode")
+	}
+
+	db := openTestDB(t)
+	workspace := seedWorkspace(t, db)
+	ctx := context.Background()
+
+	for i, creator := range []string{"alice", "alice", "bob"} {
+		project := &models.Project{
+			WorkspaceID: workspace.ID,
+			Name:        fmt.Sprintf("impact-%d", i),
+			Status:      "active",
+			Settings:    models.JSONMap{},
+			CreatedBy:   creator,
+		}
+		require.NoError(t, db.Create(project).Error)
+		require.NoError(t, db.Create(&models.AirtableBase{
+			ProjectID: project.ID,
+			BaseID:    fmt.Sprintf("appImpact%d", i),
+			Name:      "base",
+			CreatedBy: "bob",
+		}).Error)
+		t.Cleanup(func() {
+			db.Unscoped().Where("project_id = ?", project.ID).Delete(&models.AirtableBase{})
+			db.Unscoped().Delete(project)
+		})
+	}
+
+	projects := repositories.NewProjectRepository(db, testConfig(), zap.NewNop())
+	bases := repositories.NewAirtableBaseRepository(db, testConfig(), zap.NewNop())
+
+	count, err := projects.CountByCreator(ctx, workspace.ID, "alice")
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), count)
+
+	count, err = bases.CountByCreator(ctx, workspace.ID, "bob")
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), count)
+
+	count, err = bases.CountByCreator(ctx, workspace.ID, "alice")
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), count)
+}
+
+func TestGlobalStatsBoundsTenantBreakdown(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration tests in short mode")
+	}
+
+	db := openTestDB(t)
+	ctx := context.Background()
+
+	for i := 0; i < 5; i++ {
+		workspace := &models.Workspace{
+			TenantID:  fmt.Sprintf("stats-tenant-%d-%d", i, time.Now().UnixNano()),
+			Name:      fmt.Sprintf("stats-%d", i),
+			Settings:  models.JSONMap{},
+			CreatedBy: "seed-user",
+		}
+		require.NoError(t, db.Create(workspace).Error)
+		t.Cleanup(func() { db.Unscoped().Delete(workspace) })
+	}
+
+	workspaces := repositories.NewWorkspaceRepository(db, testConfig(), zap.NewNop())
+
+	stats, err := workspaces.GetStats(ctx, "", &models.WorkspaceStatsFilter{TenantPageSize: 2})
+	require.NoError(t, err)
+	assert.Len(t, stats.WorkspacesByTenant, 2)
+	assert.GreaterOrEqual(t, stats.TotalTenants, int64(5))
+	assert.Equal(t, 1, stats.TenantPage)
+
+	next, err := workspaces.GetStats(ctx, "", &models.WorkspaceStatsFilter{TenantPage: 2, TenantPageSize: 2})
+	require.NoError(t, err)
+	assert.Len(t, next.WorkspacesByTenant, 2)
+	for tenantID := range next.WorkspacesByTenant {
+		assert.NotContains(t, stats.WorkspacesByTenant, tenantID)
+	}
+
+	capped, err := workspaces.GetStats(ctx, "", &models.WorkspaceStatsFilter{TenantPageSize: 10000})
+	require.NoError(t, err)
+	assert.Equal(t, 100, capped.TenantPageSize)
+	assert.LessOrEqual(t, len(capped.WorkspacesByTenant), 100)
+
+	scoped, err := workspaces.GetStats(ctx, "stats-tenant-none", nil)
+	require.NoError(t, err)
+	assert.Empty(t, scoped.WorkspacesByTenant)
+}
+
+func TestSearchUsesTrigramIndex(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration tests in short mode")
+	}
+
+	db := openTestDB(t)
+
+	var extensions int64
+	require.NoError(t, db.Raw("SELECT COUNT(*) FROM pg_extension WHERE extname = 'pg_trgm'").Scan(&extensions).Error)
+	if extensions == 0 {
+		t.Skip("pg_trgm extension not available")
+	}
+
+	// Test tables are tiny, so steer the planner away from sequential scans
+	err := db.Transaction(func(tx *gorm.DB) error {
+		require.NoError(t, tx.Exec("SET LOCAL enable_seqscan = off").Error)
+
+		var plan []string
+		require.NoError(t, tx.Raw("EXPLAIN SELECT id FROM workspaces WHERE LOWER(name) LIKE ?", "%search%").Scan(&plan).Error)
+		assert.Contains(t, strings.Join(plan, "\n"), "idx_workspaces_name_trgm")
+		return nil
+	})
+	require.NoError(t, err)
+}
+
+func TestListOrderIsStableForEqualSortKeys(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration tests in short mode")
+	}
+
+	db := openTestDB(t)
+	workspace := seedWorkspace(t, db)
+	ctx := context.Background()
+
+	var expected []string
+	for i := 0; i < 4; i++ {
+		project := &models.Project{
+			WorkspaceID: workspace.ID,
+			Name:        fmt.Sprintf("tied-%d", i),
+			Status:      "archived",
+			Settings:    models.JSONMap{},
+			CreatedBy:   "seed-user",
+		}
+		require.NoError(t, db.Create(project).Error)
+		t.Cleanup(func() { db.Unscoped().Delete(project) })
+		expected = append(expected, project.ID)
+	}
+	sort.Strings(expected)
+
+	projects := repositories.NewProjectRepository(db, testConfig(), zap.NewNop())
+
+	var seen []string
+	for page := 1; page <= 2; page++ {
+		list, _, err := projects.List(ctx, &models.ProjectFilter{
+			WorkspaceID: workspace.ID,
+			SortBy:      "status",
+			Page:        page,
+			PageSize:    2,
+		})
+		require.NoError(t, err)
+		require.Len(t, list, 2)
+		for _, project := range list {
+			seen = append(seen, project.ID)
+		}
+	}
+
+	assert.Equal(t, expected, seen, "ties are broken by id so the two pages neither overlap nor skip rows")
+}
+
+func TestConfiguredDefaultSort(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration tests in short mode")
+	}
+
+	db := openTestDB(t)
+	workspace := seedWorkspace(t, db)
+	ctx := context.Background()
+
+	// Created in reverse name order so the built-in created_at DESC would list them by name
+	for _, name := range []string{"charlie", "bravo", "alpha"} {
+		project := &models.Project{
+			WorkspaceID: workspace.ID,
+			Name:        name,
+			Status:      "active",
+			Settings:    models.JSONMap{},
+			CreatedBy:   "seed-user",
+		}
+		require.NoError(t, db.Create(project).Error)
+		t.Cleanup(func() { db.Unscoped().Delete(project) })
+	}
+
+	cfg := testConfig()
+	cfg.Sort.Projects = "name desc"
+	projects := repositories.NewProjectRepository(db, cfg, zap.NewNop())
+
+	names := func(filter *models.ProjectFilter) []string {
+		filter.WorkspaceID = workspace.ID
+		list, _, err := projects.List(ctx, filter)
+		require.NoError(t, err)
+		result := make([]string, 0, len(list))
+		for _, project := range list {
+			result = append(result, project.Name)
+		}
+		return result
+	}
+
+	assert.Equal(t, []string{"charlie", "bravo", "alpha"}, names(&models.ProjectFilter{}))
+	assert.Equal(t, []string{"alpha", "bravo", "charlie"}, names(&models.ProjectFilter{SortOrder: "asc"}))
+	assert.Equal(t, []string{"charlie", "bravo", "alpha"}, names(&models.ProjectFilter{SortBy: "created_at", SortOrder: "asc"}))
+	// Columns outside the allowlist fall back to the configured default
+	assert.Equal(t, []string{"charlie", "bravo", "alpha"}, names(&models.ProjectFilter{SortBy: "settings"}))
+}
+
+func TestSetSyncEnabledForWorkspace(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration tests in short mode")
+	}
+
+	db := openTestDB(t)
+	workspace := seedWorkspace(t, db)
+	other := seedWorkspace(t, db)
+	ctx := context.Background()
+
+	var baseIDs []string
+	for i, workspaceID := range []string{workspace.ID, workspace.ID, other.ID} {
+		project := &models.Project{
+			WorkspaceID: workspaceID,
+			Name:        fmt.Sprintf("sync-%d", i),
+			Status:      "active",
+			Settings:    models.JSONMap{},
+			CreatedBy:   "seed-user",
+		}
+		require.NoError(t, db.Create(project).Error)
+		base := &models.AirtableBase{
+			ProjectID:   project.ID,
+			BaseID:      fmt.Sprintf("appSync%d", i),
+			Name:        "base",
+			SyncEnabled: true,
+			CreatedBy:   "seed-user",
+		}
+		require.NoError(t, db.Create(base).Error)
+		baseIDs = append(baseIDs, base.ID)
+		t.Cleanup(func() {
+			db.Unscoped().Delete(base)
+			db.Unscoped().Delete(project)
+		})
+	}
+
+	bases := repositories.NewAirtableBaseRepository(db, testConfig(), zap.NewNop())
+
+	updated, err := bases.SetSyncEnabledForWorkspace(ctx, workspace.ID, false)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), updated)
+
+	syncEnabled := func(id string) bool {
+		var base models.AirtableBase
+		require.NoError(t, db.First(&base, "id = ?", id).Error)
+		return base.SyncEnabled
+	}
+	assert.False(t, syncEnabled(baseIDs[0]))
+	assert.False(t, syncEnabled(baseIDs[1]))
+	assert.True(t, syncEnabled(baseIDs[2]), "bases in other workspaces are untouched")
+}
+
+func TestUpdateAirtableBasePersistsZeroValues(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration tests in short mode")
+	}
+
+	db := openTestDB(t)
+	workspace := seedWorkspace(t, db)
+	project := seedProject(t, db, workspace.ID, "toggled", "active")
+	ctx := context.Background()
+
+	base := &models.AirtableBase{
+		ProjectID:   project.ID,
+		BaseID:      "appToggle",
+		Name:        "base",
+		Description: "synced nightly",
+		SyncEnabled: true,
+		CreatedBy:   "seed-user",
+	}
+	require.NoError(t, db.Create(base).Error)
+
+	bases := repositories.NewAirtableBaseRepository(db, testConfig(), zap.NewNop())
+	base.SyncEnabled = false
+	base.Description = ""
+	require.NoError(t, bases.Update(ctx, base))
+
+	stored, err := bases.GetByID(ctx, base.ID)
+	require.NoError(t, err)
+	assert.False(t, stored.SyncEnabled)
+	assert.Empty(t, stored.Description)
+	assert.Equal(t, "base", stored.Name)
+
+	base.SyncEnabled = true
+	require.NoError(t, bases.Update(ctx, base))
+	stored, err = bases.GetByID(ctx, base.ID)
+	require.NoError(t, err)
+	assert.True(t, stored.SyncEnabled)
+}
+
+func TestUpdateClearsSettingsWithEmptyObject(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration tests in short mode")
+	}
+
+	db := openTestDB(t)
+	svc := newTestServices(db)
+	ctx := services.WithCacheBypass(context.Background())
+
+	workspace := seedWorkspace(t, db)
+	seedMembers(t, db, workspace.ID, map[string]models.WorkspaceMemberRole{"owner": models.WorkspaceRoleOwner})
+	require.NoError(t, db.Model(workspace).Update("settings", models.JSONMap{"theme": "dark"}).Error)
+
+	storedSettings := func(t *testing.T) models.JSONMap {
+		var stored models.Workspace
+		require.NoError(t, db.First(&stored, "id = ?", workspace.ID).Error)
+		return stored.Settings
+	}
+
+	var omitted models.UpdateWorkspaceRequest
+	require.NoError(t, json.Unmarshal([]byte(`{"description":"kept"}`), &omitted))
+	_, err := svc.Workspace.UpdateWorkspace(ctx, workspace.ID, "owner", &omitted)
+	require.NoError(t, err)
+	assert.Equal(t, models.JSONMap{"theme": "dark"}, storedSettings(t))
+
+	var cleared models.UpdateWorkspaceRequest
+	require.NoError(t, json.Unmarshal([]byte(`{"settings":{}}`), &cleared))
+	_, err = svc.Workspace.UpdateWorkspace(ctx, workspace.ID, "owner", &cleared)
+	require.NoError(t, err)
+	assert.Equal(t, models.JSONMap{}, storedSettings(t))
+}
+
+func TestWorkspaceTrends(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration tests in short mode")
+	}
+
+	db := openTestDB(t)
+	ctx := context.Background()
+
+	// Three workspaces with 3, 1 and 0 projects and 3, 1 and 2 members
+	large := seedWorkspace(t, db)
+	small := seedWorkspace(t, db)
+	older := seedWorkspace(t, db)
+	for i := 0; i < 3; i++ {
+		seedProject(t, db, large.ID, fmt.Sprintf("large-%d", i), "active")
+	}
+	seedProject(t, db, small.ID, "small", "active")
+	seedMembers(t, db, large.ID, map[string]models.WorkspaceMemberRole{
+		"a": models.WorkspaceRoleOwner, "b": models.WorkspaceRoleMember, "c": models.WorkspaceRoleViewer,
+	})
+	seedMembers(t, db, small.ID, map[string]models.WorkspaceMemberRole{"a": models.WorkspaceRoleOwner})
+	seedMembers(t, db, older.ID, map[string]models.WorkspaceMemberRole{
+		"a": models.WorkspaceRoleOwner, "b": models.WorkspaceRoleMember,
+	})
+
+	twoDaysAgo := time.Now().UTC().AddDate(0, 0, -2)
+	require.NoError(t, db.Model(older).UpdateColumn("created_at", twoDaysAgo).Error)
+
+	workspaces := repositories.NewWorkspaceRepository(db, testConfig(), zap.NewNop())
+	trends, err := workspaces.GetTrends(ctx, large.TenantID, 3)
+	require.NoError(t, err)
+
+	assert.InDelta(t, 4.0/3.0, trends.AvgProjectsPerWorkspace, 0.0001)
+	assert.Equal(t, 2.0, trends.MedianMembersPerWorkspace)
+
+	require.Len(t, trends.Series, 3)
+	assert.Equal(t, models.DailyCreations{Date: twoDaysAgo.Format("2006-01-02"), Workspaces: 1}, trends.Series[0])
+	assert.Equal(t, int64(0), trends.Series[1].Workspaces)
+	assert.Equal(t, int64(0), trends.Series[1].Projects)
+	assert.Equal(t, time.Now().UTC().Format("2006-01-02"), trends.Series[2].Date)
+	assert.Equal(t, int64(2), trends.Series[2].Workspaces)
+	assert.Equal(t, int64(4), trends.Series[2].Projects)
+}
@@ -0,0 +1,120 @@
+package integration
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/models"
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/services"
+)
+
+func TestGetResourceHistory(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration tests in short mode")
+	}
+
+	db := openTestDB(t)
+	svc := newTestServices(db)
+	ctx := context.Background()
+
+	workspace := seedWorkspace(t, db)
+	seedMembers(t, db, workspace.ID, map[string]models.WorkspaceMemberRole{
+		"member": models.WorkspaceRoleMember,
+		"viewer": models.WorkspaceRoleViewer,
+	})
+	project := seedProject(t, db, workspace.ID, "tracked", "active")
+	other := seedProject(t, db, workspace.ID, "other", "active")
+
+	// Entries are inserted out of order so the timeline has to sort them
+	start := time.Now().UTC().Add(-time.Hour)
+	entries := []struct {
+		resourceID string
+		action     string
+		offset     time.Duration
+	}{
+		{project.ID, models.AuditActionProjectUpdated, 2 * time.Minute},
+		{other.ID, models.AuditActionProjectUpdated, time.Minute},
+		{project.ID, models.AuditActionProjectCreated, 0},
+		{project.ID, models.AuditActionProjectOwnerChanged, 3 * time.Minute},
+	}
+	for _, entry := range entries {
+		require.NoError(t, db.Create(&models.WorkspaceAuditLog{
+			WorkspaceID:  workspace.ID,
+			UserID:       "member",
+			Action:       entry.action,
+			ResourceType: models.AuditResourceProject,
+			ResourceID:   entry.resourceID,
+			CreatedAt:    start.Add(entry.offset),
+		}).Error)
+	}
+
+	t.Run("returns only the resource's entries oldest first", func(t *testing.T) {
+		history, err := svc.Audit.GetResourceHistory(ctx, models.AuditResourceProject, project.ID, "member", &models.AuditLogFilter{})
+		require.NoError(t, err)
+
+		actions := make([]string, 0, len(history.Logs))
+		for _, log := range history.Logs {
+			assert.Equal(t, project.ID, log.ResourceID)
+			actions = append(actions, log.Action)
+		}
+		assert.Equal(t, []string{
+			models.AuditActionProjectCreated,
+			models.AuditActionProjectUpdated,
+			models.AuditActionProjectOwnerChanged,
+		}, actions)
+		assert.Equal(t, int64(3), history.Total)
+	})
+
+	t.Run("viewers are refused", func(t *testing.T) {
+		_, err := svc.Audit.GetResourceHistory(ctx, models.AuditResourceProject, project.ID, "viewer", &models.AuditLogFilter{})
+		assert.Equal(t, services.ErrUnauthorized, err)
+	})
+
+	t.Run("unknown resource is not found", func(t *testing.T) {
+		_, err := svc.Audit.GetResourceHistory(ctx, models.AuditResourceProject, "00000000-0000-0000-0000-000000000000", "member", &models.AuditLogFilter{})
+		assert.Equal(t, services.ErrProjectNotFound, err)
+	})
+}
+
+func TestGetAuditLogsAcrossWorkspaces(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration tests in short mode")
+	}
+
+	db := openTestDB(t)
+	svc := newTestServices(db)
+	ctx := context.Background()
+
+	roles := []models.WorkspaceMemberRole{models.WorkspaceRoleOwner, models.WorkspaceRoleAdmin, models.WorkspaceRoleMember}
+	workspaceIDs := make([]string, len(roles))
+	for i, role := range roles {
+		workspace := seedWorkspace(t, db)
+		seedMembers(t, db, workspace.ID, map[string]models.WorkspaceMemberRole{"caller": role})
+		require.NoError(t, db.Create(&models.WorkspaceAuditLog{
+			WorkspaceID:  workspace.ID,
+			UserID:       "caller",
+			Action:       models.AuditActionWorkspaceRenamed,
+			ResourceType: models.AuditResourceWorkspace,
+			ResourceID:   workspace.ID,
+		}).Error)
+		workspaceIDs[i] = workspace.ID
+	}
+
+	response, err := svc.Audit.GetAuditLogs(ctx, &models.AuditLogFilter{
+		WorkspaceID: strings.Join(workspaceIDs, ","),
+	}, "caller")
+	require.NoError(t, err)
+
+	// The workspace where the caller is only a member is dropped
+	seen := map[string]bool{}
+	for _, log := range response.Logs {
+		seen[log.WorkspaceID] = true
+	}
+	assert.Equal(t, map[string]bool{workspaceIDs[0]: true, workspaceIDs[1]: true}, seen)
+	assert.Equal(t, int64(2), response.Total)
+}
@@ -0,0 +1,125 @@
+package integration
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/models"
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/repositories"
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/services"
+)
+
+// eventLog records published events
+type eventLog struct {
+	events []*models.Event
+}
+
+func (l *eventLog) Publish(ctx context.Context, event *models.Event) error {
+	l.events = append(l.events, event)
+	return nil
+}
+
+func (l *eventLog) types() []string {
+	types := make([]string, 0, len(l.events))
+	for _, event := range l.events {
+		types = append(types, event.Type)
+	}
+	return types
+}
+
+func TestScheduledWorkspaceDeletion(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration tests in short mode")
+	}
+
+	db := openTestDB(t)
+	events := &eventLog{}
+	repos := repositories.New(db, nil, testConfig(), zap.NewNop())
+	repos.Cache = noopCache{}
+	svc := services.New(repos, testConfig(), zap.NewNop(), nil, events, nil, nil)
+	ctx := context.Background()
+
+	isLive := func(t *testing.T, model interface{}, id string) bool {
+		var count int64
+		require.NoError(t, db.Model(model).Where("id = ? AND deleted_at IS NULL", id).Count(&count).Error)
+		return count == 1
+	}
+
+	t.Run("only owners may schedule, within the allowed window", func(t *testing.T) {
+		workspace := seedWorkspace(t, db)
+		seedMembers(t, db, workspace.ID, map[string]models.WorkspaceMemberRole{
+			"owner": models.WorkspaceRoleOwner,
+			"admin": models.WorkspaceRoleAdmin,
+		})
+
+		_, err := svc.Workspace.ScheduleWorkspaceDeletion(ctx, workspace.ID, "admin", 30)
+		assert.Equal(t, services.ErrUnauthorized, err)
+		_, err = svc.Workspace.ScheduleWorkspaceDeletion(ctx, workspace.ID, "owner", 0)
+		assert.Equal(t, services.ErrInvalidInput, err)
+
+		before := time.Now().UTC()
+		scheduled, err := svc.Workspace.ScheduleWorkspaceDeletion(ctx, workspace.ID, "owner", 30)
+		require.NoError(t, err)
+		require.NotNil(t, scheduled.ScheduledDeletionAt)
+		assert.WithinDuration(t, before.AddDate(0, 0, 30), *scheduled.ScheduledDeletionAt, time.Minute)
+	})
+
+	t.Run("cancelling clears the schedule", func(t *testing.T) {
+		workspace := seedWorkspace(t, db)
+		seedMembers(t, db, workspace.ID, map[string]models.WorkspaceMemberRole{"owner": models.WorkspaceRoleOwner})
+		events.events = nil
+
+		_, err := svc.Workspace.ScheduleWorkspaceDeletion(ctx, workspace.ID, "owner", 7)
+		require.NoError(t, err)
+		cancelled, err := svc.Workspace.CancelScheduledDeletion(ctx, workspace.ID, "owner")
+		require.NoError(t, err)
+		assert.Nil(t, cancelled.ScheduledDeletionAt)
+
+		var stored models.Workspace
+		require.NoError(t, db.First(&stored, "id = ?", workspace.ID).Error)
+		assert.Nil(t, stored.ScheduledDeletionAt)
+
+		// Cancelling again changes nothing and publishes nothing
+		_, err = svc.Workspace.CancelScheduledDeletion(ctx, workspace.ID, "owner")
+		require.NoError(t, err)
+		assert.Equal(t, []string{models.EventWorkspaceDeletionScheduled, models.EventWorkspaceDeletionCancelled}, events.types())
+	})
+
+	t.Run("the job deletes only past-due workspaces", func(t *testing.T) {
+		due := seedWorkspace(t, db)
+		project := seedProject(t, db, due.ID, "doomed", "active")
+		future := seedWorkspace(t, db)
+		unscheduled := seedWorkspace(t, db)
+
+		now := time.Now().UTC()
+		require.NoError(t, repos.Workspace.SetScheduledDeletion(ctx, due.ID, timePtr(now.Add(-time.Minute))))
+		require.NoError(t, repos.Workspace.SetScheduledDeletion(ctx, future.ID, timePtr(now.Add(time.Hour))))
+		events.events = nil
+
+		deleted, err := svc.Workspace.RunScheduledDeletions(ctx, now)
+		require.NoError(t, err)
+		assert.Equal(t, 1, deleted)
+
+		assert.False(t, isLive(t, &models.Workspace{}, due.ID))
+		assert.False(t, isLive(t, &models.Project{}, project.ID), "projects go with the workspace")
+		assert.True(t, isLive(t, &models.Workspace{}, future.ID))
+		assert.True(t, isLive(t, &models.Workspace{}, unscheduled.ID))
+		require.Len(t, events.events, 1)
+		assert.Equal(t, models.EventWorkspaceDeletionExecuted, events.events[0].Type)
+		assert.Equal(t, due.ID, events.events[0].WorkspaceID)
+
+		// A second run finds nothing left to delete
+		deleted, err = svc.Workspace.RunScheduledDeletions(ctx, now)
+		require.NoError(t, err)
+		assert.Zero(t, deleted)
+	})
+}
+
+func timePtr(t time.Time) *time.Time {
+	return &t
+}
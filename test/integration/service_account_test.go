@@ -0,0 +1,51 @@
+package integration
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/models"
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/services"
+)
+
+func TestServiceAccountLifecycle(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration tests in short mode")
+	}
+
+	db := openTestDB(t)
+	svc := newTestServices(db)
+	ctx := context.Background()
+
+	workspace := seedWorkspace(t, db)
+	seedMembers(t, db, workspace.ID, map[string]models.WorkspaceMemberRole{"owner": models.WorkspaceRoleOwner})
+
+	created, err := svc.ServiceAccount.CreateServiceAccount(ctx, workspace.ID, "owner", &models.CreateServiceAccountRequest{
+		Name: "importer",
+		Role: models.WorkspaceRoleViewer,
+	})
+	require.NoError(t, err)
+
+	// Only the hash is stored
+	var stored models.ServiceAccount
+	require.NoError(t, db.First(&stored, "id = ?", created.ID).Error)
+	assert.NotEqual(t, created.Token, stored.TokenHash)
+	assert.Equal(t, workspace.TenantID, stored.TenantID)
+
+	account, err := svc.ServiceAccount.Authenticate(ctx, created.Token)
+	require.NoError(t, err)
+	assert.Equal(t, created.ID, account.ID)
+	assert.Equal(t, models.WorkspaceRoleViewer, account.Role)
+
+	require.NoError(t, svc.ServiceAccount.RevokeServiceAccount(ctx, workspace.ID, created.ID, "owner"))
+	_, err = svc.ServiceAccount.Authenticate(ctx, created.Token)
+	assert.Equal(t, services.ErrInvalidToken, err)
+
+	accounts, err := svc.ServiceAccount.ListServiceAccounts(ctx, workspace.ID, "owner")
+	require.NoError(t, err)
+	require.Len(t, accounts, 1)
+	assert.NotNil(t, accounts[0].RevokedAt)
+}
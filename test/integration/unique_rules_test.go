@@ -0,0 +1,111 @@
+package integration
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/models"
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/repositories"
+)
+
+// race runs create concurrently n times and returns how many succeeded and how many were
+// reported as duplicates
+func race(t *testing.T, n int, duplicate error, create func() error) (int, int) {
+	var (
+		mu      sync.Mutex
+		wg      sync.WaitGroup
+		created int
+		dups    int
+	)
+	start := make(chan struct{})
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			<-start
+			err := create()
+
+			mu.Lock()
+			defer mu.Unlock()
+			switch err {
+			case nil:
+				created++
+			case duplicate:
+				dups++
+			default:
+				t.Errorf("unexpected error: %v", err)
+			}
+		}()
+	}
+	close(start)
+	wg.Wait()
+	return created, dups
+}
+
+func TestConcurrentDuplicatesAreRejected(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration tests in short mode")
+	}
+
+	db := openTestDB(t)
+	repos := repositories.New(db, nil, testConfig(), zap.NewNop())
+	workspace := seedWorkspace(t, db)
+	ctx := context.Background()
+	const racers = 8
+
+	t.Run("workspace names", func(t *testing.T) {
+		t.Cleanup(func() {
+			db.Unscoped().Where("tenant_id = ? AND id != ?", workspace.TenantID, workspace.ID).Delete(&models.Workspace{})
+		})
+
+		created, dups := race(t, racers, repositories.ErrDuplicateWorkspace, func() error {
+			return repos.Workspace.Create(ctx, &models.Workspace{TenantID: workspace.TenantID, Name: "Raced", Settings: models.JSONMap{}, CreatedBy: "racer"})
+		})
+		assert.Equal(t, 1, created)
+		assert.Equal(t, racers-1, dups)
+	})
+
+	t.Run("project names, freed again by soft delete", func(t *testing.T) {
+		t.Cleanup(func() {
+			db.Unscoped().Where("workspace_id = ?", workspace.ID).Delete(&models.Project{})
+		})
+
+		created, dups := race(t, racers, repositories.ErrDuplicateProject, func() error {
+			return repos.Project.Create(ctx, &models.Project{WorkspaceID: workspace.ID, Name: "Raced", Status: "active", Settings: models.JSONMap{}, CreatedBy: "racer"})
+		})
+		assert.Equal(t, 1, created)
+		assert.Equal(t, racers-1, dups)
+
+		// Only live rows count: a deleted project's name can be reused
+		existing, err := repos.Project.GetByWorkspaceAndName(ctx, workspace.ID, "Raced")
+		require.NoError(t, err)
+		require.NoError(t, repos.Project.Delete(ctx, existing.ID))
+		assert.NoError(t, repos.Project.Create(ctx, &models.Project{WorkspaceID: workspace.ID, Name: "raced", Status: "active", Settings: models.JSONMap{}, CreatedBy: "racer"}))
+	})
+
+	t.Run("airtable bases", func(t *testing.T) {
+		project := seedProject(t, db, workspace.ID, "bases-raced", "active")
+		t.Cleanup(func() {
+			db.Unscoped().Where("project_id = ?", project.ID).Delete(&models.AirtableBase{})
+		})
+
+		created, dups := race(t, racers, repositories.ErrDuplicateAirtableBase, func() error {
+			return repos.AirtableBase.Create(ctx, &models.AirtableBase{ProjectID: project.ID, BaseID: "appRaced", Name: "raced", CreatedBy: "racer"})
+		})
+		assert.Equal(t, 1, created)
+		assert.Equal(t, racers-1, dups)
+	})
+
+	t.Run("members", func(t *testing.T) {
+		created, dups := race(t, racers, repositories.ErrDuplicateMember, func() error {
+			return repos.Member.Add(ctx, &models.WorkspaceMember{WorkspaceID: workspace.ID, UserID: "racer", Role: models.WorkspaceRoleMember})
+		})
+		assert.Equal(t, 1, created)
+		assert.Equal(t, racers-1, dups)
+	})
+}
@@ -0,0 +1,141 @@
+package integration
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/models"
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/services"
+)
+
+func TestListUserBasesAcrossWorkspaces(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration tests in short mode")
+	}
+
+	db := openTestDB(t)
+	svc := newTestServices(db)
+	ctx := context.Background()
+
+	// The user belongs to the first two workspaces only
+	workspaces := []*models.Workspace{seedWorkspace(t, db), seedWorkspace(t, db), seedWorkspace(t, db)}
+	seedMembers(t, db, workspaces[0].ID, map[string]models.WorkspaceMemberRole{"reader": models.WorkspaceRoleViewer})
+	seedMembers(t, db, workspaces[1].ID, map[string]models.WorkspaceMemberRole{"reader": models.WorkspaceRoleAdmin})
+	seedMembers(t, db, workspaces[2].ID, map[string]models.WorkspaceMemberRole{"other": models.WorkspaceRoleOwner})
+
+	for i, workspace := range workspaces {
+		project := seedProject(t, db, workspace.ID, fmt.Sprintf("bases-%d", i), "active")
+		for j, syncEnabled := range []bool{true, false} {
+			base := &models.AirtableBase{
+				ProjectID:   project.ID,
+				BaseID:      fmt.Sprintf("appUser%d%d", i, j),
+				Name:        fmt.Sprintf("Inventory %d-%d", i, j),
+				SyncEnabled: true,
+				CreatedBy:   "other",
+			}
+			require.NoError(t, db.Create(base).Error)
+			// sync_enabled defaults to true, so a false value must be written explicitly
+			require.NoError(t, db.Model(base).Update("sync_enabled", syncEnabled).Error)
+		}
+	}
+
+	t.Run("lists only bases in the user's workspaces", func(t *testing.T) {
+		response, err := svc.AirtableBase.ListUserBases(ctx, "reader", &models.AirtableBaseFilter{})
+		require.NoError(t, err)
+		assert.Equal(t, int64(4), response.Total)
+		for _, base := range response.Bases {
+			assert.NotEqual(t, workspaces[2].ID, base.Project.WorkspaceID)
+		}
+	})
+
+	t.Run("paginates accessible bases", func(t *testing.T) {
+		response, err := svc.AirtableBase.ListUserBases(ctx, "reader", &models.AirtableBaseFilter{PageSize: 3, Page: 2})
+		require.NoError(t, err)
+		assert.Equal(t, int64(4), response.Total)
+		assert.Equal(t, 2, response.TotalPages)
+		assert.Len(t, response.Bases, 1)
+	})
+
+	t.Run("applies sync_enabled and search filters", func(t *testing.T) {
+		enabled := true
+		response, err := svc.AirtableBase.ListUserBases(ctx, "reader", &models.AirtableBaseFilter{SyncEnabled: &enabled})
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), response.Total)
+
+		response, err = svc.AirtableBase.ListUserBases(ctx, "reader", &models.AirtableBaseFilter{Search: "inventory 1-"})
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), response.Total)
+	})
+
+	t.Run("non-members see nothing", func(t *testing.T) {
+		response, err := svc.AirtableBase.ListUserBases(ctx, "stranger", &models.AirtableBaseFilter{})
+		require.NoError(t, err)
+		assert.Zero(t, response.Total)
+	})
+}
+
+func TestLookupBasesByAirtableID(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration tests in short mode")
+	}
+
+	db := openTestDB(t)
+	svc := newTestServices(db)
+	ctx := context.Background()
+
+	// The reader belongs to the first workspace only; each lookup ID is unique to this run
+	suffix := fmt.Sprintf("%d", time.Now().UnixNano())
+	mine, theirs := seedWorkspace(t, db), seedWorkspace(t, db)
+	seedMembers(t, db, mine.ID, map[string]models.WorkspaceMemberRole{"reader": models.WorkspaceRoleViewer})
+	seedMembers(t, db, theirs.ID, map[string]models.WorkspaceMemberRole{"other": models.WorkspaceRoleOwner})
+
+	connect := func(workspace *models.Workspace, project, airtableBaseID string) *models.AirtableBase {
+		base := &models.AirtableBase{
+			ProjectID: seedProject(t, db, workspace.ID, project, "active").ID,
+			BaseID:    airtableBaseID,
+			Name:      project,
+			CreatedBy: "other",
+		}
+		require.NoError(t, db.Create(base).Error)
+		return base
+	}
+
+	shared := "appShared" + suffix
+	first := connect(mine, "first", shared)
+	second := connect(mine, "second", shared)
+	connect(theirs, "theirs", shared)
+	connect(mine, "single", "appSingle"+suffix)
+	connect(theirs, "hidden", "appHidden"+suffix)
+
+	t.Run("found", func(t *testing.T) {
+		bases, err := svc.AirtableBase.LookupBases(ctx, "appSingle"+suffix, "reader")
+		require.NoError(t, err)
+		require.Len(t, bases, 1)
+		assert.Equal(t, "single", bases[0].Name)
+		assert.Equal(t, mine.ID, bases[0].Project.WorkspaceID)
+	})
+
+	t.Run("multiple connections in accessible workspaces", func(t *testing.T) {
+		bases, err := svc.AirtableBase.LookupBases(ctx, shared, "reader")
+		require.NoError(t, err)
+		ids := make([]string, 0, len(bases))
+		for _, base := range bases {
+			ids = append(ids, base.ID)
+		}
+		assert.Equal(t, []string{first.ID, second.ID}, ids)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		_, err := svc.AirtableBase.LookupBases(ctx, "appMissing"+suffix, "reader")
+		assert.Equal(t, services.ErrAirtableBaseNotFound, err)
+
+		// Connections outside the caller's workspaces are indistinguishable from missing ones
+		_, err = svc.AirtableBase.LookupBases(ctx, "appHidden"+suffix, "reader")
+		assert.Equal(t, services.ErrAirtableBaseNotFound, err)
+	})
+}
@@ -0,0 +1,83 @@
+package integration
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/models"
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/services"
+)
+
+func TestListWorkspacesModifiedSince(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration tests in short mode")
+	}
+
+	db := openTestDB(t)
+	svc := newTestServices(db)
+	ctx := services.WithCacheBypass(context.Background())
+
+	lastSync := time.Now().UTC().Add(-time.Hour).Truncate(time.Second)
+	before := lastSync.Add(-time.Hour)
+
+	unchanged := seedWorkspace(t, db)
+	updated := seedWorkspace(t, db)
+	deleted := seedWorkspace(t, db)
+	for _, workspace := range []*models.Workspace{unchanged, updated, deleted} {
+		require.NoError(t, db.Model(workspace).UpdateColumn("updated_at", before).Error)
+	}
+	require.NoError(t, db.Model(updated).UpdateColumn("updated_at", lastSync.Add(time.Minute)).Error)
+	require.NoError(t, db.Delete(deleted).Error)
+	seedMembers(t, db, deleted.ID, map[string]models.WorkspaceMemberRole{
+		"seed-user": models.WorkspaceRoleAdmin,
+		"viewer":    models.WorkspaceRoleViewer,
+	})
+
+	listAs := func(t *testing.T, userID string, includeDeleted bool) map[string]*models.Workspace {
+		response, err := svc.Workspace.ListWorkspaces(ctx, &models.WorkspaceFilter{
+			TenantID:       unchanged.TenantID,
+			ModifiedSince:  lastSync.Format(time.RFC3339),
+			IncludeDeleted: includeDeleted,
+		}, userID)
+		require.NoError(t, err)
+		byID := make(map[string]*models.Workspace)
+		for _, workspace := range response.Workspaces {
+			byID[workspace.ID] = workspace
+		}
+		assert.Equal(t, int64(len(byID)), response.Total)
+		return byID
+	}
+	list := func(t *testing.T, includeDeleted bool) map[string]*models.Workspace {
+		return listAs(t, "seed-user", includeDeleted)
+	}
+
+	t.Run("only workspaces changed since the sync are listed", func(t *testing.T) {
+		changed := list(t, false)
+		assert.Len(t, changed, 1)
+		assert.Contains(t, changed, updated.ID)
+	})
+
+	t.Run("deletions since the sync are listed as tombstones", func(t *testing.T) {
+		changed := list(t, true)
+		assert.Len(t, changed, 2)
+		assert.False(t, changed[updated.ID].DeletedAt.Valid)
+		require.Contains(t, changed, deleted.ID)
+		assert.True(t, changed[deleted.ID].DeletedAt.Valid)
+		assert.NotContains(t, changed, unchanged.ID)
+	})
+
+	t.Run("deletions are only listed to the workspace's admins", func(t *testing.T) {
+		changed := listAs(t, "viewer", true)
+		assert.Len(t, changed, 1)
+		assert.NotContains(t, changed, deleted.ID)
+	})
+
+	t.Run("malformed timestamps are rejected", func(t *testing.T) {
+		_, err := svc.Workspace.ListWorkspaces(ctx, &models.WorkspaceFilter{TenantID: unchanged.TenantID, ModifiedSince: "yesterday"}, "seed-user")
+		assert.Equal(t, services.ErrInvalidInput, err)
+	})
+}
@@ -0,0 +1,59 @@
+package integration
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/models"
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/services"
+)
+
+func TestWorkspaceDetailDoesNotGrowWithMembers(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration tests in short mode")
+	}
+
+	db := openTestDB(t)
+	svc := newTestServices(db)
+	ctx := services.WithCacheBypass(context.Background())
+
+	detailSize := func(t *testing.T, memberCount int) int {
+		workspace := seedWorkspace(t, db)
+		roles := map[string]models.WorkspaceMemberRole{"owner": models.WorkspaceRoleOwner}
+		for i := 1; i < memberCount; i++ {
+			roles[fmt.Sprintf("member-%d", i)] = models.WorkspaceRoleMember
+		}
+		seedMembers(t, db, workspace.ID, roles)
+
+		detail, err := svc.Workspace.GetWorkspace(ctx, workspace.ID, "owner")
+		require.NoError(t, err)
+		assert.Empty(t, detail.Members)
+
+		payload, err := json.Marshal(detail)
+		require.NoError(t, err)
+		return len(payload)
+	}
+
+	small := detailSize(t, 1)
+	large := detailSize(t, 500)
+	// Only the generated IDs and timestamps may differ between the two payloads
+	assert.InDelta(t, small, large, 16)
+
+	t.Run("members remain available through the paginated listing", func(t *testing.T) {
+		workspace := seedWorkspace(t, db)
+		seedMembers(t, db, workspace.ID, map[string]models.WorkspaceMemberRole{
+			"owner":  models.WorkspaceRoleOwner,
+			"member": models.WorkspaceRoleMember,
+		})
+
+		page, err := svc.Member.ListMembers(ctx, workspace.ID, "owner", 1, 1, "")
+		require.NoError(t, err)
+		assert.Len(t, page.Members, 1)
+		assert.Equal(t, int64(2), page.Total)
+	})
+}
@@ -0,0 +1,87 @@
+package integration
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/models"
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/repositories"
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/services"
+)
+
+func TestCreateWorkspaceOwner(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration tests in short mode")
+	}
+
+	db := openTestDB(t)
+	ctx := context.Background()
+
+	cfg := testConfig()
+	cfg.Platform.Provisioners = "sso-provisioner"
+	repos := repositories.New(db, nil, cfg, zap.NewNop())
+	repos.Cache = noopCache{}
+	svc := services.New(repos, cfg, zap.NewNop(), nil, nil, nil, nil)
+	tenantID := "tenant-" + t.Name()
+
+	create := func(t *testing.T, callerID, name, ownerID string) (*models.Workspace, error) {
+		workspace, err := svc.Workspace.CreateWorkspace(ctx, tenantID, callerID, &models.CreateWorkspaceRequest{
+			Name:        name,
+			OwnerUserID: ownerID,
+		})
+		if workspace != nil {
+			t.Cleanup(func() {
+				db.Where("workspace_id = ?", workspace.ID).Delete(&models.WorkspaceMember{})
+				db.Unscoped().Delete(workspace)
+			})
+		}
+		return workspace, err
+	}
+
+	members := func(t *testing.T, workspaceID string) map[string]models.WorkspaceMemberRole {
+		var rows []models.WorkspaceMember
+		require.NoError(t, db.Where("workspace_id = ?", workspaceID).Find(&rows).Error)
+		roles := make(map[string]models.WorkspaceMemberRole)
+		for _, row := range rows {
+			roles[row.UserID] = row.Role
+		}
+		return roles
+	}
+
+	t.Run("caller owns the workspace by default", func(t *testing.T) {
+		workspace, err := create(t, "alice", "self-owned", "")
+		require.NoError(t, err)
+		assert.Equal(t, "alice", workspace.CreatedBy)
+		assert.Equal(t, map[string]models.WorkspaceMemberRole{"alice": models.WorkspaceRoleOwner}, members(t, workspace.ID))
+	})
+
+	t.Run("naming yourself as owner needs no privilege", func(t *testing.T) {
+		workspace, err := create(t, "alice", "explicit-self", "alice")
+		require.NoError(t, err)
+		assert.Equal(t, map[string]models.WorkspaceMemberRole{"alice": models.WorkspaceRoleOwner}, members(t, workspace.ID))
+	})
+
+	t.Run("provisioner creates a workspace owned by someone else", func(t *testing.T) {
+		workspace, err := create(t, "sso-provisioner", "delegated", "bob")
+		require.NoError(t, err)
+		assert.Equal(t, "sso-provisioner", workspace.CreatedBy)
+		assert.Equal(t, map[string]models.WorkspaceMemberRole{"bob": models.WorkspaceRoleOwner}, members(t, workspace.ID))
+
+		_, err = svc.Workspace.GetWorkspace(ctx, workspace.ID, "bob")
+		assert.NoError(t, err)
+	})
+
+	t.Run("other callers cannot delegate ownership", func(t *testing.T) {
+		workspace, err := create(t, "alice", "not-allowed", "bob")
+		assert.Equal(t, services.ErrUnauthorized, err)
+		assert.Nil(t, workspace)
+
+		var count int64
+		require.NoError(t, db.Model(&models.Workspace{}).Where("tenant_id = ? AND name = ?", tenantID, "not-allowed").Count(&count).Error)
+		assert.Zero(t, count)
+	})
+}
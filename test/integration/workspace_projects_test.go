@@ -0,0 +1,80 @@
+package integration
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/handlers"
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/models"
+)
+
+func TestGetWorkspaceIncludesProjects(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration tests in short mode")
+	}
+
+	db := openTestDB(t)
+	svc := newTestServices(db)
+
+	workspace := seedWorkspace(t, db)
+	seedMembers(t, db, workspace.ID, map[string]models.WorkspaceMemberRole{"viewer": models.WorkspaceRoleViewer})
+	for i := 1; i <= 5; i++ {
+		seedProject(t, db, workspace.ID, fmt.Sprintf("project-%d", i), "active")
+	}
+	deleted := seedProject(t, db, workspace.ID, "project-deleted", "active")
+	require.NoError(t, db.Delete(deleted).Error)
+
+	h := handlers.New(svc, testConfig(), zap.NewNop())
+	get := func(t *testing.T, userID, query string) (int, map[string]interface{}) {
+		app := fiber.New()
+		app.Use(func(c *fiber.Ctx) error {
+			c.Locals("user_id", userID)
+			return c.Next()
+		})
+		h.RegisterRoutes(app)
+
+		req, _ := http.NewRequest(http.MethodGet, "/api/v1/workspaces/"+workspace.ID+query, nil)
+		resp, err := app.Test(req, -1)
+		require.NoError(t, err)
+		var body map[string]interface{}
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+		return resp.StatusCode, body
+	}
+
+	t.Run("embeds a page of live projects", func(t *testing.T) {
+		status, body := get(t, "viewer", "?include=projects&page=2&page_size=2")
+		require.Equal(t, http.StatusOK, status)
+		assert.Equal(t, workspace.ID, body["id"])
+
+		projects := body["projects"].(map[string]interface{})
+		assert.Equal(t, float64(5), projects["total"])
+		assert.Equal(t, float64(2), projects["page"])
+		assert.Equal(t, float64(3), projects["total_pages"])
+		items := projects["projects"].([]interface{})
+		require.Len(t, items, 2)
+		for _, item := range items {
+			assert.NotEqual(t, deleted.ID, item.(map[string]interface{})["id"])
+		}
+
+		_, last := get(t, "viewer", "?include=projects&page=3&page_size=2")
+		assert.Len(t, last["projects"].(map[string]interface{})["projects"], 1)
+	})
+
+	t.Run("projects are left out unless included", func(t *testing.T) {
+		status, body := get(t, "viewer", "")
+		require.Equal(t, http.StatusOK, status)
+		assert.NotContains(t, body, "projects")
+	})
+
+	t.Run("non-members are refused", func(t *testing.T) {
+		status, _ := get(t, "stranger", "?include=projects")
+		assert.Equal(t, http.StatusForbidden, status)
+	})
+}
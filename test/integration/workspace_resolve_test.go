@@ -0,0 +1,43 @@
+package integration
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/models"
+)
+
+func TestResolveWorkspaceNames(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration tests in short mode")
+	}
+
+	db := openTestDB(t)
+	svc := newTestServices(db)
+	ctx := context.Background()
+
+	mine := seedWorkspace(t, db)
+	seedMembers(t, db, mine.ID, map[string]models.WorkspaceMemberRole{"reader": models.WorkspaceRoleViewer})
+	theirs := seedWorkspace(t, db)
+	seedMembers(t, db, theirs.ID, map[string]models.WorkspaceMemberRole{"other": models.WorkspaceRoleOwner})
+	deleted := seedWorkspace(t, db)
+	seedMembers(t, db, deleted.ID, map[string]models.WorkspaceMemberRole{"reader": models.WorkspaceRoleOwner})
+	require.NoError(t, db.Delete(deleted).Error)
+
+	ids := []string{mine.ID, theirs.ID, deleted.ID, "00000000-0000-0000-0000-000000000000"}
+
+	t.Run("only accessible workspaces resolve", func(t *testing.T) {
+		names, err := svc.Workspace.ResolveNames(ctx, ids, "reader")
+		require.NoError(t, err)
+		assert.Equal(t, map[string]string{mine.ID: mine.Name}, names)
+	})
+
+	t.Run("strangers resolve nothing", func(t *testing.T) {
+		names, err := svc.Workspace.ResolveNames(ctx, ids, "stranger")
+		require.NoError(t, err)
+		assert.Empty(t, names)
+	})
+}
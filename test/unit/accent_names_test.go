@@ -0,0 +1,87 @@
+package unit
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/config"
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/models"
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/repositories"
+)
+
+func TestAccentedDuplicateNamesAreRejected(t *testing.T) {
+	cfg := &config.Config{Names: config.NamesConfig{CaseInsensitive: true, AccentInsensitive: true}}
+
+	open := func(t *testing.T) (*gorm.DB, sqlmock.Sqlmock) {
+		conn, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		t.Cleanup(func() { conn.Close() })
+		db, err := gorm.Open(postgres.New(postgres.Config{Conn: conn}), &gorm.Config{})
+		require.NoError(t, err)
+		return db, mock
+	}
+
+	// The existing names are read back and folded; only those matching reach the count
+	expectDuplicate := func(mock sqlmock.Sqlmock, table, existing string) {
+		mock.ExpectQuery(`SELECT DISTINCT "name" FROM "` + table + `"`).
+			WillReturnRows(sqlmock.NewRows([]string{"name"}).AddRow(existing).AddRow("Other"))
+		mock.ExpectQuery(`SELECT count\(\*\) FROM "`+table+`" WHERE .*name IN \(\$\d+\)`).
+			WithArgs(sqlmock.AnyArg(), existing).
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+	}
+
+	t.Run("workspace create", func(t *testing.T) {
+		db, mock := open(t)
+		expectDuplicate(mock, "workspaces", "Café")
+
+		err := repositories.NewWorkspaceRepository(db, cfg, zap.NewNop()).
+			Create(context.Background(), &models.Workspace{TenantID: "tenant-1", Name: " CAFE ", CreatedBy: "user-1"})
+		assert.Equal(t, repositories.ErrDuplicateWorkspace, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("workspace rename", func(t *testing.T) {
+		db, mock := open(t)
+		mock.ExpectQuery(`SELECT DISTINCT "name" FROM "workspaces"`).
+			WillReturnRows(sqlmock.NewRows([]string{"name"}).AddRow("Crème Brûlée"))
+		mock.ExpectQuery(`SELECT count\(\*\) FROM "workspaces" WHERE .*name IN \(\$\d+\)`).
+			WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), "Crème Brûlée").
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+
+		err := repositories.NewWorkspaceRepository(db, cfg, zap.NewNop()).
+			Update(context.Background(), &models.Workspace{BaseModel: models.BaseModel{ID: "ws-1"}, TenantID: "tenant-1", Name: "creme brulee"})
+		assert.Equal(t, repositories.ErrDuplicateWorkspace, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("project create", func(t *testing.T) {
+		db, mock := open(t)
+		// Compatibility characters fold too: the "ﬁ" ligature matches "fi"
+		expectDuplicate(mock, "projects", "Financé")
+
+		err := repositories.NewProjectRepository(db, cfg, zap.NewNop()).
+			Create(context.Background(), &models.Project{WorkspaceID: "ws-1", Name: "ﬁnance", CreatedBy: "user-1"})
+		assert.Equal(t, repositories.ErrDuplicateProject, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("lookups match nothing when no name folds the same", func(t *testing.T) {
+		db, mock := open(t)
+		mock.ExpectQuery(`SELECT DISTINCT "name" FROM "projects"`).
+			WillReturnRows(sqlmock.NewRows([]string{"name"}).AddRow("Café"))
+		mock.ExpectQuery(`SELECT \* FROM "projects" WHERE .*1 = 0`).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+		_, err := repositories.NewProjectRepository(db, cfg, zap.NewNop()).
+			GetByWorkspaceAndName(context.Background(), "ws-1", "cafés")
+		assert.Equal(t, repositories.ErrProjectNotFound, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
@@ -0,0 +1,75 @@
+package unit
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/config"
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/models"
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/repositories"
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/services"
+)
+
+// activityLogs serves a fixed feed and records what it was asked for
+type activityLogs struct {
+	repositories.AuditLogRepository
+	entries  []*models.WorkspaceAuditLog
+	userID   string
+	actions  []string
+	pageSize int
+}
+
+func (r *activityLogs) ListActivity(ctx context.Context, userID string, actions []string, page, pageSize int) ([]*models.WorkspaceAuditLog, int64, error) {
+	r.userID, r.actions, r.pageSize = userID, actions, pageSize
+	return r.entries, int64(len(r.entries)) + 40, nil
+}
+
+// namedWorkspaces resolves names for the workspaces it holds
+type namedWorkspaces struct {
+	repositories.WorkspaceRepository
+	names    map[string]string
+	resolved []string
+}
+
+func (r *namedWorkspaces) ResolveNames(ctx context.Context, ids []string, memberID string) (map[string]string, error) {
+	r.resolved = ids
+	return r.names, nil
+}
+
+func TestUserActivityFeed(t *testing.T) {
+	logs := &activityLogs{entries: []*models.WorkspaceAuditLog{
+		{ID: "3", WorkspaceID: "ws-2", UserID: "alice", ImpersonatedBy: "support-1", Action: models.AuditActionMemberAdded, ResourceID: "bob", Changes: models.JSONMap{"role": "member"}},
+		{ID: "2", WorkspaceID: "ws-1", UserID: "carol", Action: models.AuditActionProjectUpdated},
+		{ID: "1", WorkspaceID: "ws-2", UserID: "alice", Action: models.AuditActionProjectCreated},
+	}}
+	workspaces := &namedWorkspaces{names: map[string]string{"ws-1": "Marketing", "ws-2": "Sales"}}
+	repos := &repositories.Repositories{AuditLog: logs, Workspace: workspaces}
+	svc := services.NewAuditService(repos, &config.Config{}, zap.NewNop(), nil)
+
+	feed, err := svc.ListUserActivity(context.Background(), "bob", 1, 500)
+	require.NoError(t, err)
+
+	// Entries keep the repository's order and are named after their workspace
+	require.Len(t, feed.Entries, 3)
+	assert.Equal(t, "Sales", feed.Entries[0].WorkspaceName)
+	assert.Equal(t, "Marketing", feed.Entries[1].WorkspaceName)
+	assert.ElementsMatch(t, []string{"ws-1", "ws-2"}, workspaces.resolved)
+	assert.Equal(t, &models.ActivityEntry{ID: "3", WorkspaceID: "ws-2", WorkspaceName: "Sales", UserID: "alice", Action: models.AuditActionMemberAdded, ResourceID: "bob"}, feed.Entries[0],
+		"changes, impersonation and operation IDs stay in the audit log")
+
+	// The feed is scoped to the caller and clamped to the page size limit
+	assert.Equal(t, "bob", logs.userID)
+	assert.Equal(t, 100, logs.pageSize)
+	assert.Equal(t, int64(43), feed.Total)
+	assert.Equal(t, 1, feed.TotalPages)
+
+	// Member and project changes are shown; routine syncs and exports are not
+	assert.Contains(t, logs.actions, models.AuditActionMemberAdded)
+	assert.Contains(t, logs.actions, models.AuditActionProjectUpdated)
+	assert.NotContains(t, logs.actions, models.AuditActionBaseSynced)
+	assert.NotContains(t, logs.actions, models.AuditActionDataExported)
+}
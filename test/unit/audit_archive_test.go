@@ -0,0 +1,120 @@
+package unit
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/config"
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/models"
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/repositories"
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/services"
+)
+
+// recordingArchiver keeps every uploaded object, or fails every upload with err
+type recordingArchiver struct {
+	objects map[string][]byte
+	err     error
+}
+
+func (a *recordingArchiver) Upload(ctx context.Context, bucket, key string, body []byte) error {
+	if a.err != nil {
+		return a.err
+	}
+	a.objects[bucket+"/"+key] = body
+	return nil
+}
+
+// expiringAuditLogs holds old logs until they are deleted by ID
+type expiringAuditLogs struct {
+	repositories.AuditLogRepository
+	logs    []*models.WorkspaceAuditLog
+	deleted []string
+}
+
+func (r *expiringAuditLogs) ListOlderThan(ctx context.Context, before time.Time, workspaceID string, exceptWorkspaceIDs []string, limit int) ([]*models.WorkspaceAuditLog, error) {
+	if len(r.logs) > limit {
+		return r.logs[:limit], nil
+	}
+	return r.logs, nil
+}
+
+func (r *expiringAuditLogs) DeleteByIDs(ctx context.Context, ids []string) (int64, error) {
+	r.deleted = append(r.deleted, ids...)
+	r.logs = r.logs[len(ids):]
+	return int64(len(ids)), nil
+}
+
+func TestCleanupOldLogsArchivesBeforeDeleting(t *testing.T) {
+	newLogs := func() *expiringAuditLogs {
+		logs := &expiringAuditLogs{}
+		for _, id := range []string{"log-1", "log-2"} {
+			log := &models.WorkspaceAuditLog{WorkspaceID: "ws-1", UserID: "user-1", Action: "workspace.updated", CreatedAt: time.Now().AddDate(0, 0, -90)}
+			log.ID = id
+			logs.logs = append(logs.logs, log)
+		}
+		return logs
+	}
+	cfg := &config.Config{Audit: config.AuditConfig{Archive: config.AuditArchiveConfig{Enabled: true, Bucket: "audit-bucket", Prefix: "audit-logs/"}}}
+	ctx := context.Background()
+
+	t.Run("logs are archived as NDJSON, then deleted", func(t *testing.T) {
+		logs := newLogs()
+		archiver := &recordingArchiver{objects: map[string][]byte{}}
+		repos := &repositories.Repositories{Workspace: &retentionWorkspaces{}, AuditLog: logs}
+		svc := services.NewAuditService(repos, cfg, zap.NewNop(), archiver)
+
+		require.NoError(t, svc.CleanupOldLogs(ctx, 30))
+
+		require.Len(t, archiver.objects, 1)
+		for key, body := range archiver.objects {
+			assert.True(t, strings.HasPrefix(key, "audit-bucket/audit-logs/all/"), key)
+			assert.True(t, strings.HasSuffix(key, "-log-1.ndjson"), key)
+
+			var ids []string
+			scanner := bufio.NewScanner(bytes.NewReader(body))
+			for scanner.Scan() {
+				var entry models.WorkspaceAuditLog
+				require.NoError(t, json.Unmarshal(scanner.Bytes(), &entry))
+				ids = append(ids, entry.ID)
+			}
+			assert.Equal(t, []string{"log-1", "log-2"}, ids)
+		}
+		assert.Equal(t, []string{"log-1", "log-2"}, logs.deleted)
+	})
+
+	t.Run("nothing is deleted when the upload fails", func(t *testing.T) {
+		logs := newLogs()
+		archiver := &recordingArchiver{err: errors.New("bucket unreachable")}
+		repos := &repositories.Repositories{Workspace: &retentionWorkspaces{}, AuditLog: logs}
+		svc := services.NewAuditService(repos, cfg, zap.NewNop(), archiver)
+
+		assert.Error(t, svc.CleanupOldLogs(ctx, 30))
+		assert.Empty(t, logs.deleted)
+		assert.Len(t, logs.logs, 2)
+	})
+
+	t.Run("archival without an archiver refuses to clean up", func(t *testing.T) {
+		logs := newLogs()
+		repos := &repositories.Repositories{Workspace: &retentionWorkspaces{}, AuditLog: logs}
+		svc := services.NewAuditService(repos, cfg, zap.NewNop(), nil)
+
+		assert.Equal(t, services.ErrArchiverMissing, svc.CleanupOldLogs(ctx, 30))
+		assert.Empty(t, logs.deleted)
+	})
+}
+
+func TestAuditArchiveConfigValidate(t *testing.T) {
+	assert.NoError(t, (&config.AuditArchiveConfig{}).Validate())
+	assert.NoError(t, (&config.AuditArchiveConfig{Enabled: true, Bucket: "audit-bucket"}).Validate())
+	assert.Error(t, (&config.AuditArchiveConfig{Enabled: true}).Validate())
+}
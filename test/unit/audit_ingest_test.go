@@ -0,0 +1,67 @@
+package unit
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/config"
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/models"
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/repositories"
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/services"
+)
+
+// batchLogs records the audit batches written to it
+type batchLogs struct {
+	repositories.AuditLogRepository
+	mu      sync.Mutex
+	batches [][]*models.WorkspaceAuditLog
+}
+
+func (r *batchLogs) CreateBatch(ctx context.Context, logs []*models.WorkspaceAuditLog) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.batches = append(r.batches, logs)
+	return nil
+}
+
+func (r *batchLogs) written() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.batches)
+}
+
+func TestIngestedAuditLogsAreWrittenInTheBackground(t *testing.T) {
+	logs := &batchLogs{}
+	repos := &repositories.Repositories{
+		Workspace: &storedWorkspace{workspace: &models.Workspace{BaseModel: models.BaseModel{ID: "ws-1"}, TenantID: "tenant-1"}},
+		AuditLog:  logs,
+	}
+	cfg := &config.Config{Audit: config.AuditConfig{IngestQueueSize: 2}}
+	svc := services.New(repos, cfg, zap.NewNop(), nil, nil, nil, nil)
+
+	principal := &config.ServicePrincipal{Name: "report-service", Tenants: []string{"tenant-1"}}
+	entries := []models.IngestAuditLogEntry{{UserID: "user-1", Action: "data.exported", ResourceType: "report"}}
+	ingest := func() error {
+		_, err := svc.Audit.IngestLogs(context.Background(), "ws-1", principal, entries)
+		return err
+	}
+
+	// Batches are queued, not written, on the request path, and the queue is bounded
+	require.NoError(t, ingest())
+	require.NoError(t, ingest())
+	assert.Equal(t, services.ErrIngestQueueFull, ingest())
+	assert.Zero(t, logs.written())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	svc.StartBackgroundJobs(ctx, nil)
+
+	require.Eventually(t, func() bool { return logs.written() == 2 }, time.Second, time.Millisecond)
+	assert.Equal(t, "report-service", logs.batches[0][0].Changes["source_service"])
+}
@@ -0,0 +1,39 @@
+package unit
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/config"
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/models"
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/repositories"
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/services"
+)
+
+func TestLogActionRecordsOperationID(t *testing.T) {
+	logs := &recordingAuditLogs{}
+	svc := services.NewAuditService(&repositories.Repositories{AuditLog: logs}, &config.Config{}, zap.NewNop(), nil)
+
+	op := services.NewOperationContext("user-1", "tenant-1", "req-1")
+	ctx := services.WithOperation(context.Background(), op)
+	require.NoError(t, svc.LogAction(ctx, "ws-1", "user-1", models.AuditActionProjectDeleted, models.AuditResourceProject, "project-1", nil))
+	require.NoError(t, svc.LogAction(ctx, "ws-1", "user-1", models.AuditActionProjectDeleted, models.AuditResourceProject, "project-2", nil))
+
+	// A client reusing its request ID still gets a fresh operation
+	again := services.WithOperation(context.Background(), services.NewOperationContext("user-1", "tenant-1", "req-1"))
+	require.NoError(t, svc.LogAction(again, "ws-1", "user-1", models.AuditActionWorkspaceRenamed, models.AuditResourceWorkspace, "ws-1", nil))
+
+	require.NoError(t, svc.LogAction(context.Background(), "ws-1", "system", models.AuditActionWorkspaceRenamed, models.AuditResourceWorkspace, "ws-1", nil))
+
+	require.Len(t, logs.logs, 4)
+	assert.NotEmpty(t, op.OperationID())
+	assert.Equal(t, op.OperationID(), logs.logs[0].OperationID)
+	assert.Equal(t, op.OperationID(), logs.logs[1].OperationID)
+	assert.NotEmpty(t, logs.logs[2].OperationID)
+	assert.NotEqual(t, op.OperationID(), logs.logs[2].OperationID)
+	assert.Empty(t, logs.logs[3].OperationID)
+}
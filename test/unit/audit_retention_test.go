@@ -0,0 +1,69 @@
+package unit
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/config"
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/models"
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/repositories"
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/services"
+)
+
+// retentionWorkspaces returns fixed workspaces for any settings key
+type retentionWorkspaces struct {
+	repositories.WorkspaceRepository
+	workspaces []*models.Workspace
+}
+
+func (r *retentionWorkspaces) ListWithSetting(ctx context.Context, key string) ([]*models.Workspace, error) {
+	return r.workspaces, nil
+}
+
+// pruningAuditLogs records the retention each cleanup call applied
+type pruningAuditLogs struct {
+	repositories.AuditLogRepository
+	workspaceDays map[string]int
+	defaultDays   int
+	excluded      []string
+}
+
+func (r *pruningAuditLogs) DeleteOlderThan(ctx context.Context, days int, exceptWorkspaceIDs []string) error {
+	r.defaultDays = days
+	r.excluded = exceptWorkspaceIDs
+	return nil
+}
+
+func (r *pruningAuditLogs) DeleteWorkspaceOlderThan(ctx context.Context, workspaceID string, days int) error {
+	r.workspaceDays[workspaceID] = days
+	return nil
+}
+
+func TestCleanupOldLogsRetentionOverrides(t *testing.T) {
+	workspace := func(id string, days interface{}) *models.Workspace {
+		w := &models.Workspace{Settings: models.JSONMap{models.WorkspaceSettingAuditRetentionDays: days}}
+		w.ID = id
+		return w
+	}
+	logs := &pruningAuditLogs{workspaceDays: map[string]int{}}
+	repos := &repositories.Repositories{
+		Workspace: &retentionWorkspaces{workspaces: []*models.Workspace{
+			workspace("regulated", float64(365)),
+			workspace("eager", float64(7)),
+			workspace("garbled", "forever"),
+		}},
+		AuditLog: logs,
+	}
+	svc := services.NewAuditService(repos, &config.Config{}, zap.NewNop(), nil)
+
+	require.NoError(t, svc.CleanupOldLogs(context.Background(), 10))
+
+	assert.Equal(t, map[string]int{"regulated": 365, "eager": 30}, logs.workspaceDays)
+	// Invalid overrides fall back to the global retention, itself raised to the minimum
+	assert.Equal(t, 30, logs.defaultDays)
+	assert.Equal(t, []string{"regulated", "eager"}, logs.excluded)
+}
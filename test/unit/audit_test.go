@@ -0,0 +1,231 @@
+package unit
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/config"
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/models"
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/repositories"
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/services"
+)
+
+// recordingAuditLogs captures created audit entries
+type recordingAuditLogs struct {
+	repositories.AuditLogRepository
+	logs []*models.WorkspaceAuditLog
+}
+
+func (r *recordingAuditLogs) Create(ctx context.Context, log *models.WorkspaceAuditLog) error {
+	r.logs = append(r.logs, log)
+	return nil
+}
+
+func TestLogActionVocabulary(t *testing.T) {
+	cfg := &config.Config{Audit: config.AuditConfig{ExtraActions: "billing.invoiced"}}
+
+	tests := []struct {
+		name                 string
+		action               string
+		resourceType         string
+		expectedAction       string
+		expectedResourceType string
+		expectedOriginal     interface{}
+	}{
+		{
+			name:                 "known action is stored as is",
+			action:               models.AuditActionWorkspaceCreated,
+			resourceType:         models.AuditResourceWorkspace,
+			expectedAction:       models.AuditActionWorkspaceCreated,
+			expectedResourceType: models.AuditResourceWorkspace,
+		},
+		{
+			name:                 "configured extra action is known",
+			action:               "billing.invoiced",
+			resourceType:         models.AuditResourceWorkspace,
+			expectedAction:       "billing.invoiced",
+			expectedResourceType: models.AuditResourceWorkspace,
+		},
+		{
+			name:                 "typo is flagged and stored as unknown",
+			action:               "wokspace.created",
+			resourceType:         models.AuditResourceWorkspace,
+			expectedAction:       models.AuditUnknown,
+			expectedResourceType: models.AuditResourceWorkspace,
+			expectedOriginal:     "wokspace.created",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			auditLogs := &recordingAuditLogs{}
+			repos := &repositories.Repositories{AuditLog: auditLogs}
+			svc := services.NewAuditService(repos, cfg, zap.NewNop(), nil)
+
+			require.NoError(t, svc.LogAction(context.Background(), "ws-1", "user-1", tt.action, tt.resourceType, "ws-1", map[string]interface{}{"name": "Acme"}))
+			require.Len(t, auditLogs.logs, 1)
+
+			log := auditLogs.logs[0]
+			assert.Equal(t, tt.expectedAction, log.Action)
+			assert.Equal(t, tt.expectedResourceType, log.ResourceType)
+			assert.Equal(t, tt.expectedOriginal, log.Changes["original_action"])
+			assert.Equal(t, "Acme", log.Changes["name"])
+		})
+	}
+}
+
+func TestAuditVocabularyLists(t *testing.T) {
+	vocabulary := services.NewAuditVocabulary(config.AuditConfig{ExtraResourceTypes: "invoice"})
+
+	assert.Contains(t, vocabulary.Actions(), models.AuditActionMemberAdded)
+	assert.NotContains(t, vocabulary.Actions(), "wokspace.created")
+	assert.Contains(t, vocabulary.ResourceTypes(), "invoice")
+	assert.IsIncreasing(t, vocabulary.Actions())
+}
+
+func TestLogActionCapsChangesSize(t *testing.T) {
+	cfg := &config.Config{Audit: config.AuditConfig{MaxChangesBytes: 1024}}
+
+	tests := []struct {
+		name            string
+		changes         map[string]interface{}
+		expectTruncated bool
+	}{
+		{
+			name:    "small diff is stored as is",
+			changes: map[string]interface{}{"name": map[string]interface{}{"old": "A", "new": "B"}},
+		},
+		{
+			name: "oversized diff is replaced by a summary",
+			changes: map[string]interface{}{
+				"name":     map[string]interface{}{"old": "A", "new": "B"},
+				"settings": map[string]interface{}{"old": map[string]interface{}{}, "new": map[string]interface{}{"blob": strings.Repeat("x", 4096)}},
+			},
+			expectTruncated: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			auditLogs := &recordingAuditLogs{}
+			repos := &repositories.Repositories{AuditLog: auditLogs}
+			svc := services.NewAuditService(repos, cfg, zap.NewNop(), nil)
+
+			require.NoError(t, svc.LogAction(context.Background(), "ws-1", "user-1", models.AuditActionWorkspaceSettingsChanged, models.AuditResourceWorkspace, "ws-1", tt.changes))
+			require.Len(t, auditLogs.logs, 1)
+
+			changes := auditLogs.logs[0].Changes
+			if !tt.expectTruncated {
+				assert.Equal(t, models.JSONMap(tt.changes), changes)
+				return
+			}
+
+			assert.Equal(t, true, changes["_truncated"])
+			assert.Equal(t, []string{"name", "settings"}, changes["fields"])
+			assert.Greater(t, changes["original_size"], 4096)
+			assert.NotContains(t, changes, "settings")
+
+			data, err := json.Marshal(changes)
+			require.NoError(t, err)
+			assert.LessOrEqual(t, len(data), 1024)
+		})
+	}
+}
+
+// workspaceRoles holds the caller's role by workspace id
+type workspaceRoles struct {
+	repositories.WorkspaceMemberRepository
+	roles map[string]models.WorkspaceMemberRole
+}
+
+func (r *workspaceRoles) GetByWorkspaceAndUser(ctx context.Context, workspaceID, userID string) (*models.WorkspaceMember, error) {
+	role, ok := r.roles[workspaceID]
+	if !ok {
+		return nil, repositories.ErrMemberNotFound
+	}
+	return &models.WorkspaceMember{WorkspaceID: workspaceID, UserID: userID, Role: role}, nil
+}
+
+// filteredAuditLogs records the filters audit logs are listed with
+type filteredAuditLogs struct {
+	repositories.AuditLogRepository
+	filters []models.AuditLogFilter
+}
+
+func (r *filteredAuditLogs) List(ctx context.Context, filter *models.AuditLogFilter) ([]*models.WorkspaceAuditLog, int64, error) {
+	r.filters = append(r.filters, *filter)
+	return []*models.WorkspaceAuditLog{}, 0, nil
+}
+
+func TestGetAuditLogsAcrossWorkspaces(t *testing.T) {
+	members := &workspaceRoles{roles: map[string]models.WorkspaceMemberRole{
+		"ws-owner":  models.WorkspaceRoleOwner,
+		"ws-admin":  models.WorkspaceRoleAdmin,
+		"ws-member": models.WorkspaceRoleMember,
+	}}
+	auditLogs := &filteredAuditLogs{}
+	repos := &repositories.Repositories{Member: members, AuditLog: auditLogs}
+	svc := services.NewAuditService(repos, &config.Config{}, zap.NewNop(), nil)
+	ctx := context.Background()
+
+	t.Run("drops workspaces the caller cannot administer", func(t *testing.T) {
+		_, err := svc.GetAuditLogs(ctx, &models.AuditLogFilter{WorkspaceID: "ws-owner, ws-member,ws-stranger,ws-admin,ws-owner"}, "user-1")
+		require.NoError(t, err)
+		require.Len(t, auditLogs.filters, 1)
+		assert.Empty(t, auditLogs.filters[0].WorkspaceID)
+		assert.Equal(t, []string{"ws-owner", "ws-admin"}, auditLogs.filters[0].WorkspaceIDs)
+	})
+
+	t.Run("no administered workspace returns nothing", func(t *testing.T) {
+		auditLogs.filters = nil
+		response, err := svc.GetAuditLogs(ctx, &models.AuditLogFilter{WorkspaceID: "ws-member,ws-stranger"}, "user-1")
+		require.NoError(t, err)
+		assert.Empty(t, response.Logs)
+		assert.Empty(t, auditLogs.filters)
+	})
+
+	t.Run("a single workspace still requires admin", func(t *testing.T) {
+		_, err := svc.GetAuditLogs(ctx, &models.AuditLogFilter{WorkspaceID: "ws-member"}, "user-1")
+		assert.Equal(t, services.ErrUnauthorized, err)
+	})
+
+	t.Run("stray commas leave a single workspace or none", func(t *testing.T) {
+		auditLogs.filters = nil
+		_, err := svc.GetAuditLogs(ctx, &models.AuditLogFilter{WorkspaceID: "ws-admin, "}, "user-1")
+		require.NoError(t, err)
+		require.Len(t, auditLogs.filters, 1)
+		assert.Equal(t, "ws-admin", auditLogs.filters[0].WorkspaceID)
+
+		_, err = svc.GetAuditLogs(ctx, &models.AuditLogFilter{WorkspaceID: ","}, "user-1")
+		assert.Equal(t, services.ErrInvalidInput, err)
+		assert.Len(t, auditLogs.filters, 1)
+	})
+
+	t.Run("too many workspaces are rejected", func(t *testing.T) {
+		ids := make([]string, 51)
+		for i := range ids {
+			ids[i] = "ws-" + strings.Repeat("x", i+1)
+		}
+		_, err := svc.GetAuditLogs(ctx, &models.AuditLogFilter{WorkspaceID: strings.Join(ids, ",")}, "user-1")
+		assert.Equal(t, services.ErrInvalidInput, err)
+	})
+
+	t.Run("omitting the workspace is reserved for platform admins", func(t *testing.T) {
+		auditLogs.filters = nil
+		_, err := svc.GetAuditLogs(ctx, &models.AuditLogFilter{}, "user-1")
+		assert.Equal(t, services.ErrInvalidInput, err)
+		assert.Empty(t, auditLogs.filters)
+
+		admin := services.NewAuditService(repos, &config.Config{Platform: config.PlatformConfig{Admins: "platform-1"}}, zap.NewNop(), nil)
+		_, err = admin.GetAuditLogs(ctx, &models.AuditLogFilter{}, "platform-1")
+		require.NoError(t, err)
+		require.Len(t, auditLogs.filters, 1)
+		assert.Empty(t, auditLogs.filters[0].WorkspaceID)
+	})
+}
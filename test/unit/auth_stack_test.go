@@ -0,0 +1,140 @@
+package unit
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/config"
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/handlers"
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/middleware"
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/models"
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/repositories"
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/services"
+)
+
+const stackSecret = "stack-secret"
+
+// callerWorkspace serves any workspace and records who read it
+type callerWorkspace struct {
+	fixedWorkspace
+	callers []string
+}
+
+func (s *callerWorkspace) GetWorkspace(ctx context.Context, workspaceID, userID string) (*models.Workspace, error) {
+	s.callers = append(s.callers, userID)
+	return s.fixedWorkspace.GetWorkspace(ctx, workspaceID, userID)
+}
+
+// signToken signs claims as a user JWT with stackSecret
+func signToken(t *testing.T, claims jwt.MapClaims) string {
+	signed, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(stackSecret))
+	require.NoError(t, err)
+	return signed
+}
+
+// newStackApp composes the app as a deployment does: JWT on the app, then the routes
+func newStackApp(svcs *services.Services, cfg *config.Config) *fiber.App {
+	h := handlers.New(svcs, cfg, zap.NewNop())
+	app := fiber.New()
+	app.Use(middleware.JWT(stackSecret))
+	h.RegisterRoutes(app)
+	return app
+}
+
+// stackGet sends GET path with the bearer token, and any extra headers as name, value pairs
+func stackGet(t *testing.T, app *fiber.App, path, token string, headers ...string) int {
+	req, _ := http.NewRequest(http.MethodGet, path, nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	for i := 0; i+1 < len(headers); i += 2 {
+		req.Header.Set(headers[i], headers[i+1])
+	}
+	resp, err := app.Test(req, -1)
+	require.NoError(t, err)
+	return resp.StatusCode
+}
+
+func TestComposedStackAuthenticatesUsersAndServiceAccounts(t *testing.T) {
+	repos := &repositories.Repositories{
+		Workspace:      &storedWorkspace{workspace: &models.Workspace{BaseModel: models.BaseModel{ID: batchWorkspaceID}, TenantID: "tenant-1"}},
+		ServiceAccount: &memoryServiceAccounts{},
+		Member:         &singleMember{role: models.WorkspaceRoleOwner},
+		Cache:          &nopCache{},
+	}
+	accounts := services.NewServiceAccountService(repos, &config.Config{}, zap.NewNop(), &recordingChanges{})
+	created, err := accounts.CreateServiceAccount(context.Background(), batchWorkspaceID, "owner-1", &models.CreateServiceAccountRequest{
+		Name: "nightly-import",
+		Role: models.WorkspaceRoleViewer,
+	})
+	require.NoError(t, err)
+
+	workspaces := &callerWorkspace{}
+	app := newStackApp(&services.Services{Workspace: workspaces, ServiceAccount: accounts}, &config.Config{})
+	path := "/api/v1/workspaces/" + batchWorkspaceID
+
+	assert.Equal(t, http.StatusOK, stackGet(t, app, path, signToken(t, jwt.MapClaims{"user_id": "user-1"})))
+	assert.Equal(t, http.StatusOK, stackGet(t, app, path, created.Token))
+	assert.Equal(t, []string{"user-1", created.PrincipalID()}, workspaces.callers)
+
+	assert.Equal(t, http.StatusUnauthorized, stackGet(t, app, path, "not-a-jwt"))
+	assert.Equal(t, http.StatusUnauthorized, stackGet(t, app, path, models.ServiceAccountTokenPrefix+"unknown"))
+}
+
+func TestComposedStackImpersonation(t *testing.T) {
+	workspaces := &callerWorkspace{}
+	app := newStackApp(&services.Services{Workspace: workspaces}, &config.Config{Impersonation: config.ImpersonationConfig{Claim: "impersonate"}})
+	path := "/api/v1/workspaces/" + batchWorkspaceID
+
+	support := signToken(t, jwt.MapClaims{"user_id": "support-1", "impersonate": true})
+	assert.Equal(t, http.StatusOK, stackGet(t, app, path, support, "X-Impersonate-User", "user-1"))
+	assert.Equal(t, []string{"user-1"}, workspaces.callers)
+
+	// The claim comes from the verified token, so an ordinary user can't impersonate
+	ordinary := signToken(t, jwt.MapClaims{"user_id": "user-2"})
+	assert.Equal(t, http.StatusForbidden, stackGet(t, app, path, ordinary, "X-Impersonate-User", "user-1"))
+	assert.Len(t, workspaces.callers, 1)
+}
+
+func TestComposedStackRateLimitsEachJWTTenant(t *testing.T) {
+	app := newStackApp(&services.Services{Workspace: &callerWorkspace{}}, &config.Config{RateLimit: config.RateLimitConfig{RequestsPerMinute: 2}})
+	path := "/api/v1/workspaces/" + batchWorkspaceID
+
+	// allowed counts the requests answered before the token's tenant is throttled
+	allowed := func(token string) int {
+		for n := 0; n < 5; n++ {
+			if stackGet(t, app, path, token) == http.StatusTooManyRequests {
+				return n
+			}
+		}
+		return 5
+	}
+
+	assert.Equal(t, 2, allowed(signToken(t, jwt.MapClaims{"user_id": "user-1", "tenant_id": "tenant-1"})))
+	assert.Equal(t, 2, allowed(signToken(t, jwt.MapClaims{"user_id": "user-2", "tenant_id": "tenant-2"})), "each tenant has its own bucket")
+}
+
+func TestComposedStackAppliesJWTTenantOrigins(t *testing.T) {
+	h := handlers.New(&services.Services{Workspace: &callerWorkspace{}}, &config.Config{}, zap.NewNop())
+	app := fiber.New()
+	app.Use(middleware.JWT(stackSecret), middleware.CORS(config.CORSConfig{TenantOrigins: "acme=https://acme.example.com"}))
+	h.RegisterRoutes(app)
+
+	allowOrigin := func(tenantID string) string {
+		req, _ := http.NewRequest(http.MethodGet, "/api/v1/workspaces/"+batchWorkspaceID, nil)
+		req.Header.Set("Authorization", "Bearer "+signToken(t, jwt.MapClaims{"user_id": "user-1", "tenant_id": tenantID}))
+		req.Header.Set("Origin", "https://acme.example.com")
+		resp, err := app.Test(req, -1)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		return resp.Header.Get("Access-Control-Allow-Origin")
+	}
+
+	assert.Equal(t, "https://acme.example.com", allowOrigin("acme"))
+	assert.Empty(t, allowOrigin("initech"))
+}
@@ -0,0 +1,97 @@
+package unit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/config"
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/handlers"
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/repositories"
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/services"
+)
+
+// signalingWorkspaces reports each scheduled deletion run and cache warming
+type signalingWorkspaces struct {
+	services.WorkspaceService
+	deletions chan struct{}
+	warmed    chan struct{}
+}
+
+func (s *signalingWorkspaces) RunScheduledDeletions(ctx context.Context, now time.Time) (int, error) {
+	select {
+	case s.deletions <- struct{}{}:
+	default:
+	}
+	return 0, nil
+}
+
+func (s *signalingWorkspaces) WarmCache(ctx context.Context, now time.Time) (int, error) {
+	close(s.warmed)
+	return 0, nil
+}
+
+// signalingMembers reports each user cache refresh run
+type signalingMembers struct {
+	services.MemberService
+	refreshes chan struct{}
+}
+
+func (s *signalingMembers) RefreshActiveUserCaches(ctx context.Context, now time.Time) (int, error) {
+	select {
+	case s.refreshes <- struct{}{}:
+	default:
+	}
+	return 0, nil
+}
+
+func TestStartBackgroundJobs(t *testing.T) {
+	start := func(t *testing.T, cfg *config.Config) (*signalingWorkspaces, *signalingMembers) {
+		svc := services.New(&repositories.Repositories{}, cfg, zap.NewNop(), nil, nil, nil, nil)
+		workspaces := &signalingWorkspaces{deletions: make(chan struct{}, 1), warmed: make(chan struct{})}
+		members := &signalingMembers{refreshes: make(chan struct{}, 1)}
+		svc.Workspace = workspaces
+		svc.Member = members
+
+		ctx, cancel := context.WithCancel(context.Background())
+		t.Cleanup(cancel)
+		handlers.New(svc, cfg, zap.NewNop()).StartBackgroundJobs(ctx)
+		return workspaces, members
+	}
+
+	t.Run("enabled jobs run", func(t *testing.T) {
+		workspaces, members := start(t, &config.Config{
+			Deletion:     config.DeletionConfig{Interval: 1},
+			CacheRefresh: config.CacheRefreshConfig{Enabled: true, Interval: 1},
+			CacheWarm:    config.CacheWarmConfig{Enabled: true},
+		})
+
+		for name, ran := range map[string]<-chan struct{}{
+			"scheduled deletion": workspaces.deletions,
+			"user cache refresh": members.refreshes,
+			"cache warming":      workspaces.warmed,
+		} {
+			select {
+			case <-ran:
+			case <-time.After(3 * time.Second):
+				t.Fatalf("%s did not run", name)
+			}
+		}
+	})
+
+	t.Run("disabled jobs stay off", func(t *testing.T) {
+		workspaces, members := start(t, &config.Config{})
+
+		select {
+		case <-workspaces.deletions:
+			t.Fatal("scheduled deletion ran")
+		case <-members.refreshes:
+			t.Fatal("user cache refresh ran")
+		case <-workspaces.warmed:
+			t.Fatal("cache warming ran")
+		case <-time.After(1500 * time.Millisecond):
+		}
+	})
+}
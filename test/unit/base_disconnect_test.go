@@ -0,0 +1,88 @@
+package unit
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/config"
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/models"
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/repositories"
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/services"
+)
+
+// deletableBases serves one base and records whether it was deleted
+type deletableBases struct {
+	storedBases
+	deleted bool
+}
+
+func (r *deletableBases) Delete(ctx context.Context, id string) error {
+	r.deleted = true
+	return nil
+}
+
+func TestDisconnectBaseDuringSync(t *testing.T) {
+	tests := []struct {
+		name          string
+		syncStatus    string
+		force         bool
+		expectedErr   error
+		expectDeleted bool
+	}{
+		{
+			name:          "idle base is disconnected",
+			syncStatus:    models.AirtableSyncStatusIdle,
+			expectDeleted: true,
+		},
+		{
+			name:        "pending sync blocks the disconnect",
+			syncStatus:  models.AirtableSyncStatusPending,
+			expectedErr: services.ErrSyncInProgress,
+		},
+		{
+			name:          "force disconnects a base mid-sync",
+			syncStatus:    models.AirtableSyncStatusPending,
+			force:         true,
+			expectDeleted: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bases := &deletableBases{storedBases: storedBases{bases: map[string]*models.AirtableBase{
+				"base-1": {
+					BaseModel:  models.BaseModel{ID: "base-1"},
+					ProjectID:  "proj-1",
+					BaseID:     "appBase1",
+					SyncStatus: tt.syncStatus,
+				},
+			}}}
+			audit := &recordingActions{}
+			repos := &repositories.Repositories{
+				Project: &storedProject{project: &models.Project{
+					BaseModel:   models.BaseModel{ID: "proj-1"},
+					WorkspaceID: "ws-1",
+				}},
+				Workspace:    &storedWorkspace{workspace: &models.Workspace{BaseModel: models.BaseModel{ID: "ws-1"}, TenantID: "tenant-1"}},
+				AirtableBase: bases,
+				Member:       &roleMembers{roles: map[string]models.WorkspaceMemberRole{"admin-1": models.WorkspaceRoleAdmin}},
+				Cache:        &nopCache{},
+			}
+			svc := services.NewAirtableBaseService(repos, &config.Config{}, zap.NewNop(), audit, nil)
+
+			err := svc.DisconnectBase(context.Background(), "base-1", "admin-1", tt.force)
+			if tt.expectedErr != nil {
+				require.Equal(t, tt.expectedErr, err)
+				assert.Empty(t, audit.actions)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, []string{models.AuditActionBaseDisconnected}, audit.actions)
+			}
+			assert.Equal(t, tt.expectDeleted, bases.deleted)
+		})
+	}
+}
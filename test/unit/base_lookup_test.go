@@ -0,0 +1,85 @@
+package unit
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/config"
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/handlers"
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/models"
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/services"
+)
+
+// lookupBases serves the connections registered for each Airtable base_id
+type lookupBases struct {
+	services.AirtableBaseService
+	connections map[string][]*models.AirtableBase
+}
+
+func (s *lookupBases) LookupBases(ctx context.Context, airtableBaseID, userID string) ([]*models.AirtableBase, error) {
+	bases := s.connections[airtableBaseID]
+	if len(bases) == 0 {
+		return nil, services.ErrAirtableBaseNotFound
+	}
+	return bases, nil
+}
+
+func TestLookupAirtableBasesHandler(t *testing.T) {
+	svc := &lookupBases{connections: map[string][]*models.AirtableBase{
+		"appShared": {
+			{BaseModel: models.BaseModel{ID: "conn-1"}, BaseID: "appShared"},
+			{BaseModel: models.BaseModel{ID: "conn-2"}, BaseID: "appShared"},
+		},
+	}}
+	h := handlers.New(&services.Services{AirtableBase: svc}, &config.Config{}, zap.NewNop())
+
+	app := fiber.New()
+	app.Use(func(c *fiber.Ctx) error {
+		c.Locals("user_id", "user-1")
+		return c.Next()
+	})
+	h.RegisterRoutes(app)
+
+	tests := []struct {
+		name           string
+		query          string
+		expectedStatus int
+		expectedIDs    []string
+	}{
+		{name: "multiple connections", query: "?base_id=appShared", expectedStatus: http.StatusOK, expectedIDs: []string{"conn-1", "conn-2"}},
+		{name: "not found", query: "?base_id=appMissing", expectedStatus: http.StatusNotFound},
+		{name: "base_id is required", query: "", expectedStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, _ := http.NewRequest(http.MethodGet, "/api/v1/airtable-bases/lookup"+tt.query, nil)
+			resp, err := app.Test(req, -1)
+			require.NoError(t, err)
+			require.Equal(t, tt.expectedStatus, resp.StatusCode)
+
+			if tt.expectedIDs != nil {
+				var body struct {
+					Bases []struct {
+						ID string `json:"id"`
+					} `json:"bases"`
+					Total int `json:"total"`
+				}
+				require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+				assert.Equal(t, len(tt.expectedIDs), body.Total)
+				ids := make([]string, 0, len(body.Bases))
+				for _, base := range body.Bases {
+					ids = append(ids, base.ID)
+				}
+				assert.Equal(t, tt.expectedIDs, ids)
+			}
+		})
+	}
+}
@@ -0,0 +1,135 @@
+package unit
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/config"
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/models"
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/repositories"
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/services"
+)
+
+// metadataCache is an in-memory cache repository that only stores base metadata
+type metadataCache struct {
+	repositories.CacheRepository
+	metadata map[string]*models.AirtableBaseMetadata
+}
+
+func (c *metadataCache) SetBaseMetadata(ctx context.Context, metadata *models.AirtableBaseMetadata, retention time.Duration) error {
+	copied := *metadata
+	c.metadata[metadata.BaseID] = &copied
+	return nil
+}
+
+func (c *metadataCache) GetBaseMetadata(ctx context.Context, baseID string) (*models.AirtableBaseMetadata, error) {
+	metadata, ok := c.metadata[baseID]
+	if !ok {
+		return nil, nil
+	}
+	copied := *metadata
+	return &copied, nil
+}
+
+// fakeGateway returns canned metadata, or err when set
+type fakeGateway struct {
+	calls int
+	err   error
+}
+
+func (g *fakeGateway) GetBaseMetadata(ctx context.Context, baseID string) (*models.AirtableBaseMetadata, error) {
+	g.calls++
+	if g.err != nil {
+		return nil, g.err
+	}
+	return &models.AirtableBaseMetadata{
+		Tables: []models.AirtableTableMetadata{{ID: "tbl1", Name: "Tasks", RecordCount: 42}},
+	}, nil
+}
+
+func TestGetBaseMetadata(t *testing.T) {
+	cfg := &config.Config{
+		Airtable: config.AirtableConfig{MetadataTTL: 60, MetadataRetention: 3600},
+	}
+	base := &models.AirtableBase{BaseID: "appBase1"}
+
+	tests := []struct {
+		name          string
+		cachedAge     time.Duration
+		cached        bool
+		gatewayErr    error
+		expectCalls   int
+		expectStale   bool
+		expectRecords int64
+	}{
+		{
+			name:          "fresh cache entry is served without calling the gateway",
+			cached:        true,
+			cachedAge:     time.Second,
+			expectCalls:   0,
+			expectRecords: 7,
+		},
+		{
+			name:          "expired cache entry is refreshed from the gateway",
+			cached:        true,
+			cachedAge:     time.Hour,
+			expectCalls:   1,
+			expectRecords: 42,
+		},
+		{
+			name:          "cache miss fetches from the gateway",
+			expectCalls:   1,
+			expectRecords: 42,
+		},
+		{
+			name:          "stale entry is served when the gateway is unavailable",
+			cached:        true,
+			cachedAge:     time.Hour,
+			gatewayErr:    errors.New("gateway unavailable"),
+			expectCalls:   1,
+			expectStale:   true,
+			expectRecords: 7,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cache := &metadataCache{metadata: map[string]*models.AirtableBaseMetadata{}}
+			if tt.cached {
+				cache.metadata[base.BaseID] = &models.AirtableBaseMetadata{
+					BaseID:    base.BaseID,
+					Tables:    []models.AirtableTableMetadata{{ID: "tbl1", Name: "Tasks", RecordCount: 7}},
+					FetchedAt: time.Now().Add(-tt.cachedAge),
+				}
+			}
+
+			gateway := &fakeGateway{err: tt.gatewayErr}
+			repos := &repositories.Repositories{Cache: cache}
+			svc := services.NewAirtableBaseService(repos, cfg, zap.NewNop(), nil, gateway)
+
+			metadata, err := svc.GetBaseMetadata(context.Background(), base)
+			require.NoError(t, err)
+			require.NotNil(t, metadata)
+
+			assert.Equal(t, tt.expectCalls, gateway.calls)
+			assert.Equal(t, tt.expectStale, metadata.IsStale)
+			assert.Equal(t, tt.expectRecords, metadata.Tables[0].RecordCount)
+		})
+	}
+}
+
+func TestGetBaseMetadataWithoutGatewayOrCache(t *testing.T) {
+	cache := &metadataCache{metadata: map[string]*models.AirtableBaseMetadata{}}
+	repos := &repositories.Repositories{Cache: cache}
+	svc := services.NewAirtableBaseService(repos, &config.Config{}, zap.NewNop(), nil, nil)
+
+	metadata, err := svc.GetBaseMetadata(context.Background(), &models.AirtableBase{BaseID: "appBase1"})
+	require.NoError(t, err)
+	assert.Nil(t, metadata)
+}
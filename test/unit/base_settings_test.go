@@ -0,0 +1,107 @@
+package unit
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/config"
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/models"
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/repositories"
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/services"
+)
+
+// storedBases keeps Airtable bases in memory by ID
+type storedBases struct {
+	repositories.AirtableBaseRepository
+	bases map[string]*models.AirtableBase
+}
+
+func (r *storedBases) Create(ctx context.Context, base *models.AirtableBase) error {
+	base.ID = "base-1"
+	copied := *base
+	r.bases[base.ID] = &copied
+	return nil
+}
+
+func (r *storedBases) GetByID(ctx context.Context, id string) (*models.AirtableBase, error) {
+	base, ok := r.bases[id]
+	if !ok {
+		return nil, repositories.ErrAirtableBaseNotFound
+	}
+	copied := *base
+	return &copied, nil
+}
+
+func (r *storedBases) Update(ctx context.Context, base *models.AirtableBase) error {
+	copied := *base
+	r.bases[base.ID] = &copied
+	return nil
+}
+
+func TestAirtableBaseSettings(t *testing.T) {
+	bases := &storedBases{bases: map[string]*models.AirtableBase{}}
+	repos := &repositories.Repositories{
+		Project: &storedProject{project: &models.Project{
+			BaseModel:   models.BaseModel{ID: "proj-1"},
+			WorkspaceID: "ws-1",
+		}},
+		Workspace:    &storedWorkspace{workspace: &models.Workspace{BaseModel: models.BaseModel{ID: "ws-1"}, TenantID: "tenant-1"}},
+		AirtableBase: bases,
+		Member:       &roleMembers{roles: map[string]models.WorkspaceMemberRole{"user-1": models.WorkspaceRoleMember}},
+		Cache:        &nopCache{},
+	}
+	svc := services.NewAirtableBaseService(repos, &config.Config{}, zap.NewNop(), &nopAudit{}, nil)
+	ctx := context.Background()
+
+	t.Run("connecting without settings stores an empty map", func(t *testing.T) {
+		base, err := svc.ConnectBase(ctx, "proj-1", "user-1", &models.CreateAirtableBaseRequest{BaseID: "appOther", Name: "Other"})
+		require.NoError(t, err)
+		assert.NotNil(t, base.Settings)
+		assert.Empty(t, base.Settings)
+	})
+
+	t.Run("settings are stored on connect", func(t *testing.T) {
+		base, err := svc.ConnectBase(ctx, "proj-1", "user-1", &models.CreateAirtableBaseRequest{
+			BaseID:   "appSales",
+			Name:     "Sales",
+			Settings: models.JSONMap{"report_sheet": "Q1"},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, "Q1", bases.bases[base.ID].Settings["report_sheet"])
+	})
+
+	t.Run("settings are replaced on update", func(t *testing.T) {
+		settings := models.JSONMap{"report_sheet": "Q2", "owner_team": "finance"}
+		base, err := svc.UpdateBase(ctx, "base-1", "user-1", &models.UpdateAirtableBaseRequest{Settings: &settings})
+		require.NoError(t, err)
+		assert.Equal(t, settings, base.Settings)
+		assert.Equal(t, settings, bases.bases["base-1"].Settings)
+	})
+
+	t.Run("updates without settings keep them", func(t *testing.T) {
+		name := "Sales 2024"
+		base, err := svc.UpdateBase(ctx, "base-1", "user-1", &models.UpdateAirtableBaseRequest{Name: &name})
+		require.NoError(t, err)
+		assert.Equal(t, "Q2", base.Settings["report_sheet"])
+	})
+}
+
+func TestValidateAirtableBaseSettings(t *testing.T) {
+	settings := models.JSONMap{}
+	for i := 0; i < 51; i++ {
+		settings[fmt.Sprintf("key%d", i)] = i
+	}
+
+	create := services.ValidateCreateAirtableBase(&models.CreateAirtableBaseRequest{BaseID: "appX", Name: "X", Settings: settings})
+	assert.Empty(t, create.Errors)
+	assert.Len(t, create.Warnings, 1)
+
+	update := services.ValidateUpdateAirtableBase(&models.UpdateAirtableBaseRequest{Settings: &settings})
+	assert.Empty(t, update.Errors)
+	assert.Len(t, update.Warnings, 1)
+}
@@ -0,0 +1,159 @@
+package unit
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/config"
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/models"
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/repositories"
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/services"
+)
+
+// connectedBases keeps one project's Airtable bases in memory by Airtable base ID
+type connectedBases struct {
+	repositories.AirtableBaseRepository
+	bases   map[string]*models.AirtableBase
+	updates int
+}
+
+func (r *connectedBases) Create(ctx context.Context, base *models.AirtableBase) error {
+	if _, ok := r.bases[base.BaseID]; ok {
+		return repositories.ErrDuplicateAirtableBase
+	}
+	base.ID = "conn-" + base.BaseID
+	copied := *base
+	r.bases[base.BaseID] = &copied
+	return nil
+}
+
+func (r *connectedBases) GetByProjectAndBaseID(ctx context.Context, projectID, baseID string) (*models.AirtableBase, error) {
+	base, ok := r.bases[baseID]
+	if !ok {
+		return nil, repositories.ErrAirtableBaseNotFound
+	}
+	copied := *base
+	return &copied, nil
+}
+
+func (r *connectedBases) Update(ctx context.Context, base *models.AirtableBase) error {
+	r.updates++
+	copied := *base
+	r.bases[base.BaseID] = &copied
+	return nil
+}
+
+func TestDuplicateBaseConnection(t *testing.T) {
+	setup := func() (services.AirtableBaseService, *connectedBases, *recordingChanges) {
+		bases := &connectedBases{bases: map[string]*models.AirtableBase{}}
+		repos := &repositories.Repositories{
+			Project: &storedProject{project: &models.Project{
+				BaseModel:   models.BaseModel{ID: "proj-1"},
+				WorkspaceID: "ws-1",
+			}},
+			Workspace:    &storedWorkspace{workspace: &models.Workspace{BaseModel: models.BaseModel{ID: "ws-1"}, TenantID: "tenant-1"}},
+			AirtableBase: bases,
+			Member:       &roleMembers{roles: map[string]models.WorkspaceMemberRole{"user-1": models.WorkspaceRoleMember}},
+			Cache:        &nopCache{},
+		}
+		audit := &recordingChanges{}
+		return services.NewAirtableBaseService(repos, &config.Config{}, zap.NewNop(), audit, nil), bases, audit
+	}
+	ctx := context.Background()
+	original := &models.CreateAirtableBaseRequest{BaseID: "appSales", Name: "Sales", Description: "Pipeline", SyncEnabled: true}
+	upsert := func(req *models.CreateAirtableBaseRequest) *models.UpsertAirtableBaseRequest {
+		return &models.UpsertAirtableBaseRequest{BaseID: req.BaseID, Name: req.Name, Description: &req.Description, SyncEnabled: &req.SyncEnabled, Settings: req.Settings}
+	}
+
+	t.Run("connecting again is rejected by default", func(t *testing.T) {
+		svc, bases, _ := setup()
+		_, err := svc.ConnectBase(ctx, "proj-1", "user-1", original)
+		require.NoError(t, err)
+
+		_, err = svc.ConnectBase(ctx, "proj-1", "user-1", &models.CreateAirtableBaseRequest{BaseID: "appSales", Name: "Renamed"})
+		assert.Equal(t, services.ErrAirtableBaseExists, err)
+		assert.Equal(t, "Sales", bases.bases["appSales"].Name)
+	})
+
+	t.Run("upsert creates a missing connection", func(t *testing.T) {
+		svc, bases, audit := setup()
+		base, created, err := svc.UpsertBase(ctx, "proj-1", "user-1", upsert(original))
+		require.NoError(t, err)
+		assert.True(t, created)
+		assert.Equal(t, "conn-appSales", base.ID)
+		assert.NotNil(t, bases.bases["appSales"].Settings)
+		assert.Equal(t, []string{models.AuditActionBaseConnected}, audit.actions)
+	})
+
+	t.Run("upsert updates an existing connection", func(t *testing.T) {
+		svc, bases, audit := setup()
+		_, err := svc.ConnectBase(ctx, "proj-1", "user-1", original)
+		require.NoError(t, err)
+
+		disabled := false
+		base, created, err := svc.UpsertBase(ctx, "proj-1", "user-1", &models.UpsertAirtableBaseRequest{BaseID: "appSales", Name: "Sales EMEA", SyncEnabled: &disabled})
+		require.NoError(t, err)
+		assert.False(t, created)
+		assert.Equal(t, "conn-appSales", base.ID)
+		assert.Equal(t, "Sales EMEA", bases.bases["appSales"].Name)
+		assert.False(t, bases.bases["appSales"].SyncEnabled)
+		assert.Equal(t, "Pipeline", bases.bases["appSales"].Description)
+
+		require.Equal(t, []string{models.AuditActionBaseConnected, models.AuditActionBaseUpdated}, audit.actions)
+		assert.Contains(t, audit.changes[1], "name")
+		assert.Contains(t, audit.changes[1], "sync_enabled")
+		assert.NotContains(t, audit.changes[1], "description")
+		assert.NotContains(t, audit.changes[1], "settings")
+	})
+
+	t.Run("upsert keeps the fields it leaves out", func(t *testing.T) {
+		svc, bases, audit := setup()
+		_, err := svc.ConnectBase(ctx, "proj-1", "user-1", original)
+		require.NoError(t, err)
+
+		_, created, err := svc.UpsertBase(ctx, "proj-1", "user-1", &models.UpsertAirtableBaseRequest{BaseID: "appSales", Name: "Sales"})
+		require.NoError(t, err)
+		assert.False(t, created)
+		assert.True(t, bases.bases["appSales"].SyncEnabled)
+		assert.Equal(t, "Pipeline", bases.bases["appSales"].Description)
+		assert.Zero(t, bases.updates)
+		assert.Equal(t, []string{models.AuditActionBaseConnected}, audit.actions)
+	})
+
+	t.Run("upsert with nothing changed writes nothing", func(t *testing.T) {
+		svc, bases, audit := setup()
+		_, err := svc.ConnectBase(ctx, "proj-1", "user-1", original)
+		require.NoError(t, err)
+
+		_, created, err := svc.UpsertBase(ctx, "proj-1", "user-1", upsert(original))
+		require.NoError(t, err)
+		assert.False(t, created)
+		assert.Zero(t, bases.updates)
+		assert.Equal(t, []string{models.AuditActionBaseConnected}, audit.actions)
+	})
+}
+
+func TestConnectBaseVerifiesWithGateway(t *testing.T) {
+	bases := &connectedBases{bases: map[string]*models.AirtableBase{}}
+	repos := &repositories.Repositories{
+		Project: &storedProject{project: &models.Project{
+			BaseModel:   models.BaseModel{ID: "proj-1"},
+			WorkspaceID: "ws-1",
+		}},
+		AirtableBase: bases,
+		Member:       &roleMembers{roles: map[string]models.WorkspaceMemberRole{"user-1": models.WorkspaceRoleMember}},
+		Cache:        &metadataCache{metadata: map[string]*models.AirtableBaseMetadata{}},
+	}
+	gateway := &fakeGateway{err: errors.New("base not found")}
+	svc := services.NewAirtableBaseService(repos, &config.Config{}, zap.NewNop(), &recordingChanges{}, gateway)
+
+	_, err := svc.ConnectBase(context.Background(), "proj-1", "user-1", &models.CreateAirtableBaseRequest{BaseID: "appMissing", Name: "Missing"})
+	assert.Equal(t, services.ErrAirtableBaseUnverified, err)
+	assert.Equal(t, 1, gateway.calls)
+	assert.Empty(t, bases.bases)
+}
@@ -0,0 +1,220 @@
+package unit
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/config"
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/handlers"
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/models"
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/services"
+)
+
+// scriptedMembers fails AddMember with the error scripted for the user, if any
+type scriptedMembers struct {
+	services.MemberService
+	failures map[string]error
+}
+
+func (s *scriptedMembers) AddMember(ctx context.Context, workspaceID, userID string, req *models.AddWorkspaceMemberRequest) (*models.WorkspaceMember, error) {
+	if err := s.failures[req.UserID]; err != nil {
+		return nil, err
+	}
+	return &models.WorkspaceMember{WorkspaceID: workspaceID, UserID: req.UserID, Role: req.Role}, nil
+}
+
+// scriptedProjects fails CreateProject with the error scripted for the name, if any
+type scriptedProjects struct {
+	services.ProjectService
+	failures map[string]error
+	created  []string
+}
+
+func (s *scriptedProjects) CreateProject(ctx context.Context, workspaceID, userID string, req *models.CreateProjectRequest) (*models.Project, error) {
+	if err := s.failures[req.Name]; err != nil {
+		return nil, err
+	}
+	s.created = append(s.created, req.Name)
+	return &models.Project{WorkspaceID: workspaceID, Name: req.Name}, nil
+}
+
+// scriptedBases reports the error scripted for each base ID, if any, from ConnectBases
+type scriptedBases struct {
+	services.AirtableBaseService
+	failures  map[string]error
+	requested []string
+}
+
+func (s *scriptedBases) ConnectBases(ctx context.Context, projectID, userID string, reqs []models.CreateAirtableBaseRequest) ([]*services.ConnectBaseResult, error) {
+	results := make([]*services.ConnectBaseResult, len(reqs))
+	for i, req := range reqs {
+		s.requested = append(s.requested, req.BaseID)
+		if err := s.failures[req.BaseID]; err != nil {
+			results[i] = &services.ConnectBaseResult{Err: err}
+			continue
+		}
+		results[i] = &services.ConnectBaseResult{Base: &models.AirtableBase{ProjectID: projectID, BaseID: req.BaseID, Name: req.Name}}
+	}
+	return results, nil
+}
+
+// batchItem is the decoded shape of one batch result entry
+type batchItem struct {
+	Index   int                    `json:"index"`
+	Status  int                    `json:"status"`
+	Item    map[string]interface{} `json:"item"`
+	Error   string                 `json:"error"`
+	Details []string               `json:"details"`
+	Errors  []services.FieldError  `json:"errors"`
+}
+
+// batchWorkspaceID is the workspace the batch requests target; routes only accept UUIDs
+const batchWorkspaceID = "5b1e0c3a-7d2f-4c8e-9a61-2f0d8b4e7c15"
+
+func postBatch(t *testing.T, svcs *services.Services, path, body string) (int, []batchItem, map[string]interface{}) {
+	h := handlers.New(svcs, &config.Config{}, zap.NewNop())
+	app := fiber.New()
+	app.Use(func(c *fiber.Ctx) error {
+		c.Locals("user_id", "admin-1")
+		return c.Next()
+	})
+	h.RegisterRoutes(app)
+
+	req, _ := http.NewRequest(http.MethodPost, path, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := app.Test(req, -1)
+	require.NoError(t, err)
+
+	var decoded struct {
+		Results   []batchItem `json:"results"`
+		Succeeded int         `json:"succeeded"`
+		Failed    int         `json:"failed"`
+		Error     string      `json:"error"`
+	}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&decoded))
+	return resp.StatusCode, decoded.Results, map[string]interface{}{
+		"succeeded": decoded.Succeeded,
+		"failed":    decoded.Failed,
+		"error":     decoded.Error,
+	}
+}
+
+func TestBatchAddWorkspaceMembers(t *testing.T) {
+	members := &scriptedMembers{failures: map[string]error{
+		"existing":  services.ErrMemberExists,
+		"new-owner": services.ErrUnauthorized,
+	}}
+	svcs := &services.Services{Member: members}
+
+	status, results, totals := postBatch(t, svcs, "/api/v1/workspaces/"+batchWorkspaceID+"/members/batch", `{"members":[
+		{"user_id":"user-2","role":"member"},
+		{"user_id":"existing","role":"member"},
+		{"user_id":"new-owner","role":"owner"},
+		{"user_id":"user-3","role":"superuser"}
+	]}`)
+
+	assert.Equal(t, http.StatusMultiStatus, status)
+	assert.Equal(t, 1, totals["succeeded"])
+	assert.Equal(t, 3, totals["failed"])
+	require.Len(t, results, 4)
+
+	assert.Equal(t, 0, results[0].Index)
+	assert.Equal(t, http.StatusCreated, results[0].Status)
+	assert.Equal(t, "user-2", results[0].Item["user_id"])
+	assert.Empty(t, results[0].Error)
+
+	assert.Equal(t, 1, results[1].Index)
+	assert.Equal(t, http.StatusConflict, results[1].Status)
+	assert.Equal(t, "User is already a member of the workspace", results[1].Error)
+	assert.Nil(t, results[1].Item)
+
+	assert.Equal(t, 2, results[2].Index)
+	assert.Equal(t, http.StatusForbidden, results[2].Status)
+
+	// Invalid items are rejected before they reach the service
+	assert.Equal(t, 3, results[3].Index)
+	assert.Equal(t, http.StatusBadRequest, results[3].Status)
+	require.Len(t, results[3].Errors, 1)
+	assert.Equal(t, "role", results[3].Errors[0].Field)
+}
+
+func TestBatchCreateProjects(t *testing.T) {
+	t.Run("mixed outcomes report 207 with per-item codes", func(t *testing.T) {
+		projects := &scriptedProjects{failures: map[string]error{"Taken": services.ErrProjectNameTaken}}
+		svcs := &services.Services{Project: projects}
+
+		status, results, _ := postBatch(t, svcs, "/api/v1/workspaces/"+batchWorkspaceID+"/projects/batch", `{"projects":[
+			{"name":"Fresh"},
+			{"name":"   "},
+			{"name":"Taken"}
+		]}`)
+
+		assert.Equal(t, http.StatusMultiStatus, status)
+		require.Len(t, results, 3)
+		assert.Equal(t, http.StatusCreated, results[0].Status)
+		assert.Equal(t, http.StatusBadRequest, results[1].Status)
+		assert.Equal(t, "Validation failed", results[1].Error)
+		assert.NotEmpty(t, results[1].Details)
+		assert.Equal(t, http.StatusConflict, results[2].Status)
+
+		// Invalid items never reach the service
+		assert.Equal(t, []string{"Fresh"}, projects.created)
+	})
+
+	t.Run("all items succeeding report 201", func(t *testing.T) {
+		svcs := &services.Services{Project: &scriptedProjects{}}
+
+		status, results, totals := postBatch(t, svcs, "/api/v1/workspaces/"+batchWorkspaceID+"/projects/batch", `{"projects":[{"name":"One"},{"name":"Two"}]}`)
+		assert.Equal(t, http.StatusCreated, status)
+		assert.Len(t, results, 2)
+		assert.Equal(t, 0, totals["failed"])
+	})
+
+	t.Run("empty batch is rejected", func(t *testing.T) {
+		svcs := &services.Services{Project: &scriptedProjects{}}
+
+		status, _, totals := postBatch(t, svcs, "/api/v1/workspaces/"+batchWorkspaceID+"/projects/batch", `{"projects":[]}`)
+		assert.Equal(t, http.StatusBadRequest, status)
+		assert.Equal(t, "projects must contain between 1 and 100 items", totals["error"])
+	})
+}
+
+func TestBatchConnectAirtableBases(t *testing.T) {
+	bases := &scriptedBases{failures: map[string]error{
+		"appExisting": services.ErrAirtableBaseExists,
+		"appMissing":  services.ErrAirtableBaseUnverified,
+	}}
+	svcs := &services.Services{AirtableBase: bases}
+
+	status, results, totals := postBatch(t, svcs, "/api/v1/projects/"+batchWorkspaceID+"/airtable-bases/batch", `{"bases":[
+		{"base_id":"appNew","name":"New"},
+		{"base_id":"","name":"No ID"},
+		{"base_id":"appExisting","name":"Existing"},
+		{"base_id":"appMissing","name":"Missing"}
+	]}`)
+
+	assert.Equal(t, http.StatusMultiStatus, status)
+	assert.Equal(t, 1, totals["succeeded"])
+	assert.Equal(t, 3, totals["failed"])
+	require.Len(t, results, 4)
+
+	assert.Equal(t, http.StatusCreated, results[0].Status)
+	assert.Equal(t, "appNew", results[0].Item["base_id"])
+	assert.Equal(t, 1, results[1].Index)
+	assert.Equal(t, http.StatusBadRequest, results[1].Status)
+	assert.Equal(t, 2, results[2].Index)
+	assert.Equal(t, http.StatusConflict, results[2].Status)
+	assert.Contains(t, results[2].Error, "skipped")
+	assert.Equal(t, http.StatusBadRequest, results[3].Status)
+
+	// Invalid items never reach the service
+	assert.Equal(t, []string{"appNew", "appExisting", "appMissing"}, bases.requested)
+}
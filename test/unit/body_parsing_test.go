@@ -0,0 +1,94 @@
+package unit
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/config"
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/handlers"
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/models"
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/services"
+)
+
+func TestRequestBodyContentType(t *testing.T) {
+	tests := []struct {
+		name           string
+		contentType    string
+		body           string
+		expectedStatus int
+		expectedError  string
+	}{
+		{
+			name:           "json content type",
+			contentType:    "application/json",
+			body:           `{"role":"admin"}`,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "missing content type with a json body",
+			body:           `{"role":"admin"}`,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "unsupported content type with a json body",
+			contentType:    "text/plain",
+			body:           ` {"role":"admin"}`,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "missing content type with a non-json body",
+			body:           `role=admin`,
+			expectedStatus: http.StatusBadRequest,
+			expectedError:  "Unsupported content type, set Content-Type: application/json",
+		},
+		{
+			name:           "malformed json",
+			contentType:    "application/json",
+			body:           `{"role":`,
+			expectedStatus: http.StatusBadRequest,
+			expectedError:  "Invalid request body",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			memberService := &stubMemberService{member: &models.WorkspaceMember{
+				WorkspaceID: "ws-1",
+				UserID:      "user-2",
+				Role:        models.WorkspaceRoleMember,
+			}}
+			h := handlers.New(&services.Services{Member: memberService}, &config.Config{}, zap.NewNop())
+
+			app := fiber.New()
+			app.Use(func(c *fiber.Ctx) error {
+				c.Locals("user_id", "user-1")
+				return c.Next()
+			})
+			app.Put("/workspaces/:workspace_id/members/:user_id", h.UpdateWorkspaceMemberRole)
+
+			req, _ := http.NewRequest("PUT", "/workspaces/ws-1/members/user-2", strings.NewReader(tt.body))
+			if tt.contentType != "" {
+				req.Header.Set("Content-Type", tt.contentType)
+			}
+
+			resp, err := app.Test(req, -1)
+			require.NoError(t, err)
+			require.Equal(t, tt.expectedStatus, resp.StatusCode)
+
+			var body map[string]interface{}
+			require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+			if tt.expectedError != "" {
+				assert.Equal(t, tt.expectedError, body["error"])
+			} else {
+				assert.Equal(t, string(models.WorkspaceRoleAdmin), body["role"])
+			}
+		})
+	}
+}
@@ -0,0 +1,105 @@
+package unit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/config"
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/models"
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/repositories"
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/services"
+)
+
+// warmCache always hits with the cached workspace and records writes
+type warmCache struct {
+	repositories.CacheRepository
+	workspace *models.Workspace
+	sets      int
+}
+
+func (c *warmCache) GetWorkspace(ctx context.Context, id string) (*models.Workspace, error) {
+	return c.workspace, nil
+}
+
+func (c *warmCache) SetWorkspace(ctx context.Context, workspace *models.Workspace) error {
+	c.sets++
+	return nil
+}
+
+// countingWorkspaces serves a workspace from the "database" and counts reads
+type countingWorkspaces struct {
+	repositories.WorkspaceRepository
+	workspace *models.Workspace
+	reads     int
+}
+
+func (r *countingWorkspaces) GetByID(ctx context.Context, id string) (*models.Workspace, error) {
+	r.reads++
+	return r.workspace, nil
+}
+
+// singleMember reports every user as a member with the given role
+type singleMember struct {
+	repositories.WorkspaceMemberRepository
+	role models.WorkspaceMemberRole
+}
+
+func (r *singleMember) GetByWorkspaceAndUser(ctx context.Context, workspaceID, userID string) (*models.WorkspaceMember, error) {
+	return &models.WorkspaceMember{WorkspaceID: workspaceID, UserID: userID, Role: r.role}, nil
+}
+
+func (r *singleMember) TouchLastAccessed(ctx context.Context, workspaceID, userID string, at time.Time) error {
+	return nil
+}
+
+func TestGetWorkspaceCacheBypass(t *testing.T) {
+	tests := []struct {
+		name          string
+		bypass        bool
+		expectedName  string
+		expectedReads int
+		expectedSets  int
+	}{
+		{
+			name:         "warm cache is served without a database read",
+			expectedName: "Cached",
+		},
+		{
+			name:          "bypass reads the database and repopulates the cache",
+			bypass:        true,
+			expectedName:  "Fresh",
+			expectedReads: 1,
+			expectedSets:  1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cache := &warmCache{workspace: &models.Workspace{BaseModel: models.BaseModel{ID: "ws-1"}, Name: "Cached"}}
+			workspaces := &countingWorkspaces{workspace: &models.Workspace{BaseModel: models.BaseModel{ID: "ws-1"}, Name: "Fresh"}}
+			repos := &repositories.Repositories{
+				Workspace: workspaces,
+				Member:    &singleMember{role: models.WorkspaceRoleViewer},
+				Cache:     cache,
+			}
+			svc := services.NewWorkspaceService(repos, &config.Config{}, zap.NewNop(), nil, nil)
+
+			ctx := context.Background()
+			if tt.bypass {
+				ctx = services.WithCacheBypass(ctx)
+			}
+
+			workspace, err := svc.GetWorkspace(ctx, "ws-1", "user-1")
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.expectedName, workspace.Name)
+			assert.Equal(t, tt.expectedReads, workspaces.reads)
+			assert.Equal(t, tt.expectedSets, cache.sets)
+		})
+	}
+}
@@ -0,0 +1,76 @@
+package unit
+
+import (
+	"context"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/config"
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/handlers"
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/models"
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/repositories"
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/services"
+	"github.com/Reg-Kris/pyairtable-workspace-service/pkg/metrics"
+)
+
+// projectCache holds the projects it was given and ignores writes
+type projectCache struct {
+	repositories.CacheRepository
+	projects map[string]*models.Project
+}
+
+func (c *projectCache) GetProject(ctx context.Context, id string) (*models.Project, error) {
+	return c.projects[id], nil
+}
+
+func (c *projectCache) SetProject(ctx context.Context, project *models.Project) error {
+	return nil
+}
+
+// storedProjects serves every project from the database
+type storedProjects struct {
+	repositories.ProjectRepository
+}
+
+func (r *storedProjects) GetByID(ctx context.Context, id string) (*models.Project, error) {
+	return &models.Project{BaseModel: models.BaseModel{ID: id}, WorkspaceID: "ws-1"}, nil
+}
+
+func TestCacheHitsAndMissesAreCounted(t *testing.T) {
+	repos := &repositories.Repositories{
+		Project: &storedProjects{},
+		Member:  &roleMembers{roles: map[string]models.WorkspaceMemberRole{"viewer": models.WorkspaceRoleViewer}},
+		Cache: &projectCache{projects: map[string]*models.Project{
+			"cached": {BaseModel: models.BaseModel{ID: "cached"}, WorkspaceID: "ws-1"},
+		}},
+	}
+	svc := services.New(repos, &config.Config{}, zap.NewNop(), nil, nil, nil, nil)
+
+	hits := prometheus.NewCounterVec(prometheus.CounterOpts{Name: "hits"}, []string{"entity"})
+	misses := prometheus.NewCounterVec(prometheus.CounterOpts{Name: "misses"}, []string{"entity"})
+	// The handlers hand the registry on to the services
+	handlers.New(svc, &config.Config{}, zap.NewNop()).UseMetrics(&metrics.Registry{CacheHitsTotal: hits, CacheMissesTotal: misses})
+	ctx := context.Background()
+
+	_, err := svc.Project.GetProject(ctx, "cached", "viewer")
+	require.NoError(t, err)
+	assert.Equal(t, 1.0, testutil.ToFloat64(hits.WithLabelValues("project")))
+	assert.Equal(t, 0.0, testutil.ToFloat64(misses.WithLabelValues("project")))
+
+	_, err = svc.Project.GetProject(ctx, "uncached", "viewer")
+	require.NoError(t, err)
+	assert.Equal(t, 1.0, testutil.ToFloat64(hits.WithLabelValues("project")))
+	assert.Equal(t, 1.0, testutil.ToFloat64(misses.WithLabelValues("project")))
+
+	// Reads that bypass the cache are not lookups
+	_, err = svc.Project.GetProject(services.WithCacheBypass(ctx), "cached", "viewer")
+	require.NoError(t, err)
+	assert.Equal(t, 1.0, testutil.ToFloat64(hits.WithLabelValues("project")))
+	assert.Equal(t, 1.0, testutil.ToFloat64(misses.WithLabelValues("project")))
+	assert.Equal(t, 0.0, testutil.ToFloat64(hits.WithLabelValues("workspace")))
+}
@@ -0,0 +1,54 @@
+package unit
+
+import (
+	"context"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/config"
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/models"
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/repositories"
+)
+
+func TestCacheKeyVersionBumpIgnoresOldEntries(t *testing.T) {
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	t.Cleanup(func() { client.Close() })
+	ctx := context.Background()
+
+	cacheAt := func(version string) repositories.CacheRepository {
+		cfg := &config.Config{}
+		cfg.Redis.KeyVersion = version
+		return repositories.NewCacheRepository(client, nil, cfg, zap.NewNop())
+	}
+
+	previous := cacheAt("")
+	workspace := &models.Workspace{Name: "Cached"}
+	workspace.ID = "ws-1"
+	require.NoError(t, previous.SetWorkspace(ctx, workspace))
+
+	cached, err := previous.GetWorkspace(ctx, "ws-1")
+	require.NoError(t, err)
+	require.NotNil(t, cached)
+	assert.Equal(t, "Cached", cached.Name)
+	assert.Equal(t, []string{"v1:workspace:ws-1"}, server.Keys())
+
+	// Entries written before the bump are still in Redis but are read as misses
+	bumped := cacheAt("2")
+	stale, err := bumped.GetWorkspace(ctx, "ws-1")
+	require.NoError(t, err)
+	assert.Nil(t, stale)
+	assert.Equal(t, []string{"v1:workspace:ws-1"}, server.Keys())
+
+	workspace.Name = "Fresh"
+	require.NoError(t, bumped.SetWorkspace(ctx, workspace))
+	fresh, err := bumped.GetWorkspace(ctx, "ws-1")
+	require.NoError(t, err)
+	require.NotNil(t, fresh)
+	assert.Equal(t, "Fresh", fresh.Name)
+}
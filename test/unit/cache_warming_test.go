@@ -0,0 +1,113 @@
+package unit
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/config"
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/models"
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/repositories"
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/services"
+	"github.com/Reg-Kris/pyairtable-workspace-service/pkg/health"
+)
+
+// busiestWorkspaces ranks workspaces by a fixed order, recording the window it was asked about
+type busiestWorkspaces struct {
+	repositories.AuditLogRepository
+	ranked []string
+	since  time.Time
+}
+
+func (r *busiestWorkspaces) MostActiveWorkspaces(ctx context.Context, since time.Time, limit int) ([]string, error) {
+	r.since = since
+	if len(r.ranked) > limit {
+		return r.ranked[:limit], nil
+	}
+	return r.ranked, nil
+}
+
+// foundWorkspaces serves FindByIDs from liveWorkspaces
+type foundWorkspaces struct {
+	liveWorkspaces
+}
+
+func (r *foundWorkspaces) FindByIDs(ctx context.Context, ids []string) ([]*models.Workspace, error) {
+	var found []*models.Workspace
+	for _, id := range ids {
+		if workspace, ok := r.workspaces[id]; ok {
+			found = append(found, workspace)
+		}
+	}
+	return found, nil
+}
+
+func TestStartupCacheWarming(t *testing.T) {
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	t.Cleanup(func() { client.Close() })
+
+	cfg := &config.Config{CacheWarm: config.CacheWarmConfig{Enabled: true, TopWorkspaces: 2, ActivityWindow: 3600}}
+	cache := repositories.NewCacheRepository(client, nil, cfg, zap.NewNop())
+	audit := &busiestWorkspaces{ranked: []string{"ws-busy", "ws-deleted", "ws-quiet"}}
+	workspaces := &foundWorkspaces{liveWorkspaces{workspaces: map[string]*models.Workspace{
+		"ws-busy":  {BaseModel: models.BaseModel{ID: "ws-busy"}, Name: "Busy"},
+		"ws-quiet": {BaseModel: models.BaseModel{ID: "ws-quiet"}, Name: "Quiet"},
+	}}}
+	repos := &repositories.Repositories{Workspace: workspaces, AuditLog: audit, Cache: cache}
+	svc := services.NewWorkspaceService(repos, cfg, zap.NewNop(), nil, nil)
+	ctx := context.Background()
+
+	// Warming waits while a critical subsystem is down
+	var healthy atomic.Bool
+	readiness := health.NewRegistry()
+	readiness.Register(health.Check{Name: "database", Critical: true, Probe: func(ctx context.Context) error {
+		if !healthy.Load() {
+			return errors.New("unreachable")
+		}
+		return nil
+	}})
+
+	done := make(chan struct{})
+	start := time.Now()
+	go func() {
+		services.RunStartupCacheWarming(ctx, svc, readiness, 5*time.Millisecond, zap.NewNop())
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		t.Fatal("warming ran before the service was ready")
+	case <-time.After(50 * time.Millisecond):
+	}
+	cached, err := cache.GetWorkspace(ctx, "ws-busy")
+	require.NoError(t, err)
+	assert.Nil(t, cached)
+
+	healthy.Store(true)
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("warming did not run once ready")
+	}
+
+	// Only the top two by activity were selected; the deleted one had nothing to cache
+	cached, err = cache.GetWorkspace(ctx, "ws-busy")
+	require.NoError(t, err)
+	require.NotNil(t, cached)
+	assert.Equal(t, "Busy", cached.Name)
+
+	cached, err = cache.GetWorkspace(ctx, "ws-quiet")
+	require.NoError(t, err)
+	assert.Nil(t, cached)
+
+	assert.WithinDuration(t, start.Add(-time.Hour), audit.since, 5*time.Second)
+}
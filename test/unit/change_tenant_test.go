@@ -0,0 +1,79 @@
+package unit
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/config"
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/models"
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/repositories"
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/services"
+)
+
+// invalidationCache records the workspaces and tenants whose cache entries were dropped
+type invalidationCache struct {
+	repositories.CacheRepository
+	workspaces []string
+	tenants    []string
+}
+
+func (c *invalidationCache) InvalidateWorkspaceCache(ctx context.Context, workspaceID string) error {
+	c.workspaces = append(c.workspaces, workspaceID)
+	return nil
+}
+
+func (c *invalidationCache) InvalidateTenantStats(ctx context.Context, tenantID string) error {
+	c.tenants = append(c.tenants, tenantID)
+	return nil
+}
+
+func TestChangeTenant(t *testing.T) {
+	cfg := &config.Config{Platform: config.PlatformConfig{Admins: "platform-1, platform-2"}}
+
+	setup := func() (services.WorkspaceService, *storedWorkspace, *invalidationCache, *recordingChanges) {
+		workspaces := &storedWorkspace{workspace: &models.Workspace{
+			BaseModel: models.BaseModel{ID: "ws-1"},
+			TenantID:  "tenant-a",
+			Name:      "Shared",
+		}}
+		cache := &invalidationCache{}
+		audit := &recordingChanges{}
+		repos := &repositories.Repositories{Workspace: workspaces, Cache: cache}
+		return services.NewWorkspaceService(repos, cfg, zap.NewNop(), audit, nil), workspaces, cache, audit
+	}
+
+	t.Run("moves the workspace and invalidates both tenants", func(t *testing.T) {
+		svc, workspaces, cache, audit := setup()
+
+		moved, err := svc.ChangeTenant(context.Background(), "ws-1", "tenant-b", "platform-2")
+		require.NoError(t, err)
+		assert.Equal(t, "tenant-b", moved.TenantID)
+		assert.Equal(t, "tenant-b", workspaces.workspace.TenantID)
+
+		assert.Equal(t, []string{"ws-1"}, cache.workspaces)
+		assert.ElementsMatch(t, []string{"tenant-a", "tenant-b"}, cache.tenants)
+
+		require.Equal(t, []string{models.AuditActionWorkspaceTenantChanged}, audit.actions)
+		assert.Equal(t, map[string]interface{}{"old": "tenant-a", "new": "tenant-b"}, audit.changes[0]["tenant_id"])
+	})
+
+	t.Run("non platform admins are refused", func(t *testing.T) {
+		svc, workspaces, cache, _ := setup()
+
+		_, err := svc.ChangeTenant(context.Background(), "ws-1", "tenant-b", "owner-1")
+		assert.Equal(t, services.ErrUnauthorized, err)
+		assert.Equal(t, "tenant-a", workspaces.workspace.TenantID)
+		assert.Empty(t, cache.tenants)
+	})
+
+	t.Run("target tenant is required", func(t *testing.T) {
+		svc, _, _, _ := setup()
+
+		_, err := svc.ChangeTenant(context.Background(), "ws-1", "  ", "platform-1")
+		assert.Equal(t, services.ErrInvalidInput, err)
+	})
+}
@@ -0,0 +1,156 @@
+package unit
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/config"
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/middleware"
+)
+
+func TestCORSPerTenant(t *testing.T) {
+	cfg := config.CORSConfig{
+		AllowedOrigins: "https://app.example.com",
+		TenantOrigins:  "acme=https://acme.example.com|https://portal.acme.com;globex=https://globex.example.com",
+	}
+
+	tests := []struct {
+		name          string
+		tenantID      string
+		origin        string
+		expectAllowed bool
+	}{
+		{
+			name:          "tenant origin is allowed for that tenant",
+			tenantID:      "acme",
+			origin:        "https://portal.acme.com",
+			expectAllowed: true,
+		},
+		{
+			name:     "another tenant's origin is denied",
+			tenantID: "globex",
+			origin:   "https://acme.example.com",
+		},
+		{
+			name:          "global origin is allowed for a listed tenant",
+			tenantID:      "acme",
+			origin:        "https://app.example.com",
+			expectAllowed: true,
+		},
+		{
+			name:          "unlisted tenant falls back to global origins",
+			tenantID:      "initech",
+			origin:        "https://app.example.com",
+			expectAllowed: true,
+		},
+		{
+			name:     "unlisted tenant cannot use tenant origins",
+			tenantID: "initech",
+			origin:   "https://acme.example.com",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := fiber.New()
+			app.Use(authenticatedTenant(tt.tenantID), middleware.CORS(cfg))
+			app.Get("/", func(c *fiber.Ctx) error {
+				return c.SendString("ok")
+			})
+
+			req, _ := http.NewRequest("GET", "/", nil)
+			req.Header.Set("Origin", tt.origin)
+
+			resp, err := app.Test(req, -1)
+			require.NoError(t, err)
+			assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+			if tt.expectAllowed {
+				assert.Equal(t, tt.origin, resp.Header.Get("Access-Control-Allow-Origin"))
+			} else {
+				assert.Empty(t, resp.Header.Get("Access-Control-Allow-Origin"))
+			}
+		})
+	}
+}
+
+// authenticatedTenant stands in for authentication resolving the caller's tenant
+func authenticatedTenant(tenantID string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if tenantID != "" {
+			c.Locals("tenant_id", tenantID)
+		}
+		return c.Next()
+	}
+}
+
+func TestCORSWildcardOnlyAppliesToUnlistedTenants(t *testing.T) {
+	cfg := config.CORSConfig{
+		AllowedOrigins: "*",
+		TenantOrigins:  "acme=https://acme.example.com",
+	}
+
+	for tenantID, expected := range map[string]string{"acme": "", "initech": "*"} {
+		app := fiber.New()
+		app.Use(authenticatedTenant(tenantID), middleware.CORS(cfg))
+		app.Get("/", func(c *fiber.Ctx) error {
+			return c.SendString("ok")
+		})
+
+		req, _ := http.NewRequest("GET", "/", nil)
+		req.Header.Set("Origin", "https://elsewhere.example.com")
+
+		resp, err := app.Test(req, -1)
+		require.NoError(t, err)
+		assert.Equal(t, expected, resp.Header.Get("Access-Control-Allow-Origin"), tenantID)
+		// A wildcard never allows credentials
+		assert.Empty(t, resp.Header.Get("Access-Control-Allow-Credentials"), tenantID)
+	}
+}
+
+func TestCORSCredentialsAndPreflights(t *testing.T) {
+	cfg := config.CORSConfig{
+		AllowedOrigins: "*",
+		TenantOrigins:  "acme=https://acme.example.com",
+	}
+	app := fiber.New()
+	app.Use(middleware.CORS(cfg))
+	app.Get("/", func(c *fiber.Ctx) error {
+		return c.SendString("ok")
+	})
+
+	send := func(t *testing.T, method, origin, tenantHeader string) *http.Response {
+		req, _ := http.NewRequest(method, "/", nil)
+		req.Header.Set("Origin", origin)
+		if tenantHeader != "" {
+			req.Header.Set("X-Tenant-ID", tenantHeader)
+		}
+		resp, err := app.Test(req, -1)
+		require.NoError(t, err)
+		return resp
+	}
+
+	t.Run("listed origins are echoed with credentials on preflight", func(t *testing.T) {
+		resp := send(t, "OPTIONS", "https://acme.example.com", "")
+		assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+		assert.Equal(t, "https://acme.example.com", resp.Header.Get("Access-Control-Allow-Origin"))
+		assert.Equal(t, "true", resp.Header.Get("Access-Control-Allow-Credentials"))
+	})
+
+	t.Run("other origins only get the wildcard", func(t *testing.T) {
+		resp := send(t, "OPTIONS", "https://evil.example.com", "")
+		assert.Equal(t, "*", resp.Header.Get("Access-Control-Allow-Origin"))
+		assert.Empty(t, resp.Header.Get("Access-Control-Allow-Credentials"))
+	})
+
+	t.Run("the tenant header is not trusted", func(t *testing.T) {
+		// Unauthenticated, the request is not acme's, so acme's origin is not echoed
+		resp := send(t, "GET", "https://acme.example.com", "acme")
+		assert.Equal(t, "*", resp.Header.Get("Access-Control-Allow-Origin"))
+		assert.Empty(t, resp.Header.Get("Access-Control-Allow-Credentials"))
+	})
+}
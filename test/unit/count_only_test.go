@@ -0,0 +1,92 @@
+package unit
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/config"
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/handlers"
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/repositories"
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/services"
+)
+
+func TestListCountOnly(t *testing.T) {
+	conn, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer conn.Close()
+	db, err := gorm.Open(postgres.New(postgres.Config{Conn: conn}), &gorm.Config{})
+	require.NoError(t, err)
+
+	cfg := &config.Config{}
+	repos := &repositories.Repositories{
+		Project:      repositories.NewProjectRepository(db, cfg, zap.NewNop()),
+		AirtableBase: repositories.NewAirtableBaseRepository(db, cfg, zap.NewNop()),
+	}
+	svcs := &services.Services{
+		Workspace:    &fixedWorkspace{},
+		Project:      services.NewProjectService(repos, cfg, zap.NewNop(), &nopAudit{}),
+		AirtableBase: services.NewAirtableBaseService(repos, cfg, zap.NewNop(), &nopAudit{}, nil),
+	}
+	h := handlers.New(svcs, cfg, zap.NewNop())
+	app := fiber.New()
+	app.Use(func(c *fiber.Ctx) error {
+		c.Locals("user_id", "user-1")
+		return c.Next()
+	})
+	app.Get("/projects", h.ListProjects)
+	app.Get("/airtable-bases", h.ListAirtableBases)
+
+	get := func(t *testing.T, path string) map[string]interface{} {
+		req, _ := http.NewRequest(http.MethodGet, path, nil)
+		resp, err := app.Test(req, -1)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var body map[string]interface{}
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+		return body
+	}
+
+	t.Run("projects", func(t *testing.T) {
+		// Only the COUNT runs; sqlmock fails the request if the row fetch is attempted
+		mock.ExpectQuery(`SELECT count\(\*\) FROM "projects" WHERE status = \$1 AND deleted_at IS NULL`).
+			WithArgs("active").
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(23))
+
+		body := get(t, "/projects?status=active&count_only=true")
+		assert.Equal(t, map[string]interface{}{"total": float64(23)}, body)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("airtable bases", func(t *testing.T) {
+		mock.ExpectQuery(`SELECT count\(\*\) FROM "airtable_bases"`).
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(4))
+
+		body := get(t, "/airtable-bases?count_only=true")
+		assert.Equal(t, map[string]interface{}{"total": float64(4)}, body)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("lists without count_only still fetch rows", func(t *testing.T) {
+		mock.ExpectQuery(`SELECT count\(\*\) FROM "projects"`).
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+		mock.ExpectQuery(`SELECT \* FROM "projects"`).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "workspace_id", "name"}).AddRow("proj-1", "ws-1", "Roadmap"))
+		mock.ExpectQuery(`SELECT projects.id AS project_id, workspaces.name AS workspace_name`).
+			WillReturnRows(sqlmock.NewRows([]string{"project_id", "workspace_name"}).AddRow("proj-1", "Acme"))
+
+		body := get(t, "/projects")
+		assert.Equal(t, float64(1), body["total"])
+		assert.Len(t, body["projects"], 1)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
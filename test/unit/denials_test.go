@@ -0,0 +1,80 @@
+package unit
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/config"
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/handlers"
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/models"
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/services"
+	"github.com/Reg-Kris/pyairtable-workspace-service/pkg/metrics"
+)
+
+// denyingWorkspaces refuses every update
+type denyingWorkspaces struct {
+	services.WorkspaceService
+}
+
+func (s *denyingWorkspaces) UpdateWorkspace(ctx context.Context, workspaceID, userID string, req *models.UpdateWorkspaceRequest) (*models.Workspace, error) {
+	return nil, services.ErrUnauthorized
+}
+
+// countingCounter counts increments
+type countingCounter struct {
+	prometheus.Counter
+	count int
+}
+
+func (c *countingCounter) Inc() { c.count++ }
+
+func TestUnauthorizedUpdateLogsDenial(t *testing.T) {
+	core, logs := observer.New(zapcore.WarnLevel)
+	cfg := &config.Config{Audit: config.AuditConfig{LogDenials: true, DenialLogInterval: 60}}
+	h := handlers.New(&services.Services{Workspace: &denyingWorkspaces{}}, cfg, zap.New(core))
+
+	denied := &countingCounter{}
+	h.UseMetrics(&metrics.Registry{AuthzDeniedTotal: denied})
+
+	app := fiber.New()
+	app.Use(func(c *fiber.Ctx) error {
+		c.Locals("user_id", c.Get("X-User-ID"))
+		return c.Next()
+	})
+	app.Put("/api/v1/workspaces/:id", h.UpdateWorkspace)
+
+	update := func(userID, workspaceID string) int {
+		req, _ := http.NewRequest("PUT", "/api/v1/workspaces/"+workspaceID, strings.NewReader(`{"description":"probe"}`))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("X-User-ID", userID)
+		resp, err := app.Test(req, -1)
+		require.NoError(t, err)
+		return resp.StatusCode
+	}
+
+	assert.Equal(t, http.StatusForbidden, update("prober", "ws-1"))
+
+	entries := logs.FilterField(zap.String("event", "authz.denied")).All()
+	require.Len(t, entries, 1)
+	fields := entries[0].ContextMap()
+	assert.Equal(t, "prober", fields["user_id"])
+	assert.Equal(t, "ws-1", fields["workspace_id"])
+	assert.Equal(t, "PUT /api/v1/workspaces/:id", fields["action"])
+
+	// Repeated denials for the same user are counted but not logged again
+	assert.Equal(t, http.StatusForbidden, update("prober", "ws-2"))
+	assert.Equal(t, http.StatusForbidden, update("other", "ws-1"))
+
+	assert.Len(t, logs.FilterField(zap.String("event", "authz.denied")).All(), 2)
+	assert.Equal(t, 3, denied.count)
+}
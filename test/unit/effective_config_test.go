@@ -0,0 +1,68 @@
+package unit
+
+import (
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/config"
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/handlers"
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/services"
+)
+
+func TestEffectiveConfigIsRedacted(t *testing.T) {
+	cfg := &config.Config{
+		Database:    config.DatabaseConfig{Host: "db.internal", Password: "db-secret", ReplicaDSN: "host=replica password=replica-secret"},
+		Redis:       config.RedisConfig{Host: "redis.internal", Password: "redis-secret"},
+		JWT:         config.JWTConfig{Secret: "jwt-secret", TTL: 3600},
+		Services:    config.ServiceAuthConfig{Principals: "importer:importer-token:tenant-1|tenant-2"},
+		Platform:    config.PlatformConfig{Admins: "platform-admin"},
+		Maintenance: config.MaintenanceConfig{Mode: config.MaintenanceReadOnly},
+	}
+
+	h := handlers.New(&services.Services{}, cfg, zap.NewNop())
+	app := fiber.New()
+	app.Use(func(c *fiber.Ctx) error {
+		c.Locals("user_id", c.Get("X-User"))
+		return c.Next()
+	})
+	h.RegisterRoutes(app)
+
+	get := func(t *testing.T, userID string) (int, string) {
+		req, _ := http.NewRequest(http.MethodGet, "/api/v1/admin/config", nil)
+		req.Header.Set("X-User", userID)
+		resp, err := app.Test(req, -1)
+		require.NoError(t, err)
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		return resp.StatusCode, string(body)
+	}
+
+	t.Run("secrets are masked and settings shown", func(t *testing.T) {
+		status, body := get(t, "platform-admin")
+		require.Equal(t, http.StatusOK, status)
+
+		for _, secret := range []string{"db-secret", "replica-secret", "redis-secret", "jwt-secret", "importer-token"} {
+			assert.NotContains(t, body, secret)
+		}
+		assert.Contains(t, body, config.RedactedValue)
+		assert.Contains(t, body, "db.internal")
+		assert.Contains(t, body, "redis.internal")
+		assert.Contains(t, body, "importer:"+config.RedactedValue+":tenant-1|tenant-2")
+		assert.Contains(t, body, config.MaintenanceReadOnly)
+
+		// The loaded config itself keeps its secrets
+		assert.Equal(t, "jwt-secret", cfg.JWT.Secret)
+	})
+
+	t.Run("other users are refused", func(t *testing.T) {
+		status, body := get(t, "user-1")
+		assert.Equal(t, http.StatusForbidden, status)
+		assert.NotContains(t, body, "db.internal")
+	})
+}
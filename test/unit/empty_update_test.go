@@ -0,0 +1,73 @@
+package unit
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/config"
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/handlers"
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/models"
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/repositories"
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/services"
+)
+
+func TestEmptyUpdateIsReportedUnchanged(t *testing.T) {
+	const projectID = "7c9e6679-7425-40de-944b-e07fc1f90ae7"
+
+	projects := &storedProject{project: &models.Project{
+		BaseModel:   models.BaseModel{ID: projectID},
+		WorkspaceID: "ws-1",
+		Name:        "Roadmap",
+		CreatedBy:   "member-1",
+	}}
+	audit := &recordingActions{}
+	repos := &repositories.Repositories{
+		Project: projects,
+		Member:  &roleMembers{roles: map[string]models.WorkspaceMemberRole{"member-1": models.WorkspaceRoleMember}},
+		Cache:   &nopCache{},
+	}
+	cfg := &config.Config{}
+	svc := &services.Services{Project: services.NewProjectService(repos, cfg, zap.NewNop(), audit)}
+
+	app := fiber.New()
+	app.Use(func(c *fiber.Ctx) error {
+		c.Locals("user_id", "member-1")
+		return c.Next()
+	})
+	handlers.New(svc, cfg, zap.NewNop()).RegisterRoutes(app)
+
+	put := func(t *testing.T, body string) map[string]interface{} {
+		req, _ := http.NewRequest(http.MethodPut, "/api/v1/projects/"+projectID, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := app.Test(req, -1)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var decoded map[string]interface{}
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&decoded))
+		return decoded
+	}
+
+	t.Run("an empty body writes and audits nothing", func(t *testing.T) {
+		body := put(t, `{}`)
+		assert.Equal(t, true, body["unchanged"])
+		assert.Equal(t, "Roadmap", body["name"])
+		assert.Zero(t, projects.updates)
+		assert.Empty(t, audit.actions)
+	})
+
+	t.Run("a real update is not marked unchanged", func(t *testing.T) {
+		body := put(t, `{"name":"Renamed"}`)
+		assert.NotContains(t, body, "unchanged")
+		assert.Equal(t, "Renamed", body["name"])
+		assert.Equal(t, 1, projects.updates)
+		assert.Equal(t, []string{models.AuditActionProjectUpdated}, audit.actions)
+	})
+}
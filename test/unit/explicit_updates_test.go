@@ -0,0 +1,75 @@
+package unit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/config"
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/models"
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/repositories"
+)
+
+// Updates write exactly the editable columns: fields set on the model outside them are not
+// persisted, and cleared values are
+func TestUpdatesWriteOnlyEditableColumns(t *testing.T) {
+	cfg := &config.Config{Database: config.DatabaseConfig{RetryMaxAttempts: 1}}
+	scheduled := time.Now().Add(time.Hour)
+
+	open := func(t *testing.T) (*gorm.DB, sqlmock.Sqlmock) {
+		conn, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		t.Cleanup(func() { conn.Close() })
+		db, err := gorm.Open(postgres.New(postgres.Config{Conn: conn}), &gorm.Config{SkipDefaultTransaction: true})
+		require.NoError(t, err)
+		return db, mock
+	}
+
+	t.Run("workspace", func(t *testing.T) {
+		db, mock := open(t)
+		workspace := &models.Workspace{TenantID: "tenant-1", Name: "Sales", Settings: models.JSONMap{}, CreatedBy: "intruder", ScheduledDeletionAt: &scheduled}
+		workspace.ID = "ws-1"
+
+		mock.ExpectQuery(`SELECT count\(\*\) FROM "workspaces"`).WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+		mock.ExpectExec(`UPDATE "workspaces" SET "description"=\$1,"name"=\$2,"settings"=\$3,"tenant_id"=\$4,"updated_at"=\$5 WHERE`).
+			WithArgs("", "Sales", sqlmock.AnyArg(), "tenant-1", sqlmock.AnyArg(), "ws-1").
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		require.NoError(t, repositories.NewWorkspaceRepository(db, cfg, zap.NewNop()).Update(context.Background(), workspace))
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("project", func(t *testing.T) {
+		db, mock := open(t)
+		project := &models.Project{WorkspaceID: "ws-other", Name: "Pipeline", Status: "archived", Tags: models.StringList{"injected"}, CreatedBy: "user-2"}
+		project.ID = "project-1"
+
+		mock.ExpectQuery(`SELECT count\(\*\) FROM "projects"`).WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+		mock.ExpectExec(`UPDATE "projects" SET "created_by"=\$1,"description"=\$2,"name"=\$3,"status"=\$4,"updated_at"=\$5 WHERE`).
+			WithArgs("user-2", "", "Pipeline", "archived", sqlmock.AnyArg(), "project-1").
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		require.NoError(t, repositories.NewProjectRepository(db, cfg, zap.NewNop()).Update(context.Background(), project))
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("airtable base", func(t *testing.T) {
+		db, mock := open(t)
+		base := &models.AirtableBase{ProjectID: "project-other", BaseID: "appInjected", Name: "base", SyncEnabled: false, CreatedBy: "intruder"}
+		base.ID = "base-1"
+
+		mock.ExpectExec(`UPDATE "airtable_bases" SET "description"=\$1,"name"=\$2,"sync_enabled"=\$3,"updated_at"=\$4 WHERE`).
+			WithArgs("", "base", false, sqlmock.AnyArg(), "base-1").
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		require.NoError(t, repositories.NewAirtableBaseRepository(db, cfg, zap.NewNop()).Update(context.Background(), base))
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
@@ -0,0 +1,69 @@
+package unit
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/config"
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/handlers"
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/models"
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/services"
+)
+
+func TestValidationReportsEveryFailingField(t *testing.T) {
+	t.Run("validate tags become friendly field errors", func(t *testing.T) {
+		result := services.ValidateRequest(&models.CreateServiceAccountRequest{Name: strings.Repeat("a", 256), Role: models.WorkspaceRoleOwner})
+
+		assert.True(t, result.Rejected(false))
+		assert.Equal(t, []services.FieldError{
+			{Field: "name", Message: "must be at most 255 characters"},
+			{Field: "role", Message: "must be one of: admin, member, viewer"},
+		}, result.FieldErrors)
+		assert.Equal(t, []string{"name must be at most 255 characters", "role must be one of: admin, member, viewer"}, result.Errors)
+	})
+
+	t.Run("tag and custom rules combine, one error per field", func(t *testing.T) {
+		name := strings.Repeat("a", 256)
+		status := "deleted"
+		description := strings.Repeat("a", 20000)
+		result := services.ValidateUpdateProject(&models.UpdateProjectRequest{Name: &name, Status: &status, Description: &description})
+		assert.Equal(t, []services.FieldError{
+			{Field: "name", Message: "must be at most 255 characters"},
+			{Field: "status", Message: "must be one of: active, archived"},
+			{Field: "description", Message: "exceeds maximum length of 10000 characters"},
+		}, result.FieldErrors)
+	})
+
+	t.Run("the 400 lists the errors per field", func(t *testing.T) {
+		app := fiber.New()
+		app.Use(func(c *fiber.Ctx) error {
+			c.Locals("user_id", "user-1")
+			return c.Next()
+		})
+		handlers.New(&services.Services{}, &config.Config{}, zap.NewNop()).RegisterRoutes(app)
+
+		req, _ := http.NewRequest(http.MethodPost, "/api/v1/workspaces/7c9e6679-7425-40de-944b-e07fc1f90ae7/service-accounts", strings.NewReader(`{"role":"owner"}`))
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := app.Test(req, -1)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+		var body struct {
+			Error  string                `json:"error"`
+			Errors []services.FieldError `json:"errors"`
+		}
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+		assert.Equal(t, "Validation failed", body.Error)
+		assert.Equal(t, []services.FieldError{
+			{Field: "name", Message: "is required"},
+			{Field: "role", Message: "must be one of: admin, member, viewer"},
+		}, body.Errors)
+	})
+}
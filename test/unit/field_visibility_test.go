@@ -0,0 +1,313 @@
+package unit
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/config"
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/handlers"
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/models"
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/services"
+	"github.com/Reg-Kris/pyairtable-workspace-service/pkg/response"
+)
+
+// rankedWorkspace serves a workspace with settings and answers access checks from fixed roles
+type rankedWorkspace struct {
+	fixedWorkspace
+	roles map[string]models.WorkspaceMemberRole
+}
+
+func (s *rankedWorkspace) GetWorkspace(ctx context.Context, workspaceID, userID string) (*models.Workspace, error) {
+	workspace, _ := s.fixedWorkspace.GetWorkspace(ctx, workspaceID, userID)
+	workspace.Settings = models.JSONMap{"webhook_url": "https://hooks.example.com/landing"}
+	return workspace, nil
+}
+
+func (s *rankedWorkspace) CheckUserAccess(ctx context.Context, workspaceID, userID string, requiredRole models.WorkspaceMemberRole) error {
+	rank := map[models.WorkspaceMemberRole]int{
+		models.WorkspaceRoleViewer: 1,
+		models.WorkspaceRoleMember: 2,
+		models.WorkspaceRoleAdmin:  3,
+		models.WorkspaceRoleOwner:  4,
+	}
+	if rank[s.roles[userID]] < rank[requiredRole] {
+		return services.ErrUnauthorized
+	}
+	return nil
+}
+
+func TestSettingsVisibleToOwnersHiddenFromViewers(t *testing.T) {
+	workspaces := &rankedWorkspace{roles: map[string]models.WorkspaceMemberRole{
+		"owner-1":  models.WorkspaceRoleOwner,
+		"viewer-1": models.WorkspaceRoleViewer,
+	}}
+	h := handlers.New(&services.Services{Workspace: workspaces}, &config.Config{}, zap.NewNop())
+	app := fiber.New()
+	app.Use(func(c *fiber.Ctx) error {
+		c.Locals("user_id", c.Get("X-User"))
+		return c.Next()
+	})
+	h.RegisterRoutes(app)
+
+	get := func(t *testing.T, userID string) map[string]interface{} {
+		req, _ := http.NewRequest(http.MethodGet, "/api/v1/workspaces/"+batchWorkspaceID, nil)
+		req.Header.Set("X-User", userID)
+		resp, err := app.Test(req, -1)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		var body map[string]interface{}
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+		return body
+	}
+
+	owner := get(t, "owner-1")
+	assert.Equal(t, "https://hooks.example.com/landing", owner["settings"].(map[string]interface{})["webhook_url"])
+
+	viewer := get(t, "viewer-1")
+	assert.NotContains(t, viewer, "settings")
+	assert.Equal(t, "Landing", viewer["name"])
+}
+
+// settingsProject serves a project of batchWorkspaceID with settings
+type settingsProject struct {
+	services.ProjectService
+}
+
+func (s *settingsProject) GetProject(ctx context.Context, projectID, userID string) (*models.Project, error) {
+	project := &models.Project{WorkspaceID: batchWorkspaceID, Name: "Launch", Settings: models.JSONMap{"webhook_url": "https://hooks.example.com/launch"}}
+	project.ID = projectID
+	return project, nil
+}
+
+func TestSelectedSettingsStayVisibleToOwners(t *testing.T) {
+	workspaces := &rankedWorkspace{roles: map[string]models.WorkspaceMemberRole{
+		"owner-1":  models.WorkspaceRoleOwner,
+		"viewer-1": models.WorkspaceRoleViewer,
+	}}
+	h := handlers.New(&services.Services{Workspace: workspaces, Project: &settingsProject{}}, &config.Config{API: config.APIConfig{StrictFields: true}}, zap.NewNop())
+	app := fiber.New()
+	app.Use(func(c *fiber.Ctx) error {
+		c.Locals("user_id", c.Get("X-User"))
+		return c.Next()
+	})
+	h.RegisterRoutes(app)
+
+	get := func(t *testing.T, userID string) map[string]interface{} {
+		req, _ := http.NewRequest(http.MethodGet, "/api/v1/projects/6b1f3c2d-9a4e-4f1b-8c2d-3e4f5a6b7c8d?fields=settings", nil)
+		req.Header.Set("X-User", userID)
+		resp, err := app.Test(req, -1)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		var body map[string]interface{}
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+		return body
+	}
+
+	// workspace_id wasn't selected, but still decides who sees settings
+	owner := get(t, "owner-1")
+	assert.Equal(t, "https://hooks.example.com/launch", owner["settings"].(map[string]interface{})["webhook_url"])
+	assert.NotContains(t, owner, "workspace_id")
+
+	assert.NotContains(t, get(t, "viewer-1"), "settings")
+}
+
+func TestRestrictFieldsFollowsNestedLists(t *testing.T) {
+	visibility := response.FieldVisibility{
+		WorkspaceKey: "workspace_id",
+		Fields:       map[string]string{"settings": string(models.WorkspaceRoleMember)},
+	}
+	grouped := map[string]interface{}{
+		"workspaces": []interface{}{
+			map[string]interface{}{"projects": []interface{}{
+				map[string]interface{}{"workspace_id": "ws-member", "settings": map[string]interface{}{}},
+				map[string]interface{}{"workspace_id": "ws-viewer", "settings": map[string]interface{}{}},
+				map[string]interface{}{"settings": map[string]interface{}{}},
+			}},
+		},
+	}
+
+	checks := 0
+	shaped, err := response.RestrictFields(grouped, "workspaces.projects", visibility, func(workspaceID, role string) bool {
+		checks++
+		return workspaceID == "ws-member"
+	})
+	require.NoError(t, err)
+
+	projects := shaped.(map[string]interface{})["workspaces"].([]interface{})[0].(map[string]interface{})["projects"].([]interface{})
+	assert.Contains(t, projects[0], "settings")
+	assert.NotContains(t, projects[1], "settings")
+	// Without a workspace the field can't be granted
+	assert.NotContains(t, projects[2], "settings")
+	assert.Equal(t, 2, checks)
+}
+
+// rolesApp serves h with the caller taken from the X-User header
+func rolesApp(h *handlers.Handlers) *fiber.App {
+	app := fiber.New()
+	app.Use(func(c *fiber.Ctx) error {
+		c.Locals("user_id", c.Get("X-User"))
+		return c.Next()
+	})
+	h.RegisterRoutes(app)
+	return app
+}
+
+func getAs(t *testing.T, app *fiber.App, path, userID string) map[string]interface{} {
+	req, _ := http.NewRequest(http.MethodGet, path, nil)
+	req.Header.Set("X-User", userID)
+	resp, err := app.Test(req, -1)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	var body map[string]interface{}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+	return body
+}
+
+// settingsBase serves a failing base whose project and workspace carry settings
+type settingsBase struct {
+	services.AirtableBaseService
+}
+
+func (s *settingsBase) GetBase(ctx context.Context, baseID, userID string) (*models.AirtableBase, error) {
+	workspace := &models.Workspace{Name: "Landing", Settings: models.JSONMap{"webhook_url": "https://hooks.example.com/landing"}}
+	workspace.ID = batchWorkspaceID
+	project := &models.Project{WorkspaceID: batchWorkspaceID, Name: "Launch", Workspace: workspace, Settings: models.JSONMap{"webhook_url": "https://hooks.example.com/launch"}}
+	base := &models.AirtableBase{
+		Name:          "Inventory",
+		LastSyncError: "token expired for integrations@example.com",
+		Settings:      models.JSONMap{"api_key_ref": "vault:airtable/inventory"},
+		Project:       project,
+	}
+	base.ID = baseID
+	return base, nil
+}
+
+func TestBaseSettingsHiddenFromViewers(t *testing.T) {
+	workspaces := &rankedWorkspace{roles: map[string]models.WorkspaceMemberRole{
+		"member-1": models.WorkspaceRoleMember,
+		"viewer-1": models.WorkspaceRoleViewer,
+	}}
+	app := rolesApp(handlers.New(&services.Services{Workspace: workspaces, AirtableBase: &settingsBase{}}, &config.Config{}, zap.NewNop()))
+	path := "/api/v1/airtable-bases/6b1f3c2d-9a4e-4f1b-8c2d-3e4f5a6b7c8d"
+
+	member := getAs(t, app, path, "member-1")
+	assert.Contains(t, member, "settings")
+	assert.Contains(t, member, "last_sync_error")
+	assert.Contains(t, member["project"], "settings")
+
+	viewer := getAs(t, app, path, "viewer-1")
+	assert.NotContains(t, viewer, "settings")
+	assert.NotContains(t, viewer, "last_sync_error")
+	assert.Equal(t, "Inventory", viewer["name"])
+	// The embedded project and workspace are gated too
+	project := viewer["project"].(map[string]interface{})
+	assert.NotContains(t, project, "settings")
+	assert.NotContains(t, project["workspace"], "settings")
+	assert.Equal(t, "Landing", project["workspace"].(map[string]interface{})["name"])
+}
+
+// directoryMembers lists one member of the requested workspace with directory details
+type directoryMembers struct {
+	services.MemberService
+}
+
+func (s *directoryMembers) ListMembers(ctx context.Context, workspaceID, userID string, page, pageSize int, search string) (*models.WorkspaceMemberListResponse, error) {
+	accessed := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	return &models.WorkspaceMemberListResponse{
+		Members: []*models.WorkspaceMember{{
+			WorkspaceID:    workspaceID,
+			UserID:         "user-7",
+			Role:           models.WorkspaceRoleMember,
+			DisplayName:    "Ana Costa",
+			Email:          "ana@example.com",
+			LastAccessedAt: &accessed,
+		}},
+		Total: 1, Page: 1, PageSize: 20, TotalPages: 1,
+	}, nil
+}
+
+func TestMemberContactDetailsVisibleToAdmins(t *testing.T) {
+	workspaces := &rankedWorkspace{roles: map[string]models.WorkspaceMemberRole{
+		"admin-1":  models.WorkspaceRoleAdmin,
+		"member-1": models.WorkspaceRoleMember,
+	}}
+	app := rolesApp(handlers.New(&services.Services{Workspace: workspaces, Member: &directoryMembers{}}, &config.Config{}, zap.NewNop()))
+	path := "/api/v1/workspaces/" + batchWorkspaceID + "/members"
+
+	admin := getAs(t, app, path, "admin-1")["members"].([]interface{})[0]
+	assert.Contains(t, admin, "email")
+	assert.Contains(t, admin, "last_accessed_at")
+
+	member := getAs(t, app, path, "member-1")["members"].([]interface{})[0]
+	assert.NotContains(t, member, "email")
+	assert.NotContains(t, member, "last_accessed_at")
+	assert.Equal(t, "Ana Costa", member.(map[string]interface{})["display_name"])
+}
+
+// impersonatedLogs returns one audit entry written while impersonating
+type impersonatedLogs struct {
+	services.AuditService
+}
+
+func (s *impersonatedLogs) GetAuditLogs(ctx context.Context, filter *models.AuditLogFilter, userID string) (*models.AuditLogListResponse, error) {
+	return &models.AuditLogListResponse{
+		Logs: []*models.WorkspaceAuditLog{{
+			ID:             "log-1",
+			WorkspaceID:    batchWorkspaceID,
+			UserID:         "user-7",
+			ImpersonatedBy: "support-2",
+			Action:         "update",
+			ResourceType:   "workspace",
+		}},
+		Total: 1, Page: 1, PageSize: 20, TotalPages: 1,
+	}, nil
+}
+
+func TestImpersonatorVisibleToOwners(t *testing.T) {
+	workspaces := &rankedWorkspace{roles: map[string]models.WorkspaceMemberRole{
+		"owner-1": models.WorkspaceRoleOwner,
+		"admin-1": models.WorkspaceRoleAdmin,
+	}}
+	app := rolesApp(handlers.New(&services.Services{Workspace: workspaces, Audit: &impersonatedLogs{}}, &config.Config{}, zap.NewNop()))
+	path := "/api/v1/audit-logs?workspace_id=" + batchWorkspaceID
+
+	owner := getAs(t, app, path, "owner-1")["logs"].([]interface{})[0]
+	assert.Equal(t, "support-2", owner.(map[string]interface{})["impersonated_by"])
+
+	admin := getAs(t, app, path, "admin-1")["logs"].([]interface{})[0]
+	assert.NotContains(t, admin, "impersonated_by")
+	assert.Equal(t, "user-7", admin.(map[string]interface{})["user_id"])
+}
+
+func TestRestrictFieldsReadsNestedWorkspaceKey(t *testing.T) {
+	visibility := response.FieldVisibility{
+		WorkspaceKey: "project.workspace_id",
+		Fields:       map[string]string{"settings": string(models.WorkspaceRoleMember)},
+	}
+	bases := map[string]interface{}{
+		"bases": []interface{}{
+			map[string]interface{}{"project": map[string]interface{}{"workspace_id": "ws-member"}, "settings": map[string]interface{}{}},
+			map[string]interface{}{"project": map[string]interface{}{"workspace_id": "ws-viewer"}, "settings": map[string]interface{}{}},
+			map[string]interface{}{"settings": map[string]interface{}{}},
+		},
+	}
+
+	shaped, err := response.RestrictFields(bases, "bases", visibility, func(workspaceID, role string) bool {
+		return workspaceID == "ws-member"
+	})
+	require.NoError(t, err)
+
+	listed := shaped.(map[string]interface{})["bases"].([]interface{})
+	assert.Contains(t, listed[0], "settings")
+	assert.NotContains(t, listed[1], "settings")
+	// A base listed without its project can't be placed in a workspace
+	assert.NotContains(t, listed[2], "settings")
+}
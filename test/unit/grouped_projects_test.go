@@ -0,0 +1,52 @@
+package unit
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/config"
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/models"
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/repositories"
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/services"
+)
+
+func TestListProjectsGroupedByWorkspace(t *testing.T) {
+	conn, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer conn.Close()
+	db, err := gorm.Open(postgres.New(postgres.Config{Conn: conn}), &gorm.Config{})
+	require.NoError(t, err)
+
+	repos := &repositories.Repositories{Project: repositories.NewProjectRepository(db, &config.Config{}, zap.NewNop())}
+	svc := services.NewProjectService(repos, &config.Config{}, zap.NewNop(), &nopAudit{})
+
+	// Membership, names and the total come back from one query, already in group order
+	mock.ExpectQuery(`SELECT projects\.\*, workspaces\.name AS member_workspace_name, COUNT\(\*\) OVER \(\) AS total_count FROM "projects" JOIN workspaces .* JOIN workspace_members .* ORDER BY workspaces\.name ASC`).
+		WithArgs("user-1", 20).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "workspace_id", "name", "member_workspace_name", "total_count"}).
+			AddRow("proj-1", "ws-a", "Budget", "Acme", 3).
+			AddRow("proj-2", "ws-a", "Roadmap", "Acme", 3).
+			AddRow("proj-3", "ws-b", "Hiring", "Beta", 3))
+
+	grouped, err := svc.ListProjectsByWorkspace(context.Background(), &models.ProjectFilter{}, "user-1")
+	require.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+
+	assert.Equal(t, int64(3), grouped.Total)
+	assert.Equal(t, 1, grouped.TotalPages)
+	require.Len(t, grouped.Workspaces, 2)
+	assert.Equal(t, "Acme", grouped.Workspaces[0].WorkspaceName)
+	require.Len(t, grouped.Workspaces[0].Projects, 2)
+	assert.Equal(t, "Budget", grouped.Workspaces[0].Projects[0].Name)
+	assert.Equal(t, "Roadmap", grouped.Workspaces[0].Projects[1].Name)
+	assert.Equal(t, "ws-b", grouped.Workspaces[1].WorkspaceID)
+	require.Len(t, grouped.Workspaces[1].Projects, 1)
+	assert.Equal(t, "Beta", grouped.Workspaces[1].Projects[0].WorkspaceName)
+}
@@ -7,9 +7,6 @@ import (
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/stretchr/testify/assert"
-
-	"github.com/Reg-Kris/pyairtable-workspace-service/internal/handlers"
-	"github.com/Reg-Kris/pyairtable-workspace-service/internal/services"
 )
 
 func TestPingHandler(t *testing.T) {
@@ -75,6 +72,7 @@ func TestProtectedHandler(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			// Create a new Fiber app
 			app := fiber.New()
+			_ = app
 			
 			// Skip test if handlers not implemented
 			t.Skip("Implement when handlers are ready")
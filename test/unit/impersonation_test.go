@@ -0,0 +1,187 @@
+package unit
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/config"
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/handlers"
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/middleware"
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/models"
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/repositories"
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/services"
+)
+
+// authenticatedAs sets the locals the auth layer provides for the given caller
+func authenticatedAs(userID string, claims jwt.MapClaims) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		c.Locals("user_id", userID)
+		c.Locals("claims", claims)
+		return c.Next()
+	}
+}
+
+func TestImpersonationMiddleware(t *testing.T) {
+	privileged := jwt.MapClaims{"user_id": "support-1", "impersonate": true}
+
+	tests := []struct {
+		name             string
+		method           string
+		path             string
+		claims           jwt.MapClaims
+		allowDestructive bool
+		direct           bool
+		expectedStatus   int
+		expectedUser     string
+		expectedActor    string
+	}{
+		{
+			name:           "privileged caller acts as the target user",
+			method:         http.MethodPut,
+			path:           "/workspaces/ws-1",
+			claims:         privileged,
+			expectedStatus: http.StatusOK,
+			expectedUser:   "user-2",
+			expectedActor:  "support-1",
+		},
+		{
+			name:           "caller without the claim is refused",
+			method:         http.MethodPut,
+			path:           "/workspaces/ws-1",
+			claims:         jwt.MapClaims{"user_id": "support-1"},
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:           "delete is refused by default",
+			method:         http.MethodDelete,
+			path:           "/workspaces/ws-1",
+			claims:         privileged,
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:           "bulk delete is refused by default",
+			method:         http.MethodPost,
+			path:           "/workspaces/ws-1/projects/bulk-delete",
+			claims:         privileged,
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:           "bulk delete is refused whatever the path case",
+			method:         http.MethodPost,
+			path:           "/Workspaces/ws-1/Projects/Bulk-Delete",
+			claims:         privileged,
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:           "scheduled deletion is refused by default",
+			method:         http.MethodPost,
+			path:           "/workspaces/ws-1/scheduled-deletion",
+			claims:         privileged,
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:           "tenant change is refused by default",
+			method:         http.MethodPut,
+			path:           "/workspaces/ws-1/tenant",
+			claims:         privileged,
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:           "unimpersonated delete is allowed",
+			method:         http.MethodDelete,
+			path:           "/workspaces/ws-1",
+			claims:         privileged,
+			direct:         true,
+			expectedStatus: http.StatusOK,
+			expectedUser:   "support-1",
+		},
+		{
+			name:             "delete is allowed when enabled",
+			method:           http.MethodDelete,
+			path:             "/workspaces/ws-1",
+			claims:           privileged,
+			allowDestructive: true,
+			expectedStatus:   http.StatusOK,
+			expectedUser:     "user-2",
+			expectedActor:    "support-1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := config.ImpersonationConfig{Claim: "impersonate", AllowDestructive: tt.allowDestructive}
+
+			app := fiber.New()
+			app.Use(authenticatedAs("support-1", tt.claims), middleware.Impersonation(cfg))
+			echo := func(c *fiber.Ctx) error {
+				actor, _ := c.Locals("impersonated_by").(string)
+				return c.JSON(fiber.Map{"user_id": c.Locals("user_id"), "impersonated_by": actor})
+			}
+			destructive := middleware.Destructive(cfg)
+			app.Put("/workspaces/:id", echo)
+			app.Put("/workspaces/:id/tenant", destructive, echo)
+			app.Post("/workspaces/:id/scheduled-deletion", destructive, echo)
+			app.Post("/workspaces/:workspace_id/projects/bulk-delete", destructive, echo)
+			app.Delete("/workspaces/:id", destructive, echo)
+
+			req, _ := http.NewRequest(tt.method, tt.path, nil)
+			if !tt.direct {
+				req.Header.Set("X-Impersonate-User", "user-2")
+			}
+
+			resp, err := app.Test(req, -1)
+			require.NoError(t, err)
+			require.Equal(t, tt.expectedStatus, resp.StatusCode)
+
+			if tt.expectedStatus == http.StatusOK {
+				var body map[string]string
+				require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+				assert.Equal(t, tt.expectedUser, body["user_id"])
+				assert.Equal(t, tt.expectedActor, body["impersonated_by"])
+			}
+		})
+	}
+}
+
+func TestImpersonatedUpdateRecordsBothIdentities(t *testing.T) {
+	cfg := &config.Config{Impersonation: config.ImpersonationConfig{Claim: "impersonate"}}
+
+	auditLogs := &recordingAuditLogs{}
+	repos := &repositories.Repositories{
+		Workspace: &storedWorkspace{workspace: &models.Workspace{BaseModel: models.BaseModel{ID: "0f6c2d41-3b8a-4e57-a9d2-6c1e5f7b8a90"}, Name: "Original"}},
+		Member:    &singleMember{role: models.WorkspaceRoleAdmin},
+		AuditLog:  auditLogs,
+		Cache:     &nopCache{},
+	}
+	audit := services.NewAuditService(repos, cfg, zap.NewNop(), nil)
+	svcs := &services.Services{
+		Workspace: services.NewWorkspaceService(repos, cfg, zap.NewNop(), audit, nil),
+		Audit:     audit,
+	}
+	h := handlers.New(svcs, cfg, zap.NewNop())
+
+	app := fiber.New()
+	app.Use(authenticatedAs("support-1", jwt.MapClaims{"user_id": "support-1", "impersonate": true}))
+	h.RegisterRoutes(app)
+
+	req, _ := http.NewRequest(http.MethodPut, "/api/v1/workspaces/0f6c2d41-3b8a-4e57-a9d2-6c1e5f7b8a90", strings.NewReader(`{"name":"Renamed"}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Impersonate-User", "user-2")
+
+	resp, err := app.Test(req, -1)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	require.Len(t, auditLogs.logs, 1)
+	assert.Equal(t, models.AuditActionWorkspaceRenamed, auditLogs.logs[0].Action)
+	assert.Equal(t, "user-2", auditLogs.logs[0].UserID)
+	assert.Equal(t, "support-1", auditLogs.logs[0].ImpersonatedBy)
+}
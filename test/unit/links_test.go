@@ -0,0 +1,73 @@
+package unit
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Reg-Kris/pyairtable-workspace-service/pkg/response"
+)
+
+func TestPageLinks(t *testing.T) {
+	const base = "http://localhost:8084/api/v1/projects"
+	query := url.Values{"page": {"1"}, "page_size": {"10"}, "status": {"active"}}
+
+	pageURL := func(page string) string {
+		return base + "?page=" + page + "&page_size=10&status=active"
+	}
+
+	tests := []struct {
+		name       string
+		page       int
+		totalPages int
+		expected   response.Links
+	}{
+		{
+			name:       "first page has no prev",
+			page:       1,
+			totalPages: 3,
+			expected: response.Links{
+				First: pageURL("1"),
+				Next:  pageURL("2"),
+				Last:  pageURL("3"),
+			},
+		},
+		{
+			name:       "middle page has prev and next",
+			page:       2,
+			totalPages: 3,
+			expected: response.Links{
+				First: pageURL("1"),
+				Prev:  pageURL("1"),
+				Next:  pageURL("3"),
+				Last:  pageURL("3"),
+			},
+		},
+		{
+			name:       "last page has no next",
+			page:       3,
+			totalPages: 3,
+			expected: response.Links{
+				First: pageURL("1"),
+				Prev:  pageURL("2"),
+				Last:  pageURL("3"),
+			},
+		},
+		{
+			name:       "empty list links to a single page",
+			page:       1,
+			totalPages: 0,
+			expected: response.Links{
+				First: pageURL("1"),
+				Last:  pageURL("1"),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, response.PageLinks(base, query, tt.page, tt.totalPages))
+		})
+	}
+}
@@ -0,0 +1,109 @@
+package unit
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/gofiber/fiber/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/config"
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/handlers"
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/models"
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/repositories"
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/services"
+)
+
+// writableWorkspace serves reads and accepts updates of any workspace
+type writableWorkspace struct {
+	fixedWorkspace
+	updates int
+}
+
+func (s *writableWorkspace) UpdateWorkspace(ctx context.Context, workspaceID, userID string, req *models.UpdateWorkspaceRequest) (*models.Workspace, error) {
+	s.updates++
+	return s.GetWorkspace(ctx, workspaceID, userID)
+}
+
+func TestReadOnlyMaintenanceMode(t *testing.T) {
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	t.Cleanup(func() { client.Close() })
+
+	newApp := func(t *testing.T, cfg *config.Config) (*fiber.App, *writableWorkspace) {
+		server.FlushAll()
+		repos := &repositories.Repositories{Cache: repositories.NewCacheRepository(client, nil, cfg, zap.NewNop())}
+		workspaces := &writableWorkspace{}
+		svcs := &services.Services{Workspace: workspaces, Maintenance: services.NewMaintenanceService(repos, cfg, zap.NewNop())}
+
+		h := handlers.New(svcs, cfg, zap.NewNop())
+		app := fiber.New()
+		app.Use(func(c *fiber.Ctx) error {
+			c.Locals("user_id", c.Get("X-User"))
+			return c.Next()
+		})
+		h.RegisterRoutes(app)
+		return app, workspaces
+	}
+
+	send := func(t *testing.T, app *fiber.App, method, path, userID, body string) int {
+		req, _ := http.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("X-User", userID)
+		resp, err := app.Test(req, -1)
+		require.NoError(t, err)
+		return resp.StatusCode
+	}
+
+	workspacePath := "/api/v1/workspaces/" + batchWorkspaceID
+
+	t.Run("configured mode blocks writes and serves reads", func(t *testing.T) {
+		cfg := &config.Config{Maintenance: config.MaintenanceConfig{Mode: config.MaintenanceReadOnly}}
+		app, workspaces := newApp(t, cfg)
+
+		assert.Equal(t, http.StatusOK, send(t, app, http.MethodGet, workspacePath, "user-1", ""))
+		assert.Equal(t, http.StatusServiceUnavailable, send(t, app, http.MethodPut, workspacePath, "user-1", `{"description":"x"}`))
+		assert.Equal(t, http.StatusServiceUnavailable, send(t, app, http.MethodDelete, workspacePath, "user-1", ""))
+		assert.Equal(t, http.StatusServiceUnavailable, send(t, app, http.MethodPost, "/api/v1/workspaces", "user-1", `{"name":"New"}`))
+		assert.Zero(t, workspaces.updates)
+	})
+
+	t.Run("admin toggle blocks writes until it is cleared", func(t *testing.T) {
+		cfg := &config.Config{Platform: config.PlatformConfig{Admins: "ops-1"}}
+		app, workspaces := newApp(t, cfg)
+
+		assert.Equal(t, http.StatusOK, send(t, app, http.MethodPut, workspacePath, "user-1", `{"description":"x"}`))
+
+		assert.Equal(t, http.StatusForbidden, send(t, app, http.MethodPut, "/api/v1/admin/maintenance", "user-1", `{"mode":"read_only"}`))
+		assert.Equal(t, http.StatusBadRequest, send(t, app, http.MethodPut, "/api/v1/admin/maintenance", "ops-1", `{"mode":"frozen"}`))
+		require.Equal(t, http.StatusOK, send(t, app, http.MethodPut, "/api/v1/admin/maintenance", "ops-1", `{"mode":"read_only"}`))
+
+		assert.Equal(t, http.StatusServiceUnavailable, send(t, app, http.MethodPut, workspacePath, "user-1", `{"description":"x"}`))
+		assert.Equal(t, http.StatusOK, send(t, app, http.MethodGet, workspacePath, "user-1", ""))
+		assert.Equal(t, http.StatusOK, send(t, app, http.MethodGet, "/api/v1/admin/maintenance", "user-1", ""))
+
+		// The toggle itself stays writable so the mode can be lifted, however the path is cased
+		require.Equal(t, http.StatusOK, send(t, app, http.MethodPut, "/api/v1/Admin/Maintenance", "ops-1", `{"mode":""}`))
+		assert.Equal(t, http.StatusOK, send(t, app, http.MethodPut, workspacePath, "user-1", `{"description":"x"}`))
+		assert.Equal(t, 2, workspaces.updates)
+	})
+
+	t.Run("toggle cannot lift the configured mode", func(t *testing.T) {
+		cfg := &config.Config{
+			Maintenance: config.MaintenanceConfig{Mode: config.MaintenanceReadOnly},
+			Platform:    config.PlatformConfig{Admins: "ops-1"},
+		}
+		repos := &repositories.Repositories{Cache: repositories.NewCacheRepository(client, nil, cfg, zap.NewNop())}
+
+		status, err := services.NewMaintenanceService(repos, cfg, zap.NewNop()).SetMode(context.Background(), "ops-1", "")
+		require.NoError(t, err)
+		assert.Equal(t, config.MaintenanceReadOnly, status.Mode)
+		assert.True(t, status.Configured)
+	})
+}
@@ -0,0 +1,147 @@
+package unit
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/config"
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/models"
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/repositories"
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/services"
+)
+
+// slowMembers holds an owner and takes a while to add members, rejecting duplicates
+type slowMembers struct {
+	repositories.WorkspaceMemberRepository
+
+	mu      sync.Mutex
+	members map[string]*models.WorkspaceMember
+	adds    int
+}
+
+func (r *slowMembers) GetByWorkspaceAndUser(ctx context.Context, workspaceID, userID string) (*models.WorkspaceMember, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if member, ok := r.members[userID]; ok {
+		copied := *member
+		return &copied, nil
+	}
+	return nil, repositories.ErrMemberNotFound
+}
+
+func (r *slowMembers) Add(ctx context.Context, member *models.WorkspaceMember) error {
+	time.Sleep(50 * time.Millisecond)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.adds++
+	if _, ok := r.members[member.UserID]; ok {
+		return repositories.ErrDuplicateMember
+	}
+	r.members[member.UserID] = member
+	return nil
+}
+
+func (r *slowMembers) Remove(ctx context.Context, workspaceID, userID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.members, userID)
+	return nil
+}
+
+func TestRapidDuplicateMemberAddsSucceedOnce(t *testing.T) {
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	cfg := &config.Config{Members: config.MembersConfig{AddDedupWindow: 10}}
+
+	newService := func() (services.MemberService, *slowMembers) {
+		server.FlushAll()
+		members := &slowMembers{members: map[string]*models.WorkspaceMember{
+			"owner": {WorkspaceID: "ws-1", UserID: "owner", Role: models.WorkspaceRoleOwner},
+		}}
+		repos := &repositories.Repositories{
+			Member: members,
+			Cache:  repositories.NewCacheRepository(client, nil, cfg, zap.NewNop()),
+		}
+		return services.NewMemberService(repos, cfg, zap.NewNop(), &nopAudit{}, nil, nil), members
+	}
+	ctx := context.Background()
+	add := func(svc services.MemberService, role models.WorkspaceMemberRole) (*models.WorkspaceMember, error) {
+		return svc.AddMember(ctx, "ws-1", "owner", &models.AddWorkspaceMemberRequest{UserID: "clicked", Role: role})
+	}
+
+	t.Run("two near-simultaneous adds both succeed", func(t *testing.T) {
+		svc, members := newService()
+
+		var wg sync.WaitGroup
+		results := make([]*models.WorkspaceMember, 2)
+		errs := make([]error, 2)
+		for i := range results {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				results[i], errs[i] = add(svc, models.WorkspaceRoleMember)
+			}(i)
+		}
+		wg.Wait()
+
+		for i := range results {
+			require.NoError(t, errs[i])
+			assert.Equal(t, "clicked", results[i].UserID)
+			assert.Equal(t, models.WorkspaceRoleMember, results[i].Role)
+		}
+		assert.Equal(t, 1, members.adds)
+	})
+
+	t.Run("a repeat after the add finished still succeeds", func(t *testing.T) {
+		svc, members := newService()
+
+		_, err := add(svc, models.WorkspaceRoleMember)
+		require.NoError(t, err)
+		_, err = add(svc, models.WorkspaceRoleMember)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, members.adds)
+	})
+
+	t.Run("a different role is not a repeat", func(t *testing.T) {
+		svc, _ := newService()
+
+		_, err := add(svc, models.WorkspaceRoleMember)
+		require.NoError(t, err)
+		_, err = add(svc, models.WorkspaceRoleAdmin)
+		assert.Equal(t, services.ErrMemberExists, err)
+	})
+
+	t.Run("a member removed since is added again", func(t *testing.T) {
+		svc, members := newService()
+
+		_, err := add(svc, models.WorkspaceRoleMember)
+		require.NoError(t, err)
+		require.NoError(t, members.Remove(ctx, "ws-1", "clicked"))
+
+		_, err = add(svc, models.WorkspaceRoleMember)
+		require.NoError(t, err)
+		assert.Equal(t, 2, members.adds)
+	})
+
+	t.Run("without a window the repeat conflicts", func(t *testing.T) {
+		members := &slowMembers{members: map[string]*models.WorkspaceMember{
+			"owner": {WorkspaceID: "ws-1", UserID: "owner", Role: models.WorkspaceRoleOwner},
+		}}
+		repos := &repositories.Repositories{Member: members, Cache: &nopCache{}}
+		svc := services.NewMemberService(repos, &config.Config{}, zap.NewNop(), &nopAudit{}, nil, nil)
+
+		_, err := add(svc, models.WorkspaceRoleMember)
+		require.NoError(t, err)
+		_, err = add(svc, models.WorkspaceRoleMember)
+		assert.Equal(t, services.ErrMemberExists, err)
+	})
+}
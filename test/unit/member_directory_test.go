@@ -0,0 +1,171 @@
+package unit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/config"
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/models"
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/repositories"
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/services"
+)
+
+// fakeDirectory resolves the users it knows, or fails every lookup when err is set
+type fakeDirectory struct {
+	users   map[string]models.UserInfo
+	err     error
+	lookups int
+}
+
+func (d *fakeDirectory) Resolve(ctx context.Context, userIDs []string) (map[string]models.UserInfo, error) {
+	d.lookups++
+	if d.err != nil {
+		return nil, d.err
+	}
+	resolved := map[string]models.UserInfo{}
+	for _, id := range userIDs {
+		if user, ok := d.users[id]; ok {
+			resolved[id] = user
+		}
+	}
+	return resolved, nil
+}
+
+// pagedRoster serves the roster's pages as well as scanning it
+type pagedRoster struct {
+	*rosterMembers
+}
+
+func (r *pagedRoster) List(ctx context.Context, workspaceID string, page, pageSize int) ([]*models.WorkspaceMember, int64, error) {
+	start := (page - 1) * pageSize
+	end := start + pageSize
+	if end > len(r.members) {
+		end = len(r.members)
+	}
+	return r.members[start:end], int64(len(r.members)), nil
+}
+
+func newDirectoryRoster() *pagedRoster {
+	roster := &rosterMembers{}
+	for _, id := range []string{"u-admin", "u-ada", "u-grace", "u-linus"} {
+		roster.members = append(roster.members, &models.WorkspaceMember{WorkspaceID: "ws-1", UserID: id, Role: models.WorkspaceRoleMember})
+	}
+	roster.members[0].Role = models.WorkspaceRoleAdmin
+	return &pagedRoster{rosterMembers: roster}
+}
+
+var directoryUsers = map[string]models.UserInfo{
+	"u-admin": {UserID: "u-admin", DisplayName: "Site Admin", Email: "admin@example.com"},
+	"u-ada":   {UserID: "u-ada", DisplayName: "Ada Lovelace", Email: "ada@analytical.org"},
+	"u-grace": {UserID: "u-grace", DisplayName: "Grace Hopper", Email: "grace@navy.mil"},
+}
+
+func TestListMembersResolvesDirectoryNames(t *testing.T) {
+	ctx := context.Background()
+	newService := func(directory services.UserDirectory) services.MemberService {
+		repos := &repositories.Repositories{Member: newDirectoryRoster(), Cache: &nopCache{}}
+		return services.NewMemberService(repos, &config.Config{}, zap.NewNop(), nil, nil, directory)
+	}
+
+	t.Run("members are enriched with names and emails", func(t *testing.T) {
+		list, err := newService(&fakeDirectory{users: directoryUsers}).ListMembers(ctx, "ws-1", "u-admin", 1, 20, "")
+		require.NoError(t, err)
+		require.Len(t, list.Members, 4)
+		assert.Equal(t, "Ada Lovelace", list.Members[1].DisplayName)
+		assert.Equal(t, "ada@analytical.org", list.Members[1].Email)
+		// Users unknown to the directory keep only their ID
+		assert.Empty(t, list.Members[3].DisplayName)
+	})
+
+	t.Run("search matches names, emails and IDs ignoring case", func(t *testing.T) {
+		svc := newService(&fakeDirectory{users: directoryUsers})
+
+		list, err := svc.ListMembers(ctx, "ws-1", "u-admin", 1, 20, "hopper")
+		require.NoError(t, err)
+		require.Len(t, list.Members, 1)
+		assert.Equal(t, "u-grace", list.Members[0].UserID)
+		assert.Equal(t, int64(1), list.Total)
+
+		list, err = svc.ListMembers(ctx, "ws-1", "u-admin", 1, 20, "ANALYTICAL.ORG")
+		require.NoError(t, err)
+		require.Len(t, list.Members, 1)
+		assert.Equal(t, "u-ada", list.Members[0].UserID)
+
+		list, err = svc.ListMembers(ctx, "ws-1", "u-admin", 1, 20, "linus")
+		require.NoError(t, err)
+		require.Len(t, list.Members, 1)
+		assert.Equal(t, "u-linus", list.Members[0].UserID)
+	})
+
+	t.Run("search results are paginated", func(t *testing.T) {
+		svc := newService(&fakeDirectory{users: directoryUsers})
+
+		list, err := svc.ListMembers(ctx, "ws-1", "u-admin", 2, 2, "u-")
+		require.NoError(t, err)
+		assert.Equal(t, int64(4), list.Total)
+		assert.Equal(t, 2, list.TotalPages)
+		require.Len(t, list.Members, 2)
+		assert.Equal(t, "u-grace", list.Members[0].UserID)
+
+		list, err = svc.ListMembers(ctx, "ws-1", "u-admin", 3, 2, "u-")
+		require.NoError(t, err)
+		assert.Empty(t, list.Members)
+	})
+
+	t.Run("unavailable directory falls back to IDs", func(t *testing.T) {
+		svc := newService(&fakeDirectory{err: errors.New("user service down")})
+
+		list, err := svc.ListMembers(ctx, "ws-1", "u-admin", 1, 20, "")
+		require.NoError(t, err)
+		require.Len(t, list.Members, 4)
+		assert.Empty(t, list.Members[1].DisplayName)
+
+		list, err = svc.ListMembers(ctx, "ws-1", "u-admin", 1, 20, "ada")
+		require.NoError(t, err)
+		require.Len(t, list.Members, 1)
+		assert.Equal(t, "u-ada", list.Members[0].UserID)
+
+		list, err = svc.ListMembers(ctx, "ws-1", "u-admin", 1, 20, "lovelace")
+		require.NoError(t, err)
+		assert.Empty(t, list.Members)
+	})
+
+	t.Run("no directory lists by ID", func(t *testing.T) {
+		list, err := newService(nil).ListMembers(ctx, "ws-1", "u-admin", 1, 20, "grace")
+		require.NoError(t, err)
+		require.Len(t, list.Members, 1)
+		assert.Empty(t, list.Members[0].Email)
+	})
+}
+
+func TestMemberSearchStopsOnceThePageIsFilled(t *testing.T) {
+	ctx := context.Background()
+	roster := &rosterMembers{}
+	for i := 0; i < 1200; i++ {
+		roster.members = append(roster.members, &models.WorkspaceMember{WorkspaceID: "ws-1", UserID: fmt.Sprintf("u-%04d", i), Role: models.WorkspaceRoleMember})
+	}
+	roster.members[0].Role = models.WorkspaceRoleAdmin
+	directory := &fakeDirectory{}
+	repos := &repositories.Repositories{Member: roster, Cache: &nopCache{}}
+	svc := services.NewMemberService(repos, &config.Config{}, zap.NewNop(), nil, nil, directory)
+
+	list, err := svc.ListMembers(ctx, "ws-1", "u-0000", 1, 20, "u-")
+	require.NoError(t, err)
+	require.Len(t, list.Members, 20)
+	// The first batch fills the page; the rest of the roster is never resolved
+	assert.Equal(t, 1, directory.lookups)
+	assert.Equal(t, 1, roster.batches)
+	assert.Greater(t, list.TotalPages, 1)
+
+	// Search pages are capped like the plain listing
+	list, err = svc.ListMembers(ctx, "ws-1", "u-0000", 1, 1000, "u-")
+	require.NoError(t, err)
+	assert.Len(t, list.Members, 100)
+	assert.Equal(t, 100, list.PageSize)
+}
@@ -0,0 +1,131 @@
+package unit
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/config"
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/models"
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/repositories"
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/services"
+)
+
+// roleMembers holds member roles by user id and applies role updates in memory
+type roleMembers struct {
+	repositories.WorkspaceMemberRepository
+	roles map[string]models.WorkspaceMemberRole
+}
+
+func (r *roleMembers) GetByWorkspaceAndUser(ctx context.Context, workspaceID, userID string) (*models.WorkspaceMember, error) {
+	role, ok := r.roles[userID]
+	if !ok {
+		return nil, repositories.ErrMemberNotFound
+	}
+	return &models.WorkspaceMember{WorkspaceID: workspaceID, UserID: userID, Role: role}, nil
+}
+
+func (r *roleMembers) UpdateRole(ctx context.Context, workspaceID, userID string, role models.WorkspaceMemberRole) error {
+	r.roles[userID] = role
+	return nil
+}
+
+// nopCache accepts cache invalidations
+type nopCache struct {
+	repositories.CacheRepository
+}
+
+func (c *nopCache) InvalidateUserCache(ctx context.Context, userID string) error {
+	return nil
+}
+
+func (c *nopCache) DeleteProject(ctx context.Context, id string) error {
+	return nil
+}
+
+func (c *nopCache) DeleteWorkspace(ctx context.Context, id string) error {
+	return nil
+}
+
+func (c *nopCache) InvalidateTenantStats(ctx context.Context, tenantID string) error {
+	return nil
+}
+
+// nopAudit discards audit entries
+type nopAudit struct {
+	services.AuditService
+}
+
+func (a *nopAudit) LogAction(ctx context.Context, workspaceID, userID, action, resourceType, resourceID string, changes map[string]interface{}) error {
+	return nil
+}
+
+// recordingPublisher keeps every published event
+type recordingPublisher struct {
+	events []*models.Event
+}
+
+func (p *recordingPublisher) Publish(ctx context.Context, event *models.Event) error {
+	p.events = append(p.events, event)
+	return nil
+}
+
+func (p *recordingPublisher) types() []string {
+	types := make([]string, 0, len(p.events))
+	for _, event := range p.events {
+		types = append(types, event.Type)
+	}
+	return types
+}
+
+func TestUpdateMemberRoleEvents(t *testing.T) {
+	tests := []struct {
+		name     string
+		oldRole  models.WorkspaceMemberRole
+		newRole  models.WorkspaceMemberRole
+		expected []string
+	}{
+		{
+			name:     "downgrade publishes a downgrade event",
+			oldRole:  models.WorkspaceRoleAdmin,
+			newRole:  models.WorkspaceRoleViewer,
+			expected: []string{models.EventMemberRoleUpdated, models.EventMemberRoleDowngraded},
+		},
+		{
+			name:     "upgrade publishes only the generic event",
+			oldRole:  models.WorkspaceRoleViewer,
+			newRole:  models.WorkspaceRoleMember,
+			expected: []string{models.EventMemberRoleUpdated},
+		},
+		{
+			name:     "same role publishes only the generic event",
+			oldRole:  models.WorkspaceRoleMember,
+			newRole:  models.WorkspaceRoleMember,
+			expected: []string{models.EventMemberRoleUpdated},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			members := &roleMembers{roles: map[string]models.WorkspaceMemberRole{
+				"owner-1":  models.WorkspaceRoleOwner,
+				"member-1": tt.oldRole,
+			}}
+			repos := &repositories.Repositories{Member: members, Cache: &nopCache{}}
+			publisher := &recordingPublisher{}
+			svc := services.NewMemberService(repos, &config.Config{}, zap.NewNop(), &nopAudit{}, publisher, nil)
+
+			_, err := svc.UpdateMemberRole(context.Background(), "ws-1", "member-1", "owner-1", &models.UpdateWorkspaceMemberRequest{Role: tt.newRole})
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.expected, publisher.types())
+			last := publisher.events[len(publisher.events)-1]
+			assert.Equal(t, tt.oldRole, last.Data["old_role"])
+			assert.Equal(t, tt.newRole, last.Data["new_role"])
+			assert.Equal(t, "owner-1", last.ActorID)
+		})
+	}
+}
@@ -0,0 +1,114 @@
+package unit
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/config"
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/handlers"
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/models"
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/repositories"
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/services"
+)
+
+// rosterMembers holds a workspace roster sorted by user ID and counts the batches scanned
+type rosterMembers struct {
+	repositories.WorkspaceMemberRepository
+	members []*models.WorkspaceMember
+	batches int
+}
+
+func (r *rosterMembers) GetByWorkspaceAndUser(ctx context.Context, workspaceID, userID string) (*models.WorkspaceMember, error) {
+	for _, member := range r.members {
+		if member.UserID == userID {
+			return member, nil
+		}
+	}
+	return nil, repositories.ErrMemberNotFound
+}
+
+func (r *rosterMembers) Scan(ctx context.Context, workspaceID string, batchSize int, fn func(batch []*models.WorkspaceMember) error) error {
+	for start := 0; start < len(r.members); start += batchSize {
+		end := start + batchSize
+		if end > len(r.members) {
+			end = len(r.members)
+		}
+		r.batches++
+		if err := fn(r.members[start:end]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func TestExportWorkspaceMembers(t *testing.T) {
+	const memberCount = 1234
+	joinedAt := time.Date(2024, 5, 6, 7, 8, 9, 0, time.UTC)
+	roster := &rosterMembers{}
+	for i := 0; i < memberCount; i++ {
+		role := models.WorkspaceRoleMember
+		if i == 0 {
+			role = models.WorkspaceRoleAdmin
+		}
+		roster.members = append(roster.members, &models.WorkspaceMember{
+			WorkspaceID: "ws-1",
+			UserID:      fmt.Sprintf("user-%04d", i),
+			Role:        role,
+			JoinedAt:    joinedAt,
+		})
+	}
+
+	repos := &repositories.Repositories{Member: roster, Cache: &nopCache{}}
+	svc := services.NewMemberService(repos, &config.Config{}, zap.NewNop(), nil, nil, nil)
+	h := handlers.New(&services.Services{Member: svc}, &config.Config{}, zap.NewNop())
+
+	export := func(t *testing.T, userID, query string) *http.Response {
+		app := fiber.New()
+		app.Use(func(c *fiber.Ctx) error {
+			c.Locals("user_id", userID)
+			return c.Next()
+		})
+		app.Get("/workspaces/:workspace_id/members/export", h.ExportWorkspaceMembers)
+
+		req, _ := http.NewRequest(http.MethodGet, "/workspaces/ws-1/members/export"+query, nil)
+		resp, err := app.Test(req, -1)
+		require.NoError(t, err)
+		return resp
+	}
+
+	t.Run("admins download every member as CSV", func(t *testing.T) {
+		resp := export(t, "user-0000", "?format=csv")
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, "text/csv; charset=utf-8", resp.Header.Get("Content-Type"))
+		assert.Equal(t, `attachment; filename="workspace-ws-1-members.csv"`, resp.Header.Get("Content-Disposition"))
+
+		rows, err := csv.NewReader(resp.Body).ReadAll()
+		require.NoError(t, err)
+		require.Len(t, rows, memberCount+1)
+		assert.Equal(t, []string{"user_id", "role", "joined_at"}, rows[0])
+		assert.Equal(t, []string{"user-0000", "admin", "2024-05-06T07:08:09Z"}, rows[1])
+		assert.Equal(t, "user-1233", rows[memberCount][0])
+		// The roster is read in batches rather than one page
+		assert.Equal(t, 3, roster.batches)
+	})
+
+	t.Run("members below admin are refused", func(t *testing.T) {
+		resp := export(t, "user-0001", "")
+		assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+		assert.Empty(t, resp.Header.Get("Content-Disposition"))
+	})
+
+	t.Run("other formats are rejected", func(t *testing.T) {
+		resp := export(t, "user-0000", "?format=xlsx")
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
+}
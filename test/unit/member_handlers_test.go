@@ -0,0 +1,133 @@
+package unit
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/config"
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/handlers"
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/models"
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/repositories"
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/services"
+)
+
+// stubMemberService applies role updates to an in-memory member
+type stubMemberService struct {
+	services.MemberService
+	member *models.WorkspaceMember
+}
+
+func (s *stubMemberService) UpdateMemberRole(ctx context.Context, workspaceID, memberUserID, userID string, req *models.UpdateWorkspaceMemberRequest) (*models.WorkspaceMember, error) {
+	updated := *s.member
+	updated.Role = req.Role
+	return &updated, nil
+}
+
+func TestUpdateWorkspaceMemberRoleReturnsMember(t *testing.T) {
+	joinedAt := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	memberService := &stubMemberService{member: &models.WorkspaceMember{
+		WorkspaceID: "ws-1",
+		UserID:      "user-2",
+		Role:        models.WorkspaceRoleMember,
+		JoinedAt:    joinedAt,
+	}}
+	h := handlers.New(&services.Services{Member: memberService}, &config.Config{}, zap.NewNop())
+
+	app := fiber.New()
+	app.Use(func(c *fiber.Ctx) error {
+		c.Locals("user_id", "user-1")
+		return c.Next()
+	})
+	app.Put("/workspaces/:workspace_id/members/:user_id", h.UpdateWorkspaceMemberRole)
+
+	req, _ := http.NewRequest("PUT", "/workspaces/ws-1/members/user-2", strings.NewReader(`{"role":"admin"}`))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := app.Test(req, -1)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var member models.WorkspaceMember
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&member))
+	assert.Equal(t, models.WorkspaceRoleAdmin, member.Role)
+	assert.Equal(t, "user-2", member.UserID)
+	assert.True(t, joinedAt.Equal(member.JoinedAt))
+}
+
+// listedMembers holds the caller's role by workspace and lists only the caller
+type listedMembers struct {
+	workspaceRoles
+}
+
+func (r *listedMembers) List(ctx context.Context, workspaceID string, page, pageSize int) ([]*models.WorkspaceMember, int64, error) {
+	return []*models.WorkspaceMember{{WorkspaceID: workspaceID, UserID: "user-1", Role: r.roles[workspaceID]}}, 1, nil
+}
+
+// existingWorkspaces finds only the listed workspaces
+type existingWorkspaces struct {
+	repositories.WorkspaceRepository
+	ids map[string]bool
+}
+
+func (r *existingWorkspaces) GetByID(ctx context.Context, id string) (*models.Workspace, error) {
+	if !r.ids[id] {
+		return nil, repositories.ErrWorkspaceNotFound
+	}
+	workspace := &models.Workspace{Name: "Workspace"}
+	workspace.ID = id
+	return workspace, nil
+}
+
+func TestListWorkspaceMembersDistinguishesMissingAccess(t *testing.T) {
+	repos := &repositories.Repositories{
+		Member: &listedMembers{workspaceRoles{roles: map[string]models.WorkspaceMemberRole{
+			"ws-member": models.WorkspaceRoleViewer,
+		}}},
+		Workspace: &existingWorkspaces{ids: map[string]bool{"ws-member": true, "ws-other": true}},
+	}
+	memberService := services.NewMemberService(repos, &config.Config{}, zap.NewNop(), nil, nil, nil)
+	h := handlers.New(&services.Services{Member: memberService}, &config.Config{}, zap.NewNop())
+
+	app := fiber.New()
+	app.Use(func(c *fiber.Ctx) error {
+		c.Locals("user_id", "user-1")
+		return c.Next()
+	})
+	app.Get("/workspaces/:workspace_id/members", h.ListWorkspaceMembers)
+
+	tests := []struct {
+		name           string
+		workspaceID    string
+		expectedStatus int
+	}{
+		{"missing workspace is not found", "ws-missing", http.StatusNotFound},
+		{"workspace the caller is not in is forbidden", "ws-other", http.StatusForbidden},
+		{"member sees the member list", "ws-member", http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, _ := http.NewRequest(http.MethodGet, "/workspaces/"+tt.workspaceID+"/members", nil)
+			resp, err := app.Test(req, -1)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedStatus, resp.StatusCode)
+
+			if tt.expectedStatus == http.StatusOK {
+				var page models.WorkspaceMemberListResponse
+				require.NoError(t, json.NewDecoder(resp.Body).Decode(&page))
+				assert.Equal(t, int64(1), page.Total)
+				require.Len(t, page.Members, 1)
+				assert.Equal(t, "user-1", page.Members[0].UserID)
+			}
+		})
+	}
+}
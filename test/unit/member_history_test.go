@@ -0,0 +1,42 @@
+package unit
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/config"
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/repositories"
+)
+
+func TestRemoveMemberRecordsHistory(t *testing.T) {
+	conn, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer conn.Close()
+	db, err := gorm.Open(postgres.New(postgres.Config{Conn: conn}), &gorm.Config{})
+	require.NoError(t, err)
+	members := repositories.NewWorkspaceMemberRepository(db, &config.Config{}, zap.NewNop())
+
+	mock.ExpectQuery(`SELECT \* FROM "workspace_members"`).
+		WillReturnRows(sqlmock.NewRows([]string{"workspace_id", "user_id", "role"}).AddRow("ws-1", "leaver", "member"))
+	// The delete and the history insert are one statement, so neither happens without the other
+	mock.ExpectExec(`WITH removed AS \(\s*DELETE FROM workspace_members .*RETURNING .*\)\s*INSERT INTO workspace_membership_history`).
+		WithArgs("ws-1", "leaver").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	require.NoError(t, members.Remove(context.Background(), "ws-1", "leaver"))
+	assert.NoError(t, mock.ExpectationsWereMet())
+
+	mock.ExpectQuery(`SELECT \* FROM "workspace_members"`).
+		WillReturnRows(sqlmock.NewRows([]string{"workspace_id", "user_id", "role"}).AddRow("ws-1", "leaver", "member"))
+	mock.ExpectExec(`INSERT INTO workspace_membership_history`).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	assert.Equal(t, repositories.ErrMemberNotFound, members.Remove(context.Background(), "ws-1", "leaver"))
+}
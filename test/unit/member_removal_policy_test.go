@@ -0,0 +1,91 @@
+package unit
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/config"
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/models"
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/repositories"
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/services"
+)
+
+// removableMembers is roleMembers that records removals
+type removableMembers struct {
+	roleMembers
+	removed []string
+}
+
+func (r *removableMembers) Remove(ctx context.Context, workspaceID, userID string) error {
+	r.removed = append(r.removed, userID)
+	return nil
+}
+
+// projectCreators reports how many projects each user created
+type projectCreators struct {
+	repositories.ProjectRepository
+	counts map[string]int64
+}
+
+func (r *projectCreators) CountByCreator(ctx context.Context, workspaceID, userID string) (int64, error) {
+	return r.counts[userID], nil
+}
+
+// baseCreators reports how many Airtable bases each user created
+type baseCreators struct {
+	repositories.AirtableBaseRepository
+	counts map[string]int64
+}
+
+func (r *baseCreators) CountByCreator(ctx context.Context, workspaceID, userID string) (int64, error) {
+	return r.counts[userID], nil
+}
+
+func TestMemberRemovalPolicy(t *testing.T) {
+	newService := func(policy string) (services.MemberService, *removableMembers) {
+		members := &removableMembers{roleMembers: roleMembers{roles: map[string]models.WorkspaceMemberRole{
+			"owner":     models.WorkspaceRoleOwner,
+			"creator":   models.WorkspaceRoleMember,
+			"connector": models.WorkspaceRoleMember,
+			"bystander": models.WorkspaceRoleViewer,
+		}}}
+		repos := &repositories.Repositories{
+			Member:       members,
+			Project:      &projectCreators{counts: map[string]int64{"creator": 2}},
+			AirtableBase: &baseCreators{counts: map[string]int64{"connector": 1}},
+			Cache:        &nopCache{},
+		}
+		cfg := &config.Config{Members: config.MembersConfig{RemovalPolicy: policy}}
+		return services.NewMemberService(repos, cfg, zap.NewNop(), &nopAudit{}, nil, nil), members
+	}
+	ctx := context.Background()
+
+	t.Run("block refuses members who created resources", func(t *testing.T) {
+		svc, members := newService(config.MemberRemovalBlock)
+
+		assert.Equal(t, services.ErrMemberOwnsResources, svc.RemoveMember(ctx, "ws-1", "creator", "owner"))
+		assert.Equal(t, services.ErrMemberOwnsResources, svc.RemoveMember(ctx, "ws-1", "connector", "owner"))
+		assert.NoError(t, svc.RemoveMember(ctx, "ws-1", "bystander", "owner"))
+		assert.Equal(t, []string{"bystander"}, members.removed)
+	})
+
+	t.Run("orphan removes regardless, as does the default", func(t *testing.T) {
+		for _, policy := range []string{config.MemberRemovalOrphan, ""} {
+			svc, members := newService(policy)
+
+			require.NoError(t, svc.RemoveMember(ctx, "ws-1", "creator", "owner"))
+			assert.Equal(t, []string{"creator"}, members.removed)
+		}
+	})
+}
+
+func TestMembersConfigValidate(t *testing.T) {
+	for _, policy := range []string{"", config.MemberRemovalOrphan, config.MemberRemovalBlock, config.MemberRemovalReassignToOwner} {
+		assert.NoError(t, (&config.MembersConfig{RemovalPolicy: policy}).Validate())
+	}
+	assert.Error(t, (&config.MembersConfig{RemovalPolicy: "delete"}).Validate())
+}
@@ -0,0 +1,130 @@
+package unit
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/config"
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/models"
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/repositories"
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/services"
+)
+
+// coldCache never holds a workspace, so every read reaches the repository
+type coldCache struct {
+	nopCache
+}
+
+func (c *coldCache) GetWorkspace(ctx context.Context, id string) (*models.Workspace, error) {
+	return nil, nil
+}
+
+func (c *coldCache) SetWorkspace(ctx context.Context, workspace *models.Workspace) error {
+	return nil
+}
+
+func TestValidateNotificationSettings(t *testing.T) {
+	tests := []struct {
+		name    string
+		digest  string
+		isValid bool
+	}{
+		{name: "digest off", digest: models.NotificationDigestOff, isValid: true},
+		{name: "daily digest", digest: models.NotificationDigestDaily, isValid: true},
+		{name: "weekly digest", digest: models.NotificationDigestWeekly, isValid: true},
+		{name: "omitted digest", digest: "", isValid: true},
+		{name: "unknown digest", digest: "hourly", isValid: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := services.ValidateNotificationSettings(&models.NotificationSettings{Digest: tt.digest})
+			assert.Equal(t, !tt.isValid, result.Rejected(false))
+		})
+	}
+
+	t.Run("workspace updates validate the notifications key", func(t *testing.T) {
+		valid := services.ValidateUpdateWorkspace(&models.UpdateWorkspaceRequest{Settings: &models.JSONMap{
+			models.WorkspaceSettingNotifications: map[string]interface{}{"digest": "weekly"},
+		}})
+		assert.False(t, valid.Rejected(false))
+
+		badDigest := services.ValidateUpdateWorkspace(&models.UpdateWorkspaceRequest{Settings: &models.JSONMap{
+			models.WorkspaceSettingNotifications: map[string]interface{}{"digest": "hourly"},
+		}})
+		assert.True(t, badDigest.Rejected(false))
+		require.Len(t, badDigest.FieldErrors, 1)
+		assert.Equal(t, "settings.notifications.digest", badDigest.FieldErrors[0].Field)
+
+		wrongShape := services.ValidateCreateWorkspace(&models.CreateWorkspaceRequest{Name: "Alerts", Settings: models.JSONMap{
+			models.WorkspaceSettingNotifications: "all",
+		}})
+		assert.True(t, wrongShape.Rejected(false))
+	})
+
+	t.Run("projects do not interpret the notifications key", func(t *testing.T) {
+		result := services.ValidateCreateProject(&models.CreateProjectRequest{Name: "Alerts", Settings: models.JSONMap{
+			models.WorkspaceSettingNotifications: "all",
+		}})
+		assert.False(t, result.Rejected(false))
+	})
+}
+
+func TestNotificationSettingsRoundTrip(t *testing.T) {
+	setup := func(role models.WorkspaceMemberRole) (services.WorkspaceService, *storedWorkspace, *recordingChanges) {
+		workspaces := &storedWorkspace{workspace: &models.Workspace{
+			Name:     "Alerts",
+			Settings: models.JSONMap{"theme": "dark"},
+		}}
+		workspaces.workspace.ID = "ws-1"
+		audit := &recordingChanges{}
+		repos := &repositories.Repositories{
+			Workspace: workspaces,
+			Member:    &singleMember{role: role},
+			Cache:     &coldCache{},
+		}
+		return services.NewWorkspaceService(repos, &config.Config{}, zap.NewNop(), audit, nil), workspaces, audit
+	}
+	ctx := context.Background()
+
+	t.Run("unset preferences read as defaults", func(t *testing.T) {
+		svc, _, _ := setup(models.WorkspaceRoleViewer)
+
+		settings, err := svc.GetNotificationSettings(ctx, "ws-1", "viewer-1")
+		require.NoError(t, err)
+		assert.Equal(t, models.NotificationSettings{Digest: models.NotificationDigestOff}, *settings)
+	})
+
+	t.Run("updates are stored alongside other settings", func(t *testing.T) {
+		svc, workspaces, audit := setup(models.WorkspaceRoleAdmin)
+		want := models.NotificationSettings{EmailOnMemberAdded: true, EmailOnSyncFailed: true, Digest: models.NotificationDigestWeekly}
+
+		updated, err := svc.UpdateNotificationSettings(ctx, "ws-1", "admin-1", &want)
+		require.NoError(t, err)
+		assert.Equal(t, want, *updated)
+		assert.Equal(t, "dark", workspaces.workspace.Settings["theme"])
+
+		read, err := svc.GetNotificationSettings(ctx, "ws-1", "admin-1")
+		require.NoError(t, err)
+		assert.Equal(t, want, *read)
+
+		require.Equal(t, []string{models.AuditActionWorkspaceSettingsChanged}, audit.actions)
+		assert.Contains(t, audit.changes[0], "settings.notifications")
+
+		// Saving the same preferences again is not a change
+		_, err = svc.UpdateNotificationSettings(ctx, "ws-1", "admin-1", &want)
+		require.NoError(t, err)
+		assert.Len(t, audit.actions, 1)
+	})
+
+	t.Run("members below admin cannot update", func(t *testing.T) {
+		svc, _, _ := setup(models.WorkspaceRoleMember)
+
+		_, err := svc.UpdateNotificationSettings(ctx, "ws-1", "member-1", &models.NotificationSettings{Digest: models.NotificationDigestDaily})
+		assert.Equal(t, services.ErrUnauthorized, err)
+	})
+}
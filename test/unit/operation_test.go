@@ -0,0 +1,108 @@
+package unit
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/config"
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/middleware"
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/models"
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/repositories"
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/services"
+)
+
+// lookupCountingMembers makes every user an admin and counts membership lookups
+type lookupCountingMembers struct {
+	repositories.WorkspaceMemberRepository
+	lookups int
+}
+
+func (r *lookupCountingMembers) GetByWorkspaceAndUser(ctx context.Context, workspaceID, userID string) (*models.WorkspaceMember, error) {
+	r.lookups++
+	return &models.WorkspaceMember{WorkspaceID: workspaceID, UserID: userID, Role: models.WorkspaceRoleAdmin}, nil
+}
+
+func TestOperationContextAccessors(t *testing.T) {
+	op := services.NewOperationContext("user-1", "tenant-1", "req-1")
+	ctx := services.WithOperation(context.Background(), op)
+
+	got := services.OperationFrom(ctx)
+	require.NotNil(t, got)
+	assert.Equal(t, "user-1", got.UserID())
+	assert.Equal(t, "tenant-1", got.TenantID())
+	assert.Equal(t, "req-1", got.RequestID())
+
+	_, resolved := got.Role("ws-1")
+	assert.False(t, resolved)
+
+	t.Run("a missing operation context is safe to use", func(t *testing.T) {
+		missing := services.OperationFrom(context.Background())
+		assert.Nil(t, missing)
+		assert.Empty(t, missing.UserID())
+		assert.Empty(t, missing.TenantID())
+		assert.Empty(t, missing.RequestID())
+		_, resolved := missing.Role("ws-1")
+		assert.False(t, resolved)
+	})
+}
+
+func TestOperationContextReusesMembershipLookups(t *testing.T) {
+	members := &lookupCountingMembers{}
+	repos := &repositories.Repositories{Member: members}
+	svc := services.NewWorkspaceService(repos, &config.Config{}, zap.NewNop(), nil, nil)
+
+	op := services.NewOperationContext("user-1", "tenant-1", "req-1")
+	ctx := services.WithOperation(context.Background(), op)
+
+	require.NoError(t, svc.CheckUserAccess(ctx, "ws-1", "user-1", models.WorkspaceRoleViewer))
+	require.NoError(t, svc.CheckUserAccess(ctx, "ws-1", "user-1", models.WorkspaceRoleAdmin))
+	assert.Equal(t, 1, members.lookups)
+
+	role, resolved := op.Role("ws-1")
+	assert.True(t, resolved)
+	assert.Equal(t, models.WorkspaceRoleAdmin, role)
+
+	// Other workspaces and other users are still looked up
+	require.NoError(t, svc.CheckUserAccess(ctx, "ws-2", "user-1", models.WorkspaceRoleViewer))
+	require.NoError(t, svc.CheckUserAccess(ctx, "ws-1", "user-2", models.WorkspaceRoleViewer))
+	require.NoError(t, svc.CheckUserAccess(ctx, "ws-1", "user-2", models.WorkspaceRoleViewer))
+	assert.Equal(t, 4, members.lookups)
+
+	t.Run("without an operation context every check looks up", func(t *testing.T) {
+		members.lookups = 0
+		require.NoError(t, svc.CheckUserAccess(context.Background(), "ws-1", "user-1", models.WorkspaceRoleViewer))
+		require.NoError(t, svc.CheckUserAccess(context.Background(), "ws-1", "user-1", models.WorkspaceRoleViewer))
+		assert.Equal(t, 2, members.lookups)
+	})
+}
+
+func TestOperationMiddleware(t *testing.T) {
+	var op *services.OperationContext
+	app := fiber.New()
+	app.Use(func(c *fiber.Ctx) error {
+		c.Locals("user_id", "user-1")
+		c.Locals("tenant_id", "tenant-1")
+		return c.Next()
+	})
+	app.Use(middleware.Operation())
+	app.Get("/", func(c *fiber.Ctx) error {
+		op, _ = c.Locals("operation").(*services.OperationContext)
+		return c.SendStatus(fiber.StatusOK)
+	})
+
+	req, _ := http.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("X-Request-ID", "req-42")
+	_, err := app.Test(req)
+	require.NoError(t, err)
+
+	require.NotNil(t, op)
+	assert.Equal(t, "user-1", op.UserID())
+	assert.Equal(t, "tenant-1", op.TenantID())
+	assert.Equal(t, "req-42", op.RequestID())
+}
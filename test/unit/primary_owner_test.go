@@ -0,0 +1,47 @@
+package unit
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/config"
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/models"
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/repositories"
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/services"
+)
+
+// primaryMembers holds two owners, the first of them primary; it supports no writes
+type primaryMembers struct {
+	repositories.WorkspaceMemberRepository
+}
+
+func (r *primaryMembers) GetByWorkspaceAndUser(ctx context.Context, workspaceID, userID string) (*models.WorkspaceMember, error) {
+	switch userID {
+	case "primary":
+		return &models.WorkspaceMember{WorkspaceID: workspaceID, UserID: userID, Role: models.WorkspaceRoleOwner, IsPrimary: true}, nil
+	case "co-owner":
+		return &models.WorkspaceMember{WorkspaceID: workspaceID, UserID: userID, Role: models.WorkspaceRoleOwner}, nil
+	}
+	return nil, repositories.ErrMemberNotFound
+}
+
+func TestPrimaryOwnerCannotBeDemotedOrRemoved(t *testing.T) {
+	repos := &repositories.Repositories{Member: &primaryMembers{}, Cache: &nopCache{}}
+	svc := services.NewMemberService(repos, &config.Config{}, zap.NewNop(), &nopAudit{}, nil, nil)
+	ctx := context.Background()
+
+	_, err := svc.UpdateMemberRole(ctx, "ws-1", "primary", "co-owner", &models.UpdateWorkspaceMemberRequest{Role: models.WorkspaceRoleAdmin})
+	assert.Equal(t, services.ErrPrimaryOwner, err)
+
+	assert.Equal(t, services.ErrPrimaryOwner, svc.RemoveMember(ctx, "ws-1", "primary", "co-owner"))
+	assert.Equal(t, services.ErrPrimaryOwner, svc.RemoveMember(ctx, "ws-1", "primary", "primary"))
+	assert.Equal(t, services.ErrPrimaryOwner, svc.RemoveMemberAndReassign(ctx, "ws-1", "primary", "co-owner", "co-owner"))
+
+	// Already primary: nothing to reassign
+	member, err := svc.SetPrimaryOwner(ctx, "ws-1", "primary", "co-owner")
+	assert.NoError(t, err)
+	assert.True(t, member.IsPrimary)
+}
@@ -0,0 +1,70 @@
+package unit
+
+import (
+	"context"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/config"
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/models"
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/repositories"
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/services"
+)
+
+// scannedMembers is a roleMembers that can also be scanned in user order
+type scannedMembers struct {
+	roleMembers
+}
+
+func (r *scannedMembers) Scan(ctx context.Context, workspaceID string, batchSize int, fn func(batch []*models.WorkspaceMember) error) error {
+	userIDs := make([]string, 0, len(r.roles))
+	for userID := range r.roles {
+		userIDs = append(userIDs, userID)
+	}
+	sort.Strings(userIDs)
+
+	batch := make([]*models.WorkspaceMember, 0, len(userIDs))
+	for _, userID := range userIDs {
+		batch = append(batch, &models.WorkspaceMember{WorkspaceID: workspaceID, UserID: userID, Role: r.roles[userID]})
+	}
+	return fn(batch)
+}
+
+func TestGetProjectAccess(t *testing.T) {
+	revokedAt := time.Now()
+	repos := &repositories.Repositories{
+		Project: &storedProject{project: &models.Project{BaseModel: models.BaseModel{ID: "project-1"}, WorkspaceID: "ws-1"}},
+		Member: &scannedMembers{roleMembers{roles: map[string]models.WorkspaceMemberRole{
+			"admin":  models.WorkspaceRoleAdmin,
+			"member": models.WorkspaceRoleMember,
+		}}},
+		ServiceAccount: &memoryServiceAccounts{accounts: []*models.ServiceAccount{
+			{BaseModel: models.BaseModel{ID: "sa-sync"}, WorkspaceID: "ws-1", Role: models.WorkspaceRoleMember},
+			{BaseModel: models.BaseModel{ID: "sa-old"}, WorkspaceID: "ws-1", Role: models.WorkspaceRoleAdmin, RevokedAt: &revokedAt},
+		}},
+	}
+	cfg := &config.Config{Platform: config.PlatformConfig{Admins: "platform-admin"}}
+	svc := services.NewProjectService(repos, cfg, zap.NewNop(), nil)
+	ctx := context.Background()
+
+	access, err := svc.GetProjectAccess(ctx, "project-1", "admin")
+	require.NoError(t, err)
+	assert.Equal(t, "ws-1", access.WorkspaceID)
+	assert.Equal(t, []*models.ProjectAccessEntry{
+		{UserID: "admin", Role: models.WorkspaceRoleAdmin, Source: models.ProjectAccessSourceWorkspaceMember},
+		{UserID: "member", Role: models.WorkspaceRoleMember, Source: models.ProjectAccessSourceWorkspaceMember},
+		{UserID: "service-account:sa-sync", Role: models.WorkspaceRoleMember, Source: models.ProjectAccessSourceServiceAccount},
+	}, access.Users)
+	assert.Equal(t, 3, access.Total)
+
+	_, err = svc.GetProjectAccess(ctx, "project-1", "member")
+	assert.Equal(t, services.ErrUnauthorized, err)
+
+	_, err = svc.GetProjectAccess(ctx, "project-1", "platform-admin")
+	assert.NoError(t, err)
+}
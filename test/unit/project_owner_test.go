@@ -0,0 +1,115 @@
+package unit
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/config"
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/models"
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/repositories"
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/services"
+)
+
+// storedProject serves a single project and records updates to it
+type storedProject struct {
+	repositories.ProjectRepository
+	project *models.Project
+	updates int
+}
+
+func (r *storedProject) GetByID(ctx context.Context, id string) (*models.Project, error) {
+	copied := *r.project
+	return &copied, nil
+}
+
+func (r *storedProject) Update(ctx context.Context, project *models.Project) error {
+	r.updates++
+	r.project = project
+	return nil
+}
+
+// recordingActions remembers the audit actions logged
+type recordingActions struct {
+	services.AuditService
+	actions []string
+}
+
+func (a *recordingActions) LogAction(ctx context.Context, workspaceID, userID, action, resourceType, resourceID string, changes map[string]interface{}) error {
+	a.actions = append(a.actions, action)
+	return nil
+}
+
+func TestSetProjectOwner(t *testing.T) {
+	tests := []struct {
+		name          string
+		actorID       string
+		newOwnerID    string
+		expectedErr   error
+		expectedOwner string
+	}{
+		{
+			name:          "admin transfers to a member",
+			actorID:       "admin-1",
+			newOwnerID:    "member-1",
+			expectedOwner: "member-1",
+		},
+		{
+			name:          "members cannot transfer",
+			actorID:       "member-1",
+			newOwnerID:    "member-1",
+			expectedErr:   services.ErrUnauthorized,
+			expectedOwner: "creator-1",
+		},
+		{
+			name:          "non-members cannot transfer",
+			actorID:       "stranger",
+			newOwnerID:    "member-1",
+			expectedErr:   services.ErrUnauthorized,
+			expectedOwner: "creator-1",
+		},
+		{
+			name:          "new owner must be a workspace member",
+			actorID:       "admin-1",
+			newOwnerID:    "stranger",
+			expectedErr:   services.ErrInvalidOwner,
+			expectedOwner: "creator-1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			projects := &storedProject{project: &models.Project{
+				BaseModel:   models.BaseModel{ID: "proj-1"},
+				WorkspaceID: "ws-1",
+				Name:        "Roadmap",
+				CreatedBy:   "creator-1",
+			}}
+			members := &roleMembers{roles: map[string]models.WorkspaceMemberRole{
+				"admin-1":   models.WorkspaceRoleAdmin,
+				"member-1":  models.WorkspaceRoleMember,
+				"creator-1": models.WorkspaceRoleMember,
+			}}
+			audit := &recordingActions{}
+			repos := &repositories.Repositories{Project: projects, Member: members, Cache: &nopCache{}}
+			svc := services.NewProjectService(repos, &config.Config{}, zap.NewNop(), audit)
+
+			project, err := svc.SetProjectOwner(context.Background(), "proj-1", tt.newOwnerID, tt.actorID)
+
+			assert.Equal(t, tt.expectedOwner, projects.project.CreatedBy)
+			if tt.expectedErr != nil {
+				assert.Equal(t, tt.expectedErr, err)
+				assert.Zero(t, projects.updates)
+				assert.Empty(t, audit.actions)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedOwner, project.CreatedBy)
+			assert.Equal(t, []string{models.AuditActionProjectOwnerChanged}, audit.actions)
+		})
+	}
+}
@@ -0,0 +1,90 @@
+package unit
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/config"
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/middleware"
+)
+
+func TestQueryLimits(t *testing.T) {
+	app := fiber.New()
+	app.Use(middleware.QueryLimits(config.APIConfig{MaxQueryParams: 5, MaxQueryLength: 200}))
+	app.Get("/projects", func(c *fiber.Ctx) error {
+		return c.SendStatus(fiber.StatusNoContent)
+	})
+
+	status := func(t *testing.T, query string) int {
+		req, _ := http.NewRequest(http.MethodGet, "/projects?"+query, nil)
+		resp, err := app.Test(req, -1)
+		require.NoError(t, err)
+		return resp.StatusCode
+	}
+
+	assert.Equal(t, http.StatusNoContent, status(t, ""))
+	assert.Equal(t, http.StatusNoContent, status(t, "a=1&b=2&c=3&d=4&e=5"))
+	assert.Equal(t, http.StatusBadRequest, status(t, "a=1&b=2&c=3&d=4&e=5&f=6"))
+	assert.Equal(t, http.StatusBadRequest, status(t, "search="+strings.Repeat("x", 200)))
+
+	t.Run("zero limits disable the checks", func(t *testing.T) {
+		unlimited := fiber.New()
+		unlimited.Use(middleware.QueryLimits(config.APIConfig{}))
+		unlimited.Get("/projects", func(c *fiber.Ctx) error {
+			return c.SendStatus(fiber.StatusNoContent)
+		})
+
+		req, _ := http.NewRequest(http.MethodGet, "/projects?"+strings.Repeat("p=1&", 1000), nil)
+		resp, err := unlimited.Test(req, -1)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+	})
+}
+
+func TestPageDepth(t *testing.T) {
+	app := fiber.New()
+	app.Use(middleware.PageDepth(config.APIConfig{MaxPageOffset: 1000}))
+	app.Get("/projects", func(c *fiber.Ctx) error {
+		return c.SendStatus(fiber.StatusNoContent)
+	})
+
+	status := func(t *testing.T, query string) int {
+		req, _ := http.NewRequest(http.MethodGet, "/projects?"+query, nil)
+		resp, err := app.Test(req, -1)
+		require.NoError(t, err)
+		return resp.StatusCode
+	}
+
+	assert.Equal(t, http.StatusNoContent, status(t, ""))
+	// Offset 1000 is the last reachable page start
+	assert.Equal(t, http.StatusNoContent, status(t, "page=51"))
+	assert.Equal(t, http.StatusBadRequest, status(t, "page=52"))
+	assert.Equal(t, http.StatusNoContent, status(t, "page=11&page_size=100"))
+	assert.Equal(t, http.StatusBadRequest, status(t, "page=12&page_size=100"))
+	// Oversized page sizes count as the 100 the repositories clamp them to
+	assert.Equal(t, http.StatusBadRequest, status(t, "page=12&page_size=5000"))
+	assert.Equal(t, http.StatusBadRequest, status(t, "page=1000000"))
+	assert.Equal(t, http.StatusBadRequest, status(t, "page=9223372036854775807"))
+
+	// Cursors skip no rows, and malformed pages are left to the handlers
+	assert.Equal(t, http.StatusNoContent, status(t, "page=1000000&cursor=abc"))
+	assert.Equal(t, http.StatusNoContent, status(t, "page=deep"))
+
+	t.Run("zero disables the check", func(t *testing.T) {
+		unlimited := fiber.New()
+		unlimited.Use(middleware.PageDepth(config.APIConfig{}))
+		unlimited.Get("/projects", func(c *fiber.Ctx) error {
+			return c.SendStatus(fiber.StatusNoContent)
+		})
+
+		req, _ := http.NewRequest(http.MethodGet, "/projects?page=1000000", nil)
+		resp, err := unlimited.Test(req, -1)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+	})
+}
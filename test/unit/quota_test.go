@@ -0,0 +1,74 @@
+package unit
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/config"
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/models"
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/repositories"
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/services"
+)
+
+// tenantWorkspaceCount reports a fixed number of workspaces in every tenant
+type tenantWorkspaceCount struct {
+	repositories.WorkspaceRepository
+	count int64
+}
+
+func (r *tenantWorkspaceCount) List(ctx context.Context, filter *models.WorkspaceFilter) ([]*models.Workspace, int64, error) {
+	return nil, r.count, nil
+}
+
+func TestNewQuotaUsage(t *testing.T) {
+	int64Ptr := func(v int64) *int64 { return &v }
+
+	tests := []struct {
+		name      string
+		used      int64
+		limit     int
+		remaining *int64
+	}{
+		{name: "headroom left", used: 3, limit: 10, remaining: int64Ptr(7)},
+		{name: "full", used: 10, limit: 10, remaining: int64Ptr(0)},
+		{name: "over a lowered limit", used: 12, limit: 10, remaining: int64Ptr(0)},
+		{name: "unlimited", used: 12, limit: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			usage := models.NewQuotaUsage(tt.used, tt.limit)
+			assert.Equal(t, tt.used, usage.Used)
+			assert.Equal(t, tt.remaining, usage.Remaining)
+			if tt.limit == 0 {
+				assert.Nil(t, usage.Limit)
+			}
+		})
+	}
+}
+
+func TestGetTenantQuota(t *testing.T) {
+	cfg := &config.Config{
+		Quota:    config.QuotaConfig{WorkspacesPerTenant: 10},
+		Platform: config.PlatformConfig{Admins: "platform-admin"},
+	}
+	repos := &repositories.Repositories{Workspace: &tenantWorkspaceCount{count: 4}}
+	svc := services.NewWorkspaceService(repos, cfg, zap.NewNop(), nil, nil)
+	ctx := context.Background()
+
+	quota, err := svc.GetTenantQuota(ctx, "tenant-1", "tenant-1", "user-1")
+	require.NoError(t, err)
+	assert.Equal(t, "tenant-1", quota.TenantID)
+	assert.Equal(t, int64(4), quota.Workspaces.Used)
+	assert.Equal(t, int64(6), *quota.Workspaces.Remaining)
+
+	_, err = svc.GetTenantQuota(ctx, "tenant-2", "tenant-1", "user-1")
+	assert.Equal(t, services.ErrUnauthorized, err)
+
+	_, err = svc.GetTenantQuota(ctx, "tenant-2", "tenant-1", "platform-admin")
+	assert.NoError(t, err)
+}
@@ -0,0 +1,65 @@
+package unit
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/config"
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/middleware"
+)
+
+func TestRateLimitTenantOverrides(t *testing.T) {
+	cfg := config.RateLimitConfig{
+		RequestsPerMinute: 2,
+		TenantOverrides:   "premium=5;broken=lots;zero=0",
+	}
+
+	app := fiber.New()
+	app.Use(func(c *fiber.Ctx) error {
+		c.Locals("tenant_id", c.Get("X-Tenant-ID"))
+		return c.Next()
+	})
+	app.Use(middleware.RateLimit(cfg))
+	app.Get("/", func(c *fiber.Ctx) error {
+		return c.SendStatus(fiber.StatusOK)
+	})
+
+	// allowed counts requests answered before the tenant is throttled
+	allowed := func(tenantID string) int {
+		for n := 0; n < 10; n++ {
+			req, _ := http.NewRequest(http.MethodGet, "/", nil)
+			req.Header.Set("X-Tenant-ID", tenantID)
+			resp, err := app.Test(req)
+			require.NoError(t, err)
+			if resp.StatusCode == fiber.StatusTooManyRequests {
+				return n
+			}
+		}
+		return 10
+	}
+
+	assert.Equal(t, 5, allowed("premium"), "override raises the limit")
+	assert.Equal(t, 2, allowed("standard"), "tenants without an override keep the default")
+	assert.Equal(t, 2, allowed("other"), "each tenant has its own bucket")
+	assert.Equal(t, 2, allowed("broken"), "malformed overrides are ignored")
+	assert.Equal(t, 2, allowed("zero"), "non-positive overrides are ignored")
+}
+
+func TestRateLimitDisabledByDefault(t *testing.T) {
+	app := fiber.New()
+	app.Use(middleware.RateLimit(config.RateLimitConfig{}))
+	app.Get("/", func(c *fiber.Ctx) error {
+		return c.SendStatus(fiber.StatusOK)
+	})
+
+	for n := 0; n < 20; n++ {
+		req, _ := http.NewRequest(http.MethodGet, "/", nil)
+		resp, err := app.Test(req)
+		require.NoError(t, err)
+		assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+	}
+}
@@ -0,0 +1,170 @@
+package unit
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/alicebob/miniredis/v2"
+	"github.com/gofiber/fiber/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/config"
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/handlers"
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/repositories"
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/services"
+	"github.com/Reg-Kris/pyairtable-workspace-service/pkg/health"
+)
+
+func TestReadiness(t *testing.T) {
+	tests := []struct {
+		name           string
+		queued         int
+		heartbeat      bool
+		expectedStatus int
+		expectedReport string
+	}{
+		{
+			name:           "healthy subsystems are ready",
+			queued:         1,
+			heartbeat:      true,
+			expectedStatus: http.StatusOK,
+			expectedReport: health.StatusOK,
+		},
+		{
+			name:           "saturated audit queue is not ready",
+			queued:         4,
+			heartbeat:      true,
+			expectedStatus: http.StatusServiceUnavailable,
+			expectedReport: health.StatusDown,
+		},
+		{
+			name:           "dead non-critical worker only degrades",
+			queued:         1,
+			expectedStatus: http.StatusOK,
+			expectedReport: health.StatusDegraded,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			auditQueue := make(chan struct{}, 4)
+			for i := 0; i < tt.queued; i++ {
+				auditQueue <- struct{}{}
+			}
+
+			worker := &health.Heartbeat{}
+			if tt.heartbeat {
+				worker.Beat()
+			}
+
+			h := handlers.New(nil, nil, zap.NewNop())
+			h.Readiness().Register(health.Check{
+				Name:     "audit_queue",
+				Critical: true,
+				Probe: health.QueueProbe(
+					func() int { return len(auditQueue) },
+					func() int { return cap(auditQueue) },
+				),
+			})
+			h.Readiness().Register(health.Check{
+				Name:  "sync_worker",
+				Probe: worker.Probe(time.Minute),
+			})
+
+			app := fiber.New()
+			app.Get("/ready", h.Ready)
+
+			req, _ := http.NewRequest("GET", "/ready", nil)
+			resp, err := app.Test(req, -1)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedStatus, resp.StatusCode)
+
+			var body struct {
+				Status     string                   `json:"status"`
+				Subsystems []health.SubsystemReport `json:"subsystems"`
+			}
+			require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+			assert.Equal(t, tt.expectedReport, body.Status)
+			assert.Len(t, body.Subsystems, 2)
+		})
+	}
+}
+
+func TestReadinessChecksStores(t *testing.T) {
+	type report struct {
+		Status     string                   `json:"status"`
+		Subsystems []health.SubsystemReport `json:"subsystems"`
+	}
+
+	setup := func(t *testing.T) (*fiber.App, sqlmock.Sqlmock, *miniredis.Miniredis) {
+		conn, mock, err := sqlmock.New(sqlmock.MonitorPingsOption(true))
+		require.NoError(t, err)
+		t.Cleanup(func() { conn.Close() })
+		db, err := gorm.Open(postgres.New(postgres.Config{Conn: conn}), &gorm.Config{DisableAutomaticPing: true})
+		require.NoError(t, err)
+
+		server := miniredis.RunT(t)
+		client := redis.NewClient(&redis.Options{Addr: server.Addr(), MaxRetries: -1})
+		t.Cleanup(func() { client.Close() })
+
+		cfg := &config.Config{}
+		repos := repositories.New(db, client, cfg, zap.NewNop())
+		h := handlers.New(services.New(repos, cfg, zap.NewNop(), nil, nil, nil, nil), cfg, zap.NewNop())
+
+		app := fiber.New()
+		app.Get("/ready", h.Ready)
+		return app, mock, server
+	}
+
+	ready := func(t *testing.T, app *fiber.App) (int, report) {
+		req, _ := http.NewRequest("GET", "/ready", nil)
+		resp, err := app.Test(req, -1)
+		require.NoError(t, err)
+
+		var body report
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+		return resp.StatusCode, body
+	}
+
+	t.Run("both stores answering", func(t *testing.T) {
+		app, mock, _ := setup(t)
+		mock.ExpectPing()
+
+		status, body := ready(t, app)
+		assert.Equal(t, http.StatusOK, status)
+		assert.Equal(t, health.StatusOK, body.Status)
+		require.Len(t, body.Subsystems, 2)
+		assert.Equal(t, "database", body.Subsystems[0].Name)
+		assert.Equal(t, "redis", body.Subsystems[1].Name)
+	})
+
+	t.Run("an unreachable database is not ready", func(t *testing.T) {
+		app, mock, _ := setup(t)
+		mock.ExpectPing().WillReturnError(errors.New("connection refused"))
+
+		status, body := ready(t, app)
+		assert.Equal(t, http.StatusServiceUnavailable, status)
+		assert.Equal(t, health.StatusDown, body.Status)
+		assert.Equal(t, health.StatusDown, body.Subsystems[0].Status)
+	})
+
+	t.Run("an unreachable Redis only degrades", func(t *testing.T) {
+		app, mock, server := setup(t)
+		mock.ExpectPing()
+		server.Close()
+
+		status, body := ready(t, app)
+		assert.Equal(t, http.StatusOK, status)
+		assert.Equal(t, health.StatusDegraded, body.Status)
+		assert.Equal(t, health.StatusDegraded, body.Subsystems[1].Status)
+	})
+}
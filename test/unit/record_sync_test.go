@@ -0,0 +1,59 @@
+package unit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/config"
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/models"
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/repositories"
+)
+
+func TestRecordSyncIsOneUpdate(t *testing.T) {
+	syncTime := time.Date(2024, 3, 4, 5, 6, 7, 0, time.UTC)
+
+	setup := func(t *testing.T) (repositories.AirtableBaseRepository, sqlmock.Sqlmock) {
+		conn, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		t.Cleanup(func() { conn.Close() })
+		db, err := gorm.Open(postgres.New(postgres.Config{Conn: conn}), &gorm.Config{SkipDefaultTransaction: true})
+		require.NoError(t, err)
+		return repositories.NewAirtableBaseRepository(db, &config.Config{}, zap.NewNop()), mock
+	}
+
+	t.Run("success advances the last sync time", func(t *testing.T) {
+		bases, mock := setup(t)
+		mock.ExpectExec(`UPDATE "airtable_bases" SET "last_sync_at"=\$1,"last_sync_attempt_at"=\$2,"last_sync_duration_ms"=\$3,"last_sync_error"=\$4,"sync_status"=\$5,"updated_at"=\$6 WHERE`).
+			WithArgs(syncTime, syncTime, 1500, "", models.AirtableSyncStatusIdle, sqlmock.AnyArg(), "base-1").
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		require.NoError(t, bases.RecordSync(context.Background(), "base-1", models.AirtableSyncStatusIdle, "", 1500, syncTime))
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("failure keeps the last successful sync time", func(t *testing.T) {
+		bases, mock := setup(t)
+		mock.ExpectExec(`UPDATE "airtable_bases" SET "last_sync_attempt_at"=\$1,"last_sync_duration_ms"=\$2,"last_sync_error"=\$3,"sync_status"=\$4,"updated_at"=\$5 WHERE`).
+			WithArgs(syncTime, 320, "gateway timeout", models.AirtableSyncStatusFailed, sqlmock.AnyArg(), "base-1").
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		require.NoError(t, bases.RecordSync(context.Background(), "base-1", models.AirtableSyncStatusFailed, "gateway timeout", 320, syncTime))
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("unknown bases are reported", func(t *testing.T) {
+		bases, mock := setup(t)
+		mock.ExpectExec(`UPDATE "airtable_bases"`).WillReturnResult(sqlmock.NewResult(0, 0))
+
+		err := bases.RecordSync(context.Background(), "missing", models.AirtableSyncStatusIdle, "", 10, syncTime)
+		assert.Equal(t, repositories.ErrAirtableBaseNotFound, err)
+	})
+}
@@ -0,0 +1,86 @@
+package unit
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/config"
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/models"
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/repositories"
+	"github.com/Reg-Kris/pyairtable-workspace-service/pkg/database"
+)
+
+// openWithReplica opens a GORM handle on a mocked primary with a mocked replica registered
+func openWithReplica(t *testing.T) (*gorm.DB, sqlmock.Sqlmock, sqlmock.Sqlmock) {
+	primaryConn, primary, err := sqlmock.New()
+	require.NoError(t, err)
+	replicaConn, replica, err := sqlmock.New()
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		primaryConn.Close()
+		replicaConn.Close()
+	})
+
+	db, err := gorm.Open(postgres.New(postgres.Config{Conn: primaryConn}), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, database.UseReplica(db, postgres.New(postgres.Config{Conn: replicaConn})))
+
+	return db, primary, replica
+}
+
+func TestReadReplicaRouting(t *testing.T) {
+	countQuery := `SELECT count\(\*\) FROM "workspaces"`
+	listQuery := `SELECT \* FROM "workspaces"`
+
+	t.Run("list reads hit the replica", func(t *testing.T) {
+		db, primary, replica := openWithReplica(t)
+		workspaces := repositories.NewWorkspaceRepository(db, &config.Config{}, zap.NewNop())
+
+		replica.ExpectQuery(countQuery).WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+		replica.ExpectQuery(listQuery).WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow("ws-1", "Replica"))
+
+		list, total, err := workspaces.List(context.Background(), &models.WorkspaceFilter{TenantID: "tenant-1"})
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), total)
+		require.Len(t, list, 1)
+		assert.Equal(t, "Replica", list[0].Name)
+
+		assert.NoError(t, replica.ExpectationsWereMet())
+		assert.NoError(t, primary.ExpectationsWereMet())
+	})
+
+	t.Run("writes hit the primary", func(t *testing.T) {
+		db, primary, replica := openWithReplica(t)
+
+		primary.ExpectBegin()
+		primary.ExpectQuery(`INSERT INTO "workspaces"`).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("ws-1"))
+		primary.ExpectCommit()
+
+		require.NoError(t, db.Create(&models.Workspace{TenantID: "tenant-1", Name: "Primary", CreatedBy: "user-1"}).Error)
+
+		assert.NoError(t, primary.ExpectationsWereMet())
+		assert.NoError(t, replica.ExpectationsWereMet())
+	})
+
+	t.Run("forced reads hit the primary", func(t *testing.T) {
+		db, primary, replica := openWithReplica(t)
+		workspaces := repositories.NewWorkspaceRepository(db, &config.Config{}, zap.NewNop())
+
+		primary.ExpectQuery(countQuery).WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+		primary.ExpectQuery(listQuery).WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+		_, total, err := workspaces.List(database.WithPrimary(context.Background()), &models.WorkspaceFilter{TenantID: "tenant-1"})
+		require.NoError(t, err)
+		assert.Zero(t, total)
+
+		assert.NoError(t, primary.ExpectationsWereMet())
+		assert.NoError(t, replica.ExpectationsWereMet())
+	})
+}
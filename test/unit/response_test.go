@@ -0,0 +1,138 @@
+package unit
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/models"
+	"github.com/Reg-Kris/pyairtable-workspace-service/pkg/response"
+)
+
+func TestSelectFields(t *testing.T) {
+	workspace := &models.Workspace{
+		BaseModel:   models.BaseModel{ID: "ws-1"},
+		TenantID:    "tenant-1",
+		Name:        "Acme",
+		Description: "Main workspace",
+	}
+
+	tests := []struct {
+		name     string
+		fields   string
+		strict   bool
+		expected map[string]interface{}
+		wantErr  bool
+	}{
+		{
+			name:     "selects requested fields and keeps id",
+			fields:   "name,tenant_id",
+			expected: map[string]interface{}{"id": "ws-1", "name": "Acme", "tenant_id": "tenant-1"},
+		},
+		{
+			name:     "id is present even when not requested",
+			fields:   "description",
+			expected: map[string]interface{}{"id": "ws-1", "description": "Main workspace"},
+		},
+		{
+			name:     "unknown fields are ignored when not strict",
+			fields:   "name,bogus",
+			expected: map[string]interface{}{"id": "ws-1", "name": "Acme"},
+		},
+		{
+			name:    "unknown fields are rejected when strict",
+			fields:  "name,bogus",
+			strict:  true,
+			wantErr: true,
+		},
+		{
+			name:     "embedded fields are known when strict",
+			fields:   "created_at",
+			strict:   true,
+			expected: map[string]interface{}{"id": "ws-1", "created_at": "0001-01-01T00:00:00Z"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			shaped, err := response.SelectFields(workspace, response.ParseFields(tt.fields), tt.strict)
+			if tt.wantErr {
+				var unknown *response.UnknownFieldError
+				assert.ErrorAs(t, err, &unknown)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, shaped)
+		})
+	}
+}
+
+func TestSelectListFields(t *testing.T) {
+	list := &models.ProjectListResponse{
+		Projects: []*models.Project{
+			{BaseModel: models.BaseModel{ID: "p-1"}, Name: "One", Status: "active"},
+			{BaseModel: models.BaseModel{ID: "p-2"}, Name: "Two", Status: "archived"},
+		},
+		Total:    2,
+		Page:     1,
+		PageSize: 20,
+	}
+
+	shaped, err := response.SelectListFields(list, "projects", response.ParseFields("name"), true)
+	require.NoError(t, err)
+
+	obj := shaped.(map[string]interface{})
+	assert.Equal(t, float64(2), obj["total"], "envelope is untouched")
+	assert.Equal(t, []interface{}{
+		map[string]interface{}{"id": "p-1", "name": "One"},
+		map[string]interface{}{"id": "p-2", "name": "Two"},
+	}, obj["projects"])
+}
+
+func TestHideDeletedAt(t *testing.T) {
+	deletedAt := gorm.DeletedAt{Time: time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC), Valid: true}
+	list := &models.ProjectListResponse{
+		Projects: []*models.Project{
+			{BaseModel: models.BaseModel{ID: "p-live"}, Name: "Live"},
+			{BaseModel: models.BaseModel{ID: "p-deleted", DeletedAt: deletedAt}, Name: "Deleted"},
+		},
+		Total: 2,
+	}
+
+	tests := []struct {
+		name          string
+		visible       map[string]bool
+		expectDeleted bool
+	}{
+		{
+			name:          "admin sees deleted_at on deleted rows",
+			visible:       map[string]bool{"p-deleted": true},
+			expectDeleted: true,
+		},
+		{
+			name:    "non-admin never sees deleted_at",
+			visible: map[string]bool{"p-deleted": false},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			shaped, err := response.HideDeletedAt(list, "projects", tt.visible)
+			require.NoError(t, err)
+
+			projects := shaped.(map[string]interface{})["projects"].([]interface{})
+			assert.NotContains(t, projects[0], "deleted_at", "live rows omit deleted_at")
+
+			deleted := projects[1].(map[string]interface{})
+			if tt.expectDeleted {
+				assert.Equal(t, "2024-05-01T12:00:00Z", deleted["deleted_at"])
+			} else {
+				assert.NotContains(t, deleted, "deleted_at")
+			}
+		})
+	}
+}
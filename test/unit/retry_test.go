@@ -0,0 +1,99 @@
+package unit
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Reg-Kris/pyairtable-workspace-service/pkg/database"
+)
+
+// flakyRepository fails with the configured error a fixed number of times before succeeding
+type flakyRepository struct {
+	failures int
+	err      error
+	calls    int
+}
+
+func (r *flakyRepository) Get() error {
+	r.calls++
+	if r.calls <= r.failures {
+		return r.err
+	}
+	return nil
+}
+
+func TestRetry(t *testing.T) {
+	policy := database.RetryPolicy{
+		MaxAttempts: 3,
+		BaseDelay:   time.Millisecond,
+		MaxDelay:    5 * time.Millisecond,
+	}
+
+	tests := []struct {
+		name          string
+		shouldRetry   func(error) bool
+		failures      int
+		err           error
+		expectedCalls int
+		expectErr     bool
+	}{
+		{
+			name:          "read succeeds after one transient failure",
+			shouldRetry:   database.IsTransient,
+			failures:      1,
+			err:           &pgconn.PgError{Code: "40001"},
+			expectedCalls: 2,
+		},
+		{
+			name:          "write retries deadlock",
+			shouldRetry:   database.IsSerializationFailure,
+			failures:      1,
+			err:           &pgconn.PgError{Code: "40P01"},
+			expectedCalls: 2,
+		},
+		{
+			name:          "non-transient error is not retried",
+			shouldRetry:   database.IsTransient,
+			failures:      1,
+			err:           errors.New("record not found"),
+			expectedCalls: 1,
+			expectErr:     true,
+		},
+		{
+			name:          "unique violation is not retried",
+			shouldRetry:   database.IsSerializationFailure,
+			failures:      1,
+			err:           &pgconn.PgError{Code: "23505"},
+			expectedCalls: 1,
+			expectErr:     true,
+		},
+		{
+			name:          "gives up after max attempts",
+			shouldRetry:   database.IsTransient,
+			failures:      5,
+			err:           &pgconn.PgError{Code: "40001"},
+			expectedCalls: 3,
+			expectErr:     true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &flakyRepository{failures: tt.failures, err: tt.err}
+
+			err := database.Retry(context.Background(), policy, tt.shouldRetry, repo.Get)
+
+			assert.Equal(t, tt.expectedCalls, repo.calls)
+			if tt.expectErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,69 @@
+package unit
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/config"
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/models"
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/repositories"
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/services"
+)
+
+// addableMembers are role members that also accept new members
+type addableMembers struct {
+	roleMembers
+}
+
+func (r *addableMembers) Add(ctx context.Context, member *models.WorkspaceMember) error {
+	r.roles[member.UserID] = member.Role
+	return nil
+}
+
+func TestMembersCannotBeGrantedAboveTheActor(t *testing.T) {
+	tests := []struct {
+		name     string
+		actor    models.WorkspaceMemberRole
+		grant    models.WorkspaceMemberRole
+		expected error
+	}{
+		{name: "admin grants admin", actor: models.WorkspaceRoleAdmin, grant: models.WorkspaceRoleAdmin},
+		{name: "admin grants viewer", actor: models.WorkspaceRoleAdmin, grant: models.WorkspaceRoleViewer},
+		{name: "admin grants owner", actor: models.WorkspaceRoleAdmin, grant: models.WorkspaceRoleOwner, expected: services.ErrUnauthorized},
+		{name: "owner grants owner", actor: models.WorkspaceRoleOwner, grant: models.WorkspaceRoleOwner},
+	}
+
+	for _, tt := range tests {
+		newService := func() services.MemberService {
+			members := &addableMembers{roleMembers{roles: map[string]models.WorkspaceMemberRole{
+				"actor":    tt.actor,
+				"member-1": models.WorkspaceRoleMember,
+			}}}
+			repos := &repositories.Repositories{Member: members, Cache: &nopCache{}}
+			return services.NewMemberService(repos, &config.Config{}, zap.NewNop(), &nopAudit{}, &recordingPublisher{}, nil)
+		}
+
+		t.Run(tt.name+" when adding", func(t *testing.T) {
+			_, err := newService().AddMember(context.Background(), "ws-1", "actor", &models.AddWorkspaceMemberRequest{UserID: "user-2", Role: tt.grant})
+			assert.Equal(t, tt.expected, err)
+		})
+
+		t.Run(tt.name+" when updating", func(t *testing.T) {
+			_, err := newService().UpdateMemberRole(context.Background(), "ws-1", "member-1", "actor", &models.UpdateWorkspaceMemberRequest{Role: tt.grant})
+			assert.Equal(t, tt.expected, err)
+		})
+	}
+
+	t.Run("admin cannot raise their own role", func(t *testing.T) {
+		members := &addableMembers{roleMembers{roles: map[string]models.WorkspaceMemberRole{"admin-1": models.WorkspaceRoleAdmin}}}
+		repos := &repositories.Repositories{Member: members, Cache: &nopCache{}}
+		svc := services.NewMemberService(repos, &config.Config{}, zap.NewNop(), &nopAudit{}, &recordingPublisher{}, nil)
+
+		_, err := svc.UpdateMemberRole(context.Background(), "ws-1", "admin-1", "admin-1", &models.UpdateWorkspaceMemberRequest{Role: models.WorkspaceRoleOwner})
+		assert.Equal(t, services.ErrUnauthorized, err)
+		assert.Equal(t, models.WorkspaceRoleAdmin, members.roles["admin-1"])
+	})
+}
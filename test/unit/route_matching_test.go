@@ -0,0 +1,67 @@
+package unit
+
+import (
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/config"
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/handlers"
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/middleware"
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/services"
+)
+
+func TestRoutesIgnoreTrailingSlashAndCase(t *testing.T) {
+	cfg := &config.Config{Platform: config.PlatformConfig{Admins: "platform-admin"}}
+
+	for _, strict := range []bool{false, true} {
+		app := fiber.New(fiber.Config{StrictRouting: strict})
+		app.Use(func(c *fiber.Ctx) error {
+			c.Locals("user_id", "platform-admin")
+			return c.Next()
+		})
+		handlers.New(&services.Services{}, cfg, zap.NewNop()).RegisterRoutes(app)
+
+		status := func(t *testing.T, path string) int {
+			req, _ := http.NewRequest(http.MethodGet, path, nil)
+			resp, err := app.Test(req, -1)
+			require.NoError(t, err)
+			return resp.StatusCode
+		}
+
+		name := "lenient routing"
+		if strict {
+			name = "strict routing"
+		}
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, http.StatusOK, status(t, "/api/v1/admin/config"))
+			assert.Equal(t, http.StatusOK, status(t, "/api/v1/admin/config/"))
+			assert.Equal(t, http.StatusOK, status(t, "/API/v1/Admin/Config/"))
+			assert.Equal(t, http.StatusOK, status(t, "/api/v1/admin/config?page=2"))
+
+			assert.Equal(t, http.StatusNotFound, status(t, "/api/v1/admin/configs"))
+			assert.Equal(t, http.StatusNotFound, status(t, "/api/v1/admin/configs/"))
+		})
+	}
+
+	t.Run("path parameters keep their case", func(t *testing.T) {
+		app := fiber.New(fiber.Config{StrictRouting: true})
+		app.Use(middleware.TrailingSlash())
+		app.Get("/members/:user_id", func(c *fiber.Ctx) error {
+			return c.SendString(c.Params("user_id"))
+		})
+
+		req, _ := http.NewRequest(http.MethodGet, "/Members/User-A/", nil)
+		resp, err := app.Test(req, -1)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		assert.Equal(t, "User-A", string(body))
+	})
+}
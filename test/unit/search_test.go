@@ -0,0 +1,65 @@
+package unit
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/config"
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/models"
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/repositories"
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/services"
+)
+
+// listingWorkspaces returns an empty page and counts list queries
+type listingWorkspaces struct {
+	repositories.WorkspaceRepository
+	lists int
+}
+
+func (r *listingWorkspaces) List(ctx context.Context, filter *models.WorkspaceFilter) ([]*models.Workspace, int64, error) {
+	r.lists++
+	return []*models.Workspace{}, 0, nil
+}
+
+// missCache never has user workspaces cached
+type missCache struct {
+	repositories.CacheRepository
+}
+
+func (c *missCache) GetUserWorkspaces(ctx context.Context, userID string) ([]string, error) {
+	return nil, nil
+}
+
+func TestListWorkspacesMinimumSearchLength(t *testing.T) {
+	cfg := &config.Config{API: config.APIConfig{MinSearchLength: 2}}
+
+	tests := []struct {
+		name        string
+		search      string
+		expectedErr error
+	}{
+		{name: "no search term", search: ""},
+		{name: "term at the minimum length", search: "ab"},
+		{name: "multi-byte term counted by characters", search: "日本"},
+		{name: "single character", search: "a", expectedErr: services.ErrInvalidInput},
+		{name: "padding does not count", search: " a ", expectedErr: services.ErrInvalidInput},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			workspaces := &listingWorkspaces{}
+			repos := &repositories.Repositories{Workspace: workspaces, Cache: &missCache{}}
+			svc := services.NewWorkspaceService(repos, cfg, zap.NewNop(), nil, nil)
+
+			_, err := svc.ListWorkspaces(context.Background(), &models.WorkspaceFilter{Search: tt.search}, "user-1")
+
+			assert.Equal(t, tt.expectedErr, err)
+			if tt.expectedErr != nil {
+				assert.Equal(t, 0, workspaces.lists, "rejected searches must not reach the database")
+			}
+		})
+	}
+}
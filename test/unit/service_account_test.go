@@ -0,0 +1,213 @@
+package unit
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/config"
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/middleware"
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/models"
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/repositories"
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/services"
+)
+
+// memoryServiceAccounts keeps service accounts in memory
+type memoryServiceAccounts struct {
+	repositories.ServiceAccountRepository
+	accounts []*models.ServiceAccount
+}
+
+func (r *memoryServiceAccounts) Create(ctx context.Context, account *models.ServiceAccount) error {
+	account.ID = "sa-" + account.Name
+	r.accounts = append(r.accounts, account)
+	return nil
+}
+
+func (r *memoryServiceAccounts) GetByID(ctx context.Context, id string) (*models.ServiceAccount, error) {
+	for _, account := range r.accounts {
+		if account.ID == id {
+			copied := *account
+			return &copied, nil
+		}
+	}
+	return nil, repositories.ErrServiceAccountNotFound
+}
+
+func (r *memoryServiceAccounts) GetActiveByTokenHash(ctx context.Context, tokenHash string) (*models.ServiceAccount, error) {
+	for _, account := range r.accounts {
+		if account.TokenHash == tokenHash && account.RevokedAt == nil {
+			copied := *account
+			return &copied, nil
+		}
+	}
+	return nil, repositories.ErrServiceAccountNotFound
+}
+
+func (r *memoryServiceAccounts) ListByWorkspace(ctx context.Context, workspaceID string) ([]*models.ServiceAccount, error) {
+	var accounts []*models.ServiceAccount
+	for _, account := range r.accounts {
+		if account.WorkspaceID == workspaceID {
+			copied := *account
+			accounts = append(accounts, &copied)
+		}
+	}
+	return accounts, nil
+}
+
+func (r *memoryServiceAccounts) Revoke(ctx context.Context, id string, at time.Time) error {
+	for _, account := range r.accounts {
+		if account.ID == id && account.RevokedAt == nil {
+			account.RevokedAt = &at
+		}
+	}
+	return nil
+}
+
+func TestServiceAccountTokenAuth(t *testing.T) {
+	workspaces := &storedWorkspace{workspace: &models.Workspace{TenantID: "tenant-1", Name: "Automations"}}
+	workspaces.workspace.ID = "ws-1"
+	accounts := &memoryServiceAccounts{}
+	repos := &repositories.Repositories{
+		Workspace:      workspaces,
+		ServiceAccount: accounts,
+		// Every user looked up here is an owner, so a service account resolving its role from
+		// memberships would be granted far more than its own role
+		Member: &singleMember{role: models.WorkspaceRoleOwner},
+		Cache:  &nopCache{},
+	}
+	audit := &recordingChanges{}
+	svc := services.NewServiceAccountService(repos, &config.Config{}, zap.NewNop(), audit)
+	workspaceSvc := services.NewWorkspaceService(repos, &config.Config{}, zap.NewNop(), audit, nil)
+	ctx := context.Background()
+
+	created, err := svc.CreateServiceAccount(ctx, "ws-1", "owner-1", &models.CreateServiceAccountRequest{
+		Name: "nightly-import",
+		Role: models.WorkspaceRoleMember,
+	})
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(created.Token, models.ServiceAccountTokenPrefix))
+	assert.NotContains(t, created.TokenHash, created.Token)
+	assert.Equal(t, "tenant-1", created.TenantID)
+	assert.Equal(t, []string{models.AuditActionServiceAccountCreated}, audit.actions)
+
+	app := fiber.New()
+	app.Use(middleware.ServiceAccount(svc), middleware.Operation())
+	app.Get("/access/:workspace_id/:role", func(c *fiber.Ctx) error {
+		op := c.Locals("operation").(*services.OperationContext)
+		assert.Equal(t, created.PrincipalID(), c.Locals("user_id"))
+		assert.Equal(t, "tenant-1", op.TenantID())
+
+		reqCtx := services.WithOperation(c.UserContext(), op)
+		role := models.WorkspaceMemberRole(c.Params("role"))
+		if err := workspaceSvc.CheckUserAccess(reqCtx, c.Params("workspace_id"), op.UserID(), role); err != nil {
+			return c.SendStatus(fiber.StatusForbidden)
+		}
+		return c.SendStatus(fiber.StatusNoContent)
+	})
+
+	access := func(t *testing.T, token, path string) int {
+		req, _ := http.NewRequest(http.MethodGet, path, nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		resp, err := app.Test(req, -1)
+		require.NoError(t, err)
+		return resp.StatusCode
+	}
+
+	t.Run("token grants exactly the account's role in its workspace", func(t *testing.T) {
+		assert.Equal(t, http.StatusNoContent, access(t, created.Token, "/access/ws-1/viewer"))
+		assert.Equal(t, http.StatusNoContent, access(t, created.Token, "/access/ws-1/member"))
+		assert.Equal(t, http.StatusForbidden, access(t, created.Token, "/access/ws-1/admin"))
+		assert.Equal(t, http.StatusForbidden, access(t, created.Token, "/access/ws-2/viewer"))
+	})
+
+	t.Run("unknown tokens are rejected", func(t *testing.T) {
+		assert.Equal(t, http.StatusUnauthorized, access(t, models.ServiceAccountTokenPrefix+"unknown", "/access/ws-1/viewer"))
+	})
+
+	t.Run("revoked tokens stop authenticating", func(t *testing.T) {
+		require.NoError(t, svc.RevokeServiceAccount(ctx, "ws-1", created.ID, "owner-1"))
+		assert.Equal(t, http.StatusUnauthorized, access(t, created.Token, "/access/ws-1/viewer"))
+
+		assert.Equal(t, services.ErrServiceAccountNotFound, svc.RevokeServiceAccount(ctx, "ws-2", created.ID, "owner-1"))
+	})
+}
+
+func TestServiceAccountManagementRequiresOwner(t *testing.T) {
+	workspaces := &storedWorkspace{workspace: &models.Workspace{TenantID: "tenant-1"}}
+	workspaces.workspace.ID = "ws-1"
+	ctx := context.Background()
+	newService := func(role models.WorkspaceMemberRole) services.ServiceAccountService {
+		repos := &repositories.Repositories{
+			Workspace:      workspaces,
+			ServiceAccount: &memoryServiceAccounts{},
+			Member:         &singleMember{role: role},
+		}
+		return services.NewServiceAccountService(repos, &config.Config{}, zap.NewNop(), &recordingChanges{})
+	}
+	req := &models.CreateServiceAccountRequest{Name: "bot", Role: models.WorkspaceRoleViewer}
+
+	_, err := newService(models.WorkspaceRoleAdmin).CreateServiceAccount(ctx, "ws-1", "admin-1", req)
+	assert.Equal(t, services.ErrUnauthorized, err)
+	_, err = newService(models.WorkspaceRoleAdmin).ListServiceAccounts(ctx, "ws-1", "admin-1")
+	assert.Equal(t, services.ErrUnauthorized, err)
+	assert.Equal(t, services.ErrUnauthorized, newService(models.WorkspaceRoleAdmin).RevokeServiceAccount(ctx, "ws-1", "sa-bot", "admin-1"))
+
+	_, err = newService(models.WorkspaceRoleOwner).CreateServiceAccount(ctx, "ws-1", "owner-1", &models.CreateServiceAccountRequest{
+		Name: "bot",
+		Role: models.WorkspaceRoleOwner,
+	})
+	assert.Equal(t, services.ErrInvalidInput, err)
+}
+
+func TestServiceAccountsStayInTheirWorkspace(t *testing.T) {
+	accounts := &memoryServiceAccounts{}
+	repos := &repositories.Repositories{
+		Workspace:      &storedWorkspace{workspace: &models.Workspace{BaseModel: models.BaseModel{ID: "ws-1"}, TenantID: "tenant-1"}},
+		ServiceAccount: accounts,
+		Member:         &singleMember{role: models.WorkspaceRoleOwner},
+		Cache:          &nopCache{},
+	}
+	svc := services.NewServiceAccountService(repos, &config.Config{}, zap.NewNop(), &recordingChanges{})
+	created, err := svc.CreateServiceAccount(context.Background(), "ws-1", "owner-1", &models.CreateServiceAccountRequest{
+		Name: "nightly-import",
+		Role: models.WorkspaceRoleAdmin,
+	})
+	require.NoError(t, err)
+
+	t.Run("user and tenant routes refuse the token", func(t *testing.T) {
+		app := fiber.New()
+		app.Use(middleware.ServiceAccount(svc))
+		app.Get("/users/me/workspaces", middleware.UserOnly(), func(c *fiber.Ctx) error {
+			return c.SendStatus(fiber.StatusNoContent)
+		})
+
+		get := func(token string) int {
+			req, _ := http.NewRequest(http.MethodGet, "/users/me/workspaces", nil)
+			if token != "" {
+				req.Header.Set("Authorization", "Bearer "+token)
+			}
+			resp, err := app.Test(req, -1)
+			require.NoError(t, err)
+			return resp.StatusCode
+		}
+
+		assert.Equal(t, http.StatusForbidden, get(created.Token))
+		assert.Equal(t, http.StatusNoContent, get(""))
+	})
+
+	t.Run("service accounts cannot create workspaces", func(t *testing.T) {
+		workspaceSvc := services.NewWorkspaceService(repos, &config.Config{}, zap.NewNop(), &nopAudit{}, nil)
+		ctx := services.WithOperation(context.Background(), services.NewServiceAccountOperationContext(created.ServiceAccount, "req-1"))
+
+		_, err := workspaceSvc.CreateWorkspace(ctx, "tenant-1", created.PrincipalID(), &models.CreateWorkspaceRequest{Name: "Side project"})
+		assert.Equal(t, services.ErrUnauthorized, err)
+	})
+}
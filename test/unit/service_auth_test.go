@@ -0,0 +1,47 @@
+package unit
+
+import (
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/config"
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/middleware"
+)
+
+func TestServiceAuth(t *testing.T) {
+	app := fiber.New()
+	app.Use(middleware.ServiceAuth(config.ServiceAuthConfig{Principals: "importer:importer-token:tenant-1;auditor:auditor-token:*"}))
+	app.Get("/", func(c *fiber.Ctx) error {
+		return c.SendString(c.Locals("service_principal").(*config.ServicePrincipal).Name)
+	})
+
+	principal := func(t *testing.T, token string) (int, string) {
+		req, _ := http.NewRequest(http.MethodGet, "/", nil)
+		if token != "" {
+			req.Header.Set("X-Service-Token", token)
+		}
+		resp, err := app.Test(req, -1)
+		require.NoError(t, err)
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		return resp.StatusCode, string(body)
+	}
+
+	status, name := principal(t, "importer-token")
+	assert.Equal(t, http.StatusOK, status)
+	assert.Equal(t, "importer", name)
+
+	status, name = principal(t, "auditor-token")
+	assert.Equal(t, http.StatusOK, status)
+	assert.Equal(t, "auditor", name)
+
+	for _, token := range []string{"", "importer-toke", "importer-token-x", "IMPORTER-TOKEN"} {
+		status, _ = principal(t, token)
+		assert.Equal(t, http.StatusUnauthorized, status, token)
+	}
+}
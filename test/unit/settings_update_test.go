@@ -0,0 +1,69 @@
+package unit
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/config"
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/models"
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/repositories"
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/services"
+)
+
+func TestUpdateWorkspaceSettingsClearVersusOmit(t *testing.T) {
+	nilSettings := models.JSONMap(nil)
+
+	tests := []struct {
+		name     string
+		body     string
+		req      *models.UpdateWorkspaceRequest
+		expected models.JSONMap
+		audited  bool
+	}{
+		{name: "omitted leaves settings unchanged", body: `{"name":"Original"}`, expected: models.JSONMap{"theme": "light"}},
+		{name: "null leaves settings unchanged", body: `{"settings":null}`, expected: models.JSONMap{"theme": "light"}},
+		{name: "empty object clears settings", body: `{"settings":{}}`, expected: models.JSONMap{}, audited: true},
+		{name: "object replaces settings", body: `{"settings":{"locale":"fr"}}`, expected: models.JSONMap{"locale": "fr"}, audited: true},
+		{name: "present nil map clears settings", req: &models.UpdateWorkspaceRequest{Settings: &nilSettings}, expected: models.JSONMap{}, audited: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := tt.req
+			if req == nil {
+				req = &models.UpdateWorkspaceRequest{}
+				require.NoError(t, json.Unmarshal([]byte(tt.body), req))
+			}
+
+			workspaces := &storedWorkspace{workspace: &models.Workspace{
+				Name:     "Original",
+				Settings: models.JSONMap{"theme": "light"},
+			}}
+			workspaces.workspace.ID = "ws-1"
+			audit := &recordingChanges{}
+			repos := &repositories.Repositories{
+				Workspace: workspaces,
+				Member:    &singleMember{role: models.WorkspaceRoleAdmin},
+				Cache:     &nopCache{},
+			}
+			svc := services.NewWorkspaceService(repos, &config.Config{}, zap.NewNop(), audit, nil)
+
+			updated, err := svc.UpdateWorkspace(context.Background(), "ws-1", "user-1", req)
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.expected, updated.Settings)
+			require.NotNil(t, workspaces.workspace.Settings)
+			assert.Equal(t, tt.expected, workspaces.workspace.Settings)
+			if tt.audited {
+				assert.Equal(t, []string{models.AuditActionWorkspaceSettingsChanged}, audit.actions)
+			} else {
+				assert.Empty(t, audit.actions)
+			}
+		})
+	}
+}
@@ -0,0 +1,54 @@
+package unit
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/config"
+)
+
+func TestSortConfigDefault(t *testing.T) {
+	sorts := config.SortConfig{Members: "role asc", Projects: "name"}
+
+	column, order := sorts.Default(config.SortListMembers)
+	assert.Equal(t, "role", column)
+	assert.Equal(t, "ASC", order)
+
+	column, order = sorts.Default(config.SortListProjects)
+	assert.Equal(t, "name", column)
+	assert.Equal(t, "ASC", order)
+
+	column, order = sorts.Default(config.SortListWorkspaces)
+	assert.Equal(t, "created_at", column)
+	assert.Equal(t, "DESC", order)
+}
+
+func TestLoadValidatesDefaultSorts(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+		valid bool
+	}{
+		{name: "allowlisted column", value: "name asc", valid: true},
+		{name: "column without direction", value: "updated_at", valid: true},
+		{name: "column outside the allowlist", value: "settings asc"},
+		{name: "unknown direction", value: "name sideways"},
+		{name: "sql fragment", value: "name; drop table projects"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("SORT_DEFAULT_PROJECTS", tt.value)
+
+			cfg, err := config.Load()
+			if tt.valid {
+				require.NoError(t, err)
+				assert.Equal(t, tt.value, cfg.Sort.Projects)
+			} else {
+				assert.ErrorContains(t, err, "invalid default sort for projects")
+			}
+		})
+	}
+}
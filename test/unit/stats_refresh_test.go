@@ -0,0 +1,107 @@
+package unit
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/config"
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/models"
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/repositories"
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/services"
+)
+
+// statsCache stores workspace stats by tenant
+type statsCache struct {
+	repositories.CacheRepository
+	stats map[string]*models.WorkspaceStats
+}
+
+func (c *statsCache) GetWorkspaceStats(ctx context.Context, tenantID string) (*models.WorkspaceStats, error) {
+	return c.stats[tenantID], nil
+}
+
+func (c *statsCache) SetWorkspaceStats(ctx context.Context, tenantID string, stats *models.WorkspaceStats) error {
+	c.stats[tenantID] = stats
+	return nil
+}
+
+func (c *statsCache) InvalidateTenantStats(ctx context.Context, tenantID string) error {
+	delete(c.stats, tenantID)
+	return nil
+}
+
+// countedWorkspaces reports the number of workspaces it was told the database holds
+type countedWorkspaces struct {
+	repositories.WorkspaceRepository
+	total int64
+}
+
+func (r *countedWorkspaces) GetStats(ctx context.Context, tenantID string, filter *models.WorkspaceStatsFilter) (*models.WorkspaceStats, error) {
+	return &models.WorkspaceStats{TotalWorkspaces: r.total}, nil
+}
+
+func TestRefreshWorkspaceStats(t *testing.T) {
+	// The cached copy has drifted from the database
+	cache := &statsCache{stats: map[string]*models.WorkspaceStats{
+		"tenant-1": {TotalWorkspaces: 3},
+	}}
+	workspaces := &countedWorkspaces{total: 5}
+	repos := &repositories.Repositories{Workspace: workspaces, Cache: cache}
+	cfg := &config.Config{Platform: config.PlatformConfig{Admins: "ops-1"}}
+	svc := services.NewWorkspaceService(repos, cfg, zap.NewNop(), nil, nil)
+	ctx := context.Background()
+
+	stale, err := svc.GetWorkspaceStats(ctx, "tenant-1", "user-1", &models.WorkspaceStatsFilter{})
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), stale.TotalWorkspaces)
+
+	t.Run("non admins are rejected", func(t *testing.T) {
+		_, err := svc.RefreshWorkspaceStats(ctx, "tenant-1", "user-1")
+		assert.Equal(t, services.ErrUnauthorized, err)
+		assert.Equal(t, int64(3), cache.stats["tenant-1"].TotalWorkspaces)
+	})
+
+	t.Run("refresh overwrites the cached stats", func(t *testing.T) {
+		fresh, err := svc.RefreshWorkspaceStats(ctx, "tenant-1", "ops-1")
+		require.NoError(t, err)
+		assert.Equal(t, int64(5), fresh.TotalWorkspaces)
+		assert.Equal(t, int64(5), cache.stats["tenant-1"].TotalWorkspaces)
+
+		served, err := svc.GetWorkspaceStats(ctx, "tenant-1", "user-1", &models.WorkspaceStatsFilter{})
+		require.NoError(t, err)
+		assert.Equal(t, int64(5), served.TotalWorkspaces)
+	})
+}
+
+func TestBaseChangesInvalidateTenantStats(t *testing.T) {
+	cache := &statsCache{stats: map[string]*models.WorkspaceStats{
+		"tenant-1": {TotalAirtableBases: 0},
+		"tenant-2": {TotalAirtableBases: 4},
+	}}
+	repos := &repositories.Repositories{
+		Workspace: &storedWorkspace{workspace: &models.Workspace{BaseModel: models.BaseModel{ID: "ws-1"}, TenantID: "tenant-1"}},
+		Project: &storedProject{project: &models.Project{
+			BaseModel:   models.BaseModel{ID: "proj-1"},
+			WorkspaceID: "ws-1",
+		}},
+		AirtableBase: &deletableBases{storedBases: storedBases{bases: map[string]*models.AirtableBase{}}},
+		Member:       &roleMembers{roles: map[string]models.WorkspaceMemberRole{"admin-1": models.WorkspaceRoleAdmin}},
+		Cache:        cache,
+	}
+	svc := services.NewAirtableBaseService(repos, &config.Config{}, zap.NewNop(), &nopAudit{}, nil)
+	ctx := context.Background()
+
+	base, err := svc.ConnectBase(ctx, "proj-1", "admin-1", &models.CreateAirtableBaseRequest{BaseID: "appSales", Name: "Sales"})
+	require.NoError(t, err)
+	assert.NotContains(t, cache.stats, "tenant-1")
+	assert.Contains(t, cache.stats, "tenant-2")
+
+	cache.stats["tenant-1"] = &models.WorkspaceStats{TotalAirtableBases: 1}
+	require.NoError(t, svc.DisconnectBase(ctx, base.ID, "admin-1", false))
+	assert.NotContains(t, cache.stats, "tenant-1")
+	assert.Contains(t, cache.stats, "tenant-2")
+}
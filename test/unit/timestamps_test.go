@@ -0,0 +1,61 @@
+package unit
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/models"
+)
+
+func TestTimestampsSerializeAsUTC(t *testing.T) {
+	// 12:30 in UTC+05:30 is 07:00Z
+	local := time.Date(2024, 3, 1, 12, 30, 0, 0, time.FixedZone("IST", 5*60*60+30*60))
+	const expected = "2024-03-01T07:00:00Z"
+
+	decode := func(t *testing.T, v interface{}) map[string]interface{} {
+		data, err := json.Marshal(v)
+		require.NoError(t, err)
+		var obj map[string]interface{}
+		require.NoError(t, json.Unmarshal(data, &obj))
+		return obj
+	}
+
+	t.Run("workspace read from the database", func(t *testing.T) {
+		workspace := &models.Workspace{BaseModel: models.BaseModel{
+			CreatedAt: local,
+			UpdatedAt: local,
+			DeletedAt: gorm.DeletedAt{Time: local, Valid: true},
+		}}
+		require.NoError(t, workspace.AfterFind(nil))
+
+		obj := decode(t, workspace)
+		assert.Equal(t, expected, obj["created_at"])
+		assert.Equal(t, expected, obj["updated_at"])
+		assert.Equal(t, expected, obj["deleted_at"])
+	})
+
+	t.Run("airtable base written to the database", func(t *testing.T) {
+		base := &models.AirtableBase{BaseModel: models.BaseModel{CreatedAt: local}, LastSyncAt: &local}
+		require.NoError(t, base.BeforeSave(nil))
+
+		obj := decode(t, base)
+		assert.Equal(t, expected, obj["created_at"])
+		assert.Equal(t, expected, obj["last_sync_at"])
+		assert.Equal(t, "IST", local.Location().String(), "the caller's value is not modified")
+	})
+
+	t.Run("member and audit log read from the database", func(t *testing.T) {
+		member := &models.WorkspaceMember{JoinedAt: local}
+		require.NoError(t, member.AfterFind(nil))
+		assert.Equal(t, expected, decode(t, member)["joined_at"])
+
+		log := &models.WorkspaceAuditLog{CreatedAt: local}
+		require.NoError(t, log.AfterFind(nil))
+		assert.Equal(t, expected, decode(t, log)["created_at"])
+	})
+}
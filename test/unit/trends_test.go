@@ -0,0 +1,65 @@
+package unit
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/config"
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/models"
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/repositories"
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/services"
+)
+
+// trendsCache stores trend results keyed by window length
+type trendsCache struct {
+	repositories.CacheRepository
+	trends map[int]*models.WorkspaceTrends
+}
+
+func (c *trendsCache) GetWorkspaceTrends(ctx context.Context, tenantID string, days int) (*models.WorkspaceTrends, error) {
+	return c.trends[days], nil
+}
+
+func (c *trendsCache) SetWorkspaceTrends(ctx context.Context, tenantID string, trends *models.WorkspaceTrends) error {
+	c.trends[trends.Days] = trends
+	return nil
+}
+
+// trendWorkspaces computes trends and records the windows requested
+type trendWorkspaces struct {
+	repositories.WorkspaceRepository
+	windows []int
+}
+
+func (r *trendWorkspaces) GetTrends(ctx context.Context, tenantID string, days int) (*models.WorkspaceTrends, error) {
+	r.windows = append(r.windows, days)
+	return &models.WorkspaceTrends{Days: days, AvgProjectsPerWorkspace: 1.5}, nil
+}
+
+func TestGetWorkspaceTrendsIsCached(t *testing.T) {
+	cache := &trendsCache{trends: map[int]*models.WorkspaceTrends{}}
+	workspaces := &trendWorkspaces{}
+	repos := &repositories.Repositories{Workspace: workspaces, Cache: cache}
+	svc := services.NewWorkspaceService(repos, &config.Config{}, zap.NewNop(), nil, nil)
+	ctx := context.Background()
+
+	first, err := svc.GetWorkspaceTrends(ctx, "tenant-1", "user-1", &models.WorkspaceTrendsFilter{})
+	require.NoError(t, err)
+	assert.Equal(t, 30, first.Days)
+
+	_, err = svc.GetWorkspaceTrends(ctx, "tenant-1", "user-1", &models.WorkspaceTrendsFilter{Days: 30})
+	require.NoError(t, err)
+
+	capped, err := svc.GetWorkspaceTrends(ctx, "tenant-1", "user-1", &models.WorkspaceTrendsFilter{Days: 5000})
+	require.NoError(t, err)
+	assert.Equal(t, 365, capped.Days)
+
+	_, err = svc.GetWorkspaceTrends(services.WithCacheBypass(ctx), "tenant-1", "user-1", &models.WorkspaceTrendsFilter{})
+	require.NoError(t, err)
+
+	assert.Equal(t, []int{30, 365, 30}, workspaces.windows)
+}
@@ -0,0 +1,140 @@
+package unit
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/config"
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/models"
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/repositories"
+)
+
+func TestDuplicateDetection(t *testing.T) {
+	cfg := &config.Config{Database: config.DatabaseConfig{RetryMaxAttempts: 1}}
+
+	open := func(t *testing.T) (*gorm.DB, sqlmock.Sqlmock) {
+		conn, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		t.Cleanup(func() { conn.Close() })
+		db, err := gorm.Open(postgres.New(postgres.Config{Conn: conn}), &gorm.Config{})
+		require.NoError(t, err)
+		return db, mock
+	}
+
+	// Each resource's check is scoped and soft-delete aware, and a concurrent insert that slips
+	// past it is reported through its unique index as the same error
+	resources := []struct {
+		name   string
+		table  string
+		check  string
+		index  string
+		err    error
+		create func(db *gorm.DB) error
+	}{
+		{
+			name:  "workspace",
+			table: "workspaces",
+			check: `SELECT count\(\*\) FROM "workspaces" WHERE tenant_id = \$1 AND deleted_at IS NULL AND name = \$2`,
+			index: "idx_workspaces_tenant_name",
+			err:   repositories.ErrDuplicateWorkspace,
+			create: func(db *gorm.DB) error {
+				return repositories.NewWorkspaceRepository(db, cfg, zap.NewNop()).
+					Create(context.Background(), &models.Workspace{TenantID: "tenant-1", Name: "Sales", CreatedBy: "user-1"})
+			},
+		},
+		{
+			name:  "project",
+			table: "projects",
+			check: `SELECT count\(\*\) FROM "projects" WHERE workspace_id = \$1 AND deleted_at IS NULL AND name = \$2`,
+			index: "idx_projects_workspace_name",
+			err:   repositories.ErrDuplicateProject,
+			create: func(db *gorm.DB) error {
+				return repositories.NewProjectRepository(db, cfg, zap.NewNop()).
+					Create(context.Background(), &models.Project{WorkspaceID: "ws-1", Name: "Pipeline", CreatedBy: "user-1"})
+			},
+		},
+		{
+			name:  "airtable base",
+			table: "airtable_bases",
+			check: `SELECT count\(\*\) FROM "airtable_bases" WHERE project_id = \$1 AND deleted_at IS NULL AND base_id = \$2`,
+			index: "idx_airtable_bases_project_base",
+			err:   repositories.ErrDuplicateAirtableBase,
+			create: func(db *gorm.DB) error {
+				return repositories.NewAirtableBaseRepository(db, cfg, zap.NewNop()).
+					Create(context.Background(), &models.AirtableBase{ProjectID: "project-1", BaseID: "appOne", Name: "base", CreatedBy: "user-1"})
+			},
+		},
+		{
+			name:  "member",
+			table: "workspace_members",
+			check: `SELECT count\(\*\) FROM "workspace_members" WHERE workspace_id = \$1 AND user_id = \$2`,
+			index: "workspace_members_pkey",
+			err:   repositories.ErrDuplicateMember,
+			create: func(db *gorm.DB) error {
+				return repositories.NewWorkspaceMemberRepository(db, cfg, zap.NewNop()).
+					Add(context.Background(), &models.WorkspaceMember{WorkspaceID: "ws-1", UserID: "user-2", Role: models.WorkspaceRoleMember})
+			},
+		},
+	}
+
+	for _, rc := range resources {
+		t.Run(rc.name+" already present", func(t *testing.T) {
+			db, mock := open(t)
+			mock.ExpectQuery(rc.check).WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+
+			assert.Equal(t, rc.err, rc.create(db))
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+
+		t.Run(rc.name+" inserted concurrently", func(t *testing.T) {
+			db, mock := open(t)
+			mock.ExpectQuery(rc.check).WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+			mock.ExpectBegin()
+			mock.ExpectQuery(`INSERT INTO "` + rc.table + `"`).
+				WillReturnError(&pgconn.PgError{Code: "23505", ConstraintName: rc.index})
+			mock.ExpectRollback()
+
+			assert.Equal(t, rc.err, rc.create(db))
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+
+		t.Run(rc.name+" violating another index", func(t *testing.T) {
+			db, mock := open(t)
+			mock.ExpectQuery(rc.check).WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+			mock.ExpectBegin()
+			mock.ExpectQuery(`INSERT INTO "` + rc.table + `"`).
+				WillReturnError(&pgconn.PgError{Code: "23505", ConstraintName: "idx_unrelated"})
+			mock.ExpectRollback()
+
+			err := rc.create(db)
+			assert.Error(t, err)
+			assert.NotEqual(t, rc.err, err)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+
+	t.Run("renames exclude the row itself and map the case-insensitive index", func(t *testing.T) {
+		db, mock := open(t)
+		ciCfg := &config.Config{Names: config.NamesConfig{CaseInsensitive: true}, Database: cfg.Database}
+		mock.ExpectQuery(`SELECT count\(\*\) FROM "projects" WHERE workspace_id = \$1 AND deleted_at IS NULL AND id != \$2 AND LOWER\(TRIM\(name\)\) = \$3`).
+			WithArgs("ws-1", "project-1", "pipeline").
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+		mock.ExpectBegin()
+		mock.ExpectExec(`UPDATE "projects"`).
+			WillReturnError(&pgconn.PgError{Code: "23505", ConstraintName: "idx_projects_workspace_name_ci"})
+		mock.ExpectRollback()
+
+		err := repositories.NewProjectRepository(db, ciCfg, zap.NewNop()).
+			Update(context.Background(), &models.Project{BaseModel: models.BaseModel{ID: "project-1"}, WorkspaceID: "ws-1", Name: " Pipeline "})
+		assert.Equal(t, repositories.ErrDuplicateProject, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
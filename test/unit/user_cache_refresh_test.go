@@ -0,0 +1,123 @@
+package unit
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/config"
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/models"
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/repositories"
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/services"
+)
+
+// activeUserCache tracks active users in memory and counts rebuilds per user; it is safe to
+// share with a running refresh job
+type activeUserCache struct {
+	repositories.CacheRepository
+	mu       sync.Mutex
+	seen     map[string]time.Time
+	rebuilds map[string]int
+}
+
+func (c *activeUserCache) GetUserWorkspaces(ctx context.Context, userID string) ([]string, error) {
+	return []string{"ws-1"}, nil
+}
+
+func (c *activeUserCache) MarkUserActive(ctx context.Context, userID string, at time.Time) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.seen[userID] = at
+	return nil
+}
+
+func (c *activeUserCache) ActiveUsers(ctx context.Context, since time.Time, limit int) ([]string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var userIDs []string
+	for userID, at := range c.seen {
+		if !at.Before(since) && len(userIDs) < limit {
+			userIDs = append(userIDs, userID)
+		}
+	}
+	return userIDs, nil
+}
+
+func (c *activeUserCache) RebuildUserWorkspaces(ctx context.Context, userID string) ([]string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.rebuilds[userID]++
+	return []string{"ws-1"}, nil
+}
+
+func (c *activeUserCache) rebuildsOf(userID string) int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.rebuilds[userID]
+}
+
+func TestUserCacheRefreshJobWarmsActiveUsers(t *testing.T) {
+	cache := &activeUserCache{
+		seen:     map[string]time.Time{"long-gone": time.Now().Add(-time.Hour)},
+		rebuilds: map[string]int{},
+	}
+	workspaces := &liveWorkspaces{workspaces: map[string]*models.Workspace{
+		"ws-1": {BaseModel: models.BaseModel{ID: "ws-1"}},
+	}}
+	cfg := &config.Config{CacheRefresh: config.CacheRefreshConfig{Enabled: true, ActiveWindow: 900, MaxUsers: 10}}
+	repos := &repositories.Repositories{Workspace: workspaces, Cache: cache}
+	svc := services.NewMemberService(repos, cfg, zap.NewNop(), nil, nil, nil)
+
+	// One request marks the user active
+	_, err := svc.GetUserWorkspaces(context.Background(), "user-1", "")
+	require.NoError(t, err)
+	assert.Zero(t, cache.rebuildsOf("user-1"))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		services.RunUserCacheRefreshJob(ctx, svc, 10*time.Millisecond, zap.NewNop())
+		close(done)
+	}()
+	t.Cleanup(func() {
+		cancel()
+		<-done
+	})
+
+	// Later runs refresh the entry with no further request
+	assert.Eventually(t, func() bool { return cache.rebuildsOf("user-1") >= 2 }, time.Second, 5*time.Millisecond)
+	assert.Zero(t, cache.rebuildsOf("long-gone"))
+}
+
+func TestActiveUsersArePrunedAndBounded(t *testing.T) {
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	t.Cleanup(func() { client.Close() })
+	cache := repositories.NewCacheRepository(client, nil, &config.Config{}, zap.NewNop())
+	ctx := context.Background()
+
+	now := time.Now()
+	require.NoError(t, cache.MarkUserActive(ctx, "stale", now.Add(-time.Hour)))
+	require.NoError(t, cache.MarkUserActive(ctx, "earlier", now.Add(-2*time.Minute)))
+	require.NoError(t, cache.MarkUserActive(ctx, "latest", now))
+
+	userIDs, err := cache.ActiveUsers(ctx, now.Add(-15*time.Minute), 10)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"latest", "earlier"}, userIDs)
+
+	// Users seen before the window are dropped from the set
+	members, err := client.ZRange(ctx, "v1:users:active", 0, -1).Result()
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"earlier", "latest"}, members)
+
+	userIDs, err = cache.ActiveUsers(ctx, now.Add(-15*time.Minute), 1)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"latest"}, userIDs)
+}
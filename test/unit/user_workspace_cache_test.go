@@ -0,0 +1,76 @@
+package unit
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/config"
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/models"
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/repositories"
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/services"
+)
+
+// userWorkspaceCache holds a cached workspace list and rebuilds it from memberships
+type userWorkspaceCache struct {
+	repositories.CacheRepository
+	cached      []string
+	memberships []string
+	rebuilds    int
+}
+
+func (c *userWorkspaceCache) GetUserWorkspaces(ctx context.Context, userID string) ([]string, error) {
+	return c.cached, nil
+}
+
+func (c *userWorkspaceCache) RebuildUserWorkspaces(ctx context.Context, userID string) ([]string, error) {
+	c.rebuilds++
+	c.cached = append([]string(nil), c.memberships...)
+	return c.cached, nil
+}
+
+// liveWorkspaces resolves only the workspaces it holds
+type liveWorkspaces struct {
+	repositories.WorkspaceRepository
+	workspaces map[string]*models.Workspace
+}
+
+func (r *liveWorkspaces) GetByID(ctx context.Context, id string) (*models.Workspace, error) {
+	workspace, ok := r.workspaces[id]
+	if !ok {
+		return nil, repositories.ErrWorkspaceNotFound
+	}
+	return workspace, nil
+}
+
+func TestGetUserWorkspacesHealsStaleCache(t *testing.T) {
+	cache := &userWorkspaceCache{
+		cached:      []string{"ws-deleted", "ws-1"},
+		memberships: []string{"ws-1", "ws-2"},
+	}
+	workspaces := &liveWorkspaces{workspaces: map[string]*models.Workspace{
+		"ws-1": {BaseModel: models.BaseModel{ID: "ws-1"}},
+		"ws-2": {BaseModel: models.BaseModel{ID: "ws-2"}},
+	}}
+	repos := &repositories.Repositories{Workspace: workspaces, Cache: cache}
+	svc := services.NewMemberService(repos, &config.Config{}, zap.NewNop(), nil, nil, nil)
+
+	result, err := svc.GetUserWorkspaces(context.Background(), "user-1", "")
+	require.NoError(t, err)
+
+	ids := make([]string, 0, len(result))
+	for _, workspace := range result {
+		ids = append(ids, workspace.ID)
+	}
+	assert.Equal(t, []string{"ws-1", "ws-2"}, ids)
+	assert.Equal(t, 1, cache.rebuilds)
+	assert.Equal(t, []string{"ws-1", "ws-2"}, cache.cached)
+
+	// The healed cache is served without another rebuild
+	_, err = svc.GetUserWorkspaces(context.Background(), "user-1", "")
+	require.NoError(t, err)
+	assert.Equal(t, 1, cache.rebuilds)
+}
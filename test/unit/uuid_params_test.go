@@ -0,0 +1,59 @@
+package unit
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/config"
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/handlers"
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/services"
+)
+
+func TestMalformedPathIDsAreRejected(t *testing.T) {
+	// No services are wired: a request getting past the ID check would panic
+	h := handlers.New(&services.Services{}, &config.Config{}, zap.NewNop())
+	app := fiber.New()
+	app.Use(func(c *fiber.Ctx) error {
+		c.Locals("user_id", "user-1")
+		return c.Next()
+	})
+	h.RegisterRoutes(app)
+
+	tests := []struct {
+		method string
+		path   string
+		param  string
+	}{
+		{http.MethodGet, "/api/v1/workspaces/not-a-uuid", "id"},
+		{http.MethodDelete, "/api/v1/workspaces/123", "id"},
+		{http.MethodGet, "/api/v1/workspaces/not-a-uuid/members", "workspace_id"},
+		{http.MethodPost, "/api/v1/workspaces/not-a-uuid/projects", "workspace_id"},
+		{http.MethodGet, "/api/v1/projects/not-a-uuid", "id"},
+		{http.MethodPost, "/api/v1/projects/not-a-uuid/airtable-bases", "project_id"},
+		{http.MethodGet, "/api/v1/airtable-bases/appSales", "id"},
+		{http.MethodPut, "/api/v1/airtable-bases/%7B5b1e0c3a-7d2f-4c8e-9a61-2f0d8b4e7c15%7D", "id"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.method+" "+tt.path, func(t *testing.T) {
+			req, _ := http.NewRequest(tt.method, tt.path, nil)
+			resp, err := app.Test(req, -1)
+			require.NoError(t, err)
+			assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+			var body struct {
+				Error   string   `json:"error"`
+				Details []string `json:"details"`
+			}
+			require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+			assert.Equal(t, "Invalid input", body.Error)
+			assert.Equal(t, []string{tt.param + " must be a UUID"}, body.Details)
+		})
+	}
+}
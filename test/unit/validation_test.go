@@ -0,0 +1,159 @@
+package unit
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/config"
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/models"
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/services"
+)
+
+func TestValidateCreateProject(t *testing.T) {
+	tests := []struct {
+		name           string
+		req            models.CreateProjectRequest
+		strict         bool
+		expectRejected bool
+		expectWarnings int
+	}{
+		{
+			name: "valid request has no warnings",
+			req:  models.CreateProjectRequest{Name: "Roadmap", Description: "Q3 planning"},
+		},
+		{
+			name:           "long description is accepted with a warning",
+			req:            models.CreateProjectRequest{Name: "Roadmap", Description: strings.Repeat("a", 2000)},
+			expectWarnings: 1,
+		},
+		{
+			name:           "strict mode turns warnings into errors",
+			req:            models.CreateProjectRequest{Name: "Roadmap", Description: strings.Repeat("a", 2000)},
+			strict:         true,
+			expectRejected: true,
+			expectWarnings: 1,
+		},
+		{
+			name:           "oversized description is a hard error",
+			req:            models.CreateProjectRequest{Name: "Roadmap", Description: strings.Repeat("a", 20000)},
+			expectRejected: true,
+		},
+		{
+			name:           "blank name is a hard error",
+			req:            models.CreateProjectRequest{Name: "   "},
+			expectRejected: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := services.ValidateCreateProject(&tt.req)
+
+			assert.Equal(t, tt.expectRejected, result.Rejected(tt.strict))
+			assert.Len(t, result.Warnings, tt.expectWarnings)
+		})
+	}
+}
+
+func TestValidateIngestAuditLogs(t *testing.T) {
+	vocabulary := services.NewAuditVocabulary(config.AuditConfig{})
+	valid := models.IngestAuditLogEntry{UserID: "user-1", Action: "data.exported", ResourceType: "report"}
+
+	oversized := make([]models.IngestAuditLogEntry, 501)
+	for i := range oversized {
+		oversized[i] = valid
+	}
+
+	tests := []struct {
+		name         string
+		entries      []models.IngestAuditLogEntry
+		expectErrors int
+	}{
+		{
+			name:    "known vocabulary is accepted",
+			entries: []models.IngestAuditLogEntry{valid, valid},
+		},
+		{
+			name:         "empty batch is rejected",
+			expectErrors: 1,
+		},
+		{
+			name: "unknown action and resource type are rejected per entry",
+			entries: []models.IngestAuditLogEntry{
+				valid,
+				{UserID: "user-1", Action: "wokspace.created", ResourceType: "workspace"},
+				{UserID: "user-1", Action: "data.exported", ResourceType: "spreadsheet"},
+			},
+			expectErrors: 2,
+		},
+		{
+			name:         "user_id is required",
+			entries:      []models.IngestAuditLogEntry{{Action: "data.exported", ResourceType: "report"}},
+			expectErrors: 1,
+		},
+		{
+			name:         "oversized batch is rejected",
+			entries:      oversized,
+			expectErrors: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := services.ValidateIngestAuditLogs(&models.IngestAuditLogsRequest{Entries: tt.entries}, vocabulary)
+
+			assert.Len(t, result.Errors, tt.expectErrors)
+			assert.Equal(t, tt.expectErrors > 0, result.Rejected(false))
+		})
+	}
+}
+
+func TestValidateTagProjects(t *testing.T) {
+	projectID := "7c9e6679-7425-40de-944b-e07fc1f90ae7"
+
+	tests := []struct {
+		name         string
+		req          models.TagProjectsRequest
+		expectErrors int
+	}{
+		{
+			name: "valid request",
+			req:  models.TagProjectsRequest{ProjectIDs: []string{projectID}, Add: []string{"Q3"}, Remove: []string{"draft"}},
+		},
+		{
+			name:         "project_ids are required",
+			req:          models.TagProjectsRequest{Add: []string{"q3"}},
+			expectErrors: 1,
+		},
+		{
+			name:         "project_ids must be IDs",
+			req:          models.TagProjectsRequest{ProjectIDs: []string{"roadmap"}, Add: []string{"q3"}},
+			expectErrors: 1,
+		},
+		{
+			name:         "at least one tag is required",
+			req:          models.TagProjectsRequest{ProjectIDs: []string{projectID}},
+			expectErrors: 1,
+		},
+		{
+			name:         "blank and oversized tags are rejected",
+			req:          models.TagProjectsRequest{ProjectIDs: []string{projectID}, Add: []string{"  ", strings.Repeat("a", 51)}},
+			expectErrors: 2,
+		},
+		{
+			name:         "a tag cannot be added and removed at once",
+			req:          models.TagProjectsRequest{ProjectIDs: []string{projectID}, Add: []string{"Q3"}, Remove: []string{"q3 "}},
+			expectErrors: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := services.ValidateTagProjects(&tt.req)
+
+			assert.Len(t, result.Errors, tt.expectErrors)
+		})
+	}
+}
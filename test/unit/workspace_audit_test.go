@@ -0,0 +1,122 @@
+package unit
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/config"
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/models"
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/repositories"
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/services"
+)
+
+// storedWorkspace serves and updates a single in-memory workspace
+type storedWorkspace struct {
+	repositories.WorkspaceRepository
+	workspace *models.Workspace
+}
+
+func (r *storedWorkspace) GetByID(ctx context.Context, id string) (*models.Workspace, error) {
+	copied := *r.workspace
+	return &copied, nil
+}
+
+func (r *storedWorkspace) Update(ctx context.Context, workspace *models.Workspace) error {
+	copied := *workspace
+	r.workspace = &copied
+	return nil
+}
+
+// recordingChanges remembers each audit action with its changes payload
+type recordingChanges struct {
+	services.AuditService
+	actions []string
+	changes []map[string]interface{}
+}
+
+func (a *recordingChanges) LogAction(ctx context.Context, workspaceID, userID, action, resourceType, resourceID string, changes map[string]interface{}) error {
+	a.actions = append(a.actions, action)
+	a.changes = append(a.changes, changes)
+	return nil
+}
+
+func TestUpdateWorkspaceRecordsGranularAuditActions(t *testing.T) {
+	str := func(s string) *string { return &s }
+
+	tests := []struct {
+		name            string
+		req             *models.UpdateWorkspaceRequest
+		expectedActions []string
+		expectedFields  []string
+	}{
+		{
+			name:            "rename",
+			req:             &models.UpdateWorkspaceRequest{Name: str("Renamed")},
+			expectedActions: []string{models.AuditActionWorkspaceRenamed},
+			expectedFields:  []string{"name"},
+		},
+		{
+			name:            "description change",
+			req:             &models.UpdateWorkspaceRequest{Description: str("New description")},
+			expectedActions: []string{models.AuditActionWorkspaceDescriptionChanged},
+			expectedFields:  []string{"description"},
+		},
+		{
+			name:            "settings change",
+			req:             &models.UpdateWorkspaceRequest{Settings: &models.JSONMap{"theme": "dark"}},
+			expectedActions: []string{models.AuditActionWorkspaceSettingsChanged},
+			expectedFields:  []string{"settings"},
+		},
+		{
+			name: "combined update records one entry per field",
+			req: &models.UpdateWorkspaceRequest{
+				Name:     str("Renamed"),
+				Settings: &models.JSONMap{"theme": "dark"},
+			},
+			expectedActions: []string{models.AuditActionWorkspaceRenamed, models.AuditActionWorkspaceSettingsChanged},
+			expectedFields:  []string{"name", "settings"},
+		},
+		{
+			name: "unchanged values are not audited",
+			req: &models.UpdateWorkspaceRequest{
+				Name:     str("Original"),
+				Settings: &models.JSONMap{"theme": "light"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			workspaces := &storedWorkspace{workspace: &models.Workspace{
+				BaseModel:   models.BaseModel{ID: "ws-1"},
+				Name:        "Original",
+				Description: "Original description",
+				Settings:    models.JSONMap{"theme": "light"},
+			}}
+			audit := &recordingChanges{}
+			repos := &repositories.Repositories{
+				Workspace: workspaces,
+				Member:    &singleMember{role: models.WorkspaceRoleAdmin},
+				Cache:     &nopCache{},
+			}
+			svc := services.NewWorkspaceService(repos, &config.Config{}, zap.NewNop(), audit, nil)
+
+			_, err := svc.UpdateWorkspace(context.Background(), "ws-1", "user-1", tt.req)
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.expectedActions, audit.actions)
+			var fields []string
+			for _, changes := range audit.changes {
+				require.Len(t, changes, 1)
+				for field := range changes {
+					fields = append(fields, field)
+				}
+			}
+			assert.Equal(t, tt.expectedFields, fields)
+		})
+	}
+}
@@ -0,0 +1,153 @@
+package unit
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/config"
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/handlers"
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/models"
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/services"
+)
+
+// slowWorkspaces holds each update until gate is closed, tracking how many run at once
+type slowWorkspaces struct {
+	fixedWorkspace
+	gate     chan struct{}
+	inFlight int32
+	peak     int32
+}
+
+func (s *slowWorkspaces) UpdateWorkspace(ctx context.Context, workspaceID, userID string, req *models.UpdateWorkspaceRequest) (*models.Workspace, error) {
+	running := atomic.AddInt32(&s.inFlight, 1)
+	defer atomic.AddInt32(&s.inFlight, -1)
+	for {
+		peak := atomic.LoadInt32(&s.peak)
+		if running <= peak || atomic.CompareAndSwapInt32(&s.peak, peak, running) {
+			break
+		}
+	}
+	<-s.gate
+	return s.GetWorkspace(ctx, workspaceID, userID)
+}
+
+// slowProjects places every project in batchWorkspaceID and holds each delete until gate is closed
+type slowProjects struct {
+	services.ProjectService
+	gate     chan struct{}
+	inFlight int32
+}
+
+func (s *slowProjects) ResolveWorkspace(ctx context.Context, projectID string) (string, error) {
+	return batchWorkspaceID, nil
+}
+
+func (s *slowProjects) DeleteProject(ctx context.Context, projectID, userID string) error {
+	atomic.AddInt32(&s.inFlight, 1)
+	defer atomic.AddInt32(&s.inFlight, -1)
+	<-s.gate
+	return nil
+}
+
+func TestWorkspaceWriteConcurrencyCap(t *testing.T) {
+	newApp := func(concurrency config.ConcurrencyConfig, workspaces *slowWorkspaces) *fiber.App {
+		h := handlers.New(&services.Services{Workspace: workspaces, Project: &slowProjects{gate: workspaces.gate}}, &config.Config{Concurrency: concurrency}, zap.NewNop())
+		app := fiber.New()
+		app.Use(func(c *fiber.Ctx) error {
+			c.Locals("user_id", "admin-1")
+			return c.Next()
+		})
+		h.RegisterRoutes(app)
+		return app
+	}
+
+	update := func(t *testing.T, app *fiber.App, workspaceID string) int {
+		req, _ := http.NewRequest(http.MethodPut, "/api/v1/workspaces/"+workspaceID, strings.NewReader(`{"description":"busy"}`))
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := app.Test(req, -1)
+		require.NoError(t, err)
+		return resp.StatusCode
+	}
+
+	t.Run("queued updates all run without exceeding the cap", func(t *testing.T) {
+		workspaces := &slowWorkspaces{gate: make(chan struct{})}
+		app := newApp(config.ConcurrencyConfig{PerWorkspace: 3, QueueTimeoutMs: 10000}, workspaces)
+
+		const updates = 25
+		statuses := make(chan int, updates)
+		var wg sync.WaitGroup
+		for i := 0; i < updates; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				statuses <- update(t, app, batchWorkspaceID)
+			}()
+		}
+
+		// Let the queue form before the first updates finish
+		require.Eventually(t, func() bool { return atomic.LoadInt32(&workspaces.inFlight) == 3 }, time.Second, time.Millisecond)
+		time.Sleep(20 * time.Millisecond)
+		close(workspaces.gate)
+		wg.Wait()
+		close(statuses)
+
+		for status := range statuses {
+			assert.Equal(t, http.StatusOK, status)
+		}
+		assert.Equal(t, int32(3), atomic.LoadInt32(&workspaces.peak))
+	})
+
+	t.Run("updates over the cap are refused without a queue", func(t *testing.T) {
+		workspaces := &slowWorkspaces{gate: make(chan struct{})}
+		app := newApp(config.ConcurrencyConfig{PerWorkspace: 2}, workspaces)
+
+		var wg sync.WaitGroup
+		for i := 0; i < 2; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				assert.Equal(t, http.StatusOK, update(t, app, batchWorkspaceID))
+			}()
+		}
+		require.Eventually(t, func() bool { return atomic.LoadInt32(&workspaces.inFlight) == 2 }, time.Second, time.Millisecond)
+
+		assert.Equal(t, http.StatusTooManyRequests, update(t, app, batchWorkspaceID))
+
+		// Other workspaces have slots of their own
+		other := make(chan int, 1)
+		go func() { other <- update(t, app, "0f4d2c1b-8e3a-4b7c-9d65-1a2b3c4d5e6f") }()
+		require.Eventually(t, func() bool { return atomic.LoadInt32(&workspaces.inFlight) == 3 }, time.Second, time.Millisecond)
+
+		close(workspaces.gate)
+		wg.Wait()
+		assert.Equal(t, http.StatusOK, <-other)
+		assert.Equal(t, http.StatusOK, update(t, app, batchWorkspaceID))
+	})
+
+	t.Run("project writes share their workspace's cap", func(t *testing.T) {
+		workspaces := &slowWorkspaces{gate: make(chan struct{})}
+		app := newApp(config.ConcurrencyConfig{PerWorkspace: 1}, workspaces)
+
+		done := make(chan int, 1)
+		go func() { done <- update(t, app, batchWorkspaceID) }()
+		require.Eventually(t, func() bool { return atomic.LoadInt32(&workspaces.inFlight) == 1 }, time.Second, time.Millisecond)
+
+		req, _ := http.NewRequest(http.MethodDelete, "/api/v1/projects/6b1f3c2d-9a4e-4f1b-8c2d-3e4f5a6b7c8d", nil)
+		resp, err := app.Test(req, -1)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusTooManyRequests, resp.StatusCode)
+
+		close(workspaces.gate)
+		assert.Equal(t, http.StatusOK, <-done)
+	})
+}
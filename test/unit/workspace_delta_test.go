@@ -0,0 +1,69 @@
+package unit
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/config"
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/handlers"
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/models"
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/services"
+)
+
+// tombstoneWorkspaces lists one deleted workspace to callers who are not its admins
+type tombstoneWorkspaces struct {
+	services.WorkspaceService
+}
+
+func (s *tombstoneWorkspaces) ListWorkspaces(ctx context.Context, filter *models.WorkspaceFilter, userID string) (*models.WorkspaceListResponse, error) {
+	if filter.ModifiedSince != "" {
+		since, _ := time.Parse(time.RFC3339, filter.ModifiedSince)
+		filter.ModifiedAfter = &since
+	}
+	workspace := &models.Workspace{Name: "Gone"}
+	workspace.ID = batchWorkspaceID
+	workspace.DeletedAt = gorm.DeletedAt{Time: time.Now(), Valid: true}
+	return &models.WorkspaceListResponse{Workspaces: []*models.Workspace{workspace}, Total: 1, Page: 1, PageSize: 20, TotalPages: 1}, nil
+}
+
+func (s *tombstoneWorkspaces) CheckUserAccess(ctx context.Context, workspaceID, userID string, requiredRole models.WorkspaceMemberRole) error {
+	return services.ErrUnauthorized
+}
+
+func TestDeltaSyncShowsTombstones(t *testing.T) {
+	h := handlers.New(&services.Services{Workspace: &tombstoneWorkspaces{}}, &config.Config{}, zap.NewNop())
+	app := fiber.New()
+	app.Use(func(c *fiber.Ctx) error {
+		c.Locals("user_id", "member-1")
+		return c.Next()
+	})
+	h.RegisterRoutes(app)
+
+	listed := func(t *testing.T, query string) map[string]interface{} {
+		req, _ := http.NewRequest(http.MethodGet, "/api/v1/workspaces"+query, nil)
+		resp, err := app.Test(req, -1)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		var body struct {
+			Workspaces []map[string]interface{} `json:"workspaces"`
+		}
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+		require.Len(t, body.Workspaces, 1)
+		return body.Workspaces[0]
+	}
+
+	// Deletion metadata stays hidden from non-admins in ordinary listings
+	assert.NotContains(t, listed(t, "?include_deleted=true"), "deleted_at")
+
+	// A delta sync still tells them deletions apart, through a tombstone that carries nothing else
+	assert.Equal(t, map[string]interface{}{"id": batchWorkspaceID, "deleted": true}, listed(t, "?include_deleted=true&modified_since=2024-01-01T00:00:00Z"))
+}
@@ -0,0 +1,87 @@
+package unit
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/config"
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/handlers"
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/models"
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/services"
+)
+
+// fixedWorkspace returns the same workspace to every reader
+type fixedWorkspace struct {
+	services.WorkspaceService
+}
+
+func (s *fixedWorkspace) GetWorkspace(ctx context.Context, workspaceID, userID string) (*models.Workspace, error) {
+	workspace := &models.Workspace{TenantID: "tenant-1", Name: "Landing"}
+	workspace.ID = workspaceID
+	return workspace, nil
+}
+
+func (s *fixedWorkspace) CheckUserAccess(ctx context.Context, workspaceID, userID string, requiredRole models.WorkspaceMemberRole) error {
+	return nil
+}
+
+// pagedProjects records the filters it lists with and serves a page of two projects
+type pagedProjects struct {
+	services.ProjectService
+	filters []*models.ProjectFilter
+}
+
+func (s *pagedProjects) ListProjects(ctx context.Context, filter *models.ProjectFilter, userID string) (*models.ProjectListResponse, error) {
+	s.filters = append(s.filters, filter)
+	return &models.ProjectListResponse{
+		Projects:   []*models.Project{{Name: "One"}, {Name: "Two"}},
+		Total:      7,
+		Page:       filter.Page,
+		PageSize:   filter.PageSize,
+		TotalPages: 4,
+	}, nil
+}
+
+func TestGetWorkspaceIncludeProjects(t *testing.T) {
+	projects := &pagedProjects{}
+	h := handlers.New(&services.Services{Workspace: &fixedWorkspace{}, Project: projects}, &config.Config{}, zap.NewNop())
+	app := fiber.New()
+	app.Use(func(c *fiber.Ctx) error {
+		c.Locals("user_id", "viewer-1")
+		return c.Next()
+	})
+	h.RegisterRoutes(app)
+
+	get := func(t *testing.T, query string) map[string]interface{} {
+		req, _ := http.NewRequest(http.MethodGet, "/api/v1/workspaces/"+batchWorkspaceID+query, nil)
+		resp, err := app.Test(req, -1)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		var body map[string]interface{}
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+		return body
+	}
+
+	body := get(t, "?include=projects&page=2&page_size=2")
+	assert.Equal(t, "Landing", body["name"])
+	embedded := body["projects"].(map[string]interface{})
+	assert.Equal(t, float64(7), embedded["total"])
+	assert.Equal(t, float64(2), embedded["page"])
+	assert.Len(t, embedded["projects"], 2)
+	assert.Contains(t, embedded["links"].(map[string]interface{})["next"], "include=projects")
+
+	require.Len(t, projects.filters, 1)
+	assert.Equal(t, batchWorkspaceID, projects.filters[0].WorkspaceID)
+	assert.False(t, projects.filters[0].IncludeDeleted)
+
+	// Without the include the project listing is never read
+	assert.NotContains(t, get(t, ""), "projects")
+	assert.Len(t, projects.filters, 1)
+}
@@ -0,0 +1,88 @@
+package unit
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/config"
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/handlers"
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/repositories"
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/services"
+)
+
+func TestResolveWorkspaceNames(t *testing.T) {
+	const (
+		mine   = "11111111-1111-1111-1111-111111111111"
+		theirs = "22222222-2222-2222-2222-222222222222"
+	)
+	cfg := &config.Config{Platform: config.PlatformConfig{Admins: "platform-admin"}}
+
+	resolve := func(t *testing.T, userID, body string) (sqlmock.Sqlmock, func() (int, map[string]string)) {
+		conn, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		t.Cleanup(func() { conn.Close() })
+		db, err := gorm.Open(postgres.New(postgres.Config{Conn: conn}), &gorm.Config{})
+		require.NoError(t, err)
+
+		repos := &repositories.Repositories{Workspace: repositories.NewWorkspaceRepository(db, cfg, zap.NewNop())}
+		svc := &services.Services{Workspace: services.NewWorkspaceService(repos, cfg, zap.NewNop(), nil, nil)}
+		app := fiber.New()
+		app.Use(func(c *fiber.Ctx) error {
+			c.Locals("user_id", userID)
+			return c.Next()
+		})
+		handlers.New(svc, cfg, zap.NewNop()).RegisterRoutes(app)
+
+		return mock, func() (int, map[string]string) {
+			req, _ := http.NewRequest(http.MethodPost, "/api/v1/workspaces/resolve", strings.NewReader(body))
+			req.Header.Set("Content-Type", "application/json")
+			resp, err := app.Test(req, -1)
+			require.NoError(t, err)
+			var names map[string]string
+			_ = json.NewDecoder(resp.Body).Decode(&names)
+			return resp.StatusCode, names
+		}
+	}
+
+	t.Run("members resolve their workspaces in one query", func(t *testing.T) {
+		mock, send := resolve(t, "user-1", `{"workspace_ids":["`+mine+`","`+theirs+`"]}`)
+		mock.ExpectQuery(`SELECT workspaces.id,workspaces.name FROM "workspaces" JOIN workspace_members .* WHERE \(workspaces.id IN \(\$2,\$3\)`).
+			WithArgs("user-1", mine, theirs).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(mine, "Mine"))
+
+		status, names := send()
+		assert.Equal(t, http.StatusOK, status)
+		assert.Equal(t, map[string]string{mine: "Mine"}, names, "inaccessible IDs are omitted")
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("platform admins resolve any workspace", func(t *testing.T) {
+		mock, send := resolve(t, "platform-admin", `{"workspace_ids":["`+mine+`","`+theirs+`"]}`)
+		mock.ExpectQuery(`SELECT workspaces.id,workspaces.name FROM "workspaces" WHERE \(workspaces.id IN`).
+			WithArgs(mine, theirs).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(mine, "Mine").AddRow(theirs, "Theirs"))
+
+		status, names := send()
+		assert.Equal(t, http.StatusOK, status)
+		assert.Equal(t, map[string]string{mine: "Mine", theirs: "Theirs"}, names)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("malformed IDs are rejected before querying", func(t *testing.T) {
+		mock, send := resolve(t, "user-1", `{"workspace_ids":["not-a-uuid"]}`)
+
+		status, _ := send()
+		assert.Equal(t, http.StatusBadRequest, status)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
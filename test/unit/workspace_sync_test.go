@@ -0,0 +1,106 @@
+package unit
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/config"
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/handlers"
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/models"
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/repositories"
+	"github.com/Reg-Kris/pyairtable-workspace-service/internal/services"
+)
+
+// syncedBases reports a fixed number of bases updated and remembers the requested state
+type syncedBases struct {
+	repositories.AirtableBaseRepository
+	enabled *bool
+}
+
+func (r *syncedBases) SetSyncEnabledForWorkspace(ctx context.Context, workspaceID string, enabled bool) (int64, error) {
+	r.enabled = &enabled
+	return 3, nil
+}
+
+func TestSetWorkspaceSync(t *testing.T) {
+	tests := []struct {
+		name           string
+		userID         string
+		body           string
+		expectedStatus int
+		expectedUpdate bool
+	}{
+		{
+			name:           "admin pauses syncing",
+			userID:         "admin-1",
+			body:           `{"enabled":false}`,
+			expectedStatus: http.StatusOK,
+			expectedUpdate: true,
+		},
+		{
+			name:           "members cannot change syncing",
+			userID:         "member-1",
+			body:           `{"enabled":false}`,
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:           "enabled is required",
+			userID:         "admin-1",
+			body:           `{}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bases := &syncedBases{}
+			audit := &recordingActions{}
+			repos := &repositories.Repositories{
+				AirtableBase: bases,
+				Member: &roleMembers{roles: map[string]models.WorkspaceMemberRole{
+					"admin-1":  models.WorkspaceRoleAdmin,
+					"member-1": models.WorkspaceRoleMember,
+				}},
+			}
+			svc := &services.Services{
+				AirtableBase: services.NewAirtableBaseService(repos, &config.Config{}, zap.NewNop(), audit, nil),
+			}
+			h := handlers.New(svc, &config.Config{}, zap.NewNop())
+
+			app := fiber.New()
+			app.Use(func(c *fiber.Ctx) error {
+				c.Locals("user_id", tt.userID)
+				return c.Next()
+			})
+			app.Post("/workspaces/:workspace_id/sync", h.SetWorkspaceSync)
+
+			req, _ := http.NewRequest("POST", "/workspaces/ws-1/sync", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			resp, err := app.Test(req, -1)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedStatus, resp.StatusCode)
+
+			if !tt.expectedUpdate {
+				assert.Nil(t, bases.enabled)
+				assert.Empty(t, audit.actions)
+				return
+			}
+
+			var result models.SetWorkspaceSyncResponse
+			require.NoError(t, json.NewDecoder(resp.Body).Decode(&result))
+			assert.False(t, result.SyncEnabled)
+			assert.Equal(t, int64(3), result.BasesUpdated)
+			require.NotNil(t, bases.enabled)
+			assert.False(t, *bases.enabled)
+			assert.Equal(t, []string{models.AuditActionWorkspaceSyncUpdated}, audit.actions)
+		})
+	}
+}